@@ -1,6 +1,2 @@
--- DROP INDEX IF EXISTS accounts_owner_idx;
--- DROP INDEX IF EXISTS accounts_owner_currency_idx;
--- DROP INDEX IF EXISTS entries_account_id_idx;
--- DROP INDEX IF EXISTS transfers_from_account_id_idx;
--- DROP INDEX IF EXISTS transfers_to_account_id_idx;
--- DROP INDEX IF EXISTS transfers_from_to_account_idx;
+-- Description: The indexes of the accounts, the transfers and the entries
+-- are dropped along with their tables, see 000019_create_accounts.
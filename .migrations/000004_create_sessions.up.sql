@@ -0,0 +1,16 @@
+-- Description: Create table sessions
+CREATE TABLE sessions
+(
+    session_id   UUID      NOT NULL,
+    user_id      UUID      NOT NULL,
+    token_hash   TEXT      NOT NULL,
+    revoked      BOOLEAN   NOT NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_updated TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (session_id),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON "sessions" ("user_id");
@@ -0,0 +1,35 @@
+-- Description: Create table roles
+CREATE TABLE roles
+(
+    name         TEXT      NOT NULL,
+    description  TEXT      NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+    date_updated TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (name)
+);
+
+-- Description: Create table role_permissions
+CREATE TABLE role_permissions
+(
+    role_name  TEXT NOT NULL,
+    permission TEXT NOT NULL,
+
+    PRIMARY KEY (role_name, permission),
+    FOREIGN KEY (role_name) REFERENCES roles (name) ON DELETE CASCADE
+);
+
+-- Description: Seed the built-in roles
+INSERT INTO roles (name, description, date_created, date_updated)
+VALUES ('ADMIN', 'Administrator', NOW(), NOW()),
+       ('USER', 'Regular user', NOW(), NOW());
+
+INSERT INTO role_permissions (role_name, permission)
+VALUES ('ADMIN', 'user:read'),
+       ('ADMIN', 'user:write'),
+       ('ADMIN', 'product:read'),
+       ('ADMIN', 'audit:read'),
+       ('ADMIN', 'role:read'),
+       ('ADMIN', 'role:write'),
+       ('USER', 'product:read'),
+       ('USER', 'product:write');
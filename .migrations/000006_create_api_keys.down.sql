@@ -0,0 +1,3 @@
+DELETE FROM role_permissions WHERE permission IN ('service_account:read', 'service_account:write');
+DROP TABLE IF EXISTS "api_keys";
+DROP TABLE IF EXISTS "service_accounts";
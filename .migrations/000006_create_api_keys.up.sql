@@ -0,0 +1,40 @@
+-- Description: Create table service_accounts
+CREATE TABLE service_accounts
+(
+    service_account_id UUID        NOT NULL,
+    name               TEXT UNIQUE NOT NULL,
+    description        TEXT        NOT NULL,
+    roles              TEXT[]      NOT NULL,
+    enabled            BOOLEAN     NOT NULL,
+    created_by         UUID        NOT NULL,
+    date_created       TIMESTAMP   NOT NULL,
+    date_updated       TIMESTAMP   NOT NULL,
+
+    PRIMARY KEY (service_account_id)
+);
+
+-- Description: Create table api_keys
+CREATE TABLE api_keys
+(
+    api_key_id         UUID        NOT NULL,
+    service_account_id UUID        NOT NULL,
+    name               TEXT        NOT NULL,
+    prefix             TEXT UNIQUE NOT NULL,
+    key_hash           TEXT        NOT NULL,
+    scopes             TEXT[]      NOT NULL,
+    expires_at         TIMESTAMP NULL,
+    last_used_at       TIMESTAMP NULL,
+    revoked            BOOLEAN     NOT NULL,
+    date_created       TIMESTAMP   NOT NULL,
+    date_updated       TIMESTAMP   NOT NULL,
+
+    PRIMARY KEY (api_key_id),
+    FOREIGN KEY (service_account_id) REFERENCES service_accounts (service_account_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_service_account_id_idx ON "api_keys" ("service_account_id");
+
+-- Description: Grant the administrators the management of service accounts
+INSERT INTO role_permissions (role_name, permission)
+VALUES ('ADMIN', 'service_account:read'),
+       ('ADMIN', 'service_account:write');
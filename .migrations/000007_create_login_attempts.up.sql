@@ -0,0 +1,11 @@
+-- Description: Create table login_attempts
+-- A row counts the recent failed logins for an email or an ip address.
+CREATE TABLE login_attempts
+(
+    attempt_key  TEXT      NOT NULL,
+    failures     INT       NOT NULL,
+    last_failure TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP NULL,
+
+    PRIMARY KEY (attempt_key)
+);
@@ -0,0 +1,15 @@
+-- Description: Create table user_mfa
+-- The totp secret is encrypted, the recovery codes are hashed.
+CREATE TABLE user_mfa
+(
+    user_id        UUID      NOT NULL,
+    secret         TEXT      NOT NULL,
+    confirmed      BOOLEAN   NOT NULL,
+    last_used_step BIGINT    NOT NULL,
+    recovery_codes TEXT[]    NOT NULL,
+    date_created   TIMESTAMP NOT NULL,
+    date_updated   TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS tokens_valid_after;
+
+DROP TABLE IF EXISTS "password_resets";
@@ -0,0 +1,21 @@
+-- Description: Create table password_resets
+-- A row is a single use password reset token, only the hash of the token is
+-- stored.
+CREATE TABLE password_resets
+(
+    reset_id     UUID      NOT NULL,
+    user_id      UUID      NOT NULL,
+    token_hash   TEXT      NOT NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    used_at      TIMESTAMP NULL,
+    date_created TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (reset_id),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS password_resets_user_id_idx ON "password_resets" ("user_id");
+
+-- Description: Tokens issued to a user before this time are rejected.
+ALTER TABLE users
+    ADD COLUMN tokens_valid_after TIMESTAMP NULL;
@@ -0,0 +1,26 @@
+-- Description: Create table invites
+-- A row is an invitation of an email to join with a set of roles, the user is
+-- only created once the invitation is accepted.
+CREATE TABLE invites
+(
+    invite_id    UUID      NOT NULL,
+    email        TEXT      NOT NULL,
+    roles        TEXT[]    NOT NULL,
+    invited_by   UUID      NOT NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    accepted_at  TIMESTAMP NULL,
+    date_created TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (invite_id),
+    FOREIGN KEY (invited_by) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS invites_email_idx ON "invites" ("email");
+
+-- Description: The users existing before the verification are trusted, the
+-- new ones have to verify their email.
+ALTER TABLE users
+    ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE users
+    ALTER COLUMN email_verified SET DEFAULT FALSE;
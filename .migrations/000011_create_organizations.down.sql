@@ -0,0 +1,35 @@
+DELETE
+FROM roles
+WHERE name = 'SUPER_ADMIN';
+
+DROP VIEW IF EXISTS view_user_products;
+
+CREATE VIEW view_user_products AS
+SELECT p.product_id,
+       p.user_id,
+       p.name,
+       p.cost,
+       p.quantity,
+       p.date_created,
+       p.date_updated,
+       u.name AS user_name
+FROM products AS p
+         JOIN
+     users AS u ON u.user_id = p.user_id;
+
+ALTER TABLE service_accounts
+    DROP COLUMN IF EXISTS org_id;
+
+ALTER TABLE invites
+    DROP COLUMN IF EXISTS org_id;
+
+ALTER TABLE audit
+    DROP COLUMN IF EXISTS org_id;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS org_id;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS org_id;
+
+DROP TABLE IF EXISTS "organizations";
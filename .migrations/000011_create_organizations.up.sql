@@ -0,0 +1,83 @@
+-- Description: Create table organizations
+-- An organization is a tenant, the users, products and audit records belong
+-- to one and are only reached by the users of that organization.
+CREATE TABLE organizations
+(
+    org_id       UUID        NOT NULL,
+    name         TEXT UNIQUE NOT NULL,
+    date_created TIMESTAMP   NOT NULL,
+    date_updated TIMESTAMP   NOT NULL,
+
+    PRIMARY KEY (org_id)
+);
+
+-- Description: The default organization owns the data existing before the
+-- organizations.
+INSERT INTO organizations (org_id, name, date_created, date_updated)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default', NOW(), NOW());
+
+ALTER TABLE users
+    ADD COLUMN org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES organizations (org_id);
+
+ALTER TABLE products
+    ADD COLUMN org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES organizations (org_id);
+
+ALTER TABLE audit
+    ADD COLUMN org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES organizations (org_id);
+
+ALTER TABLE invites
+    ADD COLUMN org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES organizations (org_id);
+
+ALTER TABLE service_accounts
+    ADD COLUMN org_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES organizations (org_id);
+
+-- The organization of the new rows is always set.
+ALTER TABLE users
+    ALTER COLUMN org_id DROP DEFAULT;
+
+ALTER TABLE products
+    ALTER COLUMN org_id DROP DEFAULT;
+
+ALTER TABLE audit
+    ALTER COLUMN org_id DROP DEFAULT;
+
+ALTER TABLE invites
+    ALTER COLUMN org_id DROP DEFAULT;
+
+ALTER TABLE service_accounts
+    ALTER COLUMN org_id DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS users_org_id_idx ON "users" ("org_id");
+
+CREATE INDEX IF NOT EXISTS products_org_id_idx ON "products" ("org_id");
+
+CREATE INDEX IF NOT EXISTS audit_org_id_idx ON "audit" ("org_id");
+
+CREATE INDEX IF NOT EXISTS service_accounts_org_id_idx ON "service_accounts" ("org_id");
+
+CREATE OR REPLACE VIEW view_user_products AS
+SELECT p.product_id,
+       p.user_id,
+       p.name,
+       p.cost,
+       p.quantity,
+       p.date_created,
+       p.date_updated,
+       u.name AS user_name,
+       p.org_id
+FROM products AS p
+         JOIN
+     users AS u ON u.user_id = p.user_id;
+
+-- Description: The super admin works across the organizations.
+INSERT INTO roles (name, description, date_created, date_updated)
+VALUES ('SUPER_ADMIN', 'Administrator of every organization', NOW(), NOW());
+
+INSERT INTO role_permissions (role_name, permission)
+SELECT 'SUPER_ADMIN', permission
+FROM role_permissions
+WHERE role_name = 'ADMIN';
+
+INSERT INTO role_permissions (role_name, permission)
+VALUES ('SUPER_ADMIN', 'organization:read'),
+       ('SUPER_ADMIN', 'organization:write');
@@ -0,0 +1,23 @@
+ALTER VIEW view_user_products RESET (security_invoker);
+
+DROP POLICY IF EXISTS audit_isolation ON audit;
+ALTER TABLE audit
+    NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE audit
+    DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS products_isolation ON products;
+ALTER TABLE products
+    NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE products
+    DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS users_isolation ON users;
+ALTER TABLE users
+    NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE users
+    DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS app_is_admin();
+DROP FUNCTION IF EXISTS app_org_id();
+DROP FUNCTION IF EXISTS app_user_id();
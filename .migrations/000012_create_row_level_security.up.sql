@@ -0,0 +1,60 @@
+-- Description: Row level security on the users, products and audit tables.
+-- The service sets the app.user_id, app.roles and app.org_id settings for the
+-- statements run on behalf of a caller. A caller only reaches the rows of its
+-- organization, and unless an admin only the rows it owns. The statements run
+-- without a caller, like the login or the command line, are not restricted.
+-- The policies are not applied to a superuser, the service must connect with
+-- a role that is not one.
+CREATE OR REPLACE FUNCTION app_user_id() RETURNS UUID
+    LANGUAGE sql
+    STABLE
+AS
+$$
+SELECT CAST(NULLIF(current_setting('app.user_id', true), '') AS UUID)
+$$;
+
+CREATE OR REPLACE FUNCTION app_org_id() RETURNS UUID
+    LANGUAGE sql
+    STABLE
+AS
+$$
+SELECT CAST(NULLIF(current_setting('app.org_id', true), '') AS UUID)
+$$;
+
+CREATE OR REPLACE FUNCTION app_is_admin() RETURNS BOOLEAN
+    LANGUAGE sql
+    STABLE
+AS
+$$
+SELECT COALESCE(string_to_array(NULLIF(current_setting('app.roles', true), ''), ',') && ARRAY ['ADMIN', 'SUPER_ADMIN'], false)
+$$;
+
+ALTER TABLE users
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY users_isolation ON users
+    USING (app_user_id() IS NULL OR
+           ((app_org_id() IS NULL OR org_id = app_org_id()) AND (app_is_admin() OR user_id = app_user_id())));
+
+ALTER TABLE products
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE products
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY products_isolation ON products
+    USING (app_user_id() IS NULL OR
+           ((app_org_id() IS NULL OR org_id = app_org_id()) AND (app_is_admin() OR user_id = app_user_id())));
+
+ALTER TABLE audit
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY audit_isolation ON audit
+    USING (app_user_id() IS NULL OR
+           ((app_org_id() IS NULL OR org_id = app_org_id()) AND (app_is_admin() OR actor_id = app_user_id())));
+
+-- Description: The view checks the policies of the caller, not of its owner.
+ALTER VIEW view_user_products SET (security_invoker = true);
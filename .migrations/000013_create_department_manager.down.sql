@@ -0,0 +1,9 @@
+DROP POLICY IF EXISTS products_department_read ON products;
+DROP POLICY IF EXISTS users_department_read ON users;
+
+DROP FUNCTION IF EXISTS app_is_manager();
+DROP FUNCTION IF EXISTS app_department();
+
+DELETE
+FROM roles
+WHERE name = 'MANAGER';
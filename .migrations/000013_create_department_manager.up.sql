@@ -0,0 +1,38 @@
+-- Description: The manager reads the users of their department and the
+-- products these users own. The service sets the app.department setting from
+-- the claims of the caller.
+INSERT INTO roles (name, description, date_created, date_updated)
+VALUES ('MANAGER', 'Manager of a department', NOW(), NOW());
+
+INSERT INTO role_permissions (role_name, permission)
+VALUES ('MANAGER', 'product:read');
+
+CREATE OR REPLACE FUNCTION app_department() RETURNS TEXT
+    LANGUAGE sql
+    STABLE
+AS
+$$
+SELECT NULLIF(current_setting('app.department', true), '')
+$$;
+
+CREATE OR REPLACE FUNCTION app_is_manager() RETURNS BOOLEAN
+    LANGUAGE sql
+    STABLE
+AS
+$$
+SELECT COALESCE(string_to_array(NULLIF(current_setting('app.roles', true), ''), ',') && ARRAY ['MANAGER'], false)
+$$;
+
+CREATE POLICY users_department_read ON users
+    FOR SELECT
+    USING (app_is_manager() AND app_department() IS NOT NULL AND
+           (app_org_id() IS NULL OR org_id = app_org_id()) AND department = app_department());
+
+CREATE POLICY products_department_read ON products
+    FOR SELECT
+    USING (app_is_manager() AND app_department() IS NOT NULL AND
+           (app_org_id() IS NULL OR org_id = app_org_id()) AND
+           EXISTS (SELECT 1
+                   FROM users AS u
+                   WHERE u.user_id = products.user_id
+                     AND u.department = app_department()));
@@ -0,0 +1,7 @@
+DELETE
+FROM role_permissions
+WHERE permission = 'security_event:read';
+
+DROP POLICY IF EXISTS security_events_isolation ON security_events;
+
+DROP TABLE IF EXISTS "security_events";
@@ -0,0 +1,38 @@
+-- Description: Create table security_events
+-- The logins, failed logins, issued tokens and permission denials, along with
+-- where the request came from. The actor is unknown for a failed login with
+-- an unknown email.
+CREATE TABLE security_events
+(
+    id         UUID      NOT NULL,
+    org_id     UUID      NOT NULL REFERENCES organizations (org_id),
+    kind       TEXT      NOT NULL,
+    actor_id   UUID      NULL,
+    subject    TEXT      NOT NULL,
+    ip         TEXT      NOT NULL,
+    user_agent TEXT      NOT NULL,
+    outcome    TEXT      NOT NULL,
+    reason     TEXT      NOT NULL,
+    timestamp  TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS security_events_org_id_idx ON "security_events" ("org_id");
+
+CREATE INDEX IF NOT EXISTS security_events_actor_id_idx ON "security_events" ("actor_id");
+
+CREATE INDEX IF NOT EXISTS security_events_timestamp_idx ON "security_events" ("timestamp");
+
+ALTER TABLE security_events
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE security_events
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY security_events_isolation ON security_events
+    USING (app_user_id() IS NULL OR
+           ((app_org_id() IS NULL OR org_id = app_org_id()) AND (app_is_admin() OR actor_id = app_user_id())));
+
+INSERT INTO role_permissions (role_name, permission)
+VALUES ('ADMIN', 'security_event:read'),
+       ('SUPER_ADMIN', 'security_event:read');
@@ -0,0 +1,47 @@
+-- Description: The values of the users already encrypted are left as they
+-- are, only the rows without a data key can be read afterwards.
+DROP VIEW IF EXISTS view_user_products;
+
+CREATE VIEW view_user_products AS
+SELECT p.product_id,
+       p.user_id,
+       p.name,
+       p.cost,
+       p.quantity,
+       p.date_created,
+       p.date_updated,
+       u.name AS user_name,
+       p.org_id
+FROM products AS p
+         JOIN
+     users AS u ON u.user_id = p.user_id;
+
+ALTER VIEW view_user_products SET (security_invoker = true);
+
+DROP POLICY IF EXISTS products_department_read ON products;
+
+CREATE POLICY products_department_read ON products
+    FOR SELECT
+    USING (app_is_manager() AND app_department() IS NOT NULL AND
+           (app_org_id() IS NULL OR org_id = app_org_id()) AND
+           EXISTS (SELECT 1
+                   FROM users AS u
+                   WHERE u.user_id = products.user_id
+                     AND u.department = app_department()));
+
+DROP POLICY IF EXISTS users_department_read ON users;
+
+CREATE POLICY users_department_read ON users
+    FOR SELECT
+    USING (app_is_manager() AND app_department() IS NOT NULL AND
+           (app_org_id() IS NULL OR org_id = app_org_id()) AND department = app_department());
+
+DROP INDEX IF EXISTS users_department_index_idx;
+DROP INDEX IF EXISTS users_name_index_idx;
+DROP INDEX IF EXISTS users_email_index_idx;
+
+ALTER TABLE users
+    DROP COLUMN department_index,
+    DROP COLUMN name_index,
+    DROP COLUMN email_index,
+    DROP COLUMN data_key;
\ No newline at end of file
@@ -0,0 +1,54 @@
+-- Description: The name, email and department of the users are encrypted by
+-- the service under a data key of each user, stored wrapped by a master key
+-- in data_key. The rows without a data key were stored before and remain in
+-- plaintext until the reencrypt command encrypts them. The encrypted values
+-- are looked up by their blind index: the email stays unique by its own and
+-- the department of a manager, set by the service in app.department, is now
+-- its blind index too.
+ALTER TABLE users
+    ADD COLUMN data_key         TEXT NULL,
+    ADD COLUMN email_index      TEXT NULL,
+    ADD COLUMN name_index       TEXT NULL,
+    ADD COLUMN department_index TEXT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_index_idx ON "users" ("email_index");
+
+CREATE INDEX IF NOT EXISTS users_name_index_idx ON "users" ("name_index");
+
+CREATE INDEX IF NOT EXISTS users_department_index_idx ON "users" ("department_index");
+
+DROP POLICY IF EXISTS users_department_read ON users;
+
+CREATE POLICY users_department_read ON users
+    FOR SELECT
+    USING (app_is_manager() AND app_department() IS NOT NULL AND
+           (app_org_id() IS NULL OR org_id = app_org_id()) AND department_index = app_department());
+
+DROP POLICY IF EXISTS products_department_read ON products;
+
+CREATE POLICY products_department_read ON products
+    FOR SELECT
+    USING (app_is_manager() AND app_department() IS NOT NULL AND
+           (app_org_id() IS NULL OR org_id = app_org_id()) AND
+           EXISTS (SELECT 1
+                   FROM users AS u
+                   WHERE u.user_id = products.user_id
+                     AND u.department_index = app_department()));
+
+-- Description: The name of the user is encrypted, the view no longer has it.
+DROP VIEW IF EXISTS view_user_products;
+
+CREATE VIEW view_user_products AS
+SELECT p.product_id,
+       p.user_id,
+       p.name,
+       p.cost,
+       p.quantity,
+       p.date_created,
+       p.date_updated,
+       p.org_id
+FROM products AS p
+         JOIN
+     users AS u ON u.user_id = p.user_id;
+
+ALTER VIEW view_user_products SET (security_invoker = true);
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- Description: The currency is dropped, the costs are kept as they are.
+DROP VIEW IF EXISTS view_user_products;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN cost TYPE NUMERIC(10, 2);
+
+CREATE VIEW view_user_products AS
+SELECT p.product_id,
+       p.user_id,
+       p.name,
+       p.cost,
+       p.quantity,
+       p.date_created,
+       p.date_updated,
+       p.org_id
+FROM products AS p
+         JOIN
+     users AS u ON u.user_id = p.user_id;
+
+ALTER VIEW view_user_products SET (security_invoker = true);
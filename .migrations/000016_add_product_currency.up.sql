@@ -0,0 +1,24 @@
+-- Description: The cost of a product is an exact amount of its currency, an
+-- ISO 4217 code. The cost is widened so that the currencies with three
+-- decimals fit, the view depends on it and is recreated.
+DROP VIEW IF EXISTS view_user_products;
+
+ALTER TABLE products
+    ALTER COLUMN cost TYPE NUMERIC(19, 4),
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+
+CREATE VIEW view_user_products AS
+SELECT p.product_id,
+       p.user_id,
+       p.name,
+       p.cost,
+       p.currency,
+       p.quantity,
+       p.date_created,
+       p.date_updated,
+       p.org_id
+FROM products AS p
+         JOIN
+     users AS u ON u.user_id = p.user_id;
+
+ALTER VIEW view_user_products SET (security_invoker = true);
@@ -0,0 +1,10 @@
+DROP POLICY IF EXISTS stock_movements_department_read ON stock_movements;
+DROP POLICY IF EXISTS stock_movements_isolation ON stock_movements;
+DROP POLICY IF EXISTS stock_reservations_department_read ON stock_reservations;
+DROP POLICY IF EXISTS stock_reservations_isolation ON stock_reservations;
+
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS products_quantity_check;
+
+DROP TABLE IF EXISTS "stock_movements";
+DROP TABLE IF EXISTS "stock_reservations";
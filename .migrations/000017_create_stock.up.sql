@@ -0,0 +1,98 @@
+-- Description: Create table stock_reservations
+-- The stock set aside for a buyer until it expires. The stock is taken from
+-- the product when reserved, a release gives it back and a commit keeps it
+-- as sold.
+CREATE TABLE stock_reservations
+(
+    reservation_id UUID      NOT NULL,
+    org_id         UUID      NOT NULL REFERENCES organizations (org_id),
+    product_id     UUID      NOT NULL,
+    quantity       INT       NOT NULL CHECK (quantity > 0),
+    status         TEXT      NOT NULL,
+    expires_at     TIMESTAMP NOT NULL,
+    date_created   TIMESTAMP NOT NULL,
+    date_updated   TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (reservation_id),
+    FOREIGN KEY (product_id) REFERENCES products (product_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS stock_reservations_product_id_idx ON "stock_reservations" ("product_id");
+
+CREATE INDEX IF NOT EXISTS stock_reservations_pending_idx ON "stock_reservations" ("expires_at") WHERE status = 'PENDING';
+
+-- Description: Create table stock_movements
+-- The ledger of the changes of the stock of the products. The quantity of a
+-- product is the sum of the deltas of its movements, the quantity of a
+-- movement is the one of the product right after it.
+CREATE TABLE stock_movements
+(
+    movement_id    UUID      NOT NULL,
+    org_id         UUID      NOT NULL REFERENCES organizations (org_id),
+    product_id     UUID      NOT NULL,
+    reservation_id UUID      NULL,
+    kind           TEXT      NOT NULL,
+    delta          INT       NOT NULL,
+    quantity       INT       NOT NULL,
+    date_created   TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (movement_id),
+    FOREIGN KEY (product_id) REFERENCES products (product_id) ON DELETE CASCADE,
+    FOREIGN KEY (reservation_id) REFERENCES stock_reservations (reservation_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_product_id_idx ON "stock_movements" ("product_id", "date_created");
+
+CREATE INDEX IF NOT EXISTS stock_movements_reservation_id_idx ON "stock_movements" ("reservation_id");
+
+-- Description: The stock already there is recorded as received when the
+-- product was created.
+INSERT INTO stock_movements (movement_id, org_id, product_id, kind, delta, quantity, date_created)
+SELECT gen_random_uuid(), org_id, product_id, 'RECEIPT', quantity, quantity, date_created
+FROM products
+WHERE quantity > 0;
+
+-- Description: The stock never drops below zero, whatever the order the
+-- concurrent changes are made in.
+ALTER TABLE products
+    ADD CONSTRAINT products_quantity_check CHECK (quantity >= 0);
+
+-- Description: The stock of a product is reached by the callers who reach
+-- the product, and changed by its owner or an admin.
+ALTER TABLE stock_reservations
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE stock_reservations
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY stock_reservations_isolation ON stock_reservations
+    USING (app_user_id() IS NULL OR
+           EXISTS (SELECT 1
+                   FROM products AS p
+                   WHERE p.product_id = stock_reservations.product_id
+                     AND (app_is_admin() OR p.user_id = app_user_id())));
+
+CREATE POLICY stock_reservations_department_read ON stock_reservations
+    FOR SELECT
+    USING (app_is_manager() AND
+           EXISTS (SELECT 1
+                   FROM products AS p
+                   WHERE p.product_id = stock_reservations.product_id));
+
+ALTER TABLE stock_movements
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE stock_movements
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY stock_movements_isolation ON stock_movements
+    USING (app_user_id() IS NULL OR
+           EXISTS (SELECT 1
+                   FROM products AS p
+                   WHERE p.product_id = stock_movements.product_id
+                     AND (app_is_admin() OR p.user_id = app_user_id())));
+
+CREATE POLICY stock_movements_department_read ON stock_movements
+    FOR SELECT
+    USING (app_is_manager() AND
+           EXISTS (SELECT 1
+                   FROM products AS p
+                   WHERE p.product_id = stock_movements.product_id));
@@ -0,0 +1,5 @@
+DROP POLICY IF EXISTS order_items_isolation ON order_items;
+DROP POLICY IF EXISTS orders_isolation ON orders;
+
+DROP TABLE IF EXISTS "order_items";
+DROP TABLE IF EXISTS "orders";
@@ -0,0 +1,65 @@
+-- Description: Create table orders
+-- The orders of the products placed by the users. The total is the sum of
+-- the totals of the items, in the currency of the products.
+CREATE TABLE orders
+(
+    order_id     UUID           NOT NULL,
+    org_id       UUID           NOT NULL REFERENCES organizations (org_id),
+    user_id      UUID           NOT NULL,
+    status       TEXT           NOT NULL,
+    total        NUMERIC(19, 4) NOT NULL CHECK (total >= 0),
+    currency     TEXT           NOT NULL,
+    date_created TIMESTAMP      NOT NULL,
+    date_updated TIMESTAMP      NOT NULL,
+
+    PRIMARY KEY (order_id),
+    FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS orders_user_id_idx ON "orders" ("user_id", "date_created");
+
+CREATE INDEX IF NOT EXISTS orders_status_idx ON "orders" ("status");
+
+-- Description: Create table order_items
+-- The products of an order, with the name and the price they had when the
+-- order was placed. The product is cleared once it is removed, the item is
+-- kept for the history of the order.
+CREATE TABLE order_items
+(
+    item_id    UUID           NOT NULL,
+    order_id   UUID           NOT NULL,
+    product_id UUID NULL,
+    name       TEXT           NOT NULL,
+    price      NUMERIC(19, 4) NOT NULL,
+    quantity   INT            NOT NULL CHECK (quantity > 0),
+    total      NUMERIC(19, 4) NOT NULL,
+
+    PRIMARY KEY (item_id),
+    UNIQUE (order_id, product_id),
+    FOREIGN KEY (order_id) REFERENCES orders (order_id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products (product_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_items_product_id_idx ON "order_items" ("product_id");
+
+-- Description: An order is reached by the user who placed it or an admin of
+-- its organization, and its items along with it.
+ALTER TABLE orders
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE orders
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY orders_isolation ON orders
+    USING (app_user_id() IS NULL OR
+           ((app_org_id() IS NULL OR org_id = app_org_id()) AND (app_is_admin() OR user_id = app_user_id())));
+
+ALTER TABLE order_items
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE order_items
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY order_items_isolation ON order_items
+    USING (app_user_id() IS NULL OR
+           EXISTS (SELECT 1
+                   FROM orders AS o
+                   WHERE o.order_id = order_items.order_id));
@@ -0,0 +1,9 @@
+DROP POLICY IF EXISTS entries_insert ON entries;
+DROP POLICY IF EXISTS entries_isolation ON entries;
+DROP POLICY IF EXISTS transfers_insert ON transfers;
+DROP POLICY IF EXISTS transfers_isolation ON transfers;
+DROP POLICY IF EXISTS accounts_isolation ON accounts;
+
+DROP TABLE IF EXISTS "entries";
+DROP TABLE IF EXISTS "transfers";
+DROP TABLE IF EXISTS "accounts";
@@ -0,0 +1,122 @@
+-- Description: Create table accounts
+-- The money of the users, at most one account per user and currency. The
+-- clearing account of a currency has no owner, the deposits are taken from
+-- it and the withdrawals given to it, so it is the only one whose balance
+-- may drop below zero.
+CREATE TABLE accounts
+(
+    account_id   UUID           NOT NULL,
+    org_id       UUID           NOT NULL REFERENCES organizations (org_id),
+    owner        UUID NULL,
+    currency     TEXT           NOT NULL,
+    balance      NUMERIC(19, 4) NOT NULL DEFAULT 0,
+    date_created TIMESTAMP      NOT NULL,
+    date_updated TIMESTAMP      NOT NULL,
+
+    PRIMARY KEY (account_id),
+    FOREIGN KEY (owner) REFERENCES users (user_id) ON DELETE RESTRICT,
+    CONSTRAINT accounts_balance_check CHECK (balance >= 0 OR owner IS NULL)
+);
+
+CREATE INDEX IF NOT EXISTS accounts_owner_idx ON "accounts" ("owner");
+
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_owner_currency_idx ON "accounts" ("owner", "currency");
+
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_clearing_idx ON "accounts" ("org_id", "currency") WHERE owner IS NULL;
+
+-- Description: Create table transfers
+-- The money moved from an account to another of the same currency.
+CREATE TABLE transfers
+(
+    transfer_id     UUID           NOT NULL,
+    org_id          UUID           NOT NULL REFERENCES organizations (org_id),
+    from_account_id UUID           NOT NULL,
+    to_account_id   UUID           NOT NULL,
+    amount          NUMERIC(19, 4) NOT NULL CHECK (amount > 0),
+    currency        TEXT           NOT NULL,
+    date_created    TIMESTAMP      NOT NULL,
+
+    PRIMARY KEY (transfer_id),
+    FOREIGN KEY (from_account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (to_account_id) REFERENCES accounts (account_id),
+    CHECK (from_account_id <> to_account_id)
+);
+
+CREATE INDEX IF NOT EXISTS transfers_from_account_id_idx ON "transfers" ("from_account_id");
+
+CREATE INDEX IF NOT EXISTS transfers_to_account_id_idx ON "transfers" ("to_account_id");
+
+CREATE INDEX IF NOT EXISTS transfers_from_to_account_idx ON "transfers" ("from_account_id", "to_account_id");
+
+-- Description: Create table entries
+-- The two sides of every transfer: the debit of the account the money is
+-- taken from, a negative amount, and the credit of the other. The entries of
+-- a transfer sum to zero, and the balance is the one of the account right
+-- after the entry. The sequence orders the entries of an account.
+CREATE TABLE entries
+(
+    entry_id     UUID           NOT NULL,
+    seq          BIGINT GENERATED ALWAYS AS IDENTITY,
+    org_id       UUID           NOT NULL REFERENCES organizations (org_id),
+    account_id   UUID           NOT NULL,
+    transfer_id  UUID           NOT NULL,
+    amount       NUMERIC(19, 4) NOT NULL CHECK (amount <> 0),
+    balance      NUMERIC(19, 4) NOT NULL,
+    date_created TIMESTAMP      NOT NULL,
+
+    PRIMARY KEY (entry_id),
+    FOREIGN KEY (account_id) REFERENCES accounts (account_id),
+    FOREIGN KEY (transfer_id) REFERENCES transfers (transfer_id)
+);
+
+CREATE INDEX IF NOT EXISTS entries_account_id_idx ON "entries" ("account_id");
+
+CREATE INDEX IF NOT EXISTS entries_account_id_seq_idx ON "entries" ("account_id", "seq");
+
+-- Description: The accounts of an organization are reached by its users, so
+-- a transfer can credit the account of another user; the api only shows an
+-- account to its owner or an admin. The transfers and the entries are only
+-- reached by the owners of their accounts or an admin.
+ALTER TABLE accounts
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE accounts
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY accounts_isolation ON accounts
+    USING (app_user_id() IS NULL OR app_org_id() IS NULL OR org_id = app_org_id());
+
+ALTER TABLE transfers
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE transfers
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY transfers_isolation ON transfers
+    USING (app_user_id() IS NULL OR
+           ((app_org_id() IS NULL OR org_id = app_org_id()) AND
+            (app_is_admin() OR
+             EXISTS (SELECT 1
+                     FROM accounts AS a
+                     WHERE a.account_id IN (transfers.from_account_id, transfers.to_account_id)
+                       AND a.owner = app_user_id()))));
+
+CREATE POLICY transfers_insert ON transfers
+    FOR INSERT
+    WITH CHECK (app_user_id() IS NULL OR app_org_id() IS NULL OR org_id = app_org_id());
+
+ALTER TABLE entries
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE entries
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY entries_isolation ON entries
+    USING (app_user_id() IS NULL OR
+           ((app_org_id() IS NULL OR org_id = app_org_id()) AND
+            (app_is_admin() OR
+             EXISTS (SELECT 1
+                     FROM accounts AS a
+                     WHERE a.account_id = entries.account_id
+                       AND a.owner = app_user_id()))));
+
+CREATE POLICY entries_insert ON entries
+    FOR INSERT
+    WITH CHECK (app_user_id() IS NULL OR app_org_id() IS NULL OR org_id = app_org_id());
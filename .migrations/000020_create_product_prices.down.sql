@@ -0,0 +1,6 @@
+DROP POLICY IF EXISTS stock_movements_insert ON stock_movements;
+DROP POLICY IF EXISTS product_prices_insert ON product_prices;
+DROP POLICY IF EXISTS product_prices_department_read ON product_prices;
+DROP POLICY IF EXISTS product_prices_isolation ON product_prices;
+
+DROP TABLE IF EXISTS "product_prices";
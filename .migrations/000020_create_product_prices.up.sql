@@ -0,0 +1,60 @@
+-- Description: Create table product_prices
+-- The history of the costs of the products. A price is in effect from its
+-- effective date until the next price of the product, and a price scheduled
+-- for later on is applied to the cost of the product once due.
+CREATE TABLE product_prices
+(
+    price_id       UUID           NOT NULL,
+    org_id         UUID           NOT NULL REFERENCES organizations (org_id),
+    product_id     UUID           NOT NULL,
+    cost           NUMERIC(19, 4) NOT NULL CHECK (cost >= 0),
+    currency       TEXT           NOT NULL,
+    effective_from TIMESTAMP      NOT NULL,
+    date_applied   TIMESTAMP NULL,
+    date_created   TIMESTAMP      NOT NULL,
+
+    PRIMARY KEY (price_id),
+    FOREIGN KEY (product_id) REFERENCES products (product_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS product_prices_product_id_idx ON "product_prices" ("product_id", "effective_from");
+
+CREATE INDEX IF NOT EXISTS product_prices_due_idx ON "product_prices" ("effective_from") WHERE date_applied IS NULL;
+
+-- Description: The costs the products had before were not kept, their
+-- current cost is recorded as in effect since they were created.
+INSERT INTO product_prices (price_id, org_id, product_id, cost, currency, effective_from, date_applied, date_created)
+SELECT gen_random_uuid(), org_id, product_id, cost, currency, date_created, date_created, date_created
+FROM products;
+
+-- Description: The prices of a product are reached by the callers who reach
+-- the product, and changed by its owner or an admin.
+ALTER TABLE product_prices
+    ENABLE ROW LEVEL SECURITY;
+ALTER TABLE product_prices
+    FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY product_prices_isolation ON product_prices
+    USING (app_user_id() IS NULL OR
+           EXISTS (SELECT 1
+                   FROM products AS p
+                   WHERE p.product_id = product_prices.product_id
+                     AND (app_is_admin() OR p.user_id = app_user_id())));
+
+CREATE POLICY product_prices_department_read ON product_prices
+    FOR SELECT
+    USING (app_is_manager() AND
+           EXISTS (SELECT 1
+                   FROM products AS p
+                   WHERE p.product_id = product_prices.product_id));
+
+-- Description: The prices and the stock movements of a product are written
+-- along with it, by the statements the product is not visible to yet, so
+-- they are inserted under the organization only.
+CREATE POLICY product_prices_insert ON product_prices
+    FOR INSERT
+    WITH CHECK (app_user_id() IS NULL OR app_org_id() IS NULL OR org_id = app_org_id());
+
+CREATE POLICY stock_movements_insert ON stock_movements
+    FOR INSERT
+    WITH CHECK (app_user_id() IS NULL OR app_org_id() IS NULL OR org_id = app_org_id());
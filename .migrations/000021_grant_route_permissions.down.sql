@@ -0,0 +1,8 @@
+DELETE
+FROM role_permissions
+WHERE (role_name, permission) IN (('USER', 'user:read'),
+                                  ('USER', 'user:write'),
+                                  ('MANAGER', 'user:read'),
+                                  ('MANAGER', 'user:write'),
+                                  ('ADMIN', 'product:write'),
+                                  ('SUPER_ADMIN', 'product:write'));
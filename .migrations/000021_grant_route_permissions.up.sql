@@ -0,0 +1,12 @@
+-- Description: The user and product routes check the user and product
+-- permissions on top of their rules. The users read and change themselves
+-- and the admins change the products of their organization, the rules still
+-- limit who is acted on.
+INSERT INTO role_permissions (role_name, permission)
+VALUES ('USER', 'user:read'),
+       ('USER', 'user:write'),
+       ('MANAGER', 'user:read'),
+       ('MANAGER', 'user:write'),
+       ('ADMIN', 'product:write'),
+       ('SUPER_ADMIN', 'product:write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+-- Description: Create table revoked_tokens
+CREATE TABLE revoked_tokens
+(
+    token_id     TEXT      NOT NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (token_id)
+);
+
+CREATE INDEX IF NOT EXISTS revoked_tokens_expires_at_idx ON "revoked_tokens" ("expires_at");
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS users_legacy_email_idx;
@@ -0,0 +1,5 @@
+-- Description: The users not encrypted yet have no blind index of their
+-- email, the unique index of the email doesn't see them. Until the reencrypt
+-- command encrypts them, the service checks a new email against their
+-- plaintext email, looked up by this index.
+CREATE INDEX IF NOT EXISTS users_legacy_email_idx ON "users" (lower("email")) WHERE data_key IS NULL;
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/Housiadas/backend-system/internal/app/commands"
+	"github.com/Housiadas/backend-system/pkg/keystore"
 	"github.com/Housiadas/backend-system/pkg/logger"
 )
 
@@ -79,15 +81,32 @@ func processCommands(args []string, cmd *commands.Command) error {
 		}
 
 	case "genkey":
-		if err := cmd.GenKey(); err != nil {
+		fs := flag.NewFlagSet("genkey", flag.ContinueOnError)
+		alg := fs.String("alg", keystore.AlgRS256, "signing algorithm: RS256, ES256 or EdDSA, or AES256 for an encryption key")
+		kid := fs.String("kid", "", "key id, the creation time by default")
+		if err := fs.Parse(args[2:]); err != nil {
+			return commands.ErrHelp
+		}
+		if err := cmd.GenKey(*alg, *kid); err != nil {
 			return fmt.Errorf("key generation: %w", err)
 		}
 
+	case "reencrypt":
+		fs := flag.NewFlagSet("reencrypt", flag.ContinueOnError)
+		batch := fs.Int("batch", 500, "number of users encrypted at a time")
+		if err := fs.Parse(args[2:]); err != nil {
+			return commands.ErrHelp
+		}
+		if err := cmd.ReEncrypt(*batch); err != nil {
+			return fmt.Errorf("reencrypting users: %w", err)
+		}
+
 	default:
 		fmt.Println("seed:       add data to the database")
 		fmt.Println("useradd:    add a new user to the database")
-		fmt.Println("genkey:     generate a set of private/public key files")
+		fmt.Println("genkey:     generate a set of private/public key files [-alg RS256|ES256|EdDSA|AES256] [-kid id]")
 		fmt.Println("gentoken:	 generate a JWT for a user with claims")
+		fmt.Println("reencrypt:  encrypt the users again under the active master key [-batch size]")
 		fmt.Println("userevents: kafka consumer to listen to user events")
 		fmt.Println("provide a command to get more help.")
 		return commands.ErrHelp
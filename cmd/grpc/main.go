@@ -10,12 +10,28 @@ import (
 	"runtime"
 	"syscall"
 
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/app/cache/user_cache"
 	"github.com/Housiadas/backend-system/internal/app/grpc"
+	"github.com/Housiadas/backend-system/internal/app/repository/apikey_repo"
 	"github.com/Housiadas/backend-system/internal/app/repository/product_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/rbac_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/securityevent_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/session_repo"
 	"github.com/Housiadas/backend-system/internal/app/repository/user_repo"
 	"github.com/Housiadas/backend-system/internal/config"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/apikeycore"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/rbaccore"
+	"github.com/Housiadas/backend-system/internal/core/service/securityeventcore"
+	"github.com/Housiadas/backend-system/internal/core/service/sessioncore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/kafka"
+	"github.com/Housiadas/backend-system/pkg/keystore"
 	"github.com/Housiadas/backend-system/pkg/logger"
 	"github.com/Housiadas/backend-system/pkg/otel"
 	"github.com/Housiadas/backend-system/pkg/pgsql"
@@ -126,20 +142,105 @@ func run(ctx context.Context, cfg config.Config, log *logger.Logger) error {
 	// -------------------------------------------------------------------------
 	log.Info(ctx, "startup", "status", "initializing internal layer")
 
-	userBus := usercore.NewCore(log, user_repo.NewStore(log, db))
+	// The personal data of the users is encrypted with keys found in the
+	// keys folder.
+	userKeys, err := user_repo.LoadKeys(os.DirFS(cfg.Auth.KeysFolder), cfg.Encryption.MasterKey, cfg.Encryption.IndexKey)
+	if err != nil {
+		return fmt.Errorf("loading user keys: %w", err)
+	}
+
+	// The users are checked on every authenticated call, they are cached so a
+	// user disabled by another instance is refused at the latest after the
+	// cache TTL.
+	var userStore user.Storer = user_repo.NewStore(log, db, userKeys)
+	if cfg.Auth.UserCacheTTL > 0 {
+		userStore = user_cache.NewStore(log, userStore, cfg.Auth.UserCacheTTL)
+	}
+
+	userBus := usercore.NewCore(log, userStore)
 	productBus := productcore.NewCore(log, userBus, product_repo.NewStore(log, db))
+	sessionBus := sessioncore.NewCore(log, session_repo.NewStore(log, db))
+	rbacBus := rbaccore.NewCore(log, rbac_repo.NewStore(log, db))
+	apiKeyBus := apikeycore.NewCore(log, apikey_repo.NewStore(log, db))
+
+	// The security events are published to kafka when a topic is configured.
+	var securityPublisher securityevent.Publisher
+	if cfg.Kafka.SecurityEventsTopic != "" {
+		producer, err := kafka.NewProducer(kafka.ProducerConfig{
+			Brokers:          cfg.Kafka.Brokers,
+			LogLevel:         cfg.Kafka.LogLevel,
+			AddressFamily:    cfg.Kafka.AddressFamily,
+			MaxMessageBytes:  cfg.Kafka.MaxMessageBytes,
+			SecurityProtocol: cfg.Kafka.SecurityProtocol,
+		})
+		if err != nil {
+			return fmt.Errorf("creating kafka producer: %w", err)
+		}
+		defer producer.Close()
+
+		securityPublisher = securityevent_repo.NewPublisher(producer, cfg.Kafka.SecurityEventsTopic)
+	}
+	securityBus := securityeventcore.NewCore(log, securityevent_repo.NewStore(log, db), securityPublisher)
+
+	if err := rbacBus.Load(ctx); err != nil {
+		return fmt.Errorf("loading roles: %w", err)
+	}
+
+	// Callers authenticate with the same tokens and api keys as over http.
+	ks := keystore.NewWithConfig(keystore.Config{ActiveKID: cfg.Auth.ActiveKID, LegacyKID: cfg.Auth.LegacyKID})
+	if err := ks.LoadKeys(os.DirFS(cfg.Auth.KeysFolder)); err != nil {
+		return fmt.Errorf("reading keys: %w", err)
+	}
+
+	// The access tokens of the trusted issuers are verified with the keys
+	// they publish.
+	trustedIssuers := make(map[string]authcore.TrustedIssuer, len(cfg.Auth.TrustedIssuers))
+	for _, ti := range cfg.Auth.TrustedIssuers {
+		orgID, err := uuid.Parse(ti.Organization)
+		if err != nil {
+			return fmt.Errorf("parsing organization of trusted issuer[%s]: %w", ti.Issuer, err)
+		}
+
+		trustedIssuers[ti.Issuer] = authcore.TrustedIssuer{
+			KeyLookup:   keystore.NewRemote(keystore.RemoteConfig{URL: ti.JWKSURL}),
+			Audience:    ti.Audience,
+			RoleMapping: ti.RoleMapping,
+			OrgID:       orgID,
+		}
+	}
+
+	auth, err := authcore.New(authcore.Config{
+		Log:        log,
+		DB:         db,
+		KeyLookup:  ks,
+		Issuer:     cfg.Auth.Issuer,
+		Userbus:    userBus,
+		Sessionbus: sessionBus,
+		Rbacbus:    rbacBus,
+		APIKeybus:  apiKeyBus,
+		PolicyPath: cfg.Auth.PolicyPath,
+
+		TrustedIssuers: trustedIssuers,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing auth: %w", err)
+	}
 
 	// -------------------------------------------------------------------------
 	// Start Grpc Server
 	// -------------------------------------------------------------------------
 	s := grpc.New(grpc.Config{
-		ServiceName: cfg.App.Name,
-		Build:       build,
-		DB:          db,
-		Log:         log,
-		Tracer:      tracer,
-		UserBus:     userBus,
-		ProductBus:  productBus,
+		ServiceName:      cfg.App.Name,
+		Build:            build,
+		DB:               db,
+		Log:              log,
+		Tracer:           tracer,
+		Auth:             auth,
+		UserBus:          userBus,
+		ProductBus:       productBus,
+		SecurityEventBus: securityBus,
+		RowLevelSecurity: cfg.DB.RowLevelSecurity,
+		DeptIndex:        userKeys.DepartmentIndex,
 	})
 
 	// Register gRPC usecase
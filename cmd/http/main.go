@@ -10,22 +10,58 @@ import (
 	"runtime"
 	"syscall"
 
+	"github.com/google/uuid"
+
 	_ "github.com/Housiadas/backend-system/docs"
+	"github.com/Housiadas/backend-system/internal/app/cache/user_cache"
 	"github.com/Housiadas/backend-system/internal/app/handlers"
+	"github.com/Housiadas/backend-system/internal/app/repository/account_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/apikey_repo"
 	"github.com/Housiadas/backend-system/internal/app/repository/audit_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/invite_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/lockout_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/mfa_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/order_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/organization_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/passwordreset_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/price_repo"
 	"github.com/Housiadas/backend-system/internal/app/repository/product_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/rbac_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/securityevent_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/session_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/stock_repo"
 	"github.com/Housiadas/backend-system/internal/app/repository/user_repo"
 	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
 	"github.com/Housiadas/backend-system/internal/config"
+	"github.com/Housiadas/backend-system/internal/core/domain/password"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/accountcore"
+	"github.com/Housiadas/backend-system/internal/core/service/apikeycore"
 	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/internal/core/service/invitecore"
+	"github.com/Housiadas/backend-system/internal/core/service/lockoutcore"
+	"github.com/Housiadas/backend-system/internal/core/service/mfacore"
+	"github.com/Housiadas/backend-system/internal/core/service/ordercore"
+	"github.com/Housiadas/backend-system/internal/core/service/organizationcore"
+	"github.com/Housiadas/backend-system/internal/core/service/passwordresetcore"
+	"github.com/Housiadas/backend-system/internal/core/service/pricecore"
 	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/rbaccore"
+	"github.com/Housiadas/backend-system/internal/core/service/securityeventcore"
+	"github.com/Housiadas/backend-system/internal/core/service/sessioncore"
+	"github.com/Housiadas/backend-system/internal/core/service/stockcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
 	"github.com/Housiadas/backend-system/pkg/debug"
+	"github.com/Housiadas/backend-system/pkg/encrypt"
 	"github.com/Housiadas/backend-system/pkg/kafka"
 	"github.com/Housiadas/backend-system/pkg/keystore"
 	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/notifier"
+	"github.com/Housiadas/backend-system/pkg/oidc"
 	"github.com/Housiadas/backend-system/pkg/otel"
+	"github.com/Housiadas/backend-system/pkg/passhash"
 	"github.com/Housiadas/backend-system/pkg/pgsql"
 )
 
@@ -168,21 +204,186 @@ func run(ctx context.Context, cfg config.Config, log *logger.Logger) error {
 	log.Info(ctx, "startup", "status", "initializing internal layer")
 
 	auditCore := auditcore.NewCore(log, audit_repo.NewStore(log, db))
-	userCore := usercore.NewCore(log, user_repo.NewStore(log, db))
+	hasher, err := passwordHasher(cfg.Auth.Password)
+	if err != nil {
+		return fmt.Errorf("constructing password hasher: %w", err)
+	}
+
+	policy, err := passwordPolicy(cfg.Auth.Password)
+	if err != nil {
+		return fmt.Errorf("loading password policy: %w", err)
+	}
+
+	// The personal data of the users is encrypted with keys found in the
+	// keys folder, see the reencrypt command to rotate the master key.
+	userKeys, err := user_repo.LoadKeys(os.DirFS(cfg.Auth.KeysFolder), cfg.Encryption.MasterKey, cfg.Encryption.IndexKey)
+	if err != nil {
+		return fmt.Errorf("loading user keys: %w", err)
+	}
+
+	// The users are checked on every authenticated call, they are cached so a
+	// user disabled by another instance is refused at the latest after the
+	// cache TTL.
+	var userStore user.Storer = user_repo.NewStore(log, db, userKeys)
+	if cfg.Auth.UserCacheTTL > 0 {
+		userStore = user_cache.NewStore(log, userStore, cfg.Auth.UserCacheTTL)
+	}
+
+	userCore := usercore.NewCoreWithHasher(log, userStore, hasher)
 	productCore := productcore.NewCore(log, userCore, product_repo.NewStore(log, db))
+	sessionCore := sessioncore.NewCore(log, session_repo.NewStore(log, db))
+	rbacCore := rbaccore.NewCore(log, rbac_repo.NewStore(log, db))
+	apiKeyCore := apikeycore.NewCore(log, apikey_repo.NewStore(log, db))
+	lockoutCore := lockoutcore.NewCore(log, auditCore, lockout_repo.NewStore(log, db), lockoutcore.Policy{
+		MaxFailures:   cfg.Auth.Lockout.MaxFailures,
+		MaxIPFailures: cfg.Auth.Lockout.MaxIPFailures,
+		Backoff:       cfg.Auth.Lockout.Backoff,
+		Duration:      cfg.Auth.Lockout.Duration,
+	})
+
+	// The keyring encrypts the sensitive data stored, like the mfa secrets.
+	keyring, err := encrypt.NewFromBase64(cfg.Encryption.ActiveKey, cfg.Encryption.Keys)
+	if err != nil {
+		return fmt.Errorf("loading encryption keys: %w", err)
+	}
+
+	mfaCore := mfacore.NewCore(log, mfa_repo.NewStore(log, db), keyring, cfg.Auth.MFA.Issuer)
+	resetCore := passwordresetcore.NewCore(log, passwordreset_repo.NewStore(log, db), cfg.Auth.PasswordReset.TTL)
+	inviteCore := invitecore.NewCore(log, invite_repo.NewStore(log, db), cfg.Auth.Invite.TTL)
+	orgCore := organizationcore.NewCore(log, organization_repo.NewStore(log, db))
+	stockCore := stockcore.NewCore(log, stock_repo.NewStore(log, db), cfg.Stock.ReservationTTL)
+	priceCore := pricecore.NewCore(log, price_repo.NewStore(log, db))
+	orderCore := ordercore.NewCore(log, userCore, productCore, stockCore, priceCore, auditCore, order_repo.NewStore(log, db))
+	accountCore := accountcore.NewCore(log, userCore, account_repo.NewStore(log, db))
+
+	// The security events are stored and, when a topic is configured,
+	// published to kafka for a SIEM.
+	var securityPublisher securityevent.Publisher
+	if cfg.Kafka.SecurityEventsTopic != "" {
+		securityPublisher = securityevent_repo.NewPublisher(producer, cfg.Kafka.SecurityEventsTopic)
+	}
+	securityCore := securityeventcore.NewCore(log, securityevent_repo.NewStore(log, db), securityPublisher)
+
+	// Messages to the users are written to the logger unless a file is
+	// configured, a real delivery service plugs in here.
+	var notify notifier.Notifier = notifier.NewLog(log)
+	if cfg.Notifier.Kind == "file" {
+		notify = notifier.NewFile(cfg.Notifier.Path)
+	}
+
+	// Register the roles created at runtime so they can be assigned to users.
+	if err := rbacCore.Load(ctx); err != nil {
+		return fmt.Errorf("loading roles: %w", err)
+	}
 
 	// Load the private keys files from disk. We can assume some system api like
 	// Vault has created these files already. How that happens is not our concern.
-	ks := keystore.New()
-	if err := ks.LoadRSAKeys(os.DirFS(cfg.Auth.KeysFolder)); err != nil {
+	ks := keystore.NewWithConfig(keystore.Config{ActiveKID: cfg.Auth.ActiveKID, LegacyKID: cfg.Auth.LegacyKID})
+	if err := ks.LoadKeys(os.DirFS(cfg.Auth.KeysFolder)); err != nil {
 		return fmt.Errorf("reading keys: %w", err)
 	}
-	authCore := authcore.New(authcore.Config{
-		Log:       log,
-		DB:        db,
-		KeyLookup: ks,
-		Userbus:   userCore,
+
+	// Reload the keys on SIGHUP so new keys can be rotated in without a
+	// restart, the active and legacy kids are read again from the config.
+	// Tokens signed with retired keys stay valid as long as the files remain
+	// in the keys folder.
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+
+	go func() {
+		for range reload {
+			newCfg, err := config.LoadConfig("../../")
+			if err != nil {
+				log.Error(ctx, "keystore", "status", "reading config", "msg", err)
+				continue
+			}
+
+			ksCfg := keystore.Config{ActiveKID: newCfg.Auth.ActiveKID, LegacyKID: newCfg.Auth.LegacyKID}
+			if err := ks.ReloadWithConfig(ksCfg); err != nil {
+				log.Error(ctx, "keystore", "status", "reloading keys", "msg", err)
+				continue
+			}
+
+			kid, _ := ks.ActiveKID()
+			log.Info(ctx, "keystore", "status", "keys reloaded", "activeKID", kid, "kids", ks.KIDs())
+		}
+	}()
+
+	// Policy decisions are written to the logger unless the audit table is
+	// configured as the decision log.
+	var decisionSink authcore.DecisionSink = authcore.NewLogSink(log)
+	if cfg.Auth.DecisionLog == "audit" {
+		decisionSink = authcore.NewAuditSink(log, auditCore)
+	}
+
+	// The access tokens of the trusted issuers are verified with the keys
+	// they publish.
+	trustedIssuers := make(map[string]authcore.TrustedIssuer, len(cfg.Auth.TrustedIssuers))
+	for _, ti := range cfg.Auth.TrustedIssuers {
+		orgID, err := uuid.Parse(ti.Organization)
+		if err != nil {
+			return fmt.Errorf("parsing organization of trusted issuer[%s]: %w", ti.Issuer, err)
+		}
+
+		trustedIssuers[ti.Issuer] = authcore.TrustedIssuer{
+			KeyLookup:   keystore.NewRemote(keystore.RemoteConfig{URL: ti.JWKSURL}),
+			Audience:    ti.Audience,
+			RoleMapping: ti.RoleMapping,
+			OrgID:       orgID,
+		}
+	}
+
+	authCore, err := authcore.New(authcore.Config{
+		Log:          log,
+		DB:           db,
+		KeyLookup:    ks,
+		Issuer:       cfg.Auth.Issuer,
+		Userbus:      userCore,
+		Sessionbus:   sessionCore,
+		Rbacbus:      rbacCore,
+		APIKeybus:    apiKeyCore,
+		PolicyPath:   cfg.Auth.PolicyPath,
+		DecisionSink: decisionSink,
+
+		RequireVerifiedEmail: cfg.Auth.Verification.Required,
+		TrustedIssuers:       trustedIssuers,
 	})
+	if err != nil {
+		return fmt.Errorf("constructing auth: %w", err)
+	}
+
+	// Policies loaded from the policy path are recompiled and swapped in
+	// when the files change, requests keep using the previous revision
+	// until the new one compiles.
+	log.Info(ctx, "startup", "status", "policies loaded", "revision", authCore.Policies().Revision)
+
+	policyCtx, stopPolicies := context.WithCancel(ctx)
+	defer stopPolicies()
+
+	go func() {
+		if err := authCore.WatchPolicies(policyCtx); err != nil {
+			log.Error(ctx, "authcore", "status", "watching policies", "msg", err)
+		}
+	}()
+
+	// The stock of the expired reservations is given back to the products in
+	// the background.
+	stockCtx, stopStock := context.WithCancel(ctx)
+	defer stopStock()
+
+	if cfg.Stock.ReleaseInterval > 0 {
+		go stockCore.WatchExpired(stockCtx, cfg.Stock.ReleaseInterval)
+	}
+
+	// The scheduled prices that took effect are applied to the products in
+	// the background.
+	priceCtx, stopPrice := context.WithCancel(ctx)
+	defer stopPrice()
+
+	if cfg.Price.ApplyInterval > 0 {
+		go priceCore.WatchDue(priceCtx, cfg.Price.ApplyInterval)
+	}
 
 	// -------------------------------------------------------------------------
 	// Start Debug Http Core
@@ -195,6 +396,33 @@ func run(ctx context.Context, cfg config.Config, log *logger.Logger) error {
 		}
 	}()
 
+	// -------------------------------------------------------------------------
+	// Single Sign-On
+	// -------------------------------------------------------------------------
+	var provider *oidc.Provider
+	var ssoOrgID uuid.UUID
+	if cfg.Auth.OIDC.Issuer != "" {
+		log.Info(ctx, "startup", "status", "discovering identity provider", "issuer", cfg.Auth.OIDC.Issuer)
+
+		provider, err = oidc.NewProvider(ctx, oidc.Config{
+			Issuer:       cfg.Auth.OIDC.Issuer,
+			ClientID:     cfg.Auth.OIDC.ClientID,
+			ClientSecret: cfg.Auth.OIDC.ClientSecret,
+			RedirectURL:  cfg.Auth.OIDC.RedirectURL,
+			Scopes:       cfg.Auth.OIDC.Scopes,
+		})
+		if err != nil {
+			return fmt.Errorf("discovering identity provider: %w", err)
+		}
+
+		if cfg.Auth.OIDC.Organization != "" {
+			ssoOrgID, err = uuid.Parse(cfg.Auth.OIDC.Organization)
+			if err != nil {
+				return fmt.Errorf("parsing sso organization: %w", err)
+			}
+		}
+	}
+
 	// -------------------------------------------------------------------------
 	// Start API Http Server
 	// -------------------------------------------------------------------------
@@ -202,16 +430,37 @@ func run(ctx context.Context, cfg config.Config, log *logger.Logger) error {
 
 	// Initialize handlers
 	h := handlers.New(handlers.Config{
-		ServiceName: cfg.App.Name,
-		Build:       build,
-		Cors:        cfg.Cors,
-		DB:          db,
-		Log:         log,
-		Tracer:      tracer,
-		AuditCore:   auditCore,
-		AuthCore:    authCore,
-		UserCore:    userCore,
-		ProductCore: productCore,
+		DBConfig:       cfg.DB,
+		ServiceName:    cfg.App.Name,
+		Build:          build,
+		Cors:           cfg.Cors,
+		Auth:           cfg.Auth,
+		DB:             db,
+		Log:            log,
+		Tracer:         tracer,
+		AuditCore:      auditCore,
+		AuthCore:       authCore,
+		UserCore:       userCore,
+		ProductCore:    productCore,
+		SessionCore:    sessionCore,
+		RbacCore:       rbacCore,
+		APIKeyCore:     apiKeyCore,
+		LockoutCore:    lockoutCore,
+		MFACore:        mfaCore,
+		ResetCore:      resetCore,
+		InviteCore:     inviteCore,
+		OrgCore:        orgCore,
+		SecurityCore:   securityCore,
+		StockCore:      stockCore,
+		PriceCore:      priceCore,
+		OrderCore:      orderCore,
+		AccountCore:    accountCore,
+		Notifier:       notify,
+		PasswordPolicy: policy,
+		OIDCProvider:   provider,
+		OIDCOrgID:      ssoOrgID,
+		Keyring:        keyring,
+		DeptIndex:      userKeys.DepartmentIndex,
 	})
 
 	api := http.Server{
@@ -252,3 +501,49 @@ func run(ctx context.Context, cfg config.Config, log *logger.Logger) error {
 
 	return nil
 }
+
+// passwordHasher constructs the hasher of the configured algorithm.
+func passwordHasher(cfg config.Password) (passhash.Hasher, error) {
+	switch cfg.Algorithm {
+	case "", "argon2id":
+		return passhash.NewArgon2id(passhash.Argon2Params{
+			Memory:  cfg.Memory,
+			Time:    cfg.Time,
+			Threads: cfg.Threads,
+		}), nil
+	case "bcrypt":
+		return passhash.NewBcrypt(cfg.BcryptCost), nil
+	}
+
+	return nil, fmt.Errorf("unknown algorithm %q", cfg.Algorithm)
+}
+
+// passwordPolicy constructs the password policy, reading the banned
+// passwords from the configured file.
+func passwordPolicy(cfg config.Password) (password.Policy, error) {
+	policy := password.Policy{
+		MinLength:     cfg.MinLength,
+		MaxLength:     cfg.MaxLength,
+		RequireUpper:  cfg.RequireUpper,
+		RequireLower:  cfg.RequireLower,
+		RequireDigit:  cfg.RequireDigit,
+		RequireSymbol: cfg.RequireSymbol,
+	}
+
+	if cfg.BannedPath == "" {
+		return policy, nil
+	}
+
+	f, err := os.Open(cfg.BannedPath)
+	if err != nil {
+		return password.Policy{}, err
+	}
+	defer f.Close()
+
+	policy.Banned, err = password.ReadBanned(f)
+	if err != nil {
+		return password.Policy{}, err
+	}
+
+	return policy, nil
+}
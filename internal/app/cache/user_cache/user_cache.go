@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/viccon/sturdyc"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/pkg/logger"
 	"github.com/Housiadas/backend-system/pkg/order"
@@ -22,6 +23,7 @@ type Store struct {
 	storer user.Storer
 	log    *logger.Logger
 	cache  *sturdyc.Client[user.User]
+	inTx   bool
 }
 
 // NewStore constructs the api for data and caching access.
@@ -38,9 +40,23 @@ func NewStore(log *logger.Logger, storer user.Storer, ttl time.Duration) *Store
 }
 
 // NewWithTx constructs a new Store value replacing the sqlx DB
-// value with a sqlx DB value that is currently inside a transaction.
+// value with a sqlx DB value that is currently inside a transaction. The
+// transaction may be rolled back, the users it reads or writes are not
+// cached, the ones it writes are evicted instead.
 func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (user.Storer, error) {
-	return s.storer.NewWithTx(tx)
+	storer, err := s.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log:    s.log,
+		storer: storer,
+		cache:  s.cache,
+		inTx:   true,
+	}
+
+	return &store, nil
 }
 
 // Create inserts a new user into the database.
@@ -60,6 +76,11 @@ func (s *Store) Update(ctx context.Context, usr user.User) error {
 		return err
 	}
 
+	// The email may have changed, the user is not found by the old one.
+	if old, ok := s.readCache(usr.ID.String()); ok {
+		s.deleteCache(old)
+	}
+
 	s.writeCache(usr)
 
 	return nil
@@ -93,7 +114,11 @@ func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error)
 
 // QueryByID gets the specified user from the database.
 func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, error) {
-	cachedUsr, ok := s.readCache(userID.String())
+	if s.inTx {
+		return s.storer.QueryByID(ctx, userID)
+	}
+
+	cachedUsr, ok := s.readScopedCache(ctx, userID.String())
 	if ok {
 		return cachedUsr, nil
 	}
@@ -110,7 +135,11 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, err
 
 // QueryByEmail gets the specified user from the database by email.
 func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (user.User, error) {
-	cachedUsr, ok := s.readCache(email.Address)
+	if s.inTx {
+		return s.storer.QueryByEmail(ctx, email)
+	}
+
+	cachedUsr, ok := s.readScopedCache(ctx, email.Address)
 	if ok {
 		return cachedUsr, nil
 	}
@@ -135,8 +164,29 @@ func (s *Store) readCache(key string) (user.User, bool) {
 	return usr, true
 }
 
+// readScopedCache searches the cache like readCache, a user outside the
+// organization the context is scoped to is left to the storer to refuse.
+func (s *Store) readScopedCache(ctx context.Context, key string) (user.User, bool) {
+	usr, exists := s.readCache(key)
+	if !exists {
+		return user.User{}, false
+	}
+
+	if orgID := organization.ScopeID(ctx); orgID != nil && usr.OrgID != *orgID {
+		return user.User{}, false
+	}
+
+	return usr, true
+}
+
 // writeCache performs a safe writing to the cache for the specified user.
+// Inside a transaction the user is evicted instead.
 func (s *Store) writeCache(bus user.User) {
+	if s.inTx {
+		s.deleteCache(bus)
+		return
+	}
+
 	s.cache.Set(bus.ID.String(), bus)
 	s.cache.Set(bus.Email.Address, bus)
 }
@@ -4,7 +4,12 @@ package commands
 
 import (
 	"errors"
+	"fmt"
+	"os"
 
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/app/repository/user_repo"
 	"github.com/Housiadas/backend-system/internal/config"
 	"github.com/Housiadas/backend-system/pkg/logger"
 	"github.com/Housiadas/backend-system/pkg/pgsql"
@@ -14,18 +19,20 @@ import (
 var ErrHelp = errors.New("help provided")
 
 type Config struct {
-	DB      config.DB
-	Version config.Version
-	Auth    config.Auth
-	Kafka   config.Kafka
+	DB         config.DB
+	Version    config.Version
+	Auth       config.Auth
+	Kafka      config.Kafka
+	Encryption config.Encryption
 }
 
 type Command struct {
-	DB      pgsql.Config
-	Log     *logger.Logger
-	Version config.Version
-	Auth    config.Auth
-	Kafka   config.Kafka
+	DB         pgsql.Config
+	Log        *logger.Logger
+	Version    config.Version
+	Auth       config.Auth
+	Kafka      config.Kafka
+	Encryption config.Encryption
 }
 
 func New(
@@ -51,7 +58,20 @@ func New(
 		},
 		Auth: config.Auth{
 			KeysFolder: "/keys",
+			Issuer:     cfg.Auth.Issuer,
 		},
-		Kafka: cfg.Kafka,
+		Kafka:      cfg.Kafka,
+		Encryption: cfg.Encryption,
 	}
 }
+
+// userStore constructs the store of the users, with the keys encrypting
+// their personal data read from the keys folder.
+func (cmd *Command) userStore(db *sqlx.DB) (*user_repo.Store, error) {
+	keys, err := user_repo.LoadKeys(os.DirFS(cmd.Auth.KeysFolder), cmd.Encryption.MasterKey, cmd.Encryption.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("loading user keys: %w", err)
+	}
+
+	return user_repo.NewStore(cmd.Log, db, keys), nil
+}
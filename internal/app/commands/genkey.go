@@ -1,25 +1,46 @@
 package commands
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"fmt"
 	"os"
+	"time"
+
+	"github.com/Housiadas/backend-system/pkg/keystore"
 )
 
-// GenKey creates a x509 private/public key for authapi tokens.
-func (cmd *Command) GenKey() error {
+// GenKey creates a x509 private/public key for authapi tokens. The files are
+// named after the creation time which is used as the key id (kid), so a newly
+// generated key becomes the active signing key on the next keystore reload
+// while the previous keys remain valid for verification until removed.
+// The alg selects the type of the key: RS256, ES256 or EdDSA, or AES256 for
+// a key encrypting the personal data of the users. An empty kid is replaced
+// by the creation time.
+func (cmd *Command) GenKey(alg string, kid string) error {
+	if kid == "" {
+		kid = time.Now().UTC().Format("20060102150405")
+	}
+
+	if alg == AlgAES256 {
+		return genEncryptionKey(kid)
+	}
 
 	// Generate a new private key.
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	privateKey, privateBytes, err := generateKey(alg)
 	if err != nil {
 		return fmt.Errorf("generating key: %w", err)
 	}
 
 	// Create a file for the private key information in PEM form.
-	privateFile, err := os.Create("keys/private.pem")
+	privateFile, err := os.Create(fmt.Sprintf("keys/%s.pem", kid))
 	if err != nil {
 		return fmt.Errorf("creating private file: %w", err)
 	}
@@ -28,7 +49,7 @@ func (cmd *Command) GenKey() error {
 	// Construct a PEM block for the private key.
 	privateBlock := pem.Block{
 		Type:  "PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		Bytes: privateBytes,
 	}
 
 	// Write the private key to the private key file.
@@ -36,15 +57,16 @@ func (cmd *Command) GenKey() error {
 		return fmt.Errorf("encoding to private file: %w", err)
 	}
 
-	// Create a file for the public key information in PEM form.
-	publicFile, err := os.Create("keys/public.pem")
+	// Create a file for the public key information in PEM form. The .pub
+	// extension keeps the keystore from loading it as a private key.
+	publicFile, err := os.Create(fmt.Sprintf("keys/%s.pub", kid))
 	if err != nil {
 		return fmt.Errorf("creating public file: %w", err)
 	}
 	defer publicFile.Close()
 
 	// Marshal the public key from the private key to PKIX.
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
 	if err != nil {
 		return fmt.Errorf("marshaling public key: %w", err)
 	}
@@ -60,6 +82,64 @@ func (cmd *Command) GenKey() error {
 		return fmt.Errorf("encoding to public file: %w", err)
 	}
 
-	fmt.Println("private and public key files generated")
+	fmt.Printf("private and public key files generated: kid[%s] alg[%s]\n", kid, alg)
+	return nil
+}
+
+// AlgAES256 selects a key encrypting the personal data of the users.
+const AlgAES256 = "AES256"
+
+// genEncryptionKey creates a file holding a random base64 encoded 32 byte
+// key. The .key extension keeps the keystore from loading it as a signing
+// key.
+func genEncryptionKey(kid string) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("generating key: %w", err)
+	}
+
+	// The file must not replace a key the data is still encrypted with.
+	file, err := os.OpenFile(fmt.Sprintf("keys/%s.key", kid), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("creating key file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintln(file, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return fmt.Errorf("writing key file: %w", err)
+	}
+
+	fmt.Printf("encryption key file generated: kid[%s] alg[%s]\n", kid, AlgAES256)
 	return nil
 }
+
+// generateKey generates a private key for the signing algorithm and returns
+// it along with its DER encoding.
+func generateKey(alg string) (crypto.Signer, []byte, error) {
+	switch alg {
+	case keystore.AlgRS256:
+		pk, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pk, x509.MarshalPKCS1PrivateKey(pk), nil
+
+	case keystore.AlgES256:
+		pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(pk)
+		return pk, der, err
+
+	case keystore.AlgEdDSA:
+		_, pk, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(pk)
+		return pk, der, err
+	}
+
+	return nil, nil, fmt.Errorf("unsupported algorithm: %s", alg)
+}
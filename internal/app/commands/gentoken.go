@@ -9,7 +9,6 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 
-	"github.com/Housiadas/backend-system/internal/app/repository/user_repo"
 	"github.com/Housiadas/backend-system/internal/core/domain/role"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
@@ -28,15 +27,20 @@ func (cmd *Command) GenToken(userID uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	userBus := usercore.NewCore(cmd.Log, user_repo.NewStore(cmd.Log, db))
+	userStore, err := cmd.userStore(db)
+	if err != nil {
+		return err
+	}
+
+	userBus := usercore.NewCore(cmd.Log, userStore)
 
 	usr, err := userBus.QueryByID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("retrieve user: %w", err)
 	}
 
-	ks := keystore.New()
-	if err := ks.LoadRSAKeys(os.DirFS(cmd.Auth.KeysFolder)); err != nil {
+	ks := keystore.NewWithConfig(keystore.Config{ActiveKID: cmd.Auth.ActiveKID, LegacyKID: cmd.Auth.LegacyKID})
+	if err := ks.LoadKeys(os.DirFS(cmd.Auth.KeysFolder)); err != nil {
 		return fmt.Errorf("reading keys: %w", err)
 	}
 
@@ -44,10 +48,14 @@ func (cmd *Command) GenToken(userID uuid.UUID) error {
 		Log:       cmd.Log,
 		DB:        db,
 		KeyLookup: ks,
+		Issuer:    cmd.Auth.Issuer,
 		Userbus:   userBus,
 	}
 
-	a := authcore.New(authCfg)
+	a, err := authcore.New(authCfg)
+	if err != nil {
+		return fmt.Errorf("constructing auth: %w", err)
+	}
 
 	// Generating a token requires defining a set of claims. In this applications
 	// case, we only care about defining the subject and the user in question and
@@ -63,17 +71,18 @@ func (cmd *Command) GenToken(userID uuid.UUID) error {
 	claims := authcore.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   usr.ID.String(),
-			Issuer:    "usecase project",
+			Issuer:    a.Issuer(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(8760 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
 		Roles: role.ParseToString(usr.Roles),
+		Org:   usr.OrgID.String(),
+		Dept:  authcore.Department(usr.Department),
 	}
 
 	// This will generate a JWT with the claims embedded in them. The database
 	// with need to be configured with the information found in the public key
-	// file to validate these claims. Dgraph does not support key rotate at
-	// this time.
+	// file to validate these claims.
 	token, err := a.GenerateToken(claims)
 	if err != nil {
 		return fmt.Errorf("generating token: %w", err)
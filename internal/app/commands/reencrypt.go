@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// ReEncrypt encrypts the personal data of the users again under the active
+// master key, batch users at a time, after a new master key was generated
+// and configured. The previous master key must be kept in the keys folder
+// until it is done. A run stopped halfway resumes where it stopped.
+func (cmd *Command) ReEncrypt(batch int) error {
+	if batch <= 0 {
+		fmt.Println("help: reencrypt [-batch size]")
+		return ErrHelp
+	}
+
+	db, err := pgsql.Open(cmd.DB)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	store, err := cmd.userStore(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	var total int
+	for {
+		n, err := store.ReEncrypt(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("reencrypt users: %w", err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		total += n
+		fmt.Println("users encrypted again:", total)
+	}
+
+	fmt.Println("all users encrypted under the master key:", cmd.Encryption.MasterKey)
+	return nil
+}
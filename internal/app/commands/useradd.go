@@ -6,7 +6,6 @@ import (
 	"net/mail"
 	"time"
 
-	"github.com/Housiadas/backend-system/internal/app/repository/user_repo"
 	namePck "github.com/Housiadas/backend-system/internal/core/domain/name"
 	"github.com/Housiadas/backend-system/internal/core/domain/role"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
@@ -30,7 +29,12 @@ func (cmd *Command) UserAdd(name, email, password string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	userBus := usercore.NewCore(cmd.Log, user_repo.NewStore(cmd.Log, db))
+	userStore, err := cmd.userStore(db)
+	if err != nil {
+		return err
+	}
+
+	userBus := usercore.NewCore(cmd.Log, userStore)
 
 	addr, err := mail.ParseAddress(email)
 	if err != nil {
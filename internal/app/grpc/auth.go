@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	userV1 "github.com/Housiadas/backend-system/gen/go/github.com/Housiadas/backend-system/gen/user/v1"
+	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+const (
+	authorizationHeader = "authorization"
+	apiKeyHeader        = "x-api-key"
+	orgHeader           = "x-org-id"
+)
+
+// unauthenticatedMethods lists the services callable without credentials.
+var unauthenticatedMethods = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// methodAuth is what the caller of a method must be granted, the permission,
+// in scope for an api key, and the rule for the user the method acts on.
+type methodAuth struct {
+	perm  string
+	rule  string
+	owner func(req any) (uuid.UUID, error)
+}
+
+// methodAuths lists how the methods are authorized, the ones missing are
+// refused.
+var methodAuths = map[string]methodAuth{
+	userV1.UserService_GetUserById_FullMethodName: {
+		perm: permission.UserRead.String(),
+		rule: authcore.RuleAdminOrSubject,
+		owner: func(req any) (uuid.UUID, error) {
+			r, ok := req.(*userV1.GetUserByIdRequest)
+			if !ok {
+				return uuid.Nil, errors.New("unexpected request")
+			}
+			return uuid.Parse(r.GetId())
+		},
+	},
+}
+
+// authInterceptor authenticates the caller from the request metadata the same
+// way the Bearer middleware does for http, either with a token or an api key,
+// and authorizes the call as the http routes do.
+func (s *Server) authInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	for _, prefix := range unauthenticatedMethods {
+		if strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+	}
+
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationHeader); len(values) > 0 {
+			authorization = values[0]
+		}
+		if values := md.Get(apiKeyHeader); len(values) > 0 && values[0] != "" {
+			authorization = "ApiKey " + values[0]
+		}
+	}
+
+	claims, err := s.Business.Auth.Authenticate(ctx, authorization)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authenticate: %s", err)
+	}
+
+	// The requests made while impersonating a user are audited by the http
+	// api only, the impersonation tokens are refused here.
+	if claims.Impersonated() {
+		s.denied(ctx, claims, "impersonated")
+		return nil, status.Error(codes.PermissionDenied, "impersonation tokens are not accepted")
+	}
+
+	subjectID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "parsing subject: %s", err)
+	}
+
+	var requested string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(orgHeader); len(values) > 0 {
+			requested = values[0]
+		}
+	}
+
+	orgID, scoped, err := s.Business.Auth.Tenant(ctx, claims, requested)
+	if err != nil {
+		s.denied(ctx, claims, "tenant")
+		return nil, status.Errorf(codes.PermissionDenied, "tenant: %s", err)
+	}
+
+	if scoped {
+		ctx = organization.WithScope(ctx, orgID)
+	}
+
+	ctx = ctxPck.SetClaims(ctx, claims)
+	ctx = ctxPck.SetUserID(ctx, subjectID)
+
+	ctx, err = s.authorize(ctx, claims, info.FullMethod, req)
+	if err != nil {
+		s.denied(ctx, claims, "method "+info.FullMethod)
+		return nil, status.Errorf(codes.PermissionDenied, "authorize: %s", err)
+	}
+
+	if s.rls {
+		session := pgsql.Session{
+			UserID: subjectID.String(),
+			Roles:  claims.Roles,
+		}
+		if s.deptIndex != nil {
+			session.Department = s.deptIndex(claims.Dept)
+		}
+		if scoped {
+			session.OrgID = orgID.String()
+		}
+		ctx = pgsql.WithSession(ctx, session)
+	}
+
+	return handler(ctx, req)
+}
+
+// authorize checks the permission and the rule of the method for the caller.
+func (s *Server) authorize(ctx context.Context, claims authcore.Claims, method string, req any) (context.Context, error) {
+	ma, ok := methodAuths[method]
+	if !ok {
+		return ctx, fmt.Errorf("method %s not allowed", method)
+	}
+
+	if err := s.Business.Auth.AuthorizePermission(ctx, claims, ma.perm); err != nil {
+		return ctx, fmt.Errorf("permission %s: %w", ma.perm, err)
+	}
+	ctx = authcore.WithPermission(ctx, ma.perm)
+
+	ownerID, err := ma.owner(req)
+	if err != nil {
+		return ctx, fmt.Errorf("owner: %w", err)
+	}
+
+	res := authcore.Resource{
+		Type: entity.User.String(),
+	}
+
+	if err := s.Business.Auth.AuthorizeResource(ctx, claims, ownerID, res, ma.rule); err != nil {
+		return ctx, fmt.Errorf("rule %s: %w", ma.rule, err)
+	}
+
+	return ctx, nil
+}
+
+// denied records a permission denial for the caller with the claims.
+func (s *Server) denied(ctx context.Context, claims authcore.Claims, reason string) {
+	if s.Business.SecurityEvent == nil {
+		return
+	}
+
+	actorID, _ := uuid.Parse(claims.Subject)
+	orgID, _ := uuid.Parse(claims.Org)
+
+	s.Business.SecurityEvent.Record(ctx, securityevent.NewEvent{
+		Kind:    securityevent.PermissionDenied,
+		ActorID: actorID,
+		Subject: claims.Subject,
+		Outcome: securityevent.OutcomeFailure,
+		Reason:  reason,
+		OrgID:   orgID,
+	})
+}
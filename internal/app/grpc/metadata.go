@@ -3,8 +3,11 @@ package grpc
 import (
 	"context"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
 )
 
 const (
@@ -41,3 +44,21 @@ func (s *Server) extractMetadata(ctx context.Context) *Metadata {
 
 	return mtdt
 }
+
+// clientInterceptor sets the ip address and the user agent of the caller on
+// the context, for the security events recorded while serving the call.
+func (s *Server) clientInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	mtdt := s.extractMetadata(ctx)
+
+	ctx = securityevent.WithClient(ctx, securityevent.Client{
+		IP:        mtdt.ClientIP,
+		UserAgent: mtdt.UserAgent,
+	})
+
+	return handler(ctx, req)
+}
@@ -35,7 +35,7 @@ func (s *Server) Registrar() *grpc.Server {
 	// Register gRPC usecase
 	// -------------------------------------------------------------------------
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(s.grpcInterceptor),
+		grpc.ChainUnaryInterceptor(s.grpcInterceptor, s.clientInterceptor, s.authInterceptor),
 	)
 	userV1.RegisterUserServiceServer(grpcServer, s)
 	healthpb.RegisterHealthServer(grpcServer, healthServer)
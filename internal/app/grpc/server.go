@@ -9,7 +9,10 @@ import (
 	"github.com/Housiadas/backend-system/internal/app/usecase/system_usecase"
 	"github.com/Housiadas/backend-system/internal/app/usecase/transaction_usecase"
 	"github.com/Housiadas/backend-system/internal/app/usecase/user_usecase"
+	"github.com/Housiadas/backend-system/internal/core/domain/password"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/securityeventcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
 	"github.com/Housiadas/backend-system/pkg/logger"
 )
@@ -23,6 +26,8 @@ type Server struct {
 	Tracer      trace.Tracer
 	App         App
 	Business    Business
+	rls         bool
+	deptIndex   func(department string) string
 }
 
 // App represents the core cli layer
@@ -35,8 +40,10 @@ type App struct {
 
 // Business represents the core internal layer.
 type Business struct {
-	User    *usercore.Core
-	Product *productcore.Core
+	Auth          *authcore.Auth
+	User          *usercore.Core
+	Product       *productcore.Core
+	SecurityEvent *securityeventcore.Core
 }
 
 type Config struct {
@@ -45,8 +52,18 @@ type Config struct {
 	DB          *sqlx.DB
 	Log         *logger.Logger
 	Tracer      trace.Tracer
+	Auth        *authcore.Auth
 	UserBus     *usercore.Core
 	ProductBus  *productcore.Core
+
+	// SecurityEventBus records the permission denials, it is optional.
+	SecurityEventBus *securityeventcore.Core
+
+	// RowLevelSecurity runs the statements of the calls as the caller, the
+	// DeptIndex returns the blind index of the department of the caller the
+	// policies compare with the one of the users.
+	RowLevelSecurity bool
+	DeptIndex        func(department string) string
 }
 
 func New(cfg Config) *Server {
@@ -60,11 +77,15 @@ func New(cfg Config) *Server {
 			User:    user_usecase.NewApp(cfg.UserBus),
 			Product: product_usecase.NewApp(cfg.ProductBus),
 			System:  system_usecase.NewApp(cfg.Build, cfg.Log, cfg.DB),
-			Tx:      transaction_usecase.NewApp(cfg.UserBus, cfg.ProductBus),
+			Tx:      transaction_usecase.NewApp(cfg.UserBus, cfg.ProductBus, password.Policy{}),
 		},
 		Business: Business{
-			User:    cfg.UserBus,
-			Product: cfg.ProductBus,
+			Auth:          cfg.Auth,
+			User:          cfg.UserBus,
+			Product:       cfg.ProductBus,
+			SecurityEvent: cfg.SecurityEventBus,
 		},
+		rls:       cfg.RowLevelSecurity,
+		deptIndex: cfg.DeptIndex,
 	}
 }
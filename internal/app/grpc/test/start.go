@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/Housiadas/backend-system/internal/app/grpc"
+	"github.com/Housiadas/backend-system/internal/common/apitest"
 	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 	"github.com/Housiadas/backend-system/pkg/otel"
 )
 
@@ -14,6 +16,19 @@ import (
 func StartTest(t *testing.T, testName string) (*Test, error) {
 	db := dbtest.New(t, testName)
 
+	auth, err := authcore.New(authcore.Config{
+		Log:        db.Log,
+		DB:         db.DB,
+		KeyLookup:  &apitest.KeyStore{},
+		Userbus:    db.Core.User,
+		Sessionbus: db.Core.Session,
+		Rbacbus:    db.Core.Rbac,
+		APIKeybus:  db.Core.APIKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("constructing auth: %w", err)
+	}
+
 	// tracer
 	traceProvider, teardown, err := otel.InitTracing(otel.Config{
 		Log:         db.Log,
@@ -40,6 +55,7 @@ func StartTest(t *testing.T, testName string) (*Test, error) {
 		DB:          db.DB,
 		Log:         db.Log,
 		Tracer:      tracer,
+		Auth:        auth,
 		UserBus:     db.Core.User,
 		ProductBus:  db.Core.Product,
 	})
@@ -29,14 +29,13 @@ func toProtoUser(user user.User) *userV1.User {
 	}
 
 	return &userV1.User{
-		Id:           user.ID.String(),
-		Name:         user.Name.String(),
-		Email:        user.Email.String(),
-		Roles:        role.ParseToString(user.Roles),
-		PasswordHash: user.PasswordHash,
-		Department:   user.Department.String(),
-		Enabled:      user.Enabled,
-		DateCreated:  timestamppb.New(dateCreated),
-		DateUpdated:  timestamppb.New(dateUpdated),
+		Id:          user.ID.String(),
+		Name:        user.Name.String(),
+		Email:       user.Email.String(),
+		Roles:       role.ParseToString(user.Roles),
+		Department:  user.Department.String(),
+		Enabled:     user.Enabled,
+		DateCreated: timestamppb.New(dateCreated),
+		DateUpdated: timestamppb.New(dateUpdated),
 	}
 }
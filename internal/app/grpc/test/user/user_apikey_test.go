@@ -0,0 +1,137 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	userV1 "github.com/Housiadas/backend-system/gen/go/github.com/Housiadas/backend-system/gen/user/v1"
+	testPck "github.com/Housiadas/backend-system/internal/app/grpc/test"
+	"github.com/Housiadas/backend-system/internal/core/domain/apikey"
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/service/apikeycore"
+)
+
+func Test_GRPC_User_APIKey(t *testing.T) {
+	t.Parallel()
+
+	test, err := testPck.StartTest(t, "Test_GRPC_User_APIKey")
+	if err != nil {
+		t.Fatalf("Start error: %s", err)
+	}
+
+	sd, err := insertSeedData(test.DB)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	userRead, err := seedKey(test.DB.Core.APIKey, sd.Admins[0].ID, permission.UserRead)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	productRead, err := seedKey(test.DB.Core.APIKey, sd.Admins[0].ID, permission.ProductRead)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	client := dial(t, test)
+	usr := sd.Users[0]
+
+	table := []struct {
+		name string
+		md   metadata.MD
+		code codes.Code
+	}{
+		{name: "authorization", md: metadata.Pairs("authorization", "ApiKey "+userRead), code: codes.OK},
+		{name: "x-api-key", md: metadata.Pairs("x-api-key", userRead), code: codes.OK},
+		{name: "out-of-scope", md: metadata.Pairs("x-api-key", productRead), code: codes.PermissionDenied},
+		{name: "no-key", md: metadata.MD{}, code: codes.Unauthenticated},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.md)
+
+			resp, err := client.GetUserById(ctx, &userV1.GetUserByIdRequest{Id: usr.ID.String()})
+			if got := status.Code(err); got != tt.code {
+				t.Fatalf("Should get code %s: got %s: %v", tt.code, got, err)
+			}
+
+			if tt.code != codes.OK {
+				return
+			}
+
+			got := resp.GetUser()
+			if got.GetId() != usr.ID.String() || got.GetEmail() != usr.Email.Address {
+				t.Fatalf("Should get the user: got %s %s", got.GetId(), got.GetEmail())
+			}
+
+			if len(got.GetPasswordHash()) != 0 {
+				t.Fatal("Should not send the password hash")
+			}
+		})
+	}
+}
+
+// dial serves the grpc server of the test in memory and connects to it.
+func dial(t *testing.T, test *testPck.Test) userV1.UserServiceClient {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := test.Server.Registrar()
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			log.Printf("error serving grpc: %v", err)
+		}
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Should connect to the server: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return userV1.NewUserServiceClient(conn)
+}
+
+// seedKey creates an api key of an admin service account scoped to the
+// permission and returns the raw key.
+func seedKey(core *apikeycore.Core, creatorID uuid.UUID, scope permission.Permission) (string, error) {
+	ctx := context.Background()
+
+	sa, err := core.CreateServiceAccount(ctx, apikey.NewServiceAccount{
+		Name:      "sa-" + scope.String(),
+		Roles:     []role.Role{role.Admin},
+		CreatedBy: creatorID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("seeding service account : %w", err)
+	}
+
+	_, raw, err := core.CreateKey(ctx, apikey.NewKey{
+		ServiceAccountID: sa.ID,
+		Name:             scope.String(),
+		Scopes:           []permission.Permission{scope},
+	})
+	if err != nil {
+		return "", fmt.Errorf("seeding key : %w", err)
+	}
+
+	return raw, nil
+}
@@ -54,14 +54,13 @@ func toProtoUser(user user_usecase.User) (*userV1.User, error) {
 	}
 
 	return &userV1.User{
-		Id:           user.ID,
-		Name:         user.Name,
-		Email:        user.Email,
-		Roles:        user.Roles,
-		PasswordHash: user.PasswordHash,
-		Department:   user.Department,
-		Enabled:      user.Enabled,
-		DateCreated:  timestamppb.New(dateCreated),
-		DateUpdated:  timestamppb.New(dateUpdated),
+		Id:          user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		Roles:       user.Roles,
+		Department:  user.Department,
+		Enabled:     user.Enabled,
+		DateCreated: timestamppb.New(dateCreated),
+		DateUpdated: timestamppb.New(dateUpdated),
 	}, nil
 }
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/account_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// Account godoc
+// @Summary      Open Account
+// @Description  Open an account with no money in a currency
+// @Tags 		 Account
+// @Accept       json
+// @Produce      json
+// @Param        request body account_usecase.NewAccount true "Account data"
+// @Success      200  {object}  account_usecase.Account
+// @Failure      400  {object}  errs.Error
+// @Failure      409  {object}  errs.Error
+// @Router       /accounts [post]
+func (h *Handler) accountCreate(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app account_usecase.NewAccount
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	acct, err := h.App.Account.Create(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return acct
+}
+
+// Account godoc
+// @Summary      Query Accounts
+// @Description  Search accounts in database based on criteria
+// @Tags 		 Account
+// @Produce      json
+// @Success      200  {object}  page.Result[account_usecase.Account]
+// @Failure      500  {object}  errs.Error
+// @Router       /accounts [get]
+func (h *Handler) accountQuery(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	qp := accountParseQueryParams(r)
+
+	accts, err := h.App.Account.Query(ctx, qp)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return accts
+}
+
+// Account godoc
+// @Summary      Query User Accounts
+// @Description  Search the accounts of a user
+// @Tags 		 Account
+// @Produce      json
+// @Param        user_id path string true "User ID"
+// @Success      200  {object}  page.Result[account_usecase.Account]
+// @Failure      500  {object}  errs.Error
+// @Router       /users/{user_id}/accounts [get]
+func (h *Handler) accountQueryByUser(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	qp := accountParseQueryParams(r)
+
+	accts, err := h.App.Account.QueryByUser(ctx, qp)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return accts
+}
+
+// Account godoc
+// @Summary      Query Account by ID
+// @Description  Get an account along with its current balance
+// @Tags 		 Account
+// @Produce      json
+// @Param        account_id path string true "Account ID"
+// @Success      200  {object}  account_usecase.Account
+// @Failure      500  {object}  errs.Error
+// @Router       /accounts/{account_id} [get]
+func (h *Handler) accountQueryByID(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	acct, err := h.App.Account.QueryByID(ctx)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return acct
+}
+
+// Account godoc
+// @Summary      Account Balance
+// @Description  Get the balance of an account at a time, the current one by default
+// @Tags 		 Account
+// @Produce      json
+// @Param        account_id path string true "Account ID"
+// @Param        at query string false "Time in RFC3339"
+// @Success      200  {object}  account_usecase.Balance
+// @Failure      400  {object}  errs.Error
+// @Router       /accounts/{account_id}/balance [get]
+func (h *Handler) accountBalance(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	bal, err := h.App.Account.Balance(ctx, r.URL.Query().Get("at"))
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return bal
+}
+
+// Account godoc
+// @Summary      Account Statement
+// @Description  Search the entries of an account, the latest first
+// @Tags 		 Account
+// @Produce      json
+// @Param        account_id path string true "Account ID"
+// @Success      200  {object}  page.Result[account_usecase.Entry]
+// @Failure      500  {object}  errs.Error
+// @Router       /accounts/{account_id}/entries [get]
+func (h *Handler) accountEntries(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	values := r.URL.Query()
+
+	qp := account_usecase.EntryQueryParams{
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		TransferID:       values.Get("transfer_id"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+	}
+
+	ents, err := h.App.Account.QueryEntries(ctx, qp)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return ents
+}
+
+// Account godoc
+// @Summary      Transfer Money
+// @Description  Move money from an account to another of the same currency
+// @Tags 		 Account
+// @Accept       json
+// @Produce      json
+// @Param        account_id path string true "Account ID"
+// @Param        request body account_usecase.NewTransfer true "Transfer data"
+// @Success      200  {object}  account_usecase.Transfer
+// @Failure      400  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /accounts/{account_id}/transfers [post]
+func (h *Handler) accountTransfer(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app account_usecase.NewTransfer
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	tr, err := h.App.Account.Transfer(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return tr
+}
+
+// Account godoc
+// @Summary      Deposit Money
+// @Description  Credit an account with money from the clearing account of its currency
+// @Tags 		 Account
+// @Accept       json
+// @Produce      json
+// @Param        account_id path string true "Account ID"
+// @Param        request body account_usecase.NewMovement true "Deposit data"
+// @Success      200  {object}  account_usecase.Transfer
+// @Failure      400  {object}  errs.Error
+// @Router       /accounts/{account_id}/deposits [post]
+func (h *Handler) accountDeposit(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app account_usecase.NewMovement
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	tr, err := h.App.Account.Deposit(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return tr
+}
+
+// Account godoc
+// @Summary      Withdraw Money
+// @Description  Debit an account, the money goes to the clearing account of its currency
+// @Tags 		 Account
+// @Accept       json
+// @Produce      json
+// @Param        account_id path string true "Account ID"
+// @Param        request body account_usecase.NewMovement true "Withdrawal data"
+// @Success      200  {object}  account_usecase.Transfer
+// @Failure      400  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /accounts/{account_id}/withdrawals [post]
+func (h *Handler) accountWithdraw(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app account_usecase.NewMovement
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	tr, err := h.App.Account.Withdraw(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return tr
+}
+
+func accountParseQueryParams(r *http.Request) account_usecase.AppQueryParams {
+	values := r.URL.Query()
+
+	return account_usecase.AppQueryParams{
+		Page:     values.Get("page"),
+		Rows:     values.Get("rows"),
+		OrderBy:  values.Get("orderBy"),
+		ID:       values.Get("account_id"),
+		OwnerID:  values.Get("owner_id"),
+		Currency: values.Get("currency"),
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/apikey_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// ServiceAccount godoc
+// @Summary      Create Service Account
+// @Description  Create a new service account for machine to machine access
+// @Tags 		 ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Param        request body apikey_usecase.NewServiceAccount true "Service account data"
+// @Success      200  {object}  apikey_usecase.ServiceAccount
+// @Failure      500  {object}  errs.Error
+// @Router       /service-accounts [post]
+func (h *Handler) serviceAccountCreate(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app apikey_usecase.NewServiceAccount
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	sa, err := h.App.APIKey.CreateServiceAccount(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return sa
+}
+
+// ServiceAccount godoc
+// @Summary      Query Service Accounts
+// @Description  Query all the service accounts
+// @Tags 		 ServiceAccount
+// @Produce      json
+// @Success      200  {object}  apikey_usecase.ServiceAccounts
+// @Failure      500  {object}  errs.Error
+// @Router       /service-accounts [get]
+func (h *Handler) serviceAccountQuery(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	sas, err := h.App.APIKey.QueryServiceAccounts(ctx)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return sas
+}
+
+// ServiceAccount godoc
+// @Summary      Create API Key
+// @Description  Issue a new api key for a service account, the key is only returned once
+// @Tags 		 ServiceAccount
+// @Accept       json
+// @Produce      json
+// @Param        request body apikey_usecase.NewKey true "API key data"
+// @Success      200  {object}  apikey_usecase.CreatedKey
+// @Failure      500  {object}  errs.Error
+// @Router       /service-accounts/{service_account_id}/keys [post]
+func (h *Handler) apiKeyCreate(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app apikey_usecase.NewKey
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	key, err := h.App.APIKey.CreateKey(ctx, web.Param(r, "service_account_id"), app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return key
+}
+
+// ServiceAccount godoc
+// @Summary      Query API Keys
+// @Description  Query the api keys of a service account
+// @Tags 		 ServiceAccount
+// @Produce      json
+// @Success      200  {object}  apikey_usecase.Keys
+// @Failure      500  {object}  errs.Error
+// @Router       /service-accounts/{service_account_id}/keys [get]
+func (h *Handler) apiKeyQuery(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	keys, err := h.App.APIKey.QueryKeys(ctx, web.Param(r, "service_account_id"))
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return keys
+}
+
+// ServiceAccount godoc
+// @Summary      Revoke API Key
+// @Description  Revoke an api key of a service account
+// @Tags 		 ServiceAccount
+// @Produce      json
+// @Success      200
+// @Failure      500  {object}  errs.Error
+// @Router       /service-accounts/{service_account_id}/keys/{key_id} [delete]
+func (h *Handler) apiKeyRevoke(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	if err := h.App.APIKey.RevokeKey(ctx, web.Param(r, "service_account_id"), web.Param(r, "key_id")); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
+}
@@ -5,11 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"time"
-
-	"github.com/golang-jwt/jwt/v4"
 
+	"github.com/Housiadas/backend-system/internal/app/usecase/auth_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/password_usecase"
 	"github.com/Housiadas/backend-system/internal/app/usecase/user_usecase"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 	"github.com/Housiadas/backend-system/pkg/errs"
 	"github.com/Housiadas/backend-system/pkg/web"
@@ -21,43 +21,135 @@ func (h *Handler) authenticate(ctx context.Context, _ http.ResponseWriter, r *ht
 		return errs.New(errs.FailedPrecondition, err)
 	}
 
-	usr, err := h.App.User.Authenticate(ctx, requestData)
+	usr, err := h.App.User.Authenticate(ctx, requestData, securityevent.GetClient(ctx).IP)
 	if err != nil {
+		if e := errs.NewError(err); e.Code == errs.TooManyRequests || e.Code == errs.FailedPrecondition {
+			return e
+		}
 		return errs.New(errs.InvalidArgument, errors.New("invalid credentials"))
 	}
 
-	// Generating a token requires defining a set of claims. In this applications
-	// case, we only care about defining the subject and the user in question and
-	// the roles they have on the database. This token will expire in 8 hours.
-	//
-	// iss (issuer): Issuer of the JWT
-	// sub (subject): Subject of the JWT (the user)
-	// aud (audience): Recipient for which the JWT is intended
-	// exp (expiration time): Time after which the JWT expires
-	// nbf (not before time): Time before which the JWT must not be accepted for processing
-	// iat (issued at time): Time at which the JWT was issued; can be used to determine age of the JWT
-	// jti (JWT ID): Unique identifier; can be used to prevent the JWT from being replayed (allows a token to be used only once)
-	claims := authcore.Claims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   usr.ID,
-			Issuer:    "usecase project",
-			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(8 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		},
-		Roles: usr.Roles,
-	}
-
-	// This will generate a JWT with the claims embedded in them. The database
-	// with need to be configured with the information found in the public key
-	// file to validation these claims. Dgraph does not support key rotate at this time.
-	token, err := h.Core.Auth.GenerateToken(claims)
+	// A user enrolled in mfa, or required to use it, gets a challenge token
+	// to exchange for the tokens once the second factor is checked.
+	challenge, ok, err := h.App.Auth.Challenge(ctx, usr.ID)
+	if err != nil {
+		return errs.NewError(err)
+	}
+	if ok {
+		return challenge
+	}
+
+	// A session is created for the user, backed by a rotating refresh token.
+	// The access token is short-lived and carries the session id (sid) so it
+	// can be revoked along with the session.
+	token, err := h.App.Auth.Login(ctx, usr.ID)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return token
+}
+
+func (h *Handler) mfaEnroll(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	enr, err := h.App.Auth.MFAEnroll(ctx)
 	if err != nil {
-		return errs.New(errs.Internal, fmt.Errorf("generating token: %w", err))
+		return errs.NewError(err)
+	}
+
+	return enr
+}
+
+func (h *Handler) mfaConfirm(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app auth_usecase.MFACode
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
 	}
 
-	return authcore.AuthenticateResp{
-		Token: token,
+	codes, err := h.App.Auth.MFAConfirm(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
 	}
+
+	return codes
+}
+
+func (h *Handler) mfaVerify(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app auth_usecase.MFAVerify
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	token, err := h.App.Auth.MFAVerify(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return token
+}
+
+func (h *Handler) impersonate(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	imp, err := h.App.Auth.Impersonate(ctx, web.Param(r, "user_id"))
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return imp
+}
+
+func (h *Handler) passwordForgot(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app password_usecase.ForgotPassword
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := h.App.Password.Forgot(ctx, app); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
+}
+
+func (h *Handler) passwordReset(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app password_usecase.ResetPassword
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := h.App.Password.Reset(ctx, app); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
+}
+
+func (h *Handler) refresh(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app auth_usecase.RefreshToken
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	token, err := h.App.Auth.Refresh(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return token
+}
+
+func (h *Handler) logout(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	if err := h.App.Auth.Logout(ctx); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
+}
+
+func (h *Handler) userSessionsRevoke(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	if err := h.App.Auth.RevokeAll(ctx); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
 }
 
 func (h *Handler) authorize(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
@@ -66,7 +158,7 @@ func (h *Handler) authorize(ctx context.Context, _ http.ResponseWriter, r *http.
 		return errs.New(errs.FailedPrecondition, err)
 	}
 
-	if err := h.Core.Auth.Authorize(ctx, authData.Claims, authData.UserID, authData.Rule); err != nil {
+	if err := h.Core.Auth.AuthorizeResource(ctx, authData.Claims, authData.UserID, authData.Resource, authData.Rule); err != nil {
 		return errs.Newf(errs.Unauthenticated,
 			"authorize: you are not authorized for that action, claims[%v] rule[%v]: %s",
 			authData.Claims.Roles,
@@ -77,6 +169,20 @@ func (h *Handler) authorize(ctx context.Context, _ http.ResponseWriter, r *http.
 	return nil
 }
 
+func (h *Handler) explain(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var authData authcore.Authorize
+	if err := web.Decode(r, &authData); err != nil {
+		return errs.New(errs.FailedPrecondition, err)
+	}
+
+	exp, err := h.Core.Auth.Explain(ctx, authData.Claims, authData.UserID, authData.Resource, authData.Rule)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return exp
+}
+
 func (h *Handler) token(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
 
 	token, err := h.App.User.Token(ctx)
@@ -86,3 +192,20 @@ func (h *Handler) token(ctx context.Context, _ http.ResponseWriter, r *http.Requ
 
 	return token
 }
+
+func (h *Handler) policies(_ context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	return h.Core.Auth.Policies()
+}
+
+func (h *Handler) jwks(_ context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	jwks, err := h.Core.Auth.JWKS()
+	if err != nil {
+		return errs.New(errs.Internal, fmt.Errorf("jwks: %w", err))
+	}
+
+	return jwks
+}
+
+func (h *Handler) openIDConfiguration(_ context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	return h.Core.Auth.OpenIDConfiguration()
+}
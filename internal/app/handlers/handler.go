@@ -1,21 +1,51 @@
 package handlers
 
 import (
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/Housiadas/backend-system/internal/app/middleware"
+	"github.com/Housiadas/backend-system/internal/app/usecase/account_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/apikey_usecase"
 	"github.com/Housiadas/backend-system/internal/app/usecase/audit_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/auth_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/invite_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/order_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/organization_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/password_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/price_usecase"
 	"github.com/Housiadas/backend-system/internal/app/usecase/product_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/rbac_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/securityevent_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/sso_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/stock_usecase"
 	"github.com/Housiadas/backend-system/internal/app/usecase/system_usecase"
 	"github.com/Housiadas/backend-system/internal/app/usecase/transaction_usecase"
 	"github.com/Housiadas/backend-system/internal/app/usecase/user_usecase"
 	"github.com/Housiadas/backend-system/internal/config"
+	"github.com/Housiadas/backend-system/internal/core/domain/password"
+	"github.com/Housiadas/backend-system/internal/core/service/accountcore"
+	"github.com/Housiadas/backend-system/internal/core/service/apikeycore"
 	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/internal/core/service/invitecore"
+	"github.com/Housiadas/backend-system/internal/core/service/lockoutcore"
+	"github.com/Housiadas/backend-system/internal/core/service/mfacore"
+	"github.com/Housiadas/backend-system/internal/core/service/ordercore"
+	"github.com/Housiadas/backend-system/internal/core/service/organizationcore"
+	"github.com/Housiadas/backend-system/internal/core/service/passwordresetcore"
+	"github.com/Housiadas/backend-system/internal/core/service/pricecore"
 	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/rbaccore"
+	"github.com/Housiadas/backend-system/internal/core/service/securityeventcore"
+	"github.com/Housiadas/backend-system/internal/core/service/sessioncore"
+	"github.com/Housiadas/backend-system/internal/core/service/stockcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/encrypt"
 	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/notifier"
+	"github.com/Housiadas/backend-system/pkg/oidc"
 	"github.com/Housiadas/backend-system/pkg/pgsql"
 	"github.com/Housiadas/backend-system/pkg/web"
 )
@@ -41,37 +71,83 @@ type Web struct {
 
 // App represents the core cli layer
 type App struct {
-	Audit   *audit_usecase.App
-	User    *user_usecase.App
-	Product *product_usecase.App
-	System  *system_usecase.App
-	Tx      *transaction_usecase.App
+	Account  *account_usecase.App
+	APIKey   *apikey_usecase.App
+	Audit    *audit_usecase.App
+	Auth     *auth_usecase.App
+	Invite   *invite_usecase.App
+	Order    *order_usecase.App
+	Org      *organization_usecase.App
+	Password *password_usecase.App
+	Price    *price_usecase.App
+	User     *user_usecase.App
+	Product  *product_usecase.App
+	Rbac     *rbac_usecase.App
+	Security *securityevent_usecase.App
+	SSO      *sso_usecase.App
+	Stock    *stock_usecase.App
+	System   *system_usecase.App
+	Tx       *transaction_usecase.App
 }
 
 // Core represents the core internal layer.
 type Core struct {
-	Auth    *authcore.Auth
-	Audit   *auditcore.Core
-	User    *usercore.Core
-	Product *productcore.Core
+	Auth     *authcore.Auth
+	Audit    *auditcore.Core
+	User     *usercore.Core
+	Product  *productcore.Core
+	Session  *sessioncore.Core
+	Rbac     *rbaccore.Core
+	APIKey   *apikeycore.Core
+	Lockout  *lockoutcore.Core
+	MFA      *mfacore.Core
+	Reset    *passwordresetcore.Core
+	Invite   *invitecore.Core
+	Org      *organizationcore.Core
+	Security *securityeventcore.Core
+	Stock    *stockcore.Core
+	Order    *ordercore.Core
+	Account  *accountcore.Core
+	Price    *pricecore.Core
 }
 
 // Config represents the configuration for the handlers.
 type Config struct {
-	ServiceName string
-	Build       string
-	Cors        config.CorsSettings
-	DB          *sqlx.DB
-	Log         *logger.Logger
-	Tracer      trace.Tracer
-	AuditCore   *auditcore.Core
-	AuthCore    *authcore.Auth
-	UserCore    *usercore.Core
-	ProductCore *productcore.Core
+	ServiceName    string
+	Build          string
+	Cors           config.CorsSettings
+	Auth           config.Auth
+	DBConfig       config.DB
+	DB             *sqlx.DB
+	Log            *logger.Logger
+	Tracer         trace.Tracer
+	AuditCore      *auditcore.Core
+	AuthCore       *authcore.Auth
+	UserCore       *usercore.Core
+	ProductCore    *productcore.Core
+	SessionCore    *sessioncore.Core
+	RbacCore       *rbaccore.Core
+	APIKeyCore     *apikeycore.Core
+	LockoutCore    *lockoutcore.Core
+	MFACore        *mfacore.Core
+	ResetCore      *passwordresetcore.Core
+	InviteCore     *invitecore.Core
+	OrgCore        *organizationcore.Core
+	SecurityCore   *securityeventcore.Core
+	StockCore      *stockcore.Core
+	OrderCore      *ordercore.Core
+	AccountCore    *accountcore.Core
+	PriceCore      *pricecore.Core
+	Notifier       notifier.Notifier
+	PasswordPolicy password.Policy
+	OIDCProvider   *oidc.Provider
+	OIDCOrgID      uuid.UUID
+	Keyring        *encrypt.Keyring
+	DeptIndex      func(department string) string
 }
 
 func New(cfg Config) *Handler {
-	return &Handler{
+	h := Handler{
 		ServiceName: cfg.ServiceName,
 		Build:       cfg.Build,
 		Cors:        cfg.Cors,
@@ -80,27 +156,81 @@ func New(cfg Config) *Handler {
 		Tracer:      cfg.Tracer,
 		Web: Web{
 			Middleware: middleware.New(middleware.Config{
-				Log:     cfg.Log,
-				Tracer:  cfg.Tracer,
-				Tx:      pgsql.NewBeginner(cfg.DB),
-				Auth:    cfg.AuthCore,
-				User:    cfg.UserCore,
-				Product: cfg.ProductCore,
+				Log:              cfg.Log,
+				Tracer:           cfg.Tracer,
+				Tx:               pgsql.NewBeginner(cfg.DB),
+				Audit:            cfg.AuditCore,
+				Auth:             cfg.AuthCore,
+				User:             cfg.UserCore,
+				Product:          cfg.ProductCore,
+				Order:            cfg.OrderCore,
+				Account:          cfg.AccountCore,
+				SecurityEvent:    cfg.SecurityCore,
+				RowLevelSecurity: cfg.DBConfig.RowLevelSecurity,
+				DeptIndex:        cfg.DeptIndex,
 			}),
 			Res: web.NewRespond(cfg.Log),
 		},
 		App: App{
+			Account: account_usecase.NewApp(cfg.AccountCore),
+			APIKey:  apikey_usecase.NewApp(cfg.APIKeyCore),
 			Audit:   audit_usecase.NewApp(cfg.AuditCore),
-			User:    user_usecase.NewAppWithAuth(cfg.UserCore, cfg.AuthCore),
-			Product: product_usecase.NewApp(cfg.ProductCore),
-			System:  system_usecase.NewApp(cfg.Build, cfg.Log, cfg.DB),
-			Tx:      transaction_usecase.NewApp(cfg.UserCore, cfg.ProductCore),
+			Auth: auth_usecase.NewApp(cfg.AuthCore, cfg.UserCore, cfg.SessionCore, cfg.MFACore, cfg.LockoutCore, cfg.AuditCore, cfg.SecurityCore, auth_usecase.TTL{
+				Access:      cfg.Auth.AccessTokenTTL,
+				Refresh:     cfg.Auth.RefreshTokenTTL,
+				Challenge:   cfg.Auth.MFA.ChallengeTTL,
+				Impersonate: cfg.Auth.ImpersonationTTL,
+			}),
+			Invite: invite_usecase.NewApp(cfg.Log, cfg.AuthCore, cfg.UserCore, cfg.InviteCore, cfg.Notifier, cfg.PasswordPolicy, invite_usecase.Config{
+				InviteURL: cfg.Auth.Invite.URL,
+				VerifyURL: cfg.Auth.Verification.URL,
+				VerifyTTL: cfg.Auth.Verification.TTL,
+			}),
+			Order:    order_usecase.NewApp(cfg.OrderCore),
+			Org:      organization_usecase.NewApp(cfg.OrgCore),
+			Price:    price_usecase.NewApp(cfg.PriceCore),
+			Password: password_usecase.NewApp(cfg.Log, cfg.UserCore, cfg.SessionCore, cfg.ResetCore, cfg.Notifier, cfg.PasswordPolicy, cfg.Auth.PasswordReset.URL),
+			User:     user_usecase.NewAppWithAuth(cfg.UserCore, cfg.AuthCore, cfg.LockoutCore, cfg.SecurityCore, cfg.PasswordPolicy),
+			Product:  product_usecase.NewApp(cfg.ProductCore),
+			Rbac:     rbac_usecase.NewApp(cfg.RbacCore),
+			Security: securityevent_usecase.NewApp(cfg.SecurityCore),
+			Stock:    stock_usecase.NewApp(cfg.StockCore),
+			System:   system_usecase.NewApp(cfg.Build, cfg.Log, cfg.DB),
+			Tx:       transaction_usecase.NewApp(cfg.UserCore, cfg.ProductCore, cfg.PasswordPolicy),
 		},
 		Core: Core{
-			Audit:   cfg.AuditCore,
-			Auth:    cfg.AuthCore,
-			User:    cfg.UserCore,
-			Product: cfg.ProductCore,
+			Audit:    cfg.AuditCore,
+			Auth:     cfg.AuthCore,
+			User:     cfg.UserCore,
+			Product:  cfg.ProductCore,
+			Session:  cfg.SessionCore,
+			Rbac:     cfg.RbacCore,
+			APIKey:   cfg.APIKeyCore,
+			Lockout:  cfg.LockoutCore,
+			MFA:      cfg.MFACore,
+			Reset:    cfg.ResetCore,
+			Invite:   cfg.InviteCore,
+			Org:      cfg.OrgCore,
+			Security: cfg.SecurityCore,
+			Stock:    cfg.StockCore,
+			Order:    cfg.OrderCore,
+			Account:  cfg.AccountCore,
+			Price:    cfg.PriceCore,
 		},
 	}
+
+	// The single sign-on is only served when an identity provider is set.
+	if cfg.OIDCProvider != nil {
+		h.App.SSO = sso_usecase.NewApp(cfg.Log, cfg.UserCore, cfg.OIDCProvider, cfg.Keyring, sso_usecase.Config{
+			OrgID:                cfg.OIDCOrgID,
+			RolesClaim:           cfg.Auth.OIDC.RolesClaim,
+			RoleMapping:          cfg.Auth.OIDC.RoleMapping,
+			DefaultRoles:         cfg.Auth.OIDC.DefaultRoles,
+			Provision:            cfg.Auth.OIDC.Provision,
+			AllowUnverifiedEmail: cfg.Auth.OIDC.AllowUnverifiedEmail,
+			StateTTL:             cfg.Auth.OIDC.StateTTL,
+		})
+	}
+
+	return &h
 }
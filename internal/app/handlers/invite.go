@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/invite_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+func (h *Handler) inviteCreate(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app invite_usecase.NewInvite
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	inv, err := h.App.Invite.Create(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return inv
+}
+
+func (h *Handler) inviteAccept(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app invite_usecase.AcceptInvite
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := h.App.Invite.Accept(ctx, app); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
+}
+
+func (h *Handler) emailVerify(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app invite_usecase.VerifyEmail
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := h.App.Invite.Verify(ctx, app); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
+}
+
+func (h *Handler) emailVerifyResend(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app invite_usecase.ResendVerification
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := h.App.Invite.Resend(ctx, app); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/order_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// Order godoc
+// @Summary      Place Order
+// @Description  Place an order of products, taking their stock
+// @Tags 		 Order
+// @Accept       json
+// @Produce      json
+// @Param        request body order_usecase.NewOrder true "Order data"
+// @Success      200  {object}  order_usecase.Order
+// @Failure      400  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /orders [post]
+func (h *Handler) orderCreate(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app order_usecase.NewOrder
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ord, err := h.App.Order.Create(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return ord
+}
+
+// Order godoc
+// @Summary      Query Orders
+// @Description  Search orders in database based on criteria
+// @Tags 		 Order
+// @Produce      json
+// @Success      200  {object}  page.Result[order_usecase.Order]
+// @Failure      500  {object}  errs.Error
+// @Router       /orders [get]
+func (h *Handler) orderQuery(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	qp := orderParseQueryParams(r)
+
+	ords, err := h.App.Order.Query(ctx, qp)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return ords
+}
+
+// Order godoc
+// @Summary      Query User Orders
+// @Description  Search the orders placed by a user
+// @Tags 		 Order
+// @Produce      json
+// @Param        user_id path string true "User ID"
+// @Success      200  {object}  page.Result[order_usecase.Order]
+// @Failure      500  {object}  errs.Error
+// @Router       /users/{user_id}/orders [get]
+func (h *Handler) orderQueryByUser(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	qp := orderParseQueryParams(r)
+
+	ords, err := h.App.Order.QueryByUser(ctx, qp)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return ords
+}
+
+// Order godoc
+// @Summary      Query Order by ID
+// @Description  Get an order along with its items
+// @Tags 		 Order
+// @Produce      json
+// @Param        order_id path string true "Order ID"
+// @Success      200  {object}  order_usecase.Order
+// @Failure      500  {object}  errs.Error
+// @Router       /orders/{order_id} [get]
+func (h *Handler) orderQueryByID(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	ord, err := h.App.Order.QueryByID(ctx)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return ord
+}
+
+// Order godoc
+// @Summary      Pay Order
+// @Description  Mark a pending order as paid
+// @Tags 		 Order
+// @Produce      json
+// @Param        order_id path string true "Order ID"
+// @Success      200  {object}  order_usecase.Order
+// @Failure      409  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /orders/{order_id}/pay [post]
+func (h *Handler) orderPay(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	ord, err := h.App.Order.Pay(ctx)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return ord
+}
+
+// Order godoc
+// @Summary      Ship Order
+// @Description  Mark a paid order as shipped
+// @Tags 		 Order
+// @Produce      json
+// @Param        order_id path string true "Order ID"
+// @Success      200  {object}  order_usecase.Order
+// @Failure      409  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /orders/{order_id}/ship [post]
+func (h *Handler) orderShip(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	ord, err := h.App.Order.Ship(ctx)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return ord
+}
+
+// Order godoc
+// @Summary      Cancel Order
+// @Description  Cancel an order not shipped yet, giving the stock of its products back
+// @Tags 		 Order
+// @Produce      json
+// @Param        order_id path string true "Order ID"
+// @Success      200  {object}  order_usecase.Order
+// @Failure      409  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /orders/{order_id}/cancel [post]
+func (h *Handler) orderCancel(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	ord, err := h.App.Order.Cancel(ctx)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return ord
+}
+
+func orderParseQueryParams(r *http.Request) order_usecase.AppQueryParams {
+	values := r.URL.Query()
+
+	return order_usecase.AppQueryParams{
+		Page:             values.Get("page"),
+		Rows:             values.Get("rows"),
+		OrderBy:          values.Get("orderBy"),
+		ID:               values.Get("order_id"),
+		UserID:           values.Get("user_id"),
+		Status:           values.Get("status"),
+		StartCreatedDate: values.Get("start_created_date"),
+		EndCreatedDate:   values.Get("end_created_date"),
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/organization_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// Organization godoc
+// @Summary      Create Organization
+// @Description  Create a new organization, a tenant of the system
+// @Tags 		 Organization
+// @Accept       json
+// @Produce      json
+// @Param        request body organization_usecase.NewOrganization true "Organization data"
+// @Success      200  {object}  organization_usecase.Organization
+// @Failure      500  {object}  errs.Error
+// @Router       /organizations [post]
+func (h *Handler) organizationCreate(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app organization_usecase.NewOrganization
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	org, err := h.App.Org.Create(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return org
+}
+
+// Organization godoc
+// @Summary      Query Organizations
+// @Description  Query the organizations with paging
+// @Tags 		 Organization
+// @Produce      json
+// @Success      200  {object}  page.Result[organization_usecase.Organization]
+// @Failure      500  {object}  errs.Error
+// @Router       /organizations [get]
+func (h *Handler) organizationQuery(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	values := r.URL.Query()
+
+	orgs, err := h.App.Org.Query(ctx, organization_usecase.AppQueryParams{
+		Page: values.Get("page"),
+		Rows: values.Get("rows"),
+	})
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return orgs
+}
+
+// Organization godoc
+// @Summary      Query Organization by id
+// @Description  Query an organization by its id
+// @Tags 		 Organization
+// @Produce      json
+// @Success      200  {object}  organization_usecase.Organization
+// @Failure      500  {object}  errs.Error
+// @Router       /organizations/{org_id} [get]
+func (h *Handler) organizationQueryByID(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	org, err := h.App.Org.QueryByID(ctx, web.Param(r, "org_id"))
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return org
+}
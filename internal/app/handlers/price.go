@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/price_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// Price godoc
+// @Summary      Schedule Price
+// @Description  Schedule a price of a product to take effect later on
+// @Tags 		 Price
+// @Accept       json
+// @Produce      json
+// @Param        product_id path string true "Product ID"
+// @Param        request body price_usecase.NewPrice true "Price data"
+// @Success      200  {object}  price_usecase.Price
+// @Failure      400  {object}  errs.Error
+// @Router       /products/{product_id}/prices [post]
+func (h *Handler) priceSchedule(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app price_usecase.NewPrice
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	pr, err := h.App.Price.Schedule(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return pr
+}
+
+// Price godoc
+// @Summary      Query Prices
+// @Description  Query the price history of a product with paging, scheduled prices included
+// @Tags 		 Price
+// @Produce      json
+// @Param        product_id path string true "Product ID"
+// @Success      200  {object}  page.Result[price_usecase.Price]
+// @Failure      500  {object}  errs.Error
+// @Router       /products/{product_id}/prices [get]
+func (h *Handler) priceQuery(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	qp := priceParseQueryParams(r)
+
+	prs, err := h.App.Price.Query(ctx, qp)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return prs
+}
+
+// Price godoc
+// @Summary      Price At
+// @Description  Get the price of a product in effect at a time, now by default
+// @Tags 		 Price
+// @Produce      json
+// @Param        product_id path string true "Product ID"
+// @Param        at query string false "RFC3339 time"
+// @Success      200  {object}  price_usecase.Price
+// @Failure      404  {object}  errs.Error
+// @Router       /products/{product_id}/price [get]
+func (h *Handler) priceAt(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	pr, err := h.App.Price.PriceAt(ctx, r.URL.Query().Get("at"))
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return pr
+}
+
+func priceParseQueryParams(r *http.Request) price_usecase.AppQueryParams {
+	values := r.URL.Query()
+
+	return price_usecase.AppQueryParams{
+		Page:               values.Get("page"),
+		Rows:               values.Get("rows"),
+		OrderBy:            values.Get("orderBy"),
+		Applied:            values.Get("applied"),
+		StartEffectiveDate: values.Get("start_effective_date"),
+		EndEffectiveDate:   values.Get("end_effective_date"),
+	}
+}
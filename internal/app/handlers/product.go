@@ -75,6 +75,7 @@ func productParseQueryParams(r *http.Request) product_usecase.AppQueryParams {
 		ID:       values.Get("product_id"),
 		Name:     values.Get("name"),
 		Cost:     values.Get("cost"),
+		Currency: values.Get("currency"),
 		Quantity: values.Get("quantity"),
 	}
 }
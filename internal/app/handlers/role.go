@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/rbac_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// Role godoc
+// @Summary      Create Role
+// @Description  Create a new role with its permissions
+// @Tags 		 Role
+// @Accept       json
+// @Produce      json
+// @Param        request body rbac_usecase.NewRole true "Role data"
+// @Success      200  {object}  rbac_usecase.Role
+// @Failure      500  {object}  errs.Error
+// @Router       /roles [post]
+func (h *Handler) roleCreate(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app rbac_usecase.NewRole
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	rl, err := h.App.Rbac.Create(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return rl
+}
+
+// Role godoc
+// @Summary      Update Role
+// @Description  Update the description and permissions of a role
+// @Tags 		 Role
+// @Accept       json
+// @Produce      json
+// @Param        request body rbac_usecase.UpdateRole true "Role data"
+// @Success      200  {object}  rbac_usecase.Role
+// @Failure      500  {object}  errs.Error
+// @Router       /roles/{name} [put]
+func (h *Handler) roleUpdate(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app rbac_usecase.UpdateRole
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	rl, err := h.App.Rbac.Update(ctx, web.Param(r, "name"), app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return rl
+}
+
+// Role godoc
+// @Summary      Delete Role
+// @Description  Delete a role created at runtime
+// @Tags 		 Role
+// @Produce      json
+// @Success      200
+// @Failure      500  {object}  errs.Error
+// @Router       /roles/{name} [delete]
+func (h *Handler) roleDelete(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	if err := h.App.Rbac.Delete(ctx, web.Param(r, "name")); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
+}
+
+// Role godoc
+// @Summary      Query Roles
+// @Description  Query all the roles
+// @Tags 		 Role
+// @Produce      json
+// @Success      200  {object}  rbac_usecase.Roles
+// @Failure      500  {object}  errs.Error
+// @Router       /roles [get]
+func (h *Handler) roleQuery(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	roles, err := h.App.Rbac.Query(ctx)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return roles
+}
+
+// Role godoc
+// @Summary      Query Role by name
+// @Description  Query a role by its name
+// @Tags 		 Role
+// @Produce      json
+// @Success      200  {object}  rbac_usecase.Role
+// @Failure      500  {object}  errs.Error
+// @Router       /roles/{name} [get]
+func (h *Handler) roleQueryByName(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	rl, err := h.App.Rbac.QueryByName(ctx, web.Param(r, "name"))
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return rl
+}
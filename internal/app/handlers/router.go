@@ -7,6 +7,7 @@ import (
 	"github.com/riandyrn/otelchi"
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 )
 
@@ -16,17 +17,44 @@ func (h *Handler) Routes() *chi.Mux {
 
 	// Bearer middleware
 	authenticate := mid.Bearer()
+	authenticateMFA := mid.BearerMFA()
 
 	// authorization middleware
 	ruleAny := mid.Authorize(authcore.RuleAny)
 	ruleAdmin := mid.Authorize(authcore.RuleAdminOnly)
 	ruleUserOnly := mid.Authorize(authcore.RuleUserOnly)
+	ruleSuperAdmin := mid.Authorize(authcore.RuleSuperAdmin)
+
+	// permission middleware, the permissions are granted to roles at runtime
+	auditRead := mid.RequirePermission(permission.AuditRead.String())
+	userRead := mid.RequirePermission(permission.UserRead.String())
+	userWrite := mid.RequirePermission(permission.UserWrite.String())
+	productRead := mid.RequirePermission(permission.ProductRead.String())
+	productWrite := mid.RequirePermission(permission.ProductWrite.String())
+	orgRead := mid.RequirePermission(permission.OrganizationRead.String())
+	orgWrite := mid.RequirePermission(permission.OrganizationWrite.String())
+	roleRead := mid.RequirePermission(permission.RoleRead.String())
+	roleWrite := mid.RequirePermission(permission.RoleWrite.String())
+	securityEventRead := mid.RequirePermission(permission.SecurityEventRead.String())
+	serviceAccountRead := mid.RequirePermission(permission.ServiceAccountRead.String())
+	serviceAccountWrite := mid.RequirePermission(permission.ServiceAccountWrite.String())
 
 	// authorization for resource (entity) actions
-	// Check if a user is allowed to modify another user's resources
+	// Check if a user is allowed to modify another user's resources, the
+	// permission of the route is checked before
 	requestUserAuthorizeAdmin := mid.UserPermissions(authcore.RuleAdminOnly)
 	requestUserAdminOrSubject := mid.UserPermissions(authcore.RuleAdminOrSubject)
+	requestProductAuthorizeAdmin := mid.ProductPermissions(authcore.RuleAdminOnly)
 	requestProductAdminOrSubject := mid.ProductPermissions(authcore.RuleAdminOrSubject)
+	requestProductReadable := mid.ProductPermissions(authcore.RuleAdminSubjectOrManager)
+	requestOrderAuthorizeAdmin := mid.OrderPermissions(authcore.RuleAdminOnly)
+	requestOrderAdminOrSubject := mid.OrderPermissions(authcore.RuleAdminOrSubject)
+	requestAccountAuthorizeAdmin := mid.AccountPermissions(authcore.RuleAdminOnly)
+	requestAccountAdminOrSubject := mid.AccountPermissions(authcore.RuleAdminOrSubject)
+
+	// impersonation tokens are refused for the actions an admin must take
+	// as themselves
+	notImpersonated := mid.NotImpersonated()
 
 	tran := mid.BeginCommitRollback()
 
@@ -34,6 +62,7 @@ func (h *Handler) Routes() *chi.Mux {
 	apiRouter.Use(
 		mid.Recoverer(),
 		mid.RequestID,
+		mid.Client(),
 		mid.Logger(),
 		mid.Otel(),
 		middleware.SetHeader("Content-Type", "application/json"),
@@ -57,29 +86,114 @@ func (h *Handler) Routes() *chi.Mux {
 		// Auth
 		v1.Post("/auth/authenticate", h.Web.Res.Respond(h.authenticate))
 		v1.With(authenticate).Get("/auth/authorize", h.Web.Res.Respond(h.authorize))
+		v1.Post("/auth/refresh", h.Web.Res.Respond(h.refresh))
+		v1.With(authenticateMFA, notImpersonated).Post("/auth/mfa/enroll", h.Web.Res.Respond(h.mfaEnroll))
+		v1.With(authenticateMFA, notImpersonated).Post("/auth/mfa/confirm", h.Web.Res.Respond(h.mfaConfirm))
+		v1.Post("/auth/mfa/verify", h.Web.Res.Respond(h.mfaVerify))
+		v1.Post("/auth/password/forgot", h.Web.Res.Respond(h.passwordForgot))
+		v1.Post("/auth/password/reset", h.Web.Res.Respond(h.passwordReset))
+		v1.With(tran).Post("/auth/invite/accept", h.Web.Res.Respond(h.inviteAccept))
+		v1.Post("/auth/email/verify", h.Web.Res.Respond(h.emailVerify))
+		v1.Post("/auth/email/resend", h.Web.Res.Respond(h.emailVerifyResend))
+		v1.With(authenticate, notImpersonated, ruleAdmin).Post("/invites", h.Web.Res.Respond(h.inviteCreate))
+		v1.With(authenticate, notImpersonated, ruleAdmin).Post("/auth/impersonate/{user_id}", h.Web.Res.Respond(h.impersonate))
+		if h.App.SSO != nil {
+			v1.Get("/auth/sso/login", h.Web.Res.Respond(h.ssoLogin))
+			v1.Get("/auth/sso/callback", h.Web.Res.Respond(h.ssoCallback))
+		}
+		v1.With(authenticate).Post("/auth/logout", h.Web.Res.Respond(h.logout))
+		v1.With(authenticate, ruleAdmin).Get("/auth/policies", h.Web.Res.Respond(h.policies))
+		v1.With(authenticate, ruleAdmin).Post("/auth/explain", h.Web.Res.Respond(h.explain))
 
 		// Users
 		v1.With(authenticate).Route("/users", func(u chi.Router) {
-			u.With(ruleAdmin).Get("/", h.Web.Res.Respond(h.userQuery))
-			u.With(ruleAdmin).Post("/", h.Web.Res.Respond(h.userCreate))
-			u.With(requestUserAdminOrSubject).Get("/{user_id}", h.Web.Res.Respond(h.userQueryByID))
-			u.With(requestUserAuthorizeAdmin).Put("/role/{user_id}", h.Web.Res.Respond(h.updateRole))
-			u.With(requestUserAdminOrSubject).Put("/{user_id}", h.Web.Res.Respond(h.userUpdate))
-			u.With(requestUserAdminOrSubject).Delete("/{user_id}", h.Web.Res.Respond(h.userDelete))
+			u.With(userRead, ruleAdmin).Get("/", h.Web.Res.Respond(h.userQuery))
+			u.With(userWrite, ruleAdmin).Post("/", h.Web.Res.Respond(h.userCreate))
+			u.With(userRead, requestUserAdminOrSubject).Get("/{user_id}", h.Web.Res.Respond(h.userQueryByID))
+			u.With(userRead, requestUserAdminOrSubject).Get("/{user_id}/orders", h.Web.Res.Respond(h.orderQueryByUser))
+			u.With(userRead, requestUserAdminOrSubject).Get("/{user_id}/accounts", h.Web.Res.Respond(h.accountQueryByUser))
+			u.With(notImpersonated, userWrite, requestUserAuthorizeAdmin).Put("/role/{user_id}", h.Web.Res.Respond(h.updateRole))
+			u.With(userWrite, requestUserAuthorizeAdmin).Delete("/sessions/{user_id}", h.Web.Res.Respond(h.userSessionsRevoke))
+			u.With(userWrite, requestUserAuthorizeAdmin).Put("/unlock/{user_id}", h.Web.Res.Respond(h.userUnlock))
+			u.With(userWrite, requestUserAdminOrSubject).Put("/{user_id}", h.Web.Res.Respond(h.userUpdate))
+			u.With(userWrite, requestUserAdminOrSubject).Delete("/{user_id}", h.Web.Res.Respond(h.userDelete))
 		})
 
 		// Products
 		v1.With(authenticate).Route("/products", func(p chi.Router) {
-			p.With(ruleAny).Get("/", h.Web.Res.Respond(h.productQuery))
-			p.With(ruleUserOnly).Post("/", h.Web.Res.Respond(h.productCreate))
-			p.With(requestProductAdminOrSubject).Get("/{product_id}", h.Web.Res.Respond(h.productQueryByID))
-			p.With(requestProductAdminOrSubject).Put("/{product_id}", h.Web.Res.Respond(h.productUpdate))
-			p.With(requestProductAdminOrSubject).Delete("/{product_id}", h.Web.Res.Respond(h.productDelete))
+			p.With(productRead, ruleAny).Get("/", h.Web.Res.Respond(h.productQuery))
+			p.With(productWrite, ruleUserOnly).Post("/", h.Web.Res.Respond(h.productCreate))
+			p.With(productRead, requestProductReadable).Get("/{product_id}", h.Web.Res.Respond(h.productQueryByID))
+			p.With(productWrite, requestProductAdminOrSubject).Put("/{product_id}", h.Web.Res.Respond(h.productUpdate))
+			p.With(productWrite, requestProductAdminOrSubject).Delete("/{product_id}", h.Web.Res.Respond(h.productDelete))
+			p.With(productRead, requestProductReadable).Get("/{product_id}/stock/movements", h.Web.Res.Respond(h.stockMovementQuery))
+			p.With(productWrite, requestProductAdminOrSubject).Post("/{product_id}/stock/movements", h.Web.Res.Respond(h.stockMove))
+			p.With(productWrite, requestProductAdminOrSubject).Post("/{product_id}/stock/reservations", h.Web.Res.Respond(h.stockReserve))
+			p.With(productWrite, requestProductAdminOrSubject).Post("/{product_id}/stock/reservations/{reservation_id}/commit", h.Web.Res.Respond(h.stockCommit))
+			p.With(productWrite, requestProductAdminOrSubject).Post("/{product_id}/stock/reservations/{reservation_id}/release", h.Web.Res.Respond(h.stockRelease))
+			p.With(productRead, requestProductReadable).Get("/{product_id}/prices", h.Web.Res.Respond(h.priceQuery))
+			p.With(productWrite, requestProductAuthorizeAdmin).Post("/{product_id}/prices", h.Web.Res.Respond(h.priceSchedule))
+			p.With(productRead, requestProductReadable).Get("/{product_id}/price", h.Web.Res.Respond(h.priceAt))
+		})
+
+		// Orders, their changes take the stock of the products and are
+		// audited along with it
+		v1.With(authenticate).Route("/orders", func(o chi.Router) {
+			o.With(ruleAdmin).Get("/", h.Web.Res.Respond(h.orderQuery))
+			o.With(ruleAny, tran).Post("/", h.Web.Res.Respond(h.orderCreate))
+			o.With(requestOrderAdminOrSubject).Get("/{order_id}", h.Web.Res.Respond(h.orderQueryByID))
+			o.With(requestOrderAuthorizeAdmin, tran).Post("/{order_id}/pay", h.Web.Res.Respond(h.orderPay))
+			o.With(requestOrderAuthorizeAdmin, tran).Post("/{order_id}/ship", h.Web.Res.Respond(h.orderShip))
+			o.With(requestOrderAdminOrSubject, tran).Post("/{order_id}/cancel", h.Web.Res.Respond(h.orderCancel))
+		})
+
+		// Accounts, their transfers run in serializable transactions of
+		// their own so they are not run in the one of the request
+		v1.With(authenticate).Route("/accounts", func(a chi.Router) {
+			a.With(ruleAdmin).Get("/", h.Web.Res.Respond(h.accountQuery))
+			a.With(ruleAny).Post("/", h.Web.Res.Respond(h.accountCreate))
+			a.With(requestAccountAdminOrSubject).Get("/{account_id}", h.Web.Res.Respond(h.accountQueryByID))
+			a.With(requestAccountAdminOrSubject).Get("/{account_id}/balance", h.Web.Res.Respond(h.accountBalance))
+			a.With(requestAccountAdminOrSubject).Get("/{account_id}/entries", h.Web.Res.Respond(h.accountEntries))
+			a.With(requestAccountAdminOrSubject).Post("/{account_id}/transfers", h.Web.Res.Respond(h.accountTransfer))
+			a.With(requestAccountAuthorizeAdmin).Post("/{account_id}/deposits", h.Web.Res.Respond(h.accountDeposit))
+			a.With(requestAccountAuthorizeAdmin).Post("/{account_id}/withdrawals", h.Web.Res.Respond(h.accountWithdraw))
 		})
 
 		// Audits
 		v1.With(authenticate).Route("/audits", func(a chi.Router) {
-			a.With(ruleAdmin).Get("/", h.Web.Res.Respond(h.auditQuery))
+			a.With(auditRead).Get("/", h.Web.Res.Respond(h.auditQuery))
+		})
+
+		// Security events
+		v1.With(authenticate).Route("/security-events", func(se chi.Router) {
+			se.With(securityEventRead).Get("/", h.Web.Res.Respond(h.securityEventQuery))
+		})
+
+		// Roles, they are shared by every organization so only a super
+		// admin changes them
+		v1.With(authenticate).Route("/roles", func(rl chi.Router) {
+			rl.With(roleRead).Get("/", h.Web.Res.Respond(h.roleQuery))
+			rl.With(notImpersonated, ruleSuperAdmin, roleWrite).Post("/", h.Web.Res.Respond(h.roleCreate))
+			rl.With(roleRead).Get("/{name}", h.Web.Res.Respond(h.roleQueryByName))
+			rl.With(notImpersonated, ruleSuperAdmin, roleWrite).Put("/{name}", h.Web.Res.Respond(h.roleUpdate))
+			rl.With(notImpersonated, ruleSuperAdmin, roleWrite).Delete("/{name}", h.Web.Res.Respond(h.roleDelete))
+		})
+
+		// Organizations
+		v1.With(authenticate, ruleSuperAdmin).Route("/organizations", func(o chi.Router) {
+			o.With(orgRead).Get("/", h.Web.Res.Respond(h.organizationQuery))
+			o.With(notImpersonated, orgWrite).Post("/", h.Web.Res.Respond(h.organizationCreate))
+			o.With(orgRead).Get("/{org_id}", h.Web.Res.Respond(h.organizationQueryByID))
+		})
+
+		// Service accounts
+		v1.With(authenticate).Route("/service-accounts", func(sa chi.Router) {
+			sa.With(serviceAccountRead).Get("/", h.Web.Res.Respond(h.serviceAccountQuery))
+			sa.With(serviceAccountWrite).Post("/", h.Web.Res.Respond(h.serviceAccountCreate))
+			sa.With(serviceAccountRead).Get("/{service_account_id}/keys", h.Web.Res.Respond(h.apiKeyQuery))
+			sa.With(serviceAccountWrite).Post("/{service_account_id}/keys", h.Web.Res.Respond(h.apiKeyCreate))
+			sa.With(serviceAccountWrite).Delete("/{service_account_id}/keys/{key_id}", h.Web.Res.Respond(h.apiKeyRevoke))
 		})
 
 		// Transaction example
@@ -90,6 +204,8 @@ func (h *Handler) Routes() *chi.Mux {
 	router := chi.NewRouter()
 	router.Get("/readiness", h.Web.Res.Respond(h.readiness))
 	router.Get("/liveness", h.Web.Res.Respond(h.liveness))
+	router.Get("/.well-known/jwks.json", h.Web.Res.Respond(h.jwks))
+	router.Get("/.well-known/openid-configuration", h.Web.Res.Respond(h.openIDConfiguration))
 	router.Get("/swagger/doc.json", h.Swagger)
 	router.Handle("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("./doc.json"),
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/securityevent_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// SecurityEvent godoc
+// @Summary      Query Security Events
+// @Description  Query the logins, failed logins, issued tokens and permission denials with paging
+// @Tags 		 SecurityEvent
+// @Produce      json
+// @Success      200  {object}  page.Result[securityevent_usecase.SecurityEvent]
+// @Failure      500  {object}  errs.Error
+// @Router       /security-events [get]
+func (h *Handler) securityEventQuery(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	qp := securityEventParseQueryParams(r)
+
+	events, err := h.App.Security.Query(ctx, qp)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return events
+}
+
+func securityEventParseQueryParams(r *http.Request) securityevent_usecase.AppQueryParams {
+	values := r.URL.Query()
+
+	return securityevent_usecase.AppQueryParams{
+		Page:    values.Get("page"),
+		Rows:    values.Get("rows"),
+		OrderBy: values.Get("orderBy"),
+		Kind:    values.Get("kind"),
+		ActorID: values.Get("actor_id"),
+		Outcome: values.Get("outcome"),
+		IP:      values.Get("ip"),
+		Since:   values.Get("since"),
+		Until:   values.Get("until"),
+	}
+}
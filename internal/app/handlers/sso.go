@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/sso_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// ssoCookie keeps the encrypted state of a single sign-on in progress, it is
+// only sent back to the sso routes.
+const (
+	ssoCookie     = "sso"
+	ssoCookiePath = "/api/v1/auth/sso"
+)
+
+func (h *Handler) ssoLogin(ctx context.Context, w http.ResponseWriter, r *http.Request) web.Encoder {
+	login, state, err := h.App.SSO.Start(ctx)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookie,
+		Value:    state,
+		Path:     ssoCookiePath,
+		MaxAge:   int(h.App.SSO.StateTTL().Seconds()),
+		Expires:  time.Now().Add(h.App.SSO.StateTTL()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return login
+}
+
+func (h *Handler) ssoCallback(ctx context.Context, w http.ResponseWriter, r *http.Request) web.Encoder {
+	var state string
+	if c, err := r.Cookie(ssoCookie); err == nil {
+		state = c.Value
+	}
+
+	// The state is single use, it is cleared whatever the outcome.
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookie,
+		Path:     ssoCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	q := r.URL.Query()
+	app := sso_usecase.Callback{
+		Code:             q.Get("code"),
+		State:            q.Get("state"),
+		Error:            q.Get("error"),
+		ErrorDescription: q.Get("error_description"),
+	}
+
+	usr, err := h.App.SSO.Callback(ctx, app, state)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	// The identity provider stands for the password, the second factor is
+	// still asked of a user enrolled in mfa.
+	challenge, ok, err := h.App.Auth.Challenge(ctx, usr.ID.String())
+	if err != nil {
+		return errs.NewError(err)
+	}
+	if ok {
+		return challenge
+	}
+
+	token, err := h.App.Auth.Login(ctx, usr.ID.String())
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return token
+}
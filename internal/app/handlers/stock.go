@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/stock_usecase"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// Stock godoc
+// @Summary      Move Stock
+// @Description  Receive, adjust or sell stock of a product
+// @Tags 		 Stock
+// @Accept       json
+// @Produce      json
+// @Param        product_id path string true "Product ID"
+// @Param        request body stock_usecase.NewMovement true "Movement data"
+// @Success      200  {object}  stock_usecase.Movement
+// @Failure      400  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /products/{product_id}/stock/movements [post]
+func (h *Handler) stockMove(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app stock_usecase.NewMovement
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	mov, err := h.App.Stock.Move(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return mov
+}
+
+// Stock godoc
+// @Summary      Query Stock Movements
+// @Description  Query the history of the movements of the stock of a product with paging
+// @Tags 		 Stock
+// @Produce      json
+// @Param        product_id path string true "Product ID"
+// @Success      200  {object}  page.Result[stock_usecase.Movement]
+// @Failure      500  {object}  errs.Error
+// @Router       /products/{product_id}/stock/movements [get]
+func (h *Handler) stockMovementQuery(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	qp := stockParseQueryParams(r)
+
+	movs, err := h.App.Stock.Query(ctx, qp)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return movs
+}
+
+// Stock godoc
+// @Summary      Reserve Stock
+// @Description  Set stock of a product aside until the reservation is committed, released or expires
+// @Tags 		 Stock
+// @Accept       json
+// @Produce      json
+// @Param        product_id path string true "Product ID"
+// @Param        request body stock_usecase.NewReservation true "Reservation data"
+// @Success      200  {object}  stock_usecase.Reservation
+// @Failure      400  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /products/{product_id}/stock/reservations [post]
+func (h *Handler) stockReserve(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	var app stock_usecase.NewReservation
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	res, err := h.App.Stock.Reserve(ctx, app)
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return res
+}
+
+// Stock godoc
+// @Summary      Commit Reservation
+// @Description  Keep the stock of a pending reservation as sold
+// @Tags 		 Stock
+// @Produce      json
+// @Param        product_id path string true "Product ID"
+// @Param        reservation_id path string true "Reservation ID"
+// @Success      200  {object}  stock_usecase.Reservation
+// @Failure      404  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /products/{product_id}/stock/reservations/{reservation_id}/commit [post]
+func (h *Handler) stockCommit(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	res, err := h.App.Stock.Commit(ctx, web.Param(r, "reservation_id"))
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return res
+}
+
+// Stock godoc
+// @Summary      Release Reservation
+// @Description  Give the stock of a pending reservation back to the product
+// @Tags 		 Stock
+// @Produce      json
+// @Param        product_id path string true "Product ID"
+// @Param        reservation_id path string true "Reservation ID"
+// @Success      200  {object}  stock_usecase.Reservation
+// @Failure      404  {object}  errs.Error
+// @Failure      412  {object}  errs.Error
+// @Router       /products/{product_id}/stock/reservations/{reservation_id}/release [post]
+func (h *Handler) stockRelease(ctx context.Context, _ http.ResponseWriter, r *http.Request) web.Encoder {
+	res, err := h.App.Stock.Release(ctx, web.Param(r, "reservation_id"))
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	return res
+}
+
+func stockParseQueryParams(r *http.Request) stock_usecase.AppQueryParams {
+	values := r.URL.Query()
+
+	return stock_usecase.AppQueryParams{
+		Page:          values.Get("page"),
+		Rows:          values.Get("rows"),
+		OrderBy:       values.Get("orderBy"),
+		ReservationID: values.Get("reservation_id"),
+		Kind:          values.Get("kind"),
+	}
+}
@@ -1,6 +1,7 @@
 package product_test
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/Housiadas/backend-system/internal/app/usecase/product_usecase"
@@ -12,7 +13,8 @@ func toAppProduct(prd product.Product) product_usecase.Product {
 		ID:          prd.ID.String(),
 		UserID:      prd.UserID.String(),
 		Name:        prd.Name.String(),
-		Cost:        prd.Cost.Value(),
+		Cost:        json.Number(prd.Cost.Decimal()),
+		Currency:    prd.Cost.Currency().String(),
 		Quantity:    prd.Quantity.Value(),
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
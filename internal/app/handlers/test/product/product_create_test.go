@@ -33,14 +33,15 @@ func Test_Product_Create_200(t *testing.T) {
 			StatusCode: http.StatusOK,
 			Input: &product_usecase.NewProduct{
 				Name:     "Guitar",
-				Cost:     10.34,
+				Cost:     "10.34",
 				Quantity: 10,
 			},
 			GotResp: &product_usecase.Product{},
 			ExpResp: &product_usecase.Product{
 				Name:     "Guitar",
 				UserID:   sd.Users[0].ID.String(),
-				Cost:     10.34,
+				Cost:     "10.34",
+				Currency: "USD",
 				Quantity: 10,
 			},
 			CmpFunc: func(got any, exp any) string {
@@ -90,6 +91,23 @@ func Test_Product_Create_400(t *testing.T) {
 				return cmp.Diff(got, exp)
 			},
 		},
+		{
+			Name:       "cost-range",
+			URL:        "/api/v1/products",
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusBadRequest,
+			Input: &product_usecase.NewProduct{
+				Name:     "Guitar",
+				Cost:     "1000000000000000",
+				Quantity: 1,
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "[{\"field\":\"cost\",\"error\":\"cost not valid: must be less than 1e15\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	test.Run(t, table, "create-400")
@@ -36,7 +36,7 @@ func Test_Product_Update_200(t *testing.T) {
 			StatusCode: http.StatusOK,
 			Input: &product_usecase.UpdateProduct{
 				Name:     dbtest.StringPointer("Guitar"),
-				Cost:     dbtest.FloatPointer(10.34),
+				Cost:     dbtest.NumberPointer("10.34"),
 				Quantity: dbtest.IntPointer(10),
 			},
 			GotResp: &product_usecase.Product{},
@@ -44,7 +44,8 @@ func Test_Product_Update_200(t *testing.T) {
 				ID:          sd.Users[0].Products[0].ID.String(),
 				UserID:      sd.Users[0].ID.String(),
 				Name:        "Guitar",
-				Cost:        10.34,
+				Cost:        "10.34",
+				Currency:    "USD",
 				Quantity:    10,
 				DateCreated: sd.Users[0].Products[0].DateCreated.Format(time.RFC3339),
 				DateUpdated: sd.Users[0].Products[0].DateCreated.Format(time.RFC3339),
@@ -87,11 +88,40 @@ func Test_Product_Update_400(t *testing.T) {
 			Method:     http.MethodPut,
 			StatusCode: http.StatusBadRequest,
 			Input: &product_usecase.UpdateProduct{
-				Cost:     dbtest.FloatPointer(-1.0),
 				Quantity: dbtest.IntPointer(0),
 			},
 			GotResp: &errs.Error{},
-			ExpResp: errs.Newf(errs.InvalidArgument, "validation: [{\"field\":\"cost\",\"error\":\"cost must be 0 or greater\"},{\"field\":\"quantity\",\"error\":\"quantity must be 1 or greater\"}]"),
+			ExpResp: errs.Newf(errs.InvalidArgument, "validation: [{\"field\":\"quantity\",\"error\":\"quantity must be 1 or greater\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "negative-cost",
+			URL:        fmt.Sprintf("/api/v1/products/%s", sd.Users[0].Products[0].ID),
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusBadRequest,
+			Input: &product_usecase.UpdateProduct{
+				Cost: dbtest.NumberPointer("-1.0"),
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "[{\"field\":\"cost\",\"error\":\"cost not valid: must be 0 or greater\"}]"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "cost-range",
+			URL:        fmt.Sprintf("/api/v1/products/%s", sd.Users[0].Products[0].ID),
+			Token:      sd.Users[0].Token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusBadRequest,
+			Input: &product_usecase.UpdateProduct{
+				Cost: dbtest.NumberPointer("1000000000000000"),
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.InvalidArgument, "[{\"field\":\"cost\",\"error\":\"cost not valid: must be less than 1e15\"}]"),
 			CmpFunc: func(got any, exp any) string {
 				return cmp.Diff(got, exp)
 			},
@@ -147,7 +177,7 @@ func Test_Product_Update_401(t *testing.T) {
 			StatusCode: http.StatusUnauthorized,
 			Input: &product_usecase.UpdateProduct{
 				Name:     dbtest.StringPointer("Guitar"),
-				Cost:     dbtest.FloatPointer(10.34),
+				Cost:     dbtest.NumberPointer("10.34"),
 				Quantity: dbtest.IntPointer(10),
 			},
 			GotResp: &errs.Error{},
@@ -0,0 +1,138 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/user_usecase"
+	"github.com/Housiadas/backend-system/internal/common/apitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/apikey"
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/service/apikeycore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+func Test_API_User_APIKey(t *testing.T) {
+	t.Parallel()
+
+	test, err := apitest.StartTest(t, "Test_API_User_APIKey")
+	if err != nil {
+		t.Fatalf("Start error: %s", err)
+	}
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// Both keys belong to an admin service account, the scopes alone limit
+	// what they are used for.
+	userRead, err := seedKey(test.DB.Core.APIKey, sd.Admins[0], permission.UserRead)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	productRead, err := seedKey(test.DB.Core.APIKey, sd.Admins[0], permission.ProductRead)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	usr := sd.Users[0]
+	url := fmt.Sprintf("/api/v1/users/%s", usr.ID)
+
+	cmpCode := func(got any, exp any) string {
+		return cmp.Diff(got.(*errs.Error).Code, exp.(*errs.Error).Code)
+	}
+
+	table := []apitest.Table{
+		{
+			Name:       "authorization",
+			URL:        url,
+			Header:     map[string]string{"authorization": "ApiKey " + userRead},
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &user_usecase.User{},
+			ExpResp:    toAppUserPtr(usr.User),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "x-api-key",
+			URL:        url,
+			Header:     map[string]string{"X-API-Key": userRead},
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &user_usecase.User{},
+			ExpResp:    toAppUserPtr(usr.User),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "out-of-scope-permission",
+			URL:        url,
+			Header:     map[string]string{"X-API-Key": productRead},
+			Method:     http.MethodGet,
+			StatusCode: http.StatusUnauthorized,
+			GotResp:    &errs.Error{},
+			ExpResp:    &errs.Error{Code: errs.Unauthenticated},
+			CmpFunc:    cmpCode,
+		},
+		{
+			Name:       "out-of-scope-admin",
+			URL:        "/api/v1/users",
+			Header:     map[string]string{"X-API-Key": userRead},
+			Method:     http.MethodPost,
+			Input:      &user_usecase.NewUser{},
+			StatusCode: http.StatusUnauthorized,
+			GotResp:    &errs.Error{},
+			ExpResp:    &errs.Error{Code: errs.Unauthenticated},
+			CmpFunc:    cmpCode,
+		},
+		{
+			Name:       "rule-only-route",
+			URL:        "/api/v1/invites",
+			Header:     map[string]string{"X-API-Key": userRead},
+			Method:     http.MethodPost,
+			Input:      map[string]any{},
+			StatusCode: http.StatusUnauthorized,
+			GotResp:    &errs.Error{},
+			ExpResp:    &errs.Error{Code: errs.Unauthenticated},
+			CmpFunc:    cmpCode,
+		},
+	}
+
+	test.Run(t, table, "user-apikey")
+}
+
+// seedKey creates an api key of an admin service account scoped to the
+// permission and returns the raw key.
+func seedKey(core *apikeycore.Core, creator apitest.User, scope permission.Permission) (string, error) {
+	ctx := context.Background()
+
+	sa, err := core.CreateServiceAccount(ctx, apikey.NewServiceAccount{
+		Name:      "sa-" + scope.String(),
+		Roles:     []role.Role{role.Admin},
+		CreatedBy: creator.ID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("seeding service account : %w", err)
+	}
+
+	_, raw, err := core.CreateKey(ctx, apikey.NewKey{
+		ServiceAccountID: sa.ID,
+		Name:             scope.String(),
+		Scopes:           []permission.Permission{scope},
+	})
+	if err != nil {
+		return "", fmt.Errorf("seeding key : %w", err)
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,170 @@
+package user_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Housiadas/backend-system/internal/app/usecase/auth_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/user_usecase"
+	"github.com/Housiadas/backend-system/internal/common/apitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+func Test_API_User_Impersonate_200(t *testing.T) {
+	t.Parallel()
+
+	test, err := apitest.StartTest(t, "Test_API_User")
+	if err != nil {
+		t.Fatalf("Start error: %s", err)
+	}
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	table := []apitest.Table{
+		{
+			Name:       "basic",
+			URL:        fmt.Sprintf("/api/v1/auth/impersonate/%s", sd.Users[0].ID),
+			Token:      sd.Admins[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusOK,
+			GotResp:    &auth_usecase.Impersonation{},
+			ExpResp: &auth_usecase.Impersonation{
+				TokenType: "Bearer",
+				ExpiresIn: int((15 * time.Minute).Seconds()),
+				Subject:   sd.Users[0].ID.String(),
+				Actor:     sd.Admins[0].ID.String(),
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(*auth_usecase.Impersonation)
+				if !exists {
+					return "error occurred"
+				}
+
+				if gotResp.Token == "" {
+					return "expected a token"
+				}
+
+				expResp := exp.(*auth_usecase.Impersonation)
+				gotResp.Token = expResp.Token
+
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:       "acting",
+			URL:        fmt.Sprintf("/api/v1/users/%s", sd.Users[0].ID),
+			Token:      impersonationToken(test.Auth, sd.Admins[0], sd.Users[0]),
+			Method:     http.MethodGet,
+			StatusCode: http.StatusOK,
+			GotResp:    &user_usecase.User{},
+			ExpResp: &user_usecase.User{
+				ID:          sd.Users[0].ID.String(),
+				Name:        sd.Users[0].Name.String(),
+				Email:       sd.Users[0].Email.Address,
+				Roles:       []string{"USER"},
+				Department:  sd.Users[0].Department.String(),
+				Enabled:     true,
+				DateCreated: sd.Users[0].DateCreated.Format(time.RFC3339),
+				DateUpdated: sd.Users[0].DateUpdated.Format(time.RFC3339),
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	test.Run(t, table, "impersonate-200")
+}
+
+func Test_API_User_Impersonate_403(t *testing.T) {
+	t.Parallel()
+
+	test, err := apitest.StartTest(t, "Test_API_User")
+	if err != nil {
+		t.Fatalf("Start error: %s", err)
+	}
+
+	sd, err := insertSeedData(test.DB, test.Auth)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// The admin impersonated keeps the roles, the token is refused anyway.
+	token := impersonationToken(test.Auth, sd.Admins[0], sd.Admins[1])
+
+	table := []apitest.Table{
+		{
+			Name:       "impersonate",
+			URL:        fmt.Sprintf("/api/v1/auth/impersonate/%s", sd.Users[0].ID),
+			Token:      token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusForbidden,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "not allowed while impersonating a user"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "role",
+			URL:        fmt.Sprintf("/api/v1/users/role/%s", sd.Users[0].ID),
+			Token:      token,
+			Method:     http.MethodPut,
+			StatusCode: http.StatusForbidden,
+			Input: &user_usecase.UpdateUserRole{
+				Roles: []string{"ADMIN"},
+			},
+			GotResp: &errs.Error{},
+			ExpResp: errs.Newf(errs.PermissionDenied, "not allowed while impersonating a user"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:       "admin-target",
+			URL:        fmt.Sprintf("/api/v1/auth/impersonate/%s", sd.Admins[1].ID),
+			Token:      sd.Admins[0].Token,
+			Method:     http.MethodPost,
+			StatusCode: http.StatusForbidden,
+			GotResp:    &errs.Error{},
+			ExpResp:    errs.Newf(errs.PermissionDenied, "can't impersonate a user with the ADMIN role"),
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	test.Run(t, table, "impersonate-403")
+}
+
+// impersonationToken generates a token of the actor impersonating the user.
+func impersonationToken(ath *authcore.Auth, actor apitest.User, usr apitest.User) string {
+	claims := authcore.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   usr.ID.String(),
+			Issuer:    ath.Issuer(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+		Roles: role.ParseToString(usr.Roles),
+		Actor: &authcore.Actor{Subject: actor.ID.String()},
+		Org:   usr.OrgID.String(),
+	}
+
+	token, err := ath.GenerateToken(claims)
+	if err != nil {
+		return ""
+	}
+
+	return token
+}
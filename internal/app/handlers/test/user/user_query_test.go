@@ -45,7 +45,7 @@ func Test_API_User_Query_200(t *testing.T) {
 	table := []apitest.Table{
 		{
 			Name:       "basic",
-			URL:        "/api/v1/users?page=1&rows=10&orderBy=user_id,ASC&name=Name",
+			URL:        "/api/v1/users?page=1&rows=10&orderBy=user_id,ASC",
 			Token:      sd.Admins[0].Token,
 			StatusCode: http.StatusOK,
 			Method:     http.MethodGet,
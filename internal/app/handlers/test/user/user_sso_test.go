@@ -0,0 +1,148 @@
+package user_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/url"
+	"testing"
+
+	"github.com/Housiadas/backend-system/internal/app/handlers"
+	"github.com/Housiadas/backend-system/internal/app/usecase/auth_usecase"
+	"github.com/Housiadas/backend-system/internal/app/usecase/sso_usecase"
+	"github.com/Housiadas/backend-system/internal/common/apitest"
+	"github.com/Housiadas/backend-system/internal/config"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/pkg/encrypt"
+	"github.com/Housiadas/backend-system/pkg/oidc"
+	"github.com/Housiadas/backend-system/pkg/oidc/oidctest"
+)
+
+func Test_API_User_SSO(t *testing.T) {
+	t.Parallel()
+
+	fake, err := oidctest.New("backend", "secret")
+	if err != nil {
+		t.Fatalf("Should be able to start the provider: %s", err)
+	}
+	defer fake.Close()
+
+	provider, err := oidc.NewProvider(context.Background(), oidc.Config{
+		Issuer:       fake.Issuer(),
+		ClientID:     "backend",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/api/v1/auth/sso/callback",
+	})
+	if err != nil {
+		t.Fatalf("Should be able to discover the provider: %s", err)
+	}
+
+	keyring, err := encrypt.New("test", map[string][]byte{"test": make([]byte, 32)})
+	if err != nil {
+		t.Fatalf("Should be able to create the keyring: %s", err)
+	}
+
+	test, err := apitest.StartTest(t, "Test_API_User_SSO", func(cfg *handlers.Config) {
+		cfg.OIDCProvider = provider
+		cfg.Keyring = keyring
+		cfg.Auth.OIDC = config.OIDC{
+			RolesClaim:   "groups",
+			RoleMapping:  map[string]string{"Admins": "ADMIN"},
+			DefaultRoles: []string{"USER"},
+			Provision:    true,
+		}
+	})
+	if err != nil {
+		t.Fatalf("Start error: %s", err)
+	}
+
+	fake.SetUser(map[string]any{
+		"sub":            "sso-1",
+		"email":          "sso.admin@example.com",
+		"email_verified": true,
+		"name":           "Sso Admin",
+		"groups":         []string{"admins", "engineering"},
+	})
+
+	// A first login provisions the user with the mapped roles.
+	login(t, test, fake, "", http.StatusOK)
+
+	addr, _ := mail.ParseAddress("sso.admin@example.com")
+	usr, err := test.DB.Core.User.QueryByEmail(context.Background(), *addr)
+	if err != nil {
+		t.Fatalf("Should provision the user: %s", err)
+	}
+	if len(usr.Roles) != 1 || usr.Roles[0] != role.Admin || !usr.EmailVerified {
+		t.Fatalf("Should map the roles and trust the email: got %v %v", usr.Roles, usr.EmailVerified)
+	}
+
+	// The next login finds the user by email.
+	login(t, test, fake, "", http.StatusOK)
+
+	// The state must be the one of the login started.
+	login(t, test, fake, "forged", http.StatusBadRequest)
+
+	// An email the provider did not verify is refused.
+	fake.SetUser(map[string]any{
+		"sub":            "sso-2",
+		"email":          "sso.user@example.com",
+		"email_verified": false,
+	})
+	login(t, test, fake, "", http.StatusForbidden)
+}
+
+// login goes through a single sign-on, replacing the state sent back when
+// set, and checks the status of the callback.
+func login(t *testing.T, test *apitest.Test, fake *oidctest.Provider, state string, status int) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	test.Mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/sso/login", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Should start the login: got %d", w.Code)
+	}
+
+	var start sso_usecase.Login
+	if err := json.Unmarshal(w.Body.Bytes(), &start); err != nil {
+		t.Fatalf("Should be able to unmarshal the response: %s", err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("Should keep the state in an http only cookie: got %v", cookies)
+	}
+
+	code, gotState, err := fake.Login(start.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("Should be able to log in with the provider: %s", err)
+	}
+	if state == "" {
+		state = gotState
+	}
+
+	q := url.Values{"code": {code}, "state": {state}}
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/auth/sso/callback?"+q.Encode(), nil)
+	r.AddCookie(cookies[0])
+
+	w = httptest.NewRecorder()
+	test.Mux.ServeHTTP(w, r)
+
+	if w.Code != status {
+		t.Fatalf("Should receive a status code of %d for the callback: %d: %s", status, w.Code, w.Body.String())
+	}
+
+	if status != http.StatusOK {
+		return
+	}
+
+	var token auth_usecase.Token
+	if err := json.Unmarshal(w.Body.Bytes(), &token); err != nil {
+		t.Fatalf("Should be able to unmarshal the response: %s", err)
+	}
+	if token.Token == "" || token.RefreshToken == "" {
+		t.Fatalf("Should issue the tokens: got %+v", token)
+	}
+}
@@ -30,6 +30,12 @@ func (h *Handler) userCreate(ctx context.Context, _ http.ResponseWriter, r *http
 		return errs.NewError(err)
 	}
 
+	// The user is created either way, a failed delivery of the verification
+	// can be retried with the resend.
+	if err := h.App.Invite.SendVerification(ctx, usr.ID); err != nil {
+		h.Log.Error(ctx, "user create", "status", "sending verification", "userID", usr.ID, "msg", err)
+	}
+
 	return usr
 }
 
@@ -150,3 +156,19 @@ func userParseQueryParams(r *http.Request) user_usecase.AppQueryParams {
 		EndCreatedDate:   values.Get("end_created_date"),
 	}
 }
+
+// User godoc
+// @Summary      Unlock User
+// @Description  Lift the lockout of a user after too many failed logins
+// @Tags 		 User
+// @Produce      json
+// @Success      200
+// @Failure      500  {object}  errs.Error
+// @Router       /users/unlock/{user_id} [put]
+func (h *Handler) userUnlock(ctx context.Context, _ http.ResponseWriter, _ *http.Request) web.Encoder {
+	if err := h.App.User.Unlock(ctx); err != nil {
+		return errs.NewError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// AccountPermissions executes authorization for resource (entity) actions
+// Check if a user is allowed to reach the account, the subject is its
+// owner.
+func (m *Middleware) AccountPermissions(rule string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var userID uuid.UUID
+			id := web.Param(r, "account_id")
+			ctx := r.Context()
+
+			if id != "" {
+				accountID, err := uuid.Parse(id)
+				if err != nil {
+					err = errs.New(errs.Unauthenticated, ErrInvalidID)
+					m.Log.Error(ctx, "authorize account mid: authorize", err)
+					m.Error(w, err, http.StatusUnauthorized)
+					return
+				}
+
+				// ensure that only one call to an expensive or duplicative operation is in flight at any given time
+				response, err, _ := group.Do(fmt.Sprintf("account_id:%s", accountID), func() (interface{}, error) {
+					return m.Bus.Account.QueryByID(ctx, accountID)
+				})
+				if err != nil {
+					switch {
+					case errors.Is(err, account.ErrNotFound):
+						err = errs.New(errs.Unauthenticated, err)
+					default:
+						err = errs.Newf(errs.Internal, "querybyid: accountID[%s]: %s", accountID, err)
+					}
+					m.Log.Error(ctx, "authorize account mid: authorize", err)
+					m.Error(w, err, http.StatusUnauthorized)
+					return
+				}
+
+				acct, ok := response.(account.Account)
+				if !ok {
+					err = errs.New(errs.InternalOnlyLog, errors.New("code should be reach here"))
+					m.Log.Error(ctx, "authorize error:", err)
+					m.Error(w, err, http.StatusInternalServerError)
+					return
+				}
+
+				userID = acct.OwnerID
+				ctx = context.SetAccount(ctx, acct)
+			}
+
+			authData := authcore.Authorize{
+				Claims:   context.GetClaims(ctx),
+				UserID:   userID,
+				Resource: authcore.Resource{Type: entity.Account.String()},
+				Rule:     rule,
+			}
+
+			if err := m.Bus.Auth.AuthorizeResource(ctx, authData.Claims, authData.UserID, authData.Resource, authData.Rule); err != nil {
+				err = errs.Newf(errs.Unauthenticated,
+					"authorize: you are not authorized for that action, claims[%v] rule[%v]: %s",
+					authData.Claims.Roles, authData.Rule, err,
+				)
+				m.denied(ctx, authData.Claims, "rule "+authData.Rule)
+				m.Log.Error(ctx, "authorize account mid: authorize", err)
+				m.Error(w, err, http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -32,6 +32,7 @@ func (m *Middleware) Authorize(rule string) func(next http.Handler) http.Handler
 					"authorize: you are not authorized for that action, claims[%v] rule[%v]: %s",
 					authData.Claims.Roles, authData.Rule, err,
 				)
+				m.denied(ctx, authData.Claims, "rule "+authData.Rule)
 				m.Log.Error(ctx, "authorize mid: authorize", err)
 				m.Error(w, err, http.StatusUnauthorized)
 				return
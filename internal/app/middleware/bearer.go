@@ -1,21 +1,46 @@
 package middleware
 
 import (
+	stdctx "context"
 	"errors"
 	"net/http"
 
 	"github.com/google/uuid"
 
 	"github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
 )
 
-// Bearer processes JWT token.
+// Bearer processes JWT token. An api key is accepted instead, either with the
+// ApiKey authorization scheme or in the X-API-Key header. The request is then
+// scoped to the organization of the caller, a super admin works across the
+// organizations or in the one set in the X-Org-ID header. With the row level
+// security enabled, the statements of the request run as the caller.
 func (m *Middleware) Bearer() func(next http.Handler) http.Handler {
+	return m.bearer(m.Bus.Auth.Authenticate)
+}
+
+// BearerMFA is the Bearer middleware for the mfa enrolment, it also accepts
+// the mfa challenge token so a user required to use mfa can enroll before
+// getting an access token.
+func (m *Middleware) BearerMFA() func(next http.Handler) http.Handler {
+	return m.bearer(m.Bus.Auth.AuthenticateMFA)
+}
+
+func (m *Middleware) bearer(authenticate func(ctx stdctx.Context, bearerToken string) (authcore.Claims, error)) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
-			claims, err := m.Bus.Auth.Authenticate(ctx, r.Header.Get("authorization"))
+
+			authorization := r.Header.Get("authorization")
+			if key := r.Header.Get("X-API-Key"); key != "" {
+				authorization = "ApiKey " + key
+			}
+
+			claims, err := authenticate(ctx, authorization)
 			if err != nil {
 				err = errs.New(errs.Unauthenticated, err)
 				m.Log.Error(ctx, "bearer mid: unauthenticated", errs.Unauthenticated)
@@ -34,17 +59,63 @@ func (m *Middleware) Bearer() func(next http.Handler) http.Handler {
 
 			subjectID, err := uuid.Parse(claims.Subject)
 			if err != nil {
-				m.Log.Error(ctx,
-					"bearer mid: parsing",
-					errs.Newf(errs.Unauthenticated, "parsing subject: %s", err),
-				)
+				err = errs.Newf(errs.Unauthenticated, "parsing subject: %s", err)
+				m.Log.Error(ctx, "bearer mid: parsing", err)
+				m.Error(w, err, http.StatusUnauthorized)
 				return
 			}
 
+			orgID, scoped, err := m.Bus.Auth.Tenant(ctx, claims, r.Header.Get("X-Org-ID"))
+			if err != nil {
+				err = errs.New(errs.PermissionDenied, err)
+				m.denied(ctx, claims, "tenant")
+				m.Log.Info(ctx, "bearer mid: tenant", err)
+				m.Error(w, err, http.StatusForbidden)
+				return
+			}
+
+			if scoped {
+				ctx = organization.WithScope(ctx, orgID)
+			}
+
 			ctx = context.SetClaims(ctx, claims)
 			ctx = context.SetUserID(ctx, subjectID)
 
+			// Every request made while impersonating a user is recorded
+			// along with the admin acting, it is refused when it can't be.
+			if claims.Impersonated() {
+				if err := m.auditImpersonation(ctx, r, claims, subjectID); err != nil {
+					err = errs.Newf(errs.Internal, "recording impersonation: %s", err)
+					m.Log.Error(ctx, "bearer mid: impersonation", err)
+					m.Error(w, err, http.StatusInternalServerError)
+					return
+				}
+			}
+
+			if m.rls {
+				ctx = pgsql.WithSession(ctx, m.session(claims, subjectID, orgID, scoped))
+			}
+
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// session returns the database session of the caller, an unscoped caller
+// works across the organizations.
+func (m *Middleware) session(claims authcore.Claims, subjectID uuid.UUID, orgID uuid.UUID, scoped bool) pgsql.Session {
+	s := pgsql.Session{
+		UserID: subjectID.String(),
+		Roles:  claims.Roles,
+	}
+
+	if m.deptIndex != nil {
+		s.Department = m.deptIndex(claims.Dept)
+	}
+
+	if scoped {
+		s.OrgID = orgID.String()
+	}
+
+	return s
+}
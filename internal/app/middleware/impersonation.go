@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	stdctx "context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// NotImpersonated refuses the requests made with an impersonation token, for
+// the actions an admin must take as themselves.
+func (m *Middleware) NotImpersonated() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			if claims := context.GetClaims(ctx); claims.Impersonated() {
+				err := errs.New(errs.PermissionDenied, errors.New("not allowed while impersonating a user"))
+				m.denied(ctx, claims, "impersonated")
+				m.Log.Error(ctx, "impersonation mid: refused", err)
+				m.Error(w, err, http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// auditImpersonation records a request made by the actor impersonating the
+// subject.
+func (m *Middleware) auditImpersonation(ctx stdctx.Context, r *http.Request, claims authcore.Claims, subjectID uuid.UUID) error {
+	actorID, err := uuid.Parse(claims.Actor.Subject)
+	if err != nil {
+		return fmt.Errorf("parsing actor: %w", err)
+	}
+
+	data := struct {
+		TokenID   string
+		Method    string
+		Path      string
+		RequestID string
+	}{
+		TokenID:   claims.ID,
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		RequestID: context.GetRequestID(ctx),
+	}
+
+	na := audit.NewAudit{
+		ObjID:     subjectID,
+		ObjEntity: entity.User,
+		ObjName:   name.MustParse("impersonation"),
+		ActorID:   actorID,
+		Action:    "request",
+		Data:      data,
+		Message:   r.Method + " " + r.URL.Path,
+	}
+
+	if _, err := m.Bus.Audit.Create(stdctx.WithoutCancel(ctx), na); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	return nil
+}
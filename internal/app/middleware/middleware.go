@@ -10,8 +10,12 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
 
+	"github.com/Housiadas/backend-system/internal/core/service/accountcore"
+	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/internal/core/service/ordercore"
 	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/securityeventcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
 	"github.com/Housiadas/backend-system/pkg/logger"
 	"github.com/Housiadas/backend-system/pkg/pgsql"
@@ -25,12 +29,24 @@ var (
 )
 
 type Config struct {
-	Log     *logger.Logger
-	Tracer  trace.Tracer
-	Tx      *pgsql.DBBeginner
-	Auth    *authcore.Auth
-	User    *usercore.Core
-	Product *productcore.Core
+	Log           *logger.Logger
+	Tracer        trace.Tracer
+	Tx            *pgsql.DBBeginner
+	Audit         *auditcore.Core
+	Auth          *authcore.Auth
+	User          *usercore.Core
+	Product       *productcore.Core
+	Order         *ordercore.Core
+	Account       *accountcore.Core
+	SecurityEvent *securityeventcore.Core
+
+	// RowLevelSecurity sets the caller on the statements run for the
+	// authenticated requests, for the row level security of the database.
+	RowLevelSecurity bool
+
+	// DeptIndex returns the blind index of a department, the policies
+	// compare the department of the caller with the one of the users by it.
+	DeptIndex func(department string) string
 }
 
 type Middleware struct {
@@ -38,24 +54,38 @@ type Middleware struct {
 	Log    *logger.Logger
 	Tracer trace.Tracer
 	Tx     *pgsql.DBBeginner
+	rls    bool
+
+	deptIndex func(department string) string
 }
 
 type Business struct {
-	Auth    *authcore.Auth
-	User    *usercore.Core
-	Product *productcore.Core
+	Audit         *auditcore.Core
+	Auth          *authcore.Auth
+	User          *usercore.Core
+	Product       *productcore.Core
+	Order         *ordercore.Core
+	Account       *accountcore.Core
+	SecurityEvent *securityeventcore.Core
 }
 
 func New(cfg Config) *Middleware {
 	return &Middleware{
 		Bus: Business{
-			Auth:    cfg.Auth,
-			User:    cfg.User,
-			Product: cfg.Product,
+			Audit:         cfg.Audit,
+			Auth:          cfg.Auth,
+			User:          cfg.User,
+			Product:       cfg.Product,
+			Order:         cfg.Order,
+			Account:       cfg.Account,
+			SecurityEvent: cfg.SecurityEvent,
 		},
 		Log:    cfg.Log,
 		Tracer: cfg.Tracer,
 		Tx:     cfg.Tx,
+		rls:    cfg.RowLevelSecurity,
+
+		deptIndex: cfg.DeptIndex,
 	}
 }
 
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/web"
+)
+
+// OrderPermissions executes authorization for resource (entity) actions
+// Check if a user is allowed to reach the order, the subject is the user
+// who placed it.
+func (m *Middleware) OrderPermissions(rule string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var userID uuid.UUID
+			id := web.Param(r, "order_id")
+			ctx := r.Context()
+
+			if id != "" {
+				orderID, err := uuid.Parse(id)
+				if err != nil {
+					err = errs.New(errs.Unauthenticated, ErrInvalidID)
+					m.Log.Error(ctx, "authorize order mid: authorize", err)
+					m.Error(w, err, http.StatusUnauthorized)
+					return
+				}
+
+				// ensure that only one call to an expensive or duplicative operation is in flight at any given time
+				response, err, _ := group.Do(fmt.Sprintf("order_id:%s", orderID), func() (interface{}, error) {
+					return m.Bus.Order.QueryByID(ctx, orderID)
+				})
+				if err != nil {
+					switch {
+					case errors.Is(err, order.ErrNotFound):
+						err = errs.New(errs.Unauthenticated, err)
+					default:
+						err = errs.Newf(errs.Internal, "querybyid: orderID[%s]: %s", orderID, err)
+					}
+					m.Log.Error(ctx, "authorize order mid: authorize", err)
+					m.Error(w, err, http.StatusUnauthorized)
+					return
+				}
+
+				ord, ok := response.(order.Order)
+				if !ok {
+					err = errs.New(errs.InternalOnlyLog, errors.New("code should be reach here"))
+					m.Log.Error(ctx, "authorize error:", err)
+					m.Error(w, err, http.StatusInternalServerError)
+					return
+				}
+
+				userID = ord.UserID
+				ctx = context.SetOrder(ctx, ord)
+			}
+
+			authData := authcore.Authorize{
+				Claims:   context.GetClaims(ctx),
+				UserID:   userID,
+				Resource: authcore.Resource{Type: entity.Order.String()},
+				Rule:     rule,
+			}
+
+			if err := m.Bus.Auth.AuthorizeResource(ctx, authData.Claims, authData.UserID, authData.Resource, authData.Rule); err != nil {
+				err = errs.Newf(errs.Unauthenticated,
+					"authorize: you are not authorized for that action, claims[%v] rule[%v]: %s",
+					authData.Claims.Roles, authData.Rule, err,
+				)
+				m.denied(ctx, authData.Claims, "rule "+authData.Rule)
+				m.Log.Error(ctx, "authorize order mid: authorize", err)
+				m.Error(w, err, http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// RequirePermission validates the user's roles grant the permission. The
+// permission is kept in the context, the rules checked after it authorize an
+// api key only when it is in the scopes of the key.
+func (m *Middleware) RequirePermission(perm string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			claims := context.GetClaims(ctx)
+
+			if err := m.Bus.Auth.AuthorizePermission(ctx, claims, perm); err != nil {
+				err = errs.Newf(errs.Unauthenticated,
+					"authorize: you are not authorized for that action, claims[%v] permission[%v]: %s",
+					claims.Roles, perm, err,
+				)
+				m.denied(ctx, claims, "permission "+perm)
+				m.Log.Error(ctx, "permission mid: authorize", err)
+				m.Error(w, err, http.StatusUnauthorized)
+				return
+			}
+
+			ctx = authcore.WithPermission(ctx, perm)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	stdctx "context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -8,7 +9,9 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
 	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 	"github.com/Housiadas/backend-system/pkg/errs"
 	"github.com/Housiadas/backend-system/pkg/web"
@@ -20,6 +23,7 @@ func (m *Middleware) ProductPermissions(rule string) func(next http.Handler) htt
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			var userID uuid.UUID
+			var res authcore.Resource
 			id := web.Param(r, "product_id")
 			ctx := r.Context()
 
@@ -58,19 +62,28 @@ func (m *Middleware) ProductPermissions(rule string) func(next http.Handler) htt
 
 				userID = prd.UserID
 				ctx = context.SetProduct(ctx, prd)
+
+				res, err = m.productResource(ctx, prd)
+				if err != nil {
+					m.Log.Error(ctx, "authorize product mid: authorize", err)
+					m.Error(w, err, http.StatusInternalServerError)
+					return
+				}
 			}
 
 			authData := authcore.Authorize{
-				Claims: context.GetClaims(ctx),
-				UserID: userID,
-				Rule:   rule,
+				Claims:   context.GetClaims(ctx),
+				UserID:   userID,
+				Resource: res,
+				Rule:     rule,
 			}
 
-			if err := m.Bus.Auth.Authorize(ctx, authData.Claims, authData.UserID, authData.Rule); err != nil {
+			if err := m.Bus.Auth.AuthorizeResource(ctx, authData.Claims, authData.UserID, authData.Resource, authData.Rule); err != nil {
 				err = errs.Newf(errs.Unauthenticated,
 					"authorize: you are not authorized for that action, claims[%v] rule[%v]: %s",
 					authData.Claims.Roles, authData.Rule, err,
 				)
+				m.denied(ctx, authData.Claims, "rule "+authData.Rule)
 				m.Log.Error(ctx, "authorize product mid: authorize", err)
 				m.Error(w, err, http.StatusUnauthorized)
 				return
@@ -80,3 +93,25 @@ func (m *Middleware) ProductPermissions(rule string) func(next http.Handler) htt
 		})
 	}
 }
+
+// productResource returns the attributes of the product for the rules, the
+// department of the owner is only looked up for callers in a department.
+func (m *Middleware) productResource(ctx stdctx.Context, prd product.Product) (authcore.Resource, error) {
+	res := authcore.Resource{Type: entity.Product.String()}
+
+	if context.GetClaims(ctx).Dept == "" {
+		return res, nil
+	}
+
+	usr, err := m.Bus.User.QueryByID(ctx, prd.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return res, nil
+		}
+		return authcore.Resource{}, errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", prd.UserID, err)
+	}
+
+	res.Department = authcore.Department(usr.Department)
+
+	return res, nil
+}
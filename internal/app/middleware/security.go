@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	stdctx "context"
+	"net"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+)
+
+// Client sets the ip address and the user agent of the caller on the
+// context, for the security events recorded while serving the request.
+func (m *Middleware) Client() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := securityevent.WithClient(r.Context(), securityevent.Client{
+				IP:        clientIP(r),
+				UserAgent: r.UserAgent(),
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// clientIP returns the ip address of the caller. The forwarding headers are
+// not trusted, a client could set them to dodge the limits on failed logins.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// denied records a permission denial for the caller with the claims.
+func (m *Middleware) denied(ctx stdctx.Context, claims authcore.Claims, reason string) {
+	if m.Bus.SecurityEvent == nil {
+		return
+	}
+
+	actorID, _ := uuid.Parse(claims.Subject)
+	orgID, _ := uuid.Parse(claims.Org)
+
+	m.Bus.SecurityEvent.Record(ctx, securityevent.NewEvent{
+		Kind:    securityevent.PermissionDenied,
+		ActorID: actorID,
+		Subject: claims.Subject,
+		Outcome: securityevent.OutcomeFailure,
+		Reason:  reason,
+		OrgID:   orgID,
+	})
+}
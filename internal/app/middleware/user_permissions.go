@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 	"github.com/Housiadas/backend-system/pkg/errs"
@@ -21,6 +22,7 @@ func (m *Middleware) UserPermissions(rule string) func(next http.Handler) http.H
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			var userID uuid.UUID
+			var res authcore.Resource
 			id := web.Param(r, "user_id")
 			ctx := r.Context()
 
@@ -60,19 +62,26 @@ func (m *Middleware) UserPermissions(rule string) func(next http.Handler) http.H
 
 				// Here adds in the context the requested user based on (user_id)
 				ctx = context.SetUser(ctx, usr)
+
+				res = authcore.Resource{
+					Type:       entity.User.String(),
+					Department: authcore.Department(usr.Department),
+				}
 			}
 
 			authData := authcore.Authorize{
-				Claims: context.GetClaims(ctx),
-				UserID: userID,
-				Rule:   rule,
+				Claims:   context.GetClaims(ctx),
+				UserID:   userID,
+				Resource: res,
+				Rule:     rule,
 			}
 
-			if err := m.Bus.Auth.Authorize(ctx, authData.Claims, authData.UserID, authData.Rule); err != nil {
+			if err := m.Bus.Auth.AuthorizeResource(ctx, authData.Claims, authData.UserID, authData.Resource, authData.Rule); err != nil {
 				err = errs.Newf(errs.Unauthenticated,
 					"authorize: you are not authorized for that action, claims[%v] rule[%v]: %s",
 					authData.Claims.Roles, authData.Rule, err,
 				)
+				m.denied(ctx, authData.Claims, "rule "+authData.Rule)
 				m.Log.Error(ctx, "authorize user mid: authorize", err)
 				m.Error(w, err, http.StatusUnauthorized)
 				return
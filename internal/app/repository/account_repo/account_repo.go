@@ -0,0 +1,329 @@
+// Package account_repo contains accountDB related CRUD functionality.
+package account_repo
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	orderPck "github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// transferAttempts is the number of times a transfer is run when it
+// conflicts with a concurrent one.
+const transferAttempts = 20
+
+// queries
+var (
+	//go:embed query/account_create.sql
+	accountCreateSql string
+	//go:embed query/account_lock.sql
+	accountLockSql string
+	//go:embed query/account_transfer.sql
+	accountTransferSql string
+	//go:embed query/account_query_clearing.sql
+	accountQueryClearingSql string
+	//go:embed query/account_balance_at.sql
+	accountBalanceAtSql string
+	//go:embed query/account_query.sql
+	accountQuerySql string
+	//go:embed query/account_query_by_id.sql
+	accountQueryByIdSql string
+	//go:embed query/account_count.sql
+	accountCountSql string
+	//go:embed query/entry_query.sql
+	entryQuerySql string
+	//go:embed query/entry_count.sql
+	entryCountSql string
+)
+
+// Store manages the set of APIs for accountDB database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (account.Storer, error) {
+	ec, err := pgsql.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new account into the database.
+func (s *Store) Create(ctx context.Context, acct account.Account) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, accountCreateSql, toDBAccount(acct)); err != nil {
+		if errors.Is(err, pgsql.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", account.ErrUniqueCurrency)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Transfer moves the money and writes the debit and the credit entries in a
+// serializable transaction. Both accounts are locked in the order of their
+// IDs first, so two transfers between the same accounts in opposite ways
+// wait for each other instead of deadlocking.
+func (s *Store) Transfer(ctx context.Context, tr account.Transfer) ([]account.Entry, error) {
+	dbTr := toDBTransfer(tr, uuid.New(), uuid.New())
+
+	var dest struct {
+		DebitBalance  string `db:"debit_balance"`
+		CreditBalance string `db:"credit_balance"`
+	}
+
+	err := pgsql.WithSerializable(ctx, s.db, transferAttempts, func(tx sqlx.ExtContext) error {
+		var locked []struct {
+			ID uuid.UUID `db:"account_id"`
+		}
+		if err := pgsql.NamedQuerySlice(ctx, s.log, tx, accountLockSql, dbTr, &locked); err != nil {
+			return fmt.Errorf("lock: %w", err)
+		}
+
+		if len(locked) != 2 {
+			return account.ErrNotFound
+		}
+
+		if err := pgsql.NamedQueryStruct(ctx, s.log, tx, accountTransferSql, dbTr, &dest); err != nil {
+			if errors.Is(err, pgsql.ErrDBNotFound) {
+				return account.ErrInsufficientFunds
+			}
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	currency := tr.Amount.Currency()
+
+	debitBalance, err := money.Parse(dest.DebitBalance, currency)
+	if err != nil {
+		return nil, fmt.Errorf("parse debit balance: %w", err)
+	}
+
+	creditBalance, err := money.Parse(dest.CreditBalance, currency)
+	if err != nil {
+		return nil, fmt.Errorf("parse credit balance: %w", err)
+	}
+
+	entries := []account.Entry{
+		{
+			ID:          dbTr.DebitEntryID,
+			AccountID:   tr.FromAccountID,
+			TransferID:  tr.ID,
+			Amount:      money.New(-tr.Amount.Amount(), currency),
+			Balance:     debitBalance,
+			DateCreated: tr.DateCreated,
+		},
+		{
+			ID:          dbTr.CreditEntryID,
+			AccountID:   tr.ToAccountID,
+			TransferID:  tr.ID,
+			Amount:      tr.Amount,
+			Balance:     creditBalance,
+			DateCreated: tr.DateCreated,
+		},
+	}
+
+	return entries, nil
+}
+
+// QueryByID gets the specified account from the database.
+func (s *Store) QueryByID(ctx context.Context, accountID uuid.UUID) (account.Account, error) {
+	data := struct {
+		ID         string     `db:"account_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+	}{
+		ID:         accountID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+
+	var dbAcct accountDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, accountQueryByIdSql, data, &dbAcct); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return account.Account{}, fmt.Errorf("db: %w", account.ErrNotFound)
+		}
+		return account.Account{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusAccount(dbAcct)
+}
+
+// QueryClearing gets the clearing account of the currency in the
+// organization, the account is opened the first time it is asked for.
+func (s *Store) QueryClearing(ctx context.Context, orgID uuid.UUID, currency money.Currency, now time.Time) (account.Account, error) {
+	data := struct {
+		ID          uuid.UUID `db:"account_id"`
+		OrgID       uuid.UUID `db:"org_id"`
+		Currency    string    `db:"currency"`
+		DateCreated time.Time `db:"date_created"`
+	}{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		Currency:    currency.String(),
+		DateCreated: now.UTC(),
+	}
+
+	// A clearing account opened by a concurrent request is not seen by the
+	// statement that waited for it, the second attempt finds it.
+	var dbAcct accountDB
+	for range 2 {
+		err := pgsql.NamedQueryStruct(ctx, s.log, s.db, accountQueryClearingSql, data, &dbAcct)
+		if err == nil {
+			return toBusAccount(dbAcct)
+		}
+
+		if !errors.Is(err, pgsql.ErrDBNotFound) {
+			return account.Account{}, fmt.Errorf("db: %w", err)
+		}
+	}
+
+	return account.Account{}, fmt.Errorf("db: clearing: %w", account.ErrNotFound)
+}
+
+// QueryBalanceAt gets the balance of the account right after the last entry
+// written up to the time.
+func (s *Store) QueryBalanceAt(ctx context.Context, acct account.Account, at time.Time) (money.Money, error) {
+	data := struct {
+		ID uuid.UUID `db:"account_id"`
+		At time.Time `db:"at"`
+	}{
+		ID: acct.ID,
+		At: at.UTC(),
+	}
+
+	var dest struct {
+		Balance string `db:"balance"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, accountBalanceAtSql, data, &dest); err != nil {
+		return money.Money{}, fmt.Errorf("db: %w", err)
+	}
+
+	balance, err := money.Parse(dest.Balance, acct.Balance.Currency())
+	if err != nil {
+		return money.Money{}, fmt.Errorf("parse balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+// Query retrieves a list of existing accounts from the database.
+func (s *Store) Query(ctx context.Context, filter account.QueryFilter, orderBy orderPck.By, page page.Page) ([]account.Account, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	buf := bytes.NewBufferString(accountQuerySql)
+	applyFilter(ctx, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbAccts []accountDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbAccts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusAccounts(dbAccts)
+}
+
+// Count returns the total number of accounts in the DB.
+func (s *Store) Count(ctx context.Context, filter account.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	buf := bytes.NewBufferString(accountCountSql)
+	applyFilter(ctx, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryEntries retrieves a list of the entries of an account from the
+// database.
+func (s *Store) QueryEntries(ctx context.Context, filter account.EntryFilter, orderBy orderPck.By, page page.Page) ([]account.Entry, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	buf := bytes.NewBufferString(entryQuerySql)
+	applyEntryFilter(ctx, filter, data, buf)
+
+	orderByClause, err := entryOrderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbEntries []entryDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbEntries); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusEntries(dbEntries)
+}
+
+// CountEntries returns the total number of entries of an account in the DB.
+func (s *Store) CountEntries(ctx context.Context, filter account.EntryFilter) (int, error) {
+	data := map[string]any{}
+
+	buf := bytes.NewBufferString(entryCountSql)
+	applyEntryFilter(ctx, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
@@ -0,0 +1,254 @@
+package account_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+func Test_Account(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Account")
+
+	accts, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	t.Run("overdraft", func(t *testing.T) {
+		overdraft(t, db.Core, accts[0], accts[1])
+	})
+
+	t.Run("crossed", func(t *testing.T) {
+		crossed(t, db.Core, accts[1], accts[2])
+	})
+
+	unitest.Run(t, statement(db.Core, accts[0]), "statement")
+}
+
+// =============================================================================
+
+// insertSeedData opens an account for three users, each funded with 100.
+func insertSeedData(core dbtest.Core) ([]account.Account, error) {
+	ctx := context.Background()
+
+	usrs, err := usercore.TestSeedUsers(ctx, 3, role.User, core.User)
+	if err != nil {
+		return nil, fmt.Errorf("seeding users : %w", err)
+	}
+
+	accts := make([]account.Account, len(usrs))
+	for i, usr := range usrs {
+		accts[i], err = core.Account.Create(ctx, account.NewAccount{OwnerID: usr.ID, Currency: money.USD})
+		if err != nil {
+			return nil, fmt.Errorf("seeding account : %w", err)
+		}
+
+		if _, _, err := core.Account.Deposit(ctx, accts[i], money.MustParse("100", money.USD)); err != nil {
+			return nil, fmt.Errorf("seeding deposit : %w", err)
+		}
+	}
+
+	return accts, nil
+}
+
+// =============================================================================
+
+// overdraft transfers more than the balance at the same time, the balance
+// must run out without dropping below zero.
+func overdraft(t *testing.T, core dbtest.Core, from account.Account, to account.Account) {
+	ctx := context.Background()
+
+	const workers = 15
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, _, err := core.Account.Transfer(ctx, account.NewTransfer{
+				FromAccountID: from.ID,
+				ToAccountID:   to.ID,
+				Amount:        money.MustParse("10", money.USD),
+			})
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	var done int
+	for err := range errs {
+		switch {
+		case err == nil:
+			done++
+		case errors.Is(err, account.ErrInsufficientFunds):
+		default:
+			t.Fatalf("Should only run out of money: %s", err)
+		}
+	}
+
+	if done != 10 {
+		t.Fatalf("Should transfer exactly the balance: got %d, exp 10", done)
+	}
+
+	assertBalance(t, core, from.ID, "0.00")
+	assertBalance(t, core, to.ID, "200.00")
+
+	if _, _, err := core.Account.Withdraw(ctx, from, money.MustParse("0.01", money.USD)); !errors.Is(err, account.ErrInsufficientFunds) {
+		t.Fatalf("Should refuse to withdraw from an empty account: %v", err)
+	}
+}
+
+// crossed transfers between two accounts in both ways at the same time, the
+// transfers must not deadlock and the money must be kept.
+func crossed(t *testing.T, core dbtest.Core, a account.Account, b account.Account) {
+	ctx := context.Background()
+
+	const workers = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			nt := account.NewTransfer{
+				FromAccountID: a.ID,
+				ToAccountID:   b.ID,
+				Amount:        money.MustParse("1", money.USD),
+			}
+			if i%2 == 1 {
+				nt.FromAccountID, nt.ToAccountID = b.ID, a.ID
+			}
+
+			_, _, err := core.Account.Transfer(ctx, nt)
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Should transfer: %s", err)
+		}
+	}
+
+	// As many transfers went each way.
+	assertBalance(t, core, a.ID, "200.00")
+	assertBalance(t, core, b.ID, "100.00")
+
+	ents, err := core.Account.QueryEntries(ctx, account.EntryFilter{AccountID: b.ID}, account.DefaultEntryOrderBy, page.MustParse("1", "100"))
+	if err != nil {
+		t.Fatalf("Should get the entries: %s", err)
+	}
+
+	sum := money.Zero(money.USD)
+	for _, ent := range ents {
+		if ent.Balance.IsNegative() {
+			t.Fatalf("Should never drop below zero: entry %s left %s", ent.ID, ent.Balance)
+		}
+		if sum, err = sum.Add(ent.Amount); err != nil {
+			t.Fatalf("Should add the entries: %s", err)
+		}
+	}
+
+	if sum.Decimal() != "100.00" {
+		t.Fatalf("Should derive the balance from the entries: got %s, exp 100.00", sum.Decimal())
+	}
+}
+
+func assertBalance(t *testing.T, core dbtest.Core, accountID uuid.UUID, exp string) {
+	t.Helper()
+
+	acct, err := core.Account.QueryByID(context.Background(), accountID)
+	if err != nil {
+		t.Fatalf("Should get the account: %s", err)
+	}
+
+	if acct.Balance.Decimal() != exp {
+		t.Fatalf("Should have the balance of account %s: got %s, exp %s", acct.ID, acct.Balance.Decimal(), exp)
+	}
+}
+
+// =============================================================================
+
+func statement(core dbtest.Core, acct account.Account) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "entries",
+			ExpResp: 11,
+			ExcFunc: func(ctx context.Context) any {
+				count, err := core.Account.CountEntries(ctx, account.EntryFilter{AccountID: acct.ID})
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "latest",
+			ExpResp: []string{"-10.00", "0.00"},
+			ExcFunc: func(ctx context.Context) any {
+				ents, err := core.Account.QueryEntries(ctx, account.EntryFilter{AccountID: acct.ID}, account.DefaultEntryOrderBy, page.MustParse("1", "1"))
+				if err != nil {
+					return err
+				}
+
+				return []string{ents[0].Amount.Decimal(), ents[0].Balance.Decimal()}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "balance-at",
+			ExpResp: []string{"0.00", "0.00"},
+			ExcFunc: func(ctx context.Context) any {
+				before, err := core.Account.BalanceAt(ctx, acct, acct.DateCreated.Add(-time.Hour))
+				if err != nil {
+					return err
+				}
+
+				now, err := core.Account.BalanceAt(ctx, acct, time.Now())
+				if err != nil {
+					return err
+				}
+
+				return []string{before.Decimal(), now.Decimal()}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,68 @@
+package account_repo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+)
+
+func applyFilter(ctx context.Context, filter account.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if orgID, ok := organization.Scoped(ctx); ok {
+		data["org_id"] = orgID
+		wc = append(wc, "org_id = :org_id")
+	}
+
+	if filter.ID != nil {
+		data["account_id"] = *filter.ID
+		wc = append(wc, "account_id = :account_id")
+	}
+
+	if filter.OwnerID != nil {
+		data["owner"] = *filter.OwnerID
+		wc = append(wc, "owner = :owner")
+	}
+
+	if filter.Currency != nil {
+		data["currency"] = filter.Currency.String()
+		wc = append(wc, "currency = :currency")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
+
+// applyEntryFilter always limits the entries to the ones of the account.
+func applyEntryFilter(ctx context.Context, filter account.EntryFilter, data map[string]any, buf *bytes.Buffer) {
+	data["account_id"] = filter.AccountID
+	wc := []string{"e.account_id = :account_id"}
+
+	if orgID, ok := organization.Scoped(ctx); ok {
+		data["org_id"] = orgID
+		wc = append(wc, "e.org_id = :org_id")
+	}
+
+	if filter.TransferID != nil {
+		data["transfer_id"] = *filter.TransferID
+		wc = append(wc, "e.transfer_id = :transfer_id")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = filter.StartCreatedDate.UTC()
+		wc = append(wc, "e.date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = filter.EndCreatedDate.UTC()
+		wc = append(wc, "e.date_created <= :end_date_created")
+	}
+
+	buf.WriteString(" WHERE ")
+	buf.WriteString(strings.Join(wc, " AND "))
+}
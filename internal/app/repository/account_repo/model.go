@@ -0,0 +1,150 @@
+package account_repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+)
+
+type accountDB struct {
+	ID          uuid.UUID     `db:"account_id"`
+	OrgID       uuid.UUID     `db:"org_id"`
+	OwnerID     uuid.NullUUID `db:"owner"`
+	Currency    string        `db:"currency"`
+	Balance     string        `db:"balance"`
+	DateCreated time.Time     `db:"date_created"`
+	DateUpdated time.Time     `db:"date_updated"`
+}
+
+func toDBAccount(bus account.Account) accountDB {
+	return accountDB{
+		ID:          bus.ID,
+		OrgID:       bus.OrgID,
+		OwnerID:     uuid.NullUUID{UUID: bus.OwnerID, Valid: bus.OwnerID != uuid.Nil},
+		Currency:    bus.Balance.Currency().String(),
+		Balance:     bus.Balance.Decimal(),
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusAccount(db accountDB) (account.Account, error) {
+	currency, err := money.ParseCurrency(db.Currency)
+	if err != nil {
+		return account.Account{}, fmt.Errorf("parse currency: %w", err)
+	}
+
+	balance, err := money.Parse(db.Balance, currency)
+	if err != nil {
+		return account.Account{}, fmt.Errorf("parse balance: %w", err)
+	}
+
+	bus := account.Account{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		OwnerID:     db.OwnerID.UUID,
+		Balance:     balance,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusAccounts(dbs []accountDB) ([]account.Account, error) {
+	bus := make([]account.Account, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusAccount(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type transferDB struct {
+	ID            uuid.UUID `db:"transfer_id"`
+	FromAccountID uuid.UUID `db:"from_account_id"`
+	ToAccountID   uuid.UUID `db:"to_account_id"`
+	Amount        string    `db:"amount"`
+	Currency      string    `db:"currency"`
+	DebitEntryID  uuid.UUID `db:"debit_entry_id"`
+	CreditEntryID uuid.UUID `db:"credit_entry_id"`
+	DateCreated   time.Time `db:"date_created"`
+}
+
+func toDBTransfer(bus account.Transfer, debitEntryID uuid.UUID, creditEntryID uuid.UUID) transferDB {
+	return transferDB{
+		ID:            bus.ID,
+		FromAccountID: bus.FromAccountID,
+		ToAccountID:   bus.ToAccountID,
+		Amount:        bus.Amount.Decimal(),
+		Currency:      bus.Amount.Currency().String(),
+		DebitEntryID:  debitEntryID,
+		CreditEntryID: creditEntryID,
+		DateCreated:   bus.DateCreated.UTC(),
+	}
+}
+
+// =============================================================================
+
+type entryDB struct {
+	ID          uuid.UUID `db:"entry_id"`
+	AccountID   uuid.UUID `db:"account_id"`
+	TransferID  uuid.UUID `db:"transfer_id"`
+	Amount      string    `db:"amount"`
+	Balance     string    `db:"balance"`
+	Currency    string    `db:"currency"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toBusEntry(db entryDB) (account.Entry, error) {
+	currency, err := money.ParseCurrency(db.Currency)
+	if err != nil {
+		return account.Entry{}, fmt.Errorf("parse currency: %w", err)
+	}
+
+	amount, err := money.Parse(db.Amount, currency)
+	if err != nil {
+		return account.Entry{}, fmt.Errorf("parse amount: %w", err)
+	}
+
+	balance, err := money.Parse(db.Balance, currency)
+	if err != nil {
+		return account.Entry{}, fmt.Errorf("parse balance: %w", err)
+	}
+
+	bus := account.Entry{
+		ID:          db.ID,
+		AccountID:   db.AccountID,
+		TransferID:  db.TransferID,
+		Amount:      amount,
+		Balance:     balance,
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusEntries(dbs []entryDB) ([]account.Entry, error) {
+	bus := make([]account.Entry, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusEntry(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
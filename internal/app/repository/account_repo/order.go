@@ -0,0 +1,39 @@
+package account_repo
+
+import (
+	"fmt"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	orderPck "github.com/Housiadas/backend-system/pkg/order"
+)
+
+var orderByFields = map[string]string{
+	account.OrderByID:          "account_id",
+	account.OrderByOwnerID:     "owner",
+	account.OrderByCurrency:    "currency",
+	account.OrderByBalance:     "balance",
+	account.OrderByDateCreated: "date_created",
+}
+
+var entryOrderByFields = map[string]string{
+	account.OrderByEntrySeq:    "e.seq",
+	account.OrderByEntryAmount: "e.amount",
+}
+
+func orderByClause(orderBy orderPck.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
+
+func entryOrderByClause(orderBy orderPck.By) (string, error) {
+	by, exists := entryOrderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,6 @@
+SELECT COALESCE((SELECT balance
+                 FROM entries
+                 WHERE account_id = :account_id
+                   AND date_created <= :at
+                 ORDER BY seq DESC
+                 LIMIT 1), 0) AS balance
\ No newline at end of file
@@ -0,0 +1,2 @@
+SELECT count(1)
+FROM accounts
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO accounts
+    (account_id, org_id, owner, currency, balance, date_created, date_updated)
+VALUES (:account_id, :org_id, :owner, :currency, :balance, :date_created, :date_updated)
\ No newline at end of file
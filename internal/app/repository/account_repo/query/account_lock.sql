@@ -0,0 +1,5 @@
+SELECT account_id
+FROM accounts
+WHERE account_id IN (:from_account_id, :to_account_id)
+ORDER BY account_id
+FOR UPDATE
\ No newline at end of file
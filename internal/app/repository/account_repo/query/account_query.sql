@@ -0,0 +1,8 @@
+SELECT account_id,
+       org_id,
+       owner,
+       currency,
+       balance,
+       date_created,
+       date_updated
+FROM accounts
\ No newline at end of file
@@ -0,0 +1,10 @@
+SELECT account_id,
+       org_id,
+       owner,
+       currency,
+       balance,
+       date_created,
+       date_updated
+FROM accounts
+WHERE account_id = :account_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
@@ -0,0 +1,26 @@
+WITH created AS (
+    INSERT INTO accounts
+        (account_id, org_id, owner, currency, balance, date_created, date_updated)
+        VALUES (:account_id, :org_id, NULL, :currency, 0, :date_created, :date_created)
+        ON CONFLICT (org_id, currency) WHERE owner IS NULL DO NOTHING
+        RETURNING account_id, org_id, owner, currency, balance, date_created, date_updated)
+SELECT account_id,
+       org_id,
+       owner,
+       currency,
+       balance,
+       date_created,
+       date_updated
+FROM created
+UNION ALL
+SELECT account_id,
+       org_id,
+       owner,
+       currency,
+       balance,
+       date_created,
+       date_updated
+FROM accounts
+WHERE org_id = :org_id
+  AND currency = :currency
+  AND owner IS NULL
\ No newline at end of file
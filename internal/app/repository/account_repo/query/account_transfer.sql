@@ -0,0 +1,37 @@
+WITH debit AS (
+    UPDATE accounts
+        SET balance = balance - CAST(:amount AS NUMERIC),
+            date_updated = :date_created
+        WHERE account_id = :from_account_id
+            AND currency = :currency
+            AND (balance >= CAST(:amount AS NUMERIC) OR owner IS NULL)
+        RETURNING org_id, balance),
+     credit AS (
+         UPDATE accounts
+             SET balance = balance + CAST(:amount AS NUMERIC),
+                 date_updated = :date_created
+             WHERE account_id = :to_account_id
+                 AND currency = :currency
+                 AND EXISTS (SELECT 1 FROM debit)
+             RETURNING balance),
+     transfer AS (
+         INSERT INTO transfers
+             (transfer_id, org_id, from_account_id, to_account_id, amount, currency, date_created)
+             SELECT :transfer_id, debit.org_id, :from_account_id, :to_account_id, CAST(:amount AS NUMERIC), :currency, :date_created
+             FROM debit,
+                  credit
+             RETURNING transfer_id, org_id),
+     entry AS (
+         INSERT INTO entries
+             (entry_id, org_id, account_id, transfer_id, amount, balance, date_created)
+             SELECT :debit_entry_id, transfer.org_id, :from_account_id, transfer.transfer_id, -CAST(:amount AS NUMERIC), debit.balance, :date_created
+             FROM transfer,
+                  debit
+             UNION ALL
+             SELECT :credit_entry_id, transfer.org_id, :to_account_id, transfer.transfer_id, CAST(:amount AS NUMERIC), credit.balance, :date_created
+             FROM transfer,
+                  credit)
+SELECT debit.balance  AS debit_balance,
+       credit.balance AS credit_balance
+FROM debit,
+     credit
\ No newline at end of file
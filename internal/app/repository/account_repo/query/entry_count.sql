@@ -0,0 +1,2 @@
+SELECT count(1)
+FROM entries AS e
\ No newline at end of file
@@ -0,0 +1,9 @@
+SELECT e.entry_id,
+       e.account_id,
+       e.transfer_id,
+       e.amount,
+       e.balance,
+       a.currency,
+       e.date_created
+FROM entries AS e
+         JOIN accounts AS a ON a.account_id = e.account_id
\ No newline at end of file
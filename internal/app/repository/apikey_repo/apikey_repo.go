@@ -0,0 +1,172 @@
+// Package apikey_repo contains service account and api key related CRUD functionality.
+package apikey_repo
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/apikey"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/service_account_create.sql
+	serviceAccountCreateSql string
+	//go:embed query/service_account_query.sql
+	serviceAccountQuerySql string
+	//go:embed query/service_account_query_by_id.sql
+	serviceAccountQueryByIdSql string
+	//go:embed query/api_key_create.sql
+	apiKeyCreateSql string
+	//go:embed query/api_key_update.sql
+	apiKeyUpdateSql string
+	//go:embed query/api_key_query.sql
+	apiKeyQuerySql string
+	//go:embed query/api_key_query_by_id.sql
+	apiKeyQueryByIdSql string
+	//go:embed query/api_key_query_by_prefix.sql
+	apiKeyQueryByPrefixSql string
+)
+
+// Store manages the set of APIs for service account and api key database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// CreateServiceAccount inserts a new service account into the database.
+func (s *Store) CreateServiceAccount(ctx context.Context, sa apikey.ServiceAccount) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, serviceAccountCreateSql, toServiceAccountDB(sa)); err != nil {
+		if errors.Is(err, pgsql.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", apikey.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryServiceAccounts retrieves all the service accounts from the database.
+func (s *Store) QueryServiceAccounts(ctx context.Context) ([]apikey.ServiceAccount, error) {
+	data := struct {
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+	}{
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+
+	var dbSAs []serviceAccountDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, serviceAccountQuerySql, data, &dbSAs); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toServiceAccountsDomain(dbSAs)
+}
+
+// QueryServiceAccountByID gets the specified service account from the database.
+func (s *Store) QueryServiceAccountByID(ctx context.Context, serviceAccountID uuid.UUID) (apikey.ServiceAccount, error) {
+	data := struct {
+		ID         string     `db:"service_account_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+	}{
+		ID:         serviceAccountID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+
+	var dbSA serviceAccountDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, serviceAccountQueryByIdSql, data, &dbSA); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return apikey.ServiceAccount{}, fmt.Errorf("db: %w", apikey.ErrAccountNotFound)
+		}
+		return apikey.ServiceAccount{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toServiceAccountDomain(dbSA)
+}
+
+// CreateKey inserts a new api key into the database.
+func (s *Store) CreateKey(ctx context.Context, key apikey.Key) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, apiKeyCreateSql, toKeyDB(key)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateKey replaces an api key in the database.
+func (s *Store) UpdateKey(ctx context.Context, key apikey.Key) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, apiKeyUpdateSql, toKeyDB(key)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryKeys retrieves the api keys of the specified service account.
+func (s *Store) QueryKeys(ctx context.Context, serviceAccountID uuid.UUID) ([]apikey.Key, error) {
+	data := struct {
+		ServiceAccountID string `db:"service_account_id"`
+	}{
+		ServiceAccountID: serviceAccountID.String(),
+	}
+
+	var dbKeys []keyDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, apiKeyQuerySql, data, &dbKeys); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toKeysDomain(dbKeys)
+}
+
+// QueryKeyByID gets the specified api key from the database.
+func (s *Store) QueryKeyByID(ctx context.Context, keyID uuid.UUID) (apikey.Key, error) {
+	data := struct {
+		ID string `db:"api_key_id"`
+	}{
+		ID: keyID.String(),
+	}
+
+	var dbKey keyDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, apiKeyQueryByIdSql, data, &dbKey); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return apikey.Key{}, fmt.Errorf("db: %w", apikey.ErrNotFound)
+		}
+		return apikey.Key{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toKeyDomain(dbKey)
+}
+
+// QueryKeyByPrefix gets the api key identified by the specified prefix.
+func (s *Store) QueryKeyByPrefix(ctx context.Context, prefix string) (apikey.Key, error) {
+	data := struct {
+		Prefix string `db:"prefix"`
+	}{
+		Prefix: prefix,
+	}
+
+	var dbKey keyDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, apiKeyQueryByPrefixSql, data, &dbKey); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return apikey.Key{}, fmt.Errorf("db: %w", apikey.ErrNotFound)
+		}
+		return apikey.Key{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toKeyDomain(dbKey)
+}
@@ -0,0 +1,221 @@
+package apikey_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/apikey"
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+func Test_APIKey(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_APIKey")
+
+	sa, err := db.Core.APIKey.CreateServiceAccount(context.Background(), apikey.NewServiceAccount{
+		Name:        "billing-exporter",
+		Description: "Exports invoices",
+		Roles:       []role.Role{role.Admin},
+		CreatedBy:   uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, create(db.Core, sa), "create")
+	unitest.Run(t, authenticate(db.Core, sa), "authenticate")
+}
+
+// =============================================================================
+
+func create(busDomain dbtest.Core, sa apikey.ServiceAccount) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "duplicate",
+			ExpResp: apikey.ErrUniqueName,
+			ExcFunc: func(ctx context.Context) any {
+				nsa := apikey.NewServiceAccount{
+					Name:  sa.Name,
+					Roles: []role.Role{role.User},
+				}
+				_, err := busDomain.APIKey.CreateServiceAccount(ctx, nsa)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "key",
+			ExpResp: []string{"audit:read"},
+			ExcFunc: func(ctx context.Context) any {
+				nk := apikey.NewKey{
+					ServiceAccountID: sa.ID,
+					Name:             "ci",
+					Scopes:           []permission.Permission{permission.AuditRead},
+				}
+				key, rawKey, err := busDomain.APIKey.CreateKey(ctx, nk)
+				if err != nil {
+					return err
+				}
+
+				if !strings.Contains(rawKey, key.Prefix) || strings.Contains(rawKey, key.KeyHash) {
+					return errors.New("expected the raw key to carry the prefix but not the hash")
+				}
+
+				keys, err := busDomain.APIKey.QueryKeys(ctx, sa.ID)
+				if err != nil {
+					return err
+				}
+				if len(keys) != 1 || keys[0].ExpiresAt != nil {
+					return fmt.Errorf("expected one key without expiry, got %+v", keys)
+				}
+
+				return permission.ParseToString(keys[0].Scopes)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func authenticate(busDomain dbtest.Core, sa apikey.ServiceAccount) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "valid",
+			ExpResp: sa.ID,
+			ExcFunc: func(ctx context.Context) any {
+				nk := apikey.NewKey{
+					ServiceAccountID: sa.ID,
+					Name:             "valid",
+					Scopes:           []permission.Permission{permission.UserRead},
+				}
+				_, rawKey, err := busDomain.APIKey.CreateKey(ctx, nk)
+				if err != nil {
+					return err
+				}
+
+				got, key, err := busDomain.APIKey.Authenticate(ctx, rawKey)
+				if err != nil {
+					return err
+				}
+
+				key, err = busDomain.APIKey.QueryKeyByID(ctx, key.ID)
+				if err != nil {
+					return err
+				}
+				if key.LastUsedAt == nil {
+					return errors.New("expected the last use to be recorded")
+				}
+
+				return got.ID
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "tampered",
+			ExpResp: apikey.ErrInvalidKey,
+			ExcFunc: func(ctx context.Context) any {
+				nk := apikey.NewKey{
+					ServiceAccountID: sa.ID,
+					Name:             "tampered",
+					Scopes:           []permission.Permission{permission.UserRead},
+				}
+				_, rawKey, err := busDomain.APIKey.CreateKey(ctx, nk)
+				if err != nil {
+					return err
+				}
+
+				_, _, err = busDomain.APIKey.Authenticate(ctx, rawKey+"x")
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "revoked",
+			ExpResp: apikey.ErrRevoked,
+			ExcFunc: func(ctx context.Context) any {
+				nk := apikey.NewKey{
+					ServiceAccountID: sa.ID,
+					Name:             "revoked",
+					Scopes:           []permission.Permission{permission.UserRead},
+				}
+				key, rawKey, err := busDomain.APIKey.CreateKey(ctx, nk)
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.APIKey.RevokeKey(ctx, key); err != nil {
+					return err
+				}
+
+				_, _, err = busDomain.APIKey.Authenticate(ctx, rawKey)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "expired",
+			ExpResp: apikey.ErrExpired,
+			ExcFunc: func(ctx context.Context) any {
+				expiresAt := time.Now().Add(-time.Hour)
+				nk := apikey.NewKey{
+					ServiceAccountID: sa.ID,
+					Name:             "expired",
+					Scopes:           []permission.Permission{permission.UserRead},
+					ExpiresAt:        &expiresAt,
+				}
+				_, rawKey, err := busDomain.APIKey.CreateKey(ctx, nk)
+				if err != nil {
+					return err
+				}
+
+				_, _, err = busDomain.APIKey.Authenticate(ctx, rawKey)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
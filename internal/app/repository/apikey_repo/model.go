@@ -0,0 +1,161 @@
+package apikey_repo
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/apikey"
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/pkg/pgsql/dbarray"
+)
+
+type serviceAccountDB struct {
+	ID          uuid.UUID      `db:"service_account_id"`
+	OrgID       uuid.UUID      `db:"org_id"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Roles       dbarray.String `db:"roles"`
+	Enabled     bool           `db:"enabled"`
+	CreatedBy   uuid.UUID      `db:"created_by"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toServiceAccountDB(sa apikey.ServiceAccount) serviceAccountDB {
+	return serviceAccountDB{
+		ID:          sa.ID,
+		OrgID:       sa.OrgID,
+		Name:        sa.Name,
+		Description: sa.Description,
+		Roles:       role.ParseToString(sa.Roles),
+		Enabled:     sa.Enabled,
+		CreatedBy:   sa.CreatedBy,
+		DateCreated: sa.DateCreated.UTC(),
+		DateUpdated: sa.DateUpdated.UTC(),
+	}
+}
+
+func toServiceAccountDomain(db serviceAccountDB) (apikey.ServiceAccount, error) {
+	roles, err := role.ParseMany(db.Roles)
+	if err != nil {
+		return apikey.ServiceAccount{}, fmt.Errorf("parse: %w", err)
+	}
+
+	sa := apikey.ServiceAccount{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		Name:        db.Name,
+		Description: db.Description,
+		Roles:       roles,
+		Enabled:     db.Enabled,
+		CreatedBy:   db.CreatedBy,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return sa, nil
+}
+
+func toServiceAccountsDomain(dbs []serviceAccountDB) ([]apikey.ServiceAccount, error) {
+	sas := make([]apikey.ServiceAccount, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		sas[i], err = toServiceAccountDomain(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sas, nil
+}
+
+// =============================================================================
+
+type keyDB struct {
+	ID               uuid.UUID      `db:"api_key_id"`
+	ServiceAccountID uuid.UUID      `db:"service_account_id"`
+	Name             string         `db:"name"`
+	Prefix           string         `db:"prefix"`
+	KeyHash          string         `db:"key_hash"`
+	Scopes           dbarray.String `db:"scopes"`
+	ExpiresAt        sql.NullTime   `db:"expires_at"`
+	LastUsedAt       sql.NullTime   `db:"last_used_at"`
+	Revoked          bool           `db:"revoked"`
+	DateCreated      time.Time      `db:"date_created"`
+	DateUpdated      time.Time      `db:"date_updated"`
+}
+
+func toKeyDB(key apikey.Key) keyDB {
+	return keyDB{
+		ID:               key.ID,
+		ServiceAccountID: key.ServiceAccountID,
+		Name:             key.Name,
+		Prefix:           key.Prefix,
+		KeyHash:          key.KeyHash,
+		Scopes:           permission.ParseToString(key.Scopes),
+		ExpiresAt:        toNullTime(key.ExpiresAt),
+		LastUsedAt:       toNullTime(key.LastUsedAt),
+		Revoked:          key.Revoked,
+		DateCreated:      key.DateCreated.UTC(),
+		DateUpdated:      key.DateUpdated.UTC(),
+	}
+}
+
+func toKeyDomain(db keyDB) (apikey.Key, error) {
+	scopes, err := permission.ParseMany(db.Scopes)
+	if err != nil {
+		return apikey.Key{}, fmt.Errorf("parse: %w", err)
+	}
+
+	key := apikey.Key{
+		ID:               db.ID,
+		ServiceAccountID: db.ServiceAccountID,
+		Name:             db.Name,
+		Prefix:           db.Prefix,
+		KeyHash:          db.KeyHash,
+		Scopes:           scopes,
+		ExpiresAt:        toTimePtr(db.ExpiresAt),
+		LastUsedAt:       toTimePtr(db.LastUsedAt),
+		Revoked:          db.Revoked,
+		DateCreated:      db.DateCreated.In(time.Local),
+		DateUpdated:      db.DateUpdated.In(time.Local),
+	}
+
+	return key, nil
+}
+
+func toKeysDomain(dbs []keyDB) ([]apikey.Key, error) {
+	keys := make([]apikey.Key, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		keys[i], err = toKeyDomain(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func toTimePtr(nt sql.NullTime) *time.Time {
+	if !nt.Valid {
+		return nil
+	}
+
+	t := nt.Time.In(time.Local)
+	return &t
+}
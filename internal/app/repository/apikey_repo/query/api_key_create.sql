@@ -0,0 +1,3 @@
+INSERT INTO api_keys
+    (api_key_id, service_account_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked, date_created, date_updated)
+VALUES (:api_key_id, :service_account_id, :name, :prefix, :key_hash, :scopes, :expires_at, :last_used_at, :revoked, :date_created, :date_updated)
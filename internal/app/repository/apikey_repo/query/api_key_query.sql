@@ -0,0 +1,14 @@
+SELECT api_key_id,
+       service_account_id,
+       name,
+       prefix,
+       key_hash,
+       scopes,
+       expires_at,
+       last_used_at,
+       revoked,
+       date_created,
+       date_updated
+FROM api_keys
+WHERE service_account_id = :service_account_id
+ORDER BY date_created
@@ -0,0 +1,13 @@
+SELECT api_key_id,
+       service_account_id,
+       name,
+       prefix,
+       key_hash,
+       scopes,
+       expires_at,
+       last_used_at,
+       revoked,
+       date_created,
+       date_updated
+FROM api_keys
+WHERE prefix = :prefix
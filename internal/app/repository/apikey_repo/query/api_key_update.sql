@@ -0,0 +1,9 @@
+UPDATE
+    api_keys
+SET "name"         = :name,
+    "scopes"       = :scopes,
+    "expires_at"   = :expires_at,
+    "last_used_at" = :last_used_at,
+    "revoked"      = :revoked,
+    "date_updated" = :date_updated
+WHERE api_key_id = :api_key_id
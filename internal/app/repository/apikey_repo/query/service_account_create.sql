@@ -0,0 +1,3 @@
+INSERT INTO service_accounts
+    (service_account_id, org_id, name, description, roles, enabled, created_by, date_created, date_updated)
+VALUES (:service_account_id, :org_id, :name, :description, :roles, :enabled, :created_by, :date_created, :date_updated)
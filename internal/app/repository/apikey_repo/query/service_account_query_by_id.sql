@@ -0,0 +1,12 @@
+SELECT service_account_id,
+       org_id,
+       name,
+       description,
+       roles,
+       enabled,
+       created_by,
+       date_created,
+       date_updated
+FROM service_accounts
+WHERE service_account_id = :service_account_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
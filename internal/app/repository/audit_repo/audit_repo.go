@@ -40,6 +40,22 @@ func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
 	}
 }
 
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (audit.Storer, error) {
+	ec, err := pgsql.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
 // Create inserts a new auditDB record into the database.
 func (s *Store) Create(ctx context.Context, a audit.Audit) error {
 	dbAudit, err := toDBAudit(a)
@@ -66,7 +82,7 @@ func (s *Store) Query(
 	}
 
 	buf := bytes.NewBufferString(auditQuerySql)
-	applyFilter(filter, data, buf)
+	applyFilter(ctx, filter, data, buf)
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -89,7 +105,7 @@ func (s *Store) Count(ctx context.Context, filter audit.QueryFilter) (int, error
 	data := map[string]any{}
 
 	buf := bytes.NewBufferString(auditCountSql)
-	applyFilter(filter, data, buf)
+	applyFilter(ctx, filter, data, buf)
 
 	var count struct {
 		Count int `db:"count"`
@@ -2,15 +2,22 @@ package audit_repo
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 )
 
-func applyFilter(filter audit.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+func applyFilter(ctx context.Context, filter audit.QueryFilter, data map[string]any, buf *bytes.Buffer) {
 	var wc []string
 
+	if orgID, ok := organization.Scoped(ctx); ok {
+		data["org_id"] = orgID
+		wc = append(wc, "org_id = :org_id")
+	}
+
 	if filter.ObjID != nil {
 		data["obj_id"] = filter.ObjID
 		wc = append(wc, "obj_id = :obj_id")
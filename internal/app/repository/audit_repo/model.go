@@ -15,6 +15,7 @@ import (
 
 type auditDB struct {
 	ID        uuid.UUID          `db:"id"`
+	OrgID     uuid.UUID          `db:"org_id"`
 	ObjID     uuid.UUID          `db:"obj_id"`
 	ObjEntity string             `db:"obj_entity"`
 	ObjName   string             `db:"obj_name"`
@@ -28,6 +29,7 @@ type auditDB struct {
 func toDBAudit(bus audit.Audit) (auditDB, error) {
 	db := auditDB{
 		ID:        bus.ID,
+		OrgID:     bus.OrgID,
 		ObjID:     bus.ObjID,
 		ObjEntity: bus.ObjEntity.String(),
 		ObjName:   bus.ObjName.String(),
@@ -54,6 +56,7 @@ func toDomainAudit(db auditDB) (audit.Audit, error) {
 
 	bus := audit.Audit{
 		ID:        db.ID,
+		OrgID:     db.OrgID,
 		ObjID:     db.ObjID,
 		ObjEntity: ent,
 		ObjName:   n,
@@ -1,3 +1,3 @@
 INSERT INTO audit
-(id, obj_id, obj_entity, obj_name, actor_id, action, data, message, timestamp)
-VALUES (:id, :obj_id, :obj_entity, :obj_name, :actor_id, :action, :data, :message, :timestamp)
\ No newline at end of file
+(id, org_id, obj_id, obj_entity, obj_name, actor_id, action, data, message, timestamp)
+VALUES (:id, :org_id, :obj_id, :obj_entity, :obj_name, :actor_id, :action, :data, :message, :timestamp)
\ No newline at end of file
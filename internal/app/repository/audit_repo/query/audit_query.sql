@@ -1,4 +1,4 @@
 SELECT
-    id, obj_id, obj_entity, obj_name, actor_id, action, data, message, timestamp
+    id, org_id, obj_id, obj_entity, obj_name, actor_id, action, data, message, timestamp
 FROM
     audit
\ No newline at end of file
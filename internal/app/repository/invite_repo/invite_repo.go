@@ -0,0 +1,110 @@
+// Package invite_repo contains invite related CRUD functionality.
+package invite_repo
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/invite"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/invite_create.sql
+	inviteCreateSql string
+	//go:embed query/invite_accept.sql
+	inviteAcceptSql string
+	//go:embed query/invite_query_by_id.sql
+	inviteQueryByIdSql string
+)
+
+// Store manages the set of APIs for invite database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (invite.Storer, error) {
+	ec, err := pgsql.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create inserts a new invite into the database.
+func (s *Store) Create(ctx context.Context, inv invite.Invite) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, inviteCreateSql, toInviteDB(inv)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Accept marks the invite as accepted. The update only applies to an invite
+// still pending, not accepted yet nor expired, so it can't be accepted twice
+// by concurrent requests.
+func (s *Store) Accept(ctx context.Context, inviteID uuid.UUID, acceptedAt time.Time) error {
+	data := struct {
+		ID         string    `db:"invite_id"`
+		AcceptedAt time.Time `db:"accepted_at"`
+	}{
+		ID:         inviteID.String(),
+		AcceptedAt: acceptedAt.UTC(),
+	}
+
+	var dest struct {
+		ID uuid.UUID `db:"invite_id"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, inviteAcceptSql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", invite.ErrAccepted)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified invite from the database.
+func (s *Store) QueryByID(ctx context.Context, inviteID uuid.UUID) (invite.Invite, error) {
+	data := struct {
+		ID string `db:"invite_id"`
+	}{
+		ID: inviteID.String(),
+	}
+
+	var dbInv inviteDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, inviteQueryByIdSql, data, &dbInv); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return invite.Invite{}, fmt.Errorf("db: %w", invite.ErrNotFound)
+		}
+		return invite.Invite{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toInviteDomain(dbInv)
+}
@@ -0,0 +1,200 @@
+package invite_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"testing"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/invite"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+)
+
+func Test_Invite(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Invite")
+
+	sd, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, accept(db, sd), "accept")
+	unitest.Run(t, verify(db.Core, sd), "verify")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.Core) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := usercore.TestSeedUsers(ctx, 1, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	admins, err := usercore.TestSeedUsers(ctx, 1, role.Admin, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding admins : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users:  []unitest.User{{User: usrs[0]}},
+		Admins: []unitest.User{{User: admins[0]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func accept(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	busDomain := db.Core
+	admin := sd.Admins[0].User
+
+	table := []unitest.Table{
+		{
+			Name:    "single-use",
+			ExpResp: invite.ErrAccepted,
+			ExcFunc: func(ctx context.Context) any {
+				inv, err := busDomain.Invite.Create(ctx, invite.NewInvite{
+					Email:     mail.Address{Address: "invitee@example.com"},
+					Roles:     []role.Role{role.User},
+					InvitedBy: admin.ID,
+				})
+				if err != nil {
+					return err
+				}
+
+				pending, err := busDomain.Invite.QueryPending(ctx, inv.ID)
+				if err != nil {
+					return err
+				}
+				if pending.Email.Address != inv.Email.Address || len(pending.Roles) != 1 {
+					return fmt.Errorf("expected the invite of %s: got %+v", inv.Email.Address, pending)
+				}
+
+				if _, err := busDomain.Invite.Accept(ctx, pending); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Invite.Accept(ctx, pending); !errors.Is(err, invite.ErrAccepted) {
+					return fmt.Errorf("expected accepted: %w", err)
+				}
+
+				_, err = busDomain.Invite.QueryPending(ctx, inv.ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "rollback",
+			ExpResp: nil,
+			ExcFunc: func(ctx context.Context) any {
+				inv, err := busDomain.Invite.Create(ctx, invite.NewInvite{
+					Email:     mail.Address{Address: "rollback@example.com"},
+					Roles:     []role.Role{role.User},
+					InvitedBy: admin.ID,
+				})
+				if err != nil {
+					return err
+				}
+
+				// The invite stays pending when the transaction accepting
+				// it is rolled back, like when the user can't be created.
+				tx, err := db.DB.Beginx()
+				if err != nil {
+					return err
+				}
+
+				inviteTx, err := busDomain.Invite.NewWithTx(tx)
+				if err != nil {
+					return err
+				}
+
+				if _, err := inviteTx.Accept(ctx, inv); err != nil {
+					return err
+				}
+
+				if err := tx.Rollback(); err != nil {
+					return err
+				}
+
+				_, err = busDomain.Invite.QueryPending(ctx, inv.ID)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				if got != nil {
+					return fmt.Sprintf("got %v, exp a pending invite", got)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func verify(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Users[0].User
+
+	table := []unitest.Table{
+		{
+			Name:    "email-change",
+			ExpResp: false,
+			ExcFunc: func(ctx context.Context) any {
+				usr, err := busDomain.User.VerifyEmail(ctx, usr)
+				if err != nil {
+					return err
+				}
+
+				got, err := busDomain.User.QueryByID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+				if !got.EmailVerified {
+					return errors.New("expected the email verified")
+				}
+
+				// Changing the email requires to verify it again.
+				nme := name.MustParse("Verified")
+				email := mail.Address{Address: "changed@example.com"}
+				if _, err := busDomain.User.Update(ctx, got, user.UpdateUser{Name: &nme, Email: &email}); err != nil {
+					return err
+				}
+
+				got, err = busDomain.User.QueryByID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return got.EmailVerified
+			},
+			CmpFunc: func(got any, exp any) string {
+				if got != exp {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func cmpError(got any, exp any) string {
+	err, _ := got.(error)
+	if !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", got, exp)
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package invite_repo
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/invite"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/pkg/pgsql/dbarray"
+)
+
+type inviteDB struct {
+	ID          uuid.UUID      `db:"invite_id"`
+	OrgID       uuid.UUID      `db:"org_id"`
+	Email       string         `db:"email"`
+	Roles       dbarray.String `db:"roles"`
+	InvitedBy   uuid.UUID      `db:"invited_by"`
+	ExpiresAt   time.Time      `db:"expires_at"`
+	AcceptedAt  sql.NullTime   `db:"accepted_at"`
+	DateCreated time.Time      `db:"date_created"`
+}
+
+func toInviteDB(inv invite.Invite) inviteDB {
+	db := inviteDB{
+		ID:          inv.ID,
+		OrgID:       inv.OrgID,
+		Email:       inv.Email.Address,
+		Roles:       role.ParseToString(inv.Roles),
+		InvitedBy:   inv.InvitedBy,
+		ExpiresAt:   inv.ExpiresAt.UTC(),
+		DateCreated: inv.DateCreated.UTC(),
+	}
+
+	if inv.AcceptedAt != nil {
+		db.AcceptedAt = sql.NullTime{Time: inv.AcceptedAt.UTC(), Valid: true}
+	}
+
+	return db
+}
+
+func toInviteDomain(db inviteDB) (invite.Invite, error) {
+	roles, err := role.ParseMany(db.Roles)
+	if err != nil {
+		return invite.Invite{}, fmt.Errorf("parse: %w", err)
+	}
+
+	inv := invite.Invite{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		Email:       mail.Address{Address: db.Email},
+		Roles:       roles,
+		InvitedBy:   db.InvitedBy,
+		ExpiresAt:   db.ExpiresAt.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	if db.AcceptedAt.Valid {
+		acceptedAt := db.AcceptedAt.Time.In(time.Local)
+		inv.AcceptedAt = &acceptedAt
+	}
+
+	return inv, nil
+}
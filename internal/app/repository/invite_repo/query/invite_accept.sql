@@ -0,0 +1,7 @@
+UPDATE
+    invites
+SET "accepted_at" = :accepted_at
+WHERE invite_id = :invite_id
+  AND accepted_at IS NULL
+  AND expires_at > :accepted_at
+RETURNING invite_id
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO invites
+    (invite_id, org_id, email, roles, invited_by, expires_at, accepted_at, date_created)
+VALUES (:invite_id, :org_id, :email, :roles, :invited_by, :expires_at, :accepted_at, :date_created)
\ No newline at end of file
@@ -0,0 +1,10 @@
+SELECT invite_id,
+       org_id,
+       email,
+       roles,
+       invited_by,
+       expires_at,
+       accepted_at,
+       date_created
+FROM invites
+WHERE invite_id = :invite_id
\ No newline at end of file
@@ -0,0 +1,115 @@
+// Package lockout_repo contains the failed login attempts related CRUD functionality.
+package lockout_repo
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/lockout"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/login_attempts_query_by_key.sql
+	attemptsQueryByKeySql string
+	//go:embed query/login_attempts_fail.sql
+	attemptsFailSql string
+	//go:embed query/login_attempts_lock.sql
+	attemptsLockSql string
+	//go:embed query/login_attempts_delete.sql
+	attemptsDeleteSql string
+)
+
+// Store manages the set of APIs for login attempts database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// QueryByKey gets the failed login attempts for the specified key.
+func (s *Store) QueryByKey(ctx context.Context, key string) (lockout.Attempts, error) {
+	data := struct {
+		Key string `db:"attempt_key"`
+	}{
+		Key: key,
+	}
+
+	var dbAtt attemptsDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, attemptsQueryByKeySql, data, &dbAtt); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return lockout.Attempts{}, fmt.Errorf("db: %w", lockout.ErrNotFound)
+		}
+		return lockout.Attempts{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toAttemptsDomain(dbAtt), nil
+}
+
+// Fail counts a failed login for the specified key in a single statement, so
+// concurrent attempts are all counted. Failures older than since are
+// forgotten and the count starts over.
+func (s *Store) Fail(ctx context.Context, key string, now time.Time, since time.Time) (lockout.Attempts, error) {
+	data := struct {
+		Key   string    `db:"attempt_key"`
+		Now   time.Time `db:"now"`
+		Since time.Time `db:"since"`
+	}{
+		Key:   key,
+		Now:   now.UTC(),
+		Since: since.UTC(),
+	}
+
+	var dbAtt attemptsDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, attemptsFailSql, data, &dbAtt); err != nil {
+		return lockout.Attempts{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toAttemptsDomain(dbAtt), nil
+}
+
+// Lock locks the specified key until the given time.
+func (s *Store) Lock(ctx context.Context, key string, until time.Time) error {
+	data := struct {
+		Key         string    `db:"attempt_key"`
+		LockedUntil time.Time `db:"locked_until"`
+	}{
+		Key:         key,
+		LockedUntil: until.UTC(),
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, attemptsLockSql, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete forgets the failed login attempts for the specified key.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	data := struct {
+		Key string `db:"attempt_key"`
+	}{
+		Key: key,
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, attemptsDeleteSql, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package lockout_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/lockout"
+)
+
+func Test_Lockout(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Lockout")
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, lock(db.Core), "lock")
+	unitest.Run(t, succeed(db.Core), "succeed")
+}
+
+// =============================================================================
+
+func lock(busDomain dbtest.Core) []unitest.Table {
+	key := lockout.EmailKey(mail.Address{Address: "Locked@Example.com"})
+
+	table := []unitest.Table{
+		{
+			Name:    "backoff",
+			ExpResp: lockout.ErrLocked,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Lockout.Check(ctx, key); err != nil {
+					return fmt.Errorf("expected no lockout before failing: %w", err)
+				}
+
+				if err := busDomain.Lockout.Fail(ctx, key); err != nil {
+					return err
+				}
+
+				return busDomain.Lockout.Check(ctx, key)
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "locked",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				for range 4 {
+					if err := busDomain.Lockout.Fail(ctx, key); err != nil {
+						return err
+					}
+				}
+
+				att, err := busDomain.Lockout.QueryByKey(ctx, key)
+				if err != nil {
+					return err
+				}
+				if att.Failures != 5 || att.LockedUntil == nil {
+					return fmt.Errorf("expected a lockout after 5 failures: %+v", att)
+				}
+
+				return auditCount(ctx, busDomain, "locked")
+			},
+			CmpFunc: cmpInt,
+		},
+		{
+			Name:    "unlock",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				userID, adminID := uuid.New(), uuid.New()
+
+				if err := busDomain.Lockout.Unlock(ctx, key, userID, adminID); err != nil {
+					return err
+				}
+
+				if err := busDomain.Lockout.Check(ctx, key); err != nil {
+					return fmt.Errorf("expected no lockout after unlock: %w", err)
+				}
+
+				if err := busDomain.Lockout.Unlock(ctx, key, userID, adminID); !errors.Is(err, lockout.ErrNotFound) {
+					return fmt.Errorf("expected not found: %w", err)
+				}
+
+				return auditCount(ctx, busDomain, "unlocked")
+			},
+			CmpFunc: cmpInt,
+		},
+	}
+
+	return table
+}
+
+func succeed(busDomain dbtest.Core) []unitest.Table {
+	key := lockout.EmailKey(mail.Address{Address: "succeed@example.com"})
+	ipKey := lockout.IPKey("192.0.2.10")
+
+	table := []unitest.Table{
+		{
+			Name:    "reset",
+			ExpResp: lockout.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				if err := busDomain.Lockout.Fail(ctx, key, ipKey); err != nil {
+					return err
+				}
+
+				// An ip address is never slowed down, only locked.
+				if err := busDomain.Lockout.Check(ctx, ipKey); err != nil {
+					return fmt.Errorf("expected no backoff for an ip: %w", err)
+				}
+
+				if err := busDomain.Lockout.Succeed(ctx, key); err != nil {
+					return err
+				}
+
+				_, err := busDomain.Lockout.QueryByKey(ctx, key)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+// =============================================================================
+
+func auditCount(ctx context.Context, busDomain dbtest.Core, action string) any {
+	ent := entity.Auth
+	filter := audit.QueryFilter{
+		ObjEntity: &ent,
+		Action:    &action,
+	}
+
+	n, err := busDomain.Audit.Count(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return n
+}
+
+func cmpError(got any, exp any) string {
+	err, _ := got.(error)
+	if !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", got, exp)
+	}
+	return ""
+}
+
+func cmpInt(got any, exp any) string {
+	if got != exp {
+		return fmt.Sprintf("got %v, exp %v", got, exp)
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package lockout_repo
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/lockout"
+)
+
+type attemptsDB struct {
+	Key         string       `db:"attempt_key"`
+	Failures    int          `db:"failures"`
+	LastFailure time.Time    `db:"last_failure"`
+	LockedUntil sql.NullTime `db:"locked_until"`
+}
+
+func toAttemptsDomain(db attemptsDB) lockout.Attempts {
+	att := lockout.Attempts{
+		Key:         db.Key,
+		Failures:    db.Failures,
+		LastFailure: db.LastFailure.In(time.Local),
+	}
+
+	if db.LockedUntil.Valid {
+		until := db.LockedUntil.Time.In(time.Local)
+		att.LockedUntil = &until
+	}
+
+	return att
+}
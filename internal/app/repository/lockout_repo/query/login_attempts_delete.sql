@@ -0,0 +1,3 @@
+DELETE
+FROM login_attempts
+WHERE attempt_key = :attempt_key
@@ -0,0 +1,14 @@
+INSERT INTO login_attempts
+    (attempt_key, failures, last_failure, locked_until)
+VALUES (:attempt_key, 1, :now, NULL)
+ON CONFLICT (attempt_key) DO UPDATE
+    SET failures     = CASE
+                           WHEN login_attempts.last_failure < :since THEN 1
+                           ELSE login_attempts.failures + 1
+        END,
+        locked_until = CASE
+                           WHEN login_attempts.last_failure < :since THEN NULL
+                           ELSE login_attempts.locked_until
+        END,
+        last_failure = :now
+RETURNING attempt_key, failures, last_failure, locked_until
@@ -0,0 +1,4 @@
+UPDATE
+    login_attempts
+SET "locked_until" = :locked_until
+WHERE attempt_key = :attempt_key
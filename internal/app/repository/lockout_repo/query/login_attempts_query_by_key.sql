@@ -0,0 +1,6 @@
+SELECT attempt_key,
+       failures,
+       last_failure,
+       locked_until
+FROM login_attempts
+WHERE attempt_key = :attempt_key
@@ -0,0 +1,77 @@
+// Package mfa_repo contains multi-factor authentication related CRUD functionality.
+package mfa_repo
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/mfa"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/mfa_upsert.sql
+	mfaUpsertSql string
+	//go:embed query/mfa_update.sql
+	mfaUpdateSql string
+	//go:embed query/mfa_query_by_user_id.sql
+	mfaQueryByUserIdSql string
+)
+
+// Store manages the set of APIs for mfa database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Upsert inserts the mfa enrolment of a user, replacing any previous one.
+func (s *Store) Upsert(ctx context.Context, m mfa.MFA) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, mfaUpsertSql, toMFADB(m)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the state of an mfa enrolment in the database.
+func (s *Store) Update(ctx context.Context, m mfa.MFA) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, mfaUpdateSql, toMFADB(m)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByUserID gets the mfa enrolment of the specified user.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) (mfa.MFA, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID.String(),
+	}
+
+	var dbMFA mfaDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, mfaQueryByUserIdSql, data, &dbMFA); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return mfa.MFA{}, fmt.Errorf("db: %w", mfa.ErrNotFound)
+		}
+		return mfa.MFA{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toMFADomain(dbMFA), nil
+}
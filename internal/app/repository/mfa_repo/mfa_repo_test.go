@@ -0,0 +1,140 @@
+package mfa_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/mfa"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/totp"
+)
+
+func Test_MFA(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_MFA")
+
+	sd, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, enroll(db.Core, sd), "enroll")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.Core) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := usercore.TestSeedUsers(ctx, 1, role.Admin, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Admins: []unitest.User{{User: usrs[0]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func enroll(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Admins[0].User
+
+	var recoveryCodes []string
+
+	table := []unitest.Table{
+		{
+			Name:    "confirm",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := busDomain.MFA.Confirm(ctx, usr.ID, "123456"); !errors.Is(err, mfa.ErrNotFound) {
+					return fmt.Errorf("expected not enrolled: %w", err)
+				}
+
+				enr, err := busDomain.MFA.Enroll(ctx, usr)
+				if err != nil {
+					return err
+				}
+
+				enrolled, err := busDomain.MFA.IsEnrolled(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+				if enrolled {
+					return errors.New("expected no enrolment before the confirmation")
+				}
+
+				code, err := totp.Code(enr.Secret, time.Now())
+				if err != nil {
+					return err
+				}
+
+				recoveryCodes, err = busDomain.MFA.Confirm(ctx, usr.ID, code)
+				if err != nil {
+					return err
+				}
+				if len(recoveryCodes) != 10 {
+					return fmt.Errorf("expected 10 recovery codes: got %d", len(recoveryCodes))
+				}
+
+				if _, err := busDomain.MFA.Enroll(ctx, usr); !errors.Is(err, mfa.ErrAlreadyEnrolled) {
+					return fmt.Errorf("expected already enrolled: %w", err)
+				}
+
+				// The code used to confirm can't be replayed.
+				if err := busDomain.MFA.Verify(ctx, usr.ID, code); !errors.Is(err, mfa.ErrInvalidCode) {
+					return fmt.Errorf("expected invalid code: %w", err)
+				}
+
+				enrolled, err = busDomain.MFA.IsEnrolled(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return enrolled
+			},
+			CmpFunc: func(got any, exp any) string {
+				if got != exp {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "recovery-code",
+			ExpResp: mfa.ErrInvalidCode,
+			ExcFunc: func(ctx context.Context) any {
+				if len(recoveryCodes) == 0 {
+					return errors.New("expected the recovery codes of the confirmation")
+				}
+
+				if err := busDomain.MFA.Verify(ctx, usr.ID, recoveryCodes[0]); err != nil {
+					return err
+				}
+
+				return busDomain.MFA.Verify(ctx, usr.ID, recoveryCodes[0])
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,49 @@
+package mfa_repo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/mfa"
+	"github.com/Housiadas/backend-system/pkg/pgsql/dbarray"
+)
+
+type mfaDB struct {
+	UserID        uuid.UUID      `db:"user_id"`
+	Secret        string         `db:"secret"`
+	Confirmed     bool           `db:"confirmed"`
+	LastUsedStep  int64          `db:"last_used_step"`
+	RecoveryCodes dbarray.String `db:"recovery_codes"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateUpdated   time.Time      `db:"date_updated"`
+}
+
+func toMFADB(m mfa.MFA) mfaDB {
+	codes := m.RecoveryCodes
+	if codes == nil {
+		codes = []string{}
+	}
+
+	return mfaDB{
+		UserID:        m.UserID,
+		Secret:        m.Secret,
+		Confirmed:     m.Confirmed,
+		LastUsedStep:  m.LastUsedStep,
+		RecoveryCodes: codes,
+		DateCreated:   m.DateCreated.UTC(),
+		DateUpdated:   m.DateUpdated.UTC(),
+	}
+}
+
+func toMFADomain(db mfaDB) mfa.MFA {
+	return mfa.MFA{
+		UserID:        db.UserID,
+		Secret:        db.Secret,
+		Confirmed:     db.Confirmed,
+		LastUsedStep:  db.LastUsedStep,
+		RecoveryCodes: db.RecoveryCodes,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUpdated:   db.DateUpdated.In(time.Local),
+	}
+}
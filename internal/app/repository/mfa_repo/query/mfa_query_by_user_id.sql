@@ -0,0 +1,9 @@
+SELECT user_id,
+       secret,
+       confirmed,
+       last_used_step,
+       recovery_codes,
+       date_created,
+       date_updated
+FROM user_mfa
+WHERE user_id = :user_id
@@ -0,0 +1,7 @@
+UPDATE
+    user_mfa
+SET "confirmed"      = :confirmed,
+    "last_used_step" = :last_used_step,
+    "recovery_codes" = :recovery_codes,
+    "date_updated"   = :date_updated
+WHERE user_id = :user_id
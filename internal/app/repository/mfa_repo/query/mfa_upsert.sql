@@ -0,0 +1,10 @@
+INSERT INTO user_mfa
+    (user_id, secret, confirmed, last_used_step, recovery_codes, date_created, date_updated)
+VALUES (:user_id, :secret, :confirmed, :last_used_step, :recovery_codes, :date_created, :date_updated)
+ON CONFLICT (user_id) DO UPDATE
+    SET secret         = EXCLUDED.secret,
+        confirmed      = EXCLUDED.confirmed,
+        last_used_step = EXCLUDED.last_used_step,
+        recovery_codes = EXCLUDED.recovery_codes,
+        date_created   = EXCLUDED.date_created,
+        date_updated   = EXCLUDED.date_updated
@@ -0,0 +1,49 @@
+package order_repo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+)
+
+func applyFilter(ctx context.Context, filter order.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if orgID, ok := organization.Scoped(ctx); ok {
+		data["org_id"] = orgID
+		wc = append(wc, "org_id = :org_id")
+	}
+
+	if filter.ID != nil {
+		data["order_id"] = *filter.ID
+		wc = append(wc, "order_id = :order_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.Status != nil {
+		data["status"] = filter.Status.String()
+		wc = append(wc, "status = :status")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = filter.StartCreatedDate.UTC()
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = filter.EndCreatedDate.UTC()
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
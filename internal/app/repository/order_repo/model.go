@@ -0,0 +1,159 @@
+package order_repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
+)
+
+type orderDB struct {
+	ID          uuid.UUID `db:"order_id"`
+	OrgID       uuid.UUID `db:"org_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Status      string    `db:"status"`
+	Total       string    `db:"total"`
+	Currency    string    `db:"currency"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBOrder(bus order.Order) orderDB {
+	return orderDB{
+		ID:          bus.ID,
+		OrgID:       bus.OrgID,
+		UserID:      bus.UserID,
+		Status:      bus.Status.String(),
+		Total:       bus.Total.Decimal(),
+		Currency:    bus.Total.Currency().String(),
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+// toBusOrder converts the order along with its items, the items are priced
+// in the currency of the order.
+func toBusOrder(db orderDB, dbItems []itemDB) (order.Order, error) {
+	status, err := order.ParseStatus(db.Status)
+	if err != nil {
+		return order.Order{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	currency, err := money.ParseCurrency(db.Currency)
+	if err != nil {
+		return order.Order{}, fmt.Errorf("parse currency: %w", err)
+	}
+
+	total, err := money.Parse(db.Total, currency)
+	if err != nil {
+		return order.Order{}, fmt.Errorf("parse total: %w", err)
+	}
+
+	items := make([]order.Item, len(dbItems))
+	for i, dbItem := range dbItems {
+		items[i], err = toBusItem(dbItem, currency)
+		if err != nil {
+			return order.Order{}, err
+		}
+	}
+
+	bus := order.Order{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		UserID:      db.UserID,
+		Status:      status,
+		Total:       total,
+		Items:       items,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusOrders(dbs []orderDB, dbItems []itemDB) ([]order.Order, error) {
+	byOrder := make(map[uuid.UUID][]itemDB, len(dbs))
+	for _, dbItem := range dbItems {
+		byOrder[dbItem.OrderID] = append(byOrder[dbItem.OrderID], dbItem)
+	}
+
+	bus := make([]order.Order, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusOrder(db, byOrder[db.ID])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type itemDB struct {
+	ID        uuid.UUID     `db:"item_id"`
+	OrderID   uuid.UUID     `db:"order_id"`
+	ProductID uuid.NullUUID `db:"product_id"`
+	Name      string        `db:"name"`
+	Price     string        `db:"price"`
+	Quantity  int           `db:"quantity"`
+	Total     string        `db:"total"`
+}
+
+func toDBItems(bus order.Order) []itemDB {
+	dbs := make([]itemDB, len(bus.Items))
+
+	for i, itm := range bus.Items {
+		dbs[i] = itemDB{
+			ID:        itm.ID,
+			OrderID:   bus.ID,
+			ProductID: uuid.NullUUID{UUID: itm.ProductID, Valid: itm.ProductID != uuid.Nil},
+			Name:      itm.Name.String(),
+			Price:     itm.Price.Decimal(),
+			Quantity:  itm.Quantity.Value(),
+			Total:     itm.Total.Decimal(),
+		}
+	}
+
+	return dbs
+}
+
+func toBusItem(db itemDB, currency money.Currency) (order.Item, error) {
+	n, err := name.Parse(db.Name)
+	if err != nil {
+		return order.Item{}, fmt.Errorf("parse name: %w", err)
+	}
+
+	price, err := money.Parse(db.Price, currency)
+	if err != nil {
+		return order.Item{}, fmt.Errorf("parse price: %w", err)
+	}
+
+	q, err := quantity.Parse(db.Quantity)
+	if err != nil {
+		return order.Item{}, fmt.Errorf("parse quantity: %w", err)
+	}
+
+	total, err := money.Parse(db.Total, currency)
+	if err != nil {
+		return order.Item{}, fmt.Errorf("parse total: %w", err)
+	}
+
+	bus := order.Item{
+		ID:        db.ID,
+		ProductID: db.ProductID.UUID,
+		Name:      n,
+		Price:     price,
+		Quantity:  q,
+		Total:     total,
+	}
+
+	return bus, nil
+}
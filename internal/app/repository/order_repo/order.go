@@ -0,0 +1,25 @@
+package order_repo
+
+import (
+	"fmt"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	orderPck "github.com/Housiadas/backend-system/pkg/order"
+)
+
+var orderByFields = map[string]string{
+	order.OrderByID:          "order_id",
+	order.OrderByUserID:      "user_id",
+	order.OrderByStatus:      "status",
+	order.OrderByTotal:       "total",
+	order.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy orderPck.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
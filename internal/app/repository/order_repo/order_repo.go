@@ -0,0 +1,213 @@
+// Package order_repo contains orderDB related CRUD functionality.
+package order_repo
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	orderPck "github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/order_create.sql
+	orderCreateSql string
+	//go:embed query/order_item_create.sql
+	orderItemCreateSql string
+	//go:embed query/order_update_status.sql
+	orderUpdateStatusSql string
+	//go:embed query/order_query.sql
+	orderQuerySql string
+	//go:embed query/order_query_by_id.sql
+	orderQueryByIdSql string
+	//go:embed query/order_item_query.sql
+	orderItemQuerySql string
+	//go:embed query/order_count.sql
+	orderCountSql string
+)
+
+// Store manages the set of APIs for orderDB database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (order.Storer, error) {
+	ec, err := pgsql.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create adds an Order along with its items to the pgsql.
+func (s *Store) Create(ctx context.Context, ord order.Order) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, orderCreateSql, toDBOrder(ord)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, orderItemCreateSql, toDBItems(ord)); err != nil {
+		return fmt.Errorf("namedexeccontext: items: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus moves the order to the status. The update only applies while
+// the order is still in the status it was read with, so concurrent requests
+// can't both change it.
+func (s *Store) UpdateStatus(ctx context.Context, ord order.Order, to order.Status, now time.Time) error {
+	data := struct {
+		ID          uuid.UUID  `db:"order_id"`
+		Status      string     `db:"status"`
+		FromStatus  string     `db:"from_status"`
+		DateUpdated time.Time  `db:"date_updated"`
+		ScopeOrgID  *uuid.UUID `db:"scope_org_id"`
+	}{
+		ID:          ord.ID,
+		Status:      to.String(),
+		FromStatus:  ord.Status.String(),
+		DateUpdated: now.UTC(),
+		ScopeOrgID:  organization.ScopeID(ctx),
+	}
+
+	var dest struct {
+		ID uuid.UUID `db:"order_id"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, orderUpdateStatusSql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", order.ErrStatusChanged)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified order along with its items from the database.
+func (s *Store) QueryByID(ctx context.Context, orderID uuid.UUID) (order.Order, error) {
+	data := struct {
+		ID         string     `db:"order_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+	}{
+		ID:         orderID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+
+	var dbOrd orderDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, orderQueryByIdSql, data, &dbOrd); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return order.Order{}, fmt.Errorf("db: %w", order.ErrNotFound)
+		}
+		return order.Order{}, fmt.Errorf("db: %w", err)
+	}
+
+	dbItems, err := s.queryItems(ctx, []orderDB{dbOrd})
+	if err != nil {
+		return order.Order{}, err
+	}
+
+	return toBusOrder(dbOrd, dbItems)
+}
+
+// Query retrieves a list of existing orders along with their items from the
+// database.
+func (s *Store) Query(ctx context.Context, filter order.QueryFilter, orderBy orderPck.By, page page.Page) ([]order.Order, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	buf := bytes.NewBufferString(orderQuerySql)
+	applyFilter(ctx, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbOrds []orderDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbOrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	dbItems, err := s.queryItems(ctx, dbOrds)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBusOrders(dbOrds, dbItems)
+}
+
+// Count returns the total number of orders in the DB.
+func (s *Store) Count(ctx context.Context, filter order.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	buf := bytes.NewBufferString(orderCountSql)
+	applyFilter(ctx, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// queryItems gets the items of the orders at once.
+func (s *Store) queryItems(ctx context.Context, dbOrds []orderDB) ([]itemDB, error) {
+	if len(dbOrds) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(dbOrds))
+	for i, dbOrd := range dbOrds {
+		ids[i] = dbOrd.ID.String()
+	}
+
+	data := struct {
+		OrderIDs []string `db:"order_ids"`
+	}{
+		OrderIDs: ids,
+	}
+
+	var dbItems []itemDB
+	if err := pgsql.NamedQuerySliceUsingIn(ctx, s.log, s.db, orderItemQuerySql, data, &dbItems); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: items: %w", err)
+	}
+
+	return dbItems, nil
+}
@@ -0,0 +1,268 @@
+package order_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/ordercore"
+	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+func Test_Order(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Order")
+
+	sd, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	t.Run("lifecycle", func(t *testing.T) {
+		lifecycle(t, db, sd)
+	})
+
+	unitest.Run(t, query(db.Core, sd), "query")
+}
+
+// =============================================================================
+
+type seedData struct {
+	usr  user.User
+	prds []product.Product
+}
+
+func insertSeedData(core dbtest.Core) (seedData, error) {
+	ctx := context.Background()
+
+	usrs, err := usercore.TestSeedUsers(ctx, 1, role.User, core.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err := productcore.TestGenerateSeedProducts(ctx, 2, core.Product, usrs[0].ID)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding products : %w", err)
+	}
+
+	// The orders start from a known stock.
+	for i, prd := range prds {
+		prds[i], err = core.Product.Update(ctx, prd, product.UpdateProduct{
+			Quantity: dbtest.QuantityPointer(10),
+		})
+		if err != nil {
+			return seedData{}, fmt.Errorf("seeding quantity : %w", err)
+		}
+	}
+
+	return seedData{usr: usrs[0], prds: prds}, nil
+}
+
+// =============================================================================
+
+// inTx runs the call with the order core in a transaction, committed unless
+// the call fails, the way the api runs it.
+func inTx(db *dbtest.Database, fn func(core *ordercore.Core) error) error {
+	tx, err := pgsql.NewBeginner(db.DB).Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	core, err := db.Core.Order.NewWithTx(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(core); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func lifecycle(t *testing.T, db *dbtest.Database, sd seedData) {
+	ctx := context.Background()
+
+	newOrder := func(q1, q2 int) order.NewOrder {
+		return order.NewOrder{
+			UserID: sd.usr.ID,
+			Items: []order.NewItem{
+				{ProductID: sd.prds[0].ID, Quantity: quantity.MustParse(q1)},
+				{ProductID: sd.prds[1].ID, Quantity: quantity.MustParse(q2)},
+			},
+		}
+	}
+
+	stockOf := func(i int) int {
+		prd, err := db.Core.Product.QueryByID(ctx, sd.prds[i].ID)
+		if err != nil {
+			t.Fatalf("Should get the product: %s", err)
+		}
+		return prd.Quantity.Value()
+	}
+
+	// An item out of stock leaves the stock of the others untouched.
+	err := inTx(db, func(core *ordercore.Core) error {
+		_, err := core.Create(ctx, newOrder(2, 11))
+		return err
+	})
+	if !errors.Is(err, stock.ErrInsufficientStock) {
+		t.Fatalf("Should run out of stock: %v", err)
+	}
+
+	if stockOf(0) != 10 || stockOf(1) != 10 {
+		t.Fatalf("Should roll the stock back: got %d %d", stockOf(0), stockOf(1))
+	}
+
+	var shipped order.Order
+	err = inTx(db, func(core *ordercore.Core) error {
+		var err error
+		if shipped, err = core.Create(ctx, newOrder(3, 2)); err != nil {
+			return err
+		}
+		for _, status := range []order.Status{order.Paid, order.Shipped} {
+			if shipped, err = core.UpdateStatus(ctx, shipped, order.UpdateStatus{Status: status, ActorID: sd.usr.ID}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Should place, pay and ship the order: %s", err)
+	}
+
+	exp := sd.prds[0].Cost.Amount()*3 + sd.prds[1].Cost.Amount()*2
+	if shipped.Total.Amount() != exp {
+		t.Fatalf("Should total the items: got %d, exp %d", shipped.Total.Amount(), exp)
+	}
+
+	if stockOf(0) != 7 || stockOf(1) != 8 {
+		t.Fatalf("Should take the stock: got %d %d", stockOf(0), stockOf(1))
+	}
+
+	err = inTx(db, func(core *ordercore.Core) error {
+		_, err := core.UpdateStatus(ctx, shipped, order.UpdateStatus{Status: order.Cancelled, ActorID: sd.usr.ID})
+		return err
+	})
+	if !errors.Is(err, order.ErrInvalidTransition) {
+		t.Fatalf("Should not cancel a shipped order: %v", err)
+	}
+
+	var pending order.Order
+	err = inTx(db, func(core *ordercore.Core) error {
+		var err error
+		if pending, err = core.Create(ctx, newOrder(1, 1)); err != nil {
+			return err
+		}
+		_, err = core.UpdateStatus(ctx, pending, order.UpdateStatus{Status: order.Cancelled, ActorID: sd.usr.ID})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Should place and cancel the order: %s", err)
+	}
+
+	if stockOf(0) != 7 || stockOf(1) != 8 {
+		t.Fatalf("Should restock the cancelled order: got %d %d", stockOf(0), stockOf(1))
+	}
+
+	// The order read before it was cancelled can't be cancelled again.
+	err = inTx(db, func(core *ordercore.Core) error {
+		_, err := core.UpdateStatus(ctx, pending, order.UpdateStatus{Status: order.Cancelled, ActorID: sd.usr.ID})
+		return err
+	})
+	if !errors.Is(err, order.ErrStatusChanged) {
+		t.Fatalf("Should not restock twice: %v", err)
+	}
+
+	got, err := db.Core.Order.QueryByID(ctx, shipped.ID)
+	if err != nil {
+		t.Fatalf("Should get the order: %s", err)
+	}
+
+	if diff := cmp.Diff(got.Items, shipped.Items); diff != "" {
+		t.Fatalf("Should get the items of the order:\n%s", diff)
+	}
+
+	auds, err := db.Core.Audit.Query(ctx, audit.QueryFilter{ObjID: &shipped.ID}, audit.DefaultOrderBy, page.MustParse("1", "10"))
+	if err != nil {
+		t.Fatalf("Should get the audits: %s", err)
+	}
+
+	if len(auds) != 3 {
+		t.Fatalf("Should audit every status change: got %d", len(auds))
+	}
+}
+
+// =============================================================================
+
+func query(core dbtest.Core, sd seedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "by-user",
+			ExpResp: []string{"CANCELLED", "SHIPPED"},
+			ExcFunc: func(ctx context.Context) any {
+				filter := order.QueryFilter{
+					UserID: &sd.usr.ID,
+				}
+
+				ords, err := core.Order.Query(ctx, filter, order.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				statuses := make([]string, len(ords))
+				for i, ord := range ords {
+					if len(ord.Items) != 2 {
+						return fmt.Errorf("order %s has %d items", ord.ID, len(ord.Items))
+					}
+					statuses[i] = ord.Status.String()
+				}
+
+				return statuses
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "by-status",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				status := order.Cancelled
+				filter := order.QueryFilter{
+					UserID: &sd.usr.ID,
+					Status: &status,
+				}
+
+				count, err := core.Order.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,2 @@
+SELECT count(1)
+FROM orders
\ No newline at end of file
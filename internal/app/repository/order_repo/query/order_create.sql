@@ -0,0 +1,3 @@
+INSERT INTO orders
+    (order_id, org_id, user_id, status, total, currency, date_created, date_updated)
+VALUES (:order_id, :org_id, :user_id, :status, :total, :currency, :date_created, :date_updated)
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO order_items
+    (item_id, order_id, product_id, name, price, quantity, total)
+VALUES (:item_id, :order_id, :product_id, :name, :price, :quantity, :total)
\ No newline at end of file
@@ -0,0 +1,10 @@
+SELECT item_id,
+       order_id,
+       product_id,
+       name,
+       price,
+       quantity,
+       total
+FROM order_items
+WHERE order_id IN (:order_ids)
+ORDER BY name, item_id
\ No newline at end of file
@@ -0,0 +1,9 @@
+SELECT order_id,
+       org_id,
+       user_id,
+       status,
+       total,
+       currency,
+       date_created,
+       date_updated
+FROM orders
\ No newline at end of file
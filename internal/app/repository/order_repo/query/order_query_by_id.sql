@@ -0,0 +1,11 @@
+SELECT order_id,
+       org_id,
+       user_id,
+       status,
+       total,
+       currency,
+       date_created,
+       date_updated
+FROM orders
+WHERE order_id = :order_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
@@ -0,0 +1,7 @@
+UPDATE orders
+SET status       = :status,
+    date_updated = :date_updated
+WHERE order_id = :order_id
+  AND status = :from_status
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
+RETURNING order_id
\ No newline at end of file
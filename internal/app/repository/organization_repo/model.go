@@ -0,0 +1,57 @@
+package organization_repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+)
+
+type organizationDB struct {
+	ID          uuid.UUID `db:"org_id"`
+	Name        string    `db:"name"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toOrganizationDB(org organization.Organization) organizationDB {
+	return organizationDB{
+		ID:          org.ID,
+		Name:        org.Name.String(),
+		DateCreated: org.DateCreated.UTC(),
+		DateUpdated: org.DateUpdated.UTC(),
+	}
+}
+
+func toOrganizationDomain(db organizationDB) (organization.Organization, error) {
+	nme, err := name.Parse(db.Name)
+	if err != nil {
+		return organization.Organization{}, fmt.Errorf("parse name: %w", err)
+	}
+
+	org := organization.Organization{
+		ID:          db.ID,
+		Name:        nme,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return org, nil
+}
+
+func toOrganizationsDomain(dbs []organizationDB) ([]organization.Organization, error) {
+	orgs := make([]organization.Organization, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		orgs[i], err = toOrganizationDomain(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return orgs, nil
+}
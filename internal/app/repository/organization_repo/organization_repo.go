@@ -0,0 +1,109 @@
+// Package organization_repo contains organization related CRUD functionality.
+package organization_repo
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/organization_create.sql
+	organizationCreateSql string
+	//go:embed query/organization_query.sql
+	organizationQuerySql string
+	//go:embed query/organization_count.sql
+	organizationCountSql string
+	//go:embed query/organization_query_by_id.sql
+	organizationQueryByIdSql string
+)
+
+// Store manages the set of APIs for organization database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new organization into the database.
+func (s *Store) Create(ctx context.Context, org organization.Organization) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, organizationCreateSql, toOrganizationDB(org)); err != nil {
+		if errors.Is(err, pgsql.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", organization.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of organizations from the database. A scoped caller
+// only gets its own organization.
+func (s *Store) Query(ctx context.Context, page page.Page) ([]organization.Organization, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+		"scope_org_id":  organization.ScopeID(ctx),
+	}
+
+	var dbOrgs []organizationDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, organizationQuerySql, data, &dbOrgs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toOrganizationsDomain(dbOrgs)
+}
+
+// Count returns the total number of organizations in the DB.
+func (s *Store) Count(ctx context.Context) (int, error) {
+	data := map[string]any{
+		"scope_org_id": organization.ScopeID(ctx),
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, organizationCountSql, data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID gets the specified organization from the database.
+func (s *Store) QueryByID(ctx context.Context, orgID uuid.UUID) (organization.Organization, error) {
+	data := struct {
+		ID         string     `db:"org_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+	}{
+		ID:         orgID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+
+	var dbOrg organizationDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, organizationQueryByIdSql, data, &dbOrg); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return organization.Organization{}, fmt.Errorf("db: %w", organization.ErrNotFound)
+		}
+		return organization.Organization{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toOrganizationDomain(dbOrg)
+}
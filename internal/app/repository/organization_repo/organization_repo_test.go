@@ -0,0 +1,275 @@
+package organization_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
+	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+// tenant is an organization seeded with a user owning products.
+type tenant struct {
+	org      organization.Organization
+	usr      user.User
+	products []product.Product
+	audits   []audit.Audit
+}
+
+func Test_Organization(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Organization")
+
+	tenants, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, isolation(db.Core, tenants), "isolation")
+	unitest.Run(t, superAdmin(db.Core, tenants), "superadmin")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.Core) ([]tenant, error) {
+	ctx := context.Background()
+
+	tenants := make([]tenant, 2)
+	for i := range tenants {
+		org, err := busDomain.Org.Create(ctx, organization.NewOrganization{
+			Name: name.MustParse(fmt.Sprintf("Organization%d", i)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("seeding organization : %w", err)
+		}
+
+		// The data created in the scope of the organization belongs to it.
+		orgCtx := organization.WithScope(ctx, org.ID)
+
+		usrs, err := usercore.TestSeedUsers(orgCtx, 1, role.User, busDomain.User)
+		if err != nil {
+			return nil, fmt.Errorf("seeding users : %w", err)
+		}
+
+		prds, err := productcore.TestGenerateSeedProducts(orgCtx, 2, busDomain.Product, usrs[0].ID)
+		if err != nil {
+			return nil, fmt.Errorf("seeding products : %w", err)
+		}
+
+		adts, err := auditcore.TestSeedAudits(orgCtx, 3, usrs[0].ID, entity.User, "create", busDomain.Audit)
+		if err != nil {
+			return nil, fmt.Errorf("seeding audits : %w", err)
+		}
+
+		tenants[i] = tenant{
+			org:      org,
+			usr:      usrs[0],
+			products: prds,
+			audits:   adts,
+		}
+	}
+
+	return tenants, nil
+}
+
+// =============================================================================
+
+func isolation(busDomain dbtest.Core, tenants []tenant) []unitest.Table {
+	a, b := tenants[0], tenants[1]
+
+	table := []unitest.Table{
+		{
+			Name:    "owned",
+			ExpResp: []uuid.UUID{a.org.ID, a.org.ID, a.org.ID},
+			ExcFunc: func(ctx context.Context) any {
+				ctx = organization.WithScope(ctx, a.org.ID)
+
+				usr, err := busDomain.User.QueryByID(ctx, a.usr.ID)
+				if err != nil {
+					return err
+				}
+
+				prd, err := busDomain.Product.QueryByID(ctx, a.products[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return []uuid.UUID{usr.OrgID, prd.OrgID, a.audits[0].OrgID}
+			},
+			CmpFunc: cmpValue,
+		},
+		{
+			Name:    "query",
+			ExpResp: []int{1, 2, 3, 1},
+			ExcFunc: func(ctx context.Context) any {
+				ctx = organization.WithScope(ctx, a.org.ID)
+
+				usrs, err := busDomain.User.Count(ctx, user.QueryFilter{})
+				if err != nil {
+					return err
+				}
+
+				prds, err := busDomain.Product.Count(ctx, product.QueryFilter{})
+				if err != nil {
+					return err
+				}
+
+				adts, err := busDomain.Audit.Count(ctx, audit.QueryFilter{})
+				if err != nil {
+					return err
+				}
+
+				orgs, err := busDomain.Org.Query(ctx, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return []int{usrs, prds, adts, len(orgs)}
+			},
+			CmpFunc: cmpValue,
+		},
+		{
+			Name:    "user-cross-tenant",
+			ExpResp: user.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.User.QueryByID(organization.WithScope(ctx, a.org.ID), b.usr.ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "product-cross-tenant",
+			ExpResp: product.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Product.QueryByID(organization.WithScope(ctx, a.org.ID), b.products[0].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "organization-cross-tenant",
+			ExpResp: organization.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := busDomain.Org.QueryByID(organization.WithScope(ctx, a.org.ID), b.org.ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "write-cross-tenant",
+			ExpResp: b.usr.Name.String(),
+			ExcFunc: func(ctx context.Context) any {
+				scoped := organization.WithScope(ctx, a.org.ID)
+
+				// The writes of another organization's rows match nothing.
+				nme := name.MustParse("Changed")
+				if _, err := busDomain.User.Update(scoped, b.usr, user.UpdateUser{Name: &nme}); err != nil {
+					return err
+				}
+				if err := busDomain.Product.Delete(scoped, b.products[1]); err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Product.QueryByID(ctx, b.products[1].ID); err != nil {
+					return err
+				}
+
+				usr, err := busDomain.User.QueryByID(ctx, b.usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return usr.Name.String()
+			},
+			CmpFunc: cmpValue,
+		},
+		{
+			Name:    "unscoped",
+			ExpResp: []uuid.UUID{a.org.ID, b.org.ID},
+			ExcFunc: func(ctx context.Context) any {
+				usrA, err := busDomain.User.QueryByID(ctx, a.usr.ID)
+				if err != nil {
+					return err
+				}
+
+				usrB, err := busDomain.User.QueryByID(ctx, b.usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return []uuid.UUID{usrA.OrgID, usrB.OrgID}
+			},
+			CmpFunc: cmpValue,
+		},
+	}
+
+	return table
+}
+
+func superAdmin(busDomain dbtest.Core, tenants []tenant) []unitest.Table {
+	a := tenants[0]
+
+	table := []unitest.Table{
+		{
+			Name:    "scoped-grant",
+			ExpResp: organization.ErrSuperAdminRole,
+			ExcFunc: func(ctx context.Context) any {
+				nu := usercore.TestNewUsers(1, role.SuperAdmin)[0]
+
+				_, err := busDomain.User.Create(organization.WithScope(ctx, a.org.ID), nu)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "unscoped-grant",
+			ExpResp: organization.DefaultID,
+			ExcFunc: func(ctx context.Context) any {
+				nu := usercore.TestNewUsers(1, role.SuperAdmin)[0]
+
+				usr, err := busDomain.User.Create(ctx, nu)
+				if err != nil {
+					return err
+				}
+
+				return usr.OrgID
+			},
+			CmpFunc: cmpValue,
+		},
+	}
+
+	return table
+}
+
+// =============================================================================
+
+func cmpValue(got any, exp any) string {
+	return cmp.Diff(got, exp)
+}
+
+func cmpError(got any, exp any) string {
+	err, _ := got.(error)
+	if !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", got, exp)
+	}
+	return ""
+}
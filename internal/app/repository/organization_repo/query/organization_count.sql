@@ -0,0 +1,3 @@
+SELECT count(1)
+FROM organizations
+WHERE (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO organizations
+    (org_id, name, date_created, date_updated)
+VALUES (:org_id, :name, :date_created, :date_updated)
\ No newline at end of file
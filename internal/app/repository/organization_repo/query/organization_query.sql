@@ -0,0 +1,8 @@
+SELECT org_id,
+       name,
+       date_created,
+       date_updated
+FROM organizations
+WHERE (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
+ORDER BY name
+OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY
\ No newline at end of file
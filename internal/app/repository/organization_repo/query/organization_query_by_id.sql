@@ -0,0 +1,7 @@
+SELECT org_id,
+       name,
+       date_created,
+       date_updated
+FROM organizations
+WHERE org_id = :org_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
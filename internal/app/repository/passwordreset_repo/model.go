@@ -0,0 +1,52 @@
+package passwordreset_repo
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/passwordreset"
+)
+
+type resetDB struct {
+	ID          uuid.UUID    `db:"reset_id"`
+	UserID      uuid.UUID    `db:"user_id"`
+	TokenHash   string       `db:"token_hash"`
+	ExpiresAt   time.Time    `db:"expires_at"`
+	UsedAt      sql.NullTime `db:"used_at"`
+	DateCreated time.Time    `db:"date_created"`
+}
+
+func toResetDB(r passwordreset.Reset) resetDB {
+	db := resetDB{
+		ID:          r.ID,
+		UserID:      r.UserID,
+		TokenHash:   r.TokenHash,
+		ExpiresAt:   r.ExpiresAt.UTC(),
+		DateCreated: r.DateCreated.UTC(),
+	}
+
+	if r.UsedAt != nil {
+		db.UsedAt = sql.NullTime{Time: r.UsedAt.UTC(), Valid: true}
+	}
+
+	return db
+}
+
+func toResetDomain(db resetDB) passwordreset.Reset {
+	r := passwordreset.Reset{
+		ID:          db.ID,
+		UserID:      db.UserID,
+		TokenHash:   db.TokenHash,
+		ExpiresAt:   db.ExpiresAt.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+	}
+
+	if db.UsedAt.Valid {
+		usedAt := db.UsedAt.Time.In(time.Local)
+		r.UsedAt = &usedAt
+	}
+
+	return r
+}
@@ -0,0 +1,112 @@
+// Package passwordreset_repo contains password reset related CRUD functionality.
+package passwordreset_repo
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/passwordreset"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/password_reset_create.sql
+	resetCreateSql string
+	//go:embed query/password_reset_use.sql
+	resetUseSql string
+	//go:embed query/password_reset_use_by_user_id.sql
+	resetUseByUserIdSql string
+	//go:embed query/password_reset_query_by_id.sql
+	resetQueryByIdSql string
+)
+
+// Store manages the set of APIs for password reset database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new password reset into the database.
+func (s *Store) Create(ctx context.Context, r passwordreset.Reset) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, resetCreateSql, toResetDB(r)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Use marks the password reset as used. The update only applies to a reset
+// not used yet, so a token can't be redeemed twice by concurrent requests.
+func (s *Store) Use(ctx context.Context, resetID uuid.UUID, usedAt time.Time) error {
+	data := struct {
+		ID     string    `db:"reset_id"`
+		UsedAt time.Time `db:"used_at"`
+	}{
+		ID:     resetID.String(),
+		UsedAt: usedAt.UTC(),
+	}
+
+	var dest struct {
+		ID uuid.UUID `db:"reset_id"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, resetUseSql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", passwordreset.ErrUsed)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// UseByUserID marks every password reset of a user not used yet as used.
+func (s *Store) UseByUserID(ctx context.Context, userID uuid.UUID, usedAt time.Time) error {
+	data := struct {
+		UserID string    `db:"user_id"`
+		UsedAt time.Time `db:"used_at"`
+	}{
+		UserID: userID.String(),
+		UsedAt: usedAt.UTC(),
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, resetUseByUserIdSql, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified password reset from the database.
+func (s *Store) QueryByID(ctx context.Context, resetID uuid.UUID) (passwordreset.Reset, error) {
+	data := struct {
+		ID string `db:"reset_id"`
+	}{
+		ID: resetID.String(),
+	}
+
+	var dbReset resetDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, resetQueryByIdSql, data, &dbReset); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return passwordreset.Reset{}, fmt.Errorf("db: %w", passwordreset.ErrNotFound)
+		}
+		return passwordreset.Reset{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toResetDomain(dbReset), nil
+}
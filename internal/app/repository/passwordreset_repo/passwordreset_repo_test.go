@@ -0,0 +1,137 @@
+package passwordreset_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/passwordreset"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+)
+
+func Test_PasswordReset(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_PasswordReset")
+
+	sd, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, redeem(db.Core, sd), "redeem")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.Core) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := usercore.TestSeedUsers(ctx, 1, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func redeem(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
+	usr := sd.Users[0].User
+
+	table := []unitest.Table{
+		{
+			Name:    "single-use",
+			ExpResp: passwordreset.ErrUsed,
+			ExcFunc: func(ctx context.Context) any {
+				_, token, err := busDomain.Reset.Create(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				r, err := busDomain.Reset.Redeem(ctx, token)
+				if err != nil {
+					return err
+				}
+				if r.UserID != usr.ID {
+					return fmt.Errorf("expected the reset of user %s: got %s", usr.ID, r.UserID)
+				}
+
+				_, err = busDomain.Reset.Redeem(ctx, token)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "voided",
+			ExpResp: passwordreset.ErrUsed,
+			ExcFunc: func(ctx context.Context) any {
+				_, older, err := busDomain.Reset.Create(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				_, token, err := busDomain.Reset.Create(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Reset.Redeem(ctx, token+"x"); !errors.Is(err, passwordreset.ErrInvalidToken) {
+					return fmt.Errorf("expected invalid token: %w", err)
+				}
+
+				if _, err := busDomain.Reset.Redeem(ctx, token); err != nil {
+					return err
+				}
+
+				// Redeeming a token voids the other tokens of the user.
+				_, err = busDomain.Reset.Redeem(ctx, older)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "tokens-valid-after",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				if _, err := busDomain.User.ResetPassword(ctx, usr, "new password"); err != nil {
+					return err
+				}
+
+				got, err := busDomain.User.QueryByID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return !got.TokensValidAfter.IsZero()
+			},
+			CmpFunc: func(got any, exp any) string {
+				if got != exp {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func cmpError(got any, exp any) string {
+	err, _ := got.(error)
+	if !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", got, exp)
+	}
+	return ""
+}
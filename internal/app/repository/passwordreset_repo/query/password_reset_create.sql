@@ -0,0 +1,3 @@
+INSERT INTO password_resets
+    (reset_id, user_id, token_hash, expires_at, used_at, date_created)
+VALUES (:reset_id, :user_id, :token_hash, :expires_at, :used_at, :date_created)
\ No newline at end of file
@@ -0,0 +1,8 @@
+SELECT reset_id,
+       user_id,
+       token_hash,
+       expires_at,
+       used_at,
+       date_created
+FROM password_resets
+WHERE reset_id = :reset_id
\ No newline at end of file
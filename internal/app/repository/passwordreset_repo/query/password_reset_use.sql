@@ -0,0 +1,6 @@
+UPDATE
+    password_resets
+SET "used_at" = :used_at
+WHERE reset_id = :reset_id
+  AND used_at IS NULL
+RETURNING reset_id
\ No newline at end of file
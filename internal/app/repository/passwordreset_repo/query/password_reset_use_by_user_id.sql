@@ -0,0 +1,5 @@
+UPDATE
+    password_resets
+SET "used_at" = :used_at
+WHERE user_id = :user_id
+  AND used_at IS NULL
\ No newline at end of file
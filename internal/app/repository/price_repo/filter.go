@@ -0,0 +1,48 @@
+package price_repo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+)
+
+func applyFilter(ctx context.Context, filter price.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if orgID, ok := organization.Scoped(ctx); ok {
+		data["org_id"] = orgID
+		wc = append(wc, "org_id = :org_id")
+	}
+
+	if filter.ProductID != nil {
+		data["product_id"] = *filter.ProductID
+		wc = append(wc, "product_id = :product_id")
+	}
+
+	if filter.Applied != nil {
+		switch *filter.Applied {
+		case true:
+			wc = append(wc, "date_applied IS NOT NULL")
+		default:
+			wc = append(wc, "date_applied IS NULL")
+		}
+	}
+
+	if filter.StartEffectiveDate != nil {
+		data["start_effective_from"] = filter.StartEffectiveDate.UTC()
+		wc = append(wc, "effective_from >= :start_effective_from")
+	}
+
+	if filter.EndEffectiveDate != nil {
+		data["end_effective_from"] = filter.EndEffectiveDate.UTC()
+		wc = append(wc, "effective_from <= :end_effective_from")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -0,0 +1,77 @@
+package price_repo
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+)
+
+type priceDB struct {
+	ID            uuid.UUID    `db:"price_id"`
+	OrgID         uuid.UUID    `db:"org_id"`
+	ProductID     uuid.UUID    `db:"product_id"`
+	Cost          string       `db:"cost"`
+	Currency      string       `db:"currency"`
+	EffectiveFrom time.Time    `db:"effective_from"`
+	DateApplied   sql.NullTime `db:"date_applied"`
+	DateCreated   time.Time    `db:"date_created"`
+}
+
+func toDBPrice(bus price.Price) priceDB {
+	return priceDB{
+		ID:            bus.ID,
+		OrgID:         bus.OrgID,
+		ProductID:     bus.ProductID,
+		Cost:          bus.Cost.Decimal(),
+		Currency:      bus.Cost.Currency().String(),
+		EffectiveFrom: bus.EffectiveFrom.UTC(),
+		DateApplied:   sql.NullTime{Time: bus.DateApplied.UTC(), Valid: bus.IsApplied()},
+		DateCreated:   bus.DateCreated.UTC(),
+	}
+}
+
+func toBusPrice(db priceDB) (price.Price, error) {
+	currency, err := money.ParseCurrency(db.Currency)
+	if err != nil {
+		return price.Price{}, fmt.Errorf("parse currency: %w", err)
+	}
+
+	cost, err := money.Parse(db.Cost, currency)
+	if err != nil {
+		return price.Price{}, fmt.Errorf("parse cost: %w", err)
+	}
+
+	bus := price.Price{
+		ID:            db.ID,
+		OrgID:         db.OrgID,
+		ProductID:     db.ProductID,
+		Cost:          cost,
+		EffectiveFrom: db.EffectiveFrom.In(time.Local),
+		DateCreated:   db.DateCreated.In(time.Local),
+	}
+
+	if db.DateApplied.Valid {
+		bus.DateApplied = db.DateApplied.Time.In(time.Local)
+	}
+
+	return bus, nil
+}
+
+func toBusPrices(dbs []priceDB) ([]price.Price, error) {
+	bus := make([]price.Price, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusPrice(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,23 @@
+package price_repo
+
+import (
+	"fmt"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+	"github.com/Housiadas/backend-system/pkg/order"
+)
+
+var orderByFields = map[string]string{
+	price.OrderByEffectiveFrom: "effective_from",
+	price.OrderByCost:          "cost",
+	price.OrderByDateCreated:   "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,187 @@
+// Package price_repo contains priceDB related CRUD functionality.
+package price_repo
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/price_create.sql
+	priceCreateSql string
+	//go:embed query/price_apply.sql
+	priceApplySql string
+	//go:embed query/price_query_at.sql
+	priceQueryAtSql string
+	//go:embed query/price_query_due.sql
+	priceQueryDueSql string
+	//go:embed query/price_query.sql
+	priceQuerySql string
+	//go:embed query/price_count.sql
+	priceCountSql string
+)
+
+// Store manages the set of APIs for price database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (price.Storer, error) {
+	ec, err := pgsql.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Create adds a price of a product to the database.
+func (s *Store) Create(ctx context.Context, pr price.Price) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, priceCreateSql, toDBPrice(pr)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Apply marks the price applied and sets the cost of the product to it. The
+// cost is left as it is when a later price was applied already, like a cost
+// changed after the price took effect but before it was applied.
+func (s *Store) Apply(ctx context.Context, pr price.Price, now time.Time) error {
+	data := struct {
+		ID  uuid.UUID `db:"price_id"`
+		Now time.Time `db:"now"`
+	}{
+		ID:  pr.ID,
+		Now: now.UTC(),
+	}
+
+	var dest struct {
+		ID uuid.UUID `db:"price_id"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, priceApplySql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", price.ErrAlreadyApplied)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// QueryAt gets the price of the product in effect at the time, the latest
+// one that took effect by then.
+func (s *Store) QueryAt(ctx context.Context, productID uuid.UUID, at time.Time) (price.Price, error) {
+	data := struct {
+		ProductID  uuid.UUID  `db:"product_id"`
+		At         time.Time  `db:"at"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+	}{
+		ProductID:  productID,
+		At:         at.UTC(),
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+
+	var dbPr priceDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, priceQueryAtSql, data, &dbPr); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return price.Price{}, fmt.Errorf("db: %w", price.ErrNotFound)
+		}
+		return price.Price{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusPrice(dbPr)
+}
+
+// QueryDue gets up to limit prices not applied yet that took effect by now,
+// the ones that took effect first come first.
+func (s *Store) QueryDue(ctx context.Context, now time.Time, limit int) ([]price.Price, error) {
+	data := struct {
+		Now   time.Time `db:"now"`
+		Limit int       `db:"limit"`
+	}{
+		Now:   now.UTC(),
+		Limit: limit,
+	}
+
+	var dbPrs []priceDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, priceQueryDueSql, data, &dbPrs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusPrices(dbPrs)
+}
+
+// Query retrieves a list of existing prices from the database.
+func (s *Store) Query(ctx context.Context, filter price.QueryFilter, orderBy order.By, page page.Page) ([]price.Price, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	buf := bytes.NewBufferString(priceQuerySql)
+	applyFilter(ctx, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbPrs []priceDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbPrs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusPrices(dbPrs)
+}
+
+// Count returns the total number of prices in the DB.
+func (s *Store) Count(ctx context.Context, filter price.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	buf := bytes.NewBufferString(priceCountSql)
+	applyFilter(ctx, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
@@ -0,0 +1,189 @@
+package price_repo_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/app/repository/price_repo"
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+func Test_Price(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Price")
+
+	prds, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	t.Run("due", func(t *testing.T) {
+		due(t, db, prds[1])
+	})
+
+	unitest.Run(t, history(db.Core, prds[0]), "history")
+}
+
+// =============================================================================
+
+// insertSeedData adds two products costing 10, the first one then costs 20.
+func insertSeedData(core dbtest.Core) ([]product.Product, error) {
+	ctx := context.Background()
+
+	usrs, err := usercore.TestSeedUsers(ctx, 1, role.User, core.User)
+	if err != nil {
+		return nil, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err := productcore.TestGenerateSeedProducts(ctx, 2, core.Product, usrs[0].ID)
+	if err != nil {
+		return nil, fmt.Errorf("seeding products : %w", err)
+	}
+
+	for i, prd := range prds {
+		prds[i], err = core.Product.Update(ctx, prd, product.UpdateProduct{
+			Cost: dbtest.MoneyPointer("10"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("seeding cost : %w", err)
+		}
+	}
+
+	prds[0], err = core.Product.Update(ctx, prds[0], product.UpdateProduct{
+		Cost: dbtest.MoneyPointer("20"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("seeding cost : %w", err)
+	}
+
+	return prds, nil
+}
+
+// =============================================================================
+
+// due applies a price that took effect, a price still ahead is left alone.
+func due(t *testing.T, db *dbtest.Database, prd product.Product) {
+	ctx := context.Background()
+	now := time.Now()
+
+	if _, err := db.Core.Price.Schedule(ctx, prd, price.NewPrice{
+		ProductID:     prd.ID,
+		Cost:          money.MustParse("30", money.USD),
+		EffectiveFrom: now.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("Should schedule a price: %s", err)
+	}
+
+	// A price can't be scheduled to take effect now, the store is used to
+	// have one that is due without waiting for it.
+	store := price_repo.NewStore(db.Log, db.DB)
+	if err := store.Create(ctx, price.Price{
+		ID:            uuid.New(),
+		OrgID:         prd.OrgID,
+		ProductID:     prd.ID,
+		Cost:          money.MustParse("15", money.USD),
+		EffectiveFrom: now,
+		DateCreated:   now,
+	}); err != nil {
+		t.Fatalf("Should create a due price: %s", err)
+	}
+
+	pr, err := db.Core.Price.PriceAt(ctx, prd.ID, time.Now())
+	if err != nil {
+		t.Fatalf("Should get the price in effect: %s", err)
+	}
+
+	if pr.Cost.Decimal() != "15.00" || pr.IsApplied() {
+		t.Fatalf("Should be in effect before it is applied: got %s, applied %t", pr.Cost.Decimal(), pr.IsApplied())
+	}
+
+	n, err := db.Core.Price.ApplyDue(ctx)
+	if err != nil {
+		t.Fatalf("Should apply the due prices: %s", err)
+	}
+
+	if n != 1 {
+		t.Fatalf("Should apply only the due price: got %d", n)
+	}
+
+	got, err := db.Core.Product.QueryByID(ctx, prd.ID)
+	if err != nil {
+		t.Fatalf("Should get the product: %s", err)
+	}
+
+	if got.Cost.Decimal() != "15.00" {
+		t.Fatalf("Should set the cost of the product: got %s, exp 15.00", got.Cost.Decimal())
+	}
+
+	if n, _ := db.Core.Price.ApplyDue(ctx); n != 0 {
+		t.Fatalf("Should apply a price once: got %d", n)
+	}
+}
+
+// =============================================================================
+
+func history(core dbtest.Core, prd product.Product) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "costs",
+			ExpResp: []string{"20.00", "10.00"},
+			ExcFunc: func(ctx context.Context) any {
+				prs, err := core.Price.Query(ctx, price.QueryFilter{ProductID: &prd.ID}, price.DefaultOrderBy, page.MustParse("1", "2"))
+				if err != nil {
+					return err
+				}
+
+				costs := make([]string, len(prs))
+				for i, pr := range prs {
+					costs[i] = pr.Cost.Decimal()
+				}
+
+				return costs
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "price-at",
+			ExpResp: []string{"10.00", "20.00"},
+			ExcFunc: func(ctx context.Context) any {
+				prs, err := core.Price.Query(ctx, price.QueryFilter{ProductID: &prd.ID}, price.DefaultOrderBy, page.MustParse("1", "1"))
+				if err != nil {
+					return err
+				}
+
+				before, err := core.Price.PriceAt(ctx, prd.ID, prs[0].EffectiveFrom.Add(-time.Millisecond))
+				if err != nil {
+					return err
+				}
+
+				now, err := core.Price.PriceAt(ctx, prd.ID, time.Now())
+				if err != nil {
+					return err
+				}
+
+				return []string{before.Cost.Decimal(), now.Cost.Decimal()}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
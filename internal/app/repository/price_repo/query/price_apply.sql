@@ -0,0 +1,21 @@
+WITH applied AS (
+    UPDATE product_prices
+        SET date_applied = :now
+        WHERE price_id = :price_id
+            AND date_applied IS NULL
+        RETURNING price_id, product_id, cost, currency, effective_from),
+     product AS (
+         UPDATE products
+             SET cost         = applied.cost,
+                 currency     = applied.currency,
+                 date_updated = :now
+             FROM applied
+             WHERE products.product_id = applied.product_id
+                 AND NOT EXISTS (SELECT 1
+                                 FROM product_prices AS pp
+                                 WHERE pp.product_id = applied.product_id
+                                   AND pp.date_applied IS NOT NULL
+                                   AND pp.effective_from > applied.effective_from)
+             RETURNING products.product_id)
+SELECT price_id
+FROM applied
\ No newline at end of file
@@ -0,0 +1,2 @@
+SELECT count(1)
+FROM product_prices
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO product_prices
+    (price_id, org_id, product_id, cost, currency, effective_from, date_applied, date_created)
+VALUES (:price_id, :org_id, :product_id, :cost, :currency, :effective_from, :date_applied, :date_created)
\ No newline at end of file
@@ -0,0 +1,9 @@
+SELECT price_id,
+       org_id,
+       product_id,
+       cost,
+       currency,
+       effective_from,
+       date_applied,
+       date_created
+FROM product_prices
\ No newline at end of file
@@ -0,0 +1,14 @@
+SELECT price_id,
+       org_id,
+       product_id,
+       cost,
+       currency,
+       effective_from,
+       date_applied,
+       date_created
+FROM product_prices
+WHERE product_id = :product_id
+  AND effective_from <= :at
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
+ORDER BY effective_from DESC, date_created DESC
+FETCH FIRST 1 ROWS ONLY
\ No newline at end of file
@@ -0,0 +1,13 @@
+SELECT price_id,
+       org_id,
+       product_id,
+       cost,
+       currency,
+       effective_from,
+       date_applied,
+       date_created
+FROM product_prices
+WHERE date_applied IS NULL
+  AND effective_from <= :now
+ORDER BY effective_from
+FETCH FIRST :limit ROWS ONLY
\ No newline at end of file
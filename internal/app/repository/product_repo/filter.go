@@ -2,15 +2,22 @@ package product_repo
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 	"github.com/Housiadas/backend-system/internal/core/domain/product"
 )
 
-func (s *Store) applyFilter(filter product.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+func (s *Store) applyFilter(ctx context.Context, filter product.QueryFilter, data map[string]any, buf *bytes.Buffer) {
 	var wc []string
 
+	if orgID, ok := organization.Scoped(ctx); ok {
+		data["org_id"] = orgID
+		wc = append(wc, "org_id = :org_id")
+	}
+
 	if filter.ID != nil {
 		data["product_id"] = *filter.ID
 		wc = append(wc, "product_id = :product_id")
@@ -22,8 +29,14 @@ func (s *Store) applyFilter(filter product.QueryFilter, data map[string]any, buf
 	}
 
 	if filter.Cost != nil {
-		data["cost"] = *filter.Cost
-		wc = append(wc, "cost = :cost")
+		data["cost"] = filter.Cost.Decimal()
+		data["cost_currency"] = filter.Cost.Currency().String()
+		wc = append(wc, "cost = :cost AND currency = :cost_currency")
+	}
+
+	if filter.Currency != nil {
+		data["currency"] = filter.Currency.String()
+		wc = append(wc, "currency = :currency")
 	}
 
 	if filter.Quantity != nil {
@@ -1,6 +1,7 @@
 package product_repo
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -8,15 +9,18 @@ import (
 
 	"github.com/Housiadas/backend-system/internal/core/domain/money"
 	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 	"github.com/Housiadas/backend-system/internal/core/domain/product"
 	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
 )
 
 type productDB struct {
 	ID          uuid.UUID `db:"product_id"`
+	OrgID       uuid.UUID `db:"org_id"`
 	UserID      uuid.UUID `db:"user_id"`
 	Name        string    `db:"name"`
-	Cost        float64   `db:"cost"`
+	Cost        string    `db:"cost"`
+	Currency    string    `db:"currency"`
 	Quantity    int       `db:"quantity"`
 	DateCreated time.Time `db:"date_created"`
 	DateUpdated time.Time `db:"date_updated"`
@@ -25,9 +29,11 @@ type productDB struct {
 func toDBProduct(bus product.Product) productDB {
 	db := productDB{
 		ID:          bus.ID,
+		OrgID:       bus.OrgID,
 		UserID:      bus.UserID,
 		Name:        bus.Name.String(),
-		Cost:        bus.Cost.Value(),
+		Cost:        bus.Cost.Decimal(),
+		Currency:    bus.Cost.Currency().String(),
 		Quantity:    bus.Quantity.Value(),
 		DateCreated: bus.DateCreated.UTC(),
 		DateUpdated: bus.DateUpdated.UTC(),
@@ -36,17 +42,42 @@ func toDBProduct(bus product.Product) productDB {
 	return db
 }
 
+// scopedProductDB is a product along with the organization the query is
+// scoped to, a product of another organization is left untouched.
+type scopedProductDB struct {
+	productDB
+	ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+}
+
+func toScopedDBProduct(ctx context.Context, bus product.Product) scopedProductDB {
+	return scopedProductDB{
+		productDB:  toDBProduct(bus),
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+}
+
 func toBusProduct(db productDB) (product.Product, error) {
 	n, err := name.Parse(db.Name)
 	if err != nil {
 		return product.Product{}, fmt.Errorf("parse name: %w", err)
 	}
 
+	currency, err := money.ParseCurrency(db.Currency)
+	if err != nil {
+		return product.Product{}, fmt.Errorf("parse currency: %w", err)
+	}
+
+	cost, err := money.Parse(db.Cost, currency)
+	if err != nil {
+		return product.Product{}, fmt.Errorf("parse cost: %w", err)
+	}
+
 	bus := product.Product{
 		ID:          db.ID,
+		OrgID:       db.OrgID,
 		UserID:      db.UserID,
 		Name:        n,
-		Cost:        money.MustParse(db.Cost),
+		Cost:        cost,
 		Quantity:    quantity.MustParse(db.Quantity),
 		DateCreated: db.DateCreated.In(time.Local),
 		DateUpdated: db.DateUpdated.In(time.Local),
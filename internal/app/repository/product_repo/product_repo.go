@@ -11,7 +11,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
 	"github.com/Housiadas/backend-system/pkg/logger"
 	"github.com/Housiadas/backend-system/pkg/order"
 	"github.com/Housiadas/backend-system/pkg/page"
@@ -66,32 +69,78 @@ func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (product.Storer, error) {
 	return &store, nil
 }
 
-// Create adds a Product to the pgsql. It returns the created Product with
-// fields like ID and DateCreated populated.
+// Create adds a Product to the pgsql. Its quantity is recorded as received in
+// the stock movements, and its cost as its first price, at the same time.
 func (s *Store) Create(ctx context.Context, prd product.Product) error {
-	if err := pgsql.NamedExecContext(ctx, s.log, s.db, productCreateSql, toDBProduct(prd)); err != nil {
+	data := struct {
+		productDB
+		MovementID uuid.UUID `db:"movement_id"`
+		Kind       string    `db:"kind"`
+		PriceID    uuid.UUID `db:"price_id"`
+	}{
+		productDB:  toDBProduct(prd),
+		MovementID: uuid.New(),
+		Kind:       stock.Receipt.String(),
+		PriceID:    uuid.New(),
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, productCreateSql, data); err != nil {
 		return fmt.Errorf("name_exec_context: %w", err)
 	}
 
 	return nil
 }
 
-// Update modifies data about a product. It will error if the specified ID is
-// invalid or does not reference an existing product.
-func (s *Store) Update(ctx context.Context, prd product.Product) error {
-	if err := pgsql.NamedExecContext(ctx, s.log, s.db, productUpdateSql, toDBProduct(prd)); err != nil {
-		return fmt.Errorf("name_exec_context: %w", err)
+// Update modifies data about a product. A quantity given replaces the one the
+// product has, the difference is recorded as an adjustment in the stock
+// movements, a nil quantity keeps the stock as it is. A new cost is recorded
+// as a price in effect from now. All of it happens in one statement.
+func (s *Store) Update(ctx context.Context, prd product.Product, qnt *quantity.Quantity) (product.Product, error) {
+	data := struct {
+		scopedProductDB
+		SetQuantity *int      `db:"set_quantity"`
+		PriceID     uuid.UUID `db:"price_id"`
+		MovementID  uuid.UUID `db:"movement_id"`
+		Kind        string    `db:"kind"`
+	}{
+		scopedProductDB: toScopedDBProduct(ctx, prd),
+		PriceID:         uuid.New(),
+		MovementID:      uuid.New(),
+		Kind:            stock.Adjustment.String(),
 	}
 
-	return nil
+	if qnt != nil {
+		value := qnt.Value()
+		data.SetQuantity = &value
+	}
+
+	var dest struct {
+		Quantity int `db:"quantity"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, productUpdateSql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return product.Product{}, fmt.Errorf("db: %w", product.ErrNotFound)
+		}
+		return product.Product{}, fmt.Errorf("db: %w", err)
+	}
+
+	updQnt, err := quantity.Parse(dest.Quantity)
+	if err != nil {
+		return product.Product{}, fmt.Errorf("parse quantity: %w", err)
+	}
+	prd.Quantity = updQnt
+
+	return prd, nil
 }
 
 // Delete removes the productDB identified by a given ID.
 func (s *Store) Delete(ctx context.Context, prd product.Product) error {
 	data := struct {
-		ID string `db:"product_id"`
+		ID         string     `db:"product_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
 	}{
-		ID: prd.ID.String(),
+		ID:         prd.ID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
 	}
 
 	if err := pgsql.NamedExecContext(ctx, s.log, s.db, productDeleteSql, data); err != nil {
@@ -109,7 +158,7 @@ func (s *Store) Query(ctx context.Context, filter product.QueryFilter, orderBy o
 	}
 
 	buf := bytes.NewBufferString(productQuerySql)
-	s.applyFilter(filter, data, buf)
+	s.applyFilter(ctx, filter, data, buf)
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -131,7 +180,7 @@ func (s *Store) Query(ctx context.Context, filter product.QueryFilter, orderBy o
 func (s *Store) Count(ctx context.Context, filter product.QueryFilter) (int, error) {
 	data := map[string]any{}
 	buf := bytes.NewBufferString(productCountSql)
-	s.applyFilter(filter, data, buf)
+	s.applyFilter(ctx, filter, data, buf)
 
 	var count struct {
 		Count   int `db:"count"`
@@ -148,9 +197,11 @@ func (s *Store) Count(ctx context.Context, filter product.QueryFilter) (int, err
 // QueryByID finds the productDB identified by a given ID.
 func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (product.Product, error) {
 	data := struct {
-		ID string `db:"product_id"`
+		ID         string     `db:"product_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
 	}{
-		ID: productID.String(),
+		ID:         productID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
 	}
 
 	var dbPrd productDB
@@ -167,9 +218,11 @@ func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (product.Pro
 // QueryByUserID finds the productDB identified by a given User ID.
 func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]product.Product, error) {
 	data := struct {
-		ID string `db:"user_id"`
+		ID         string     `db:"user_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
 	}{
-		ID: userID.String(),
+		ID:         userID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
 	}
 
 	var dbPrds []productDB
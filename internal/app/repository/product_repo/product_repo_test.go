@@ -2,6 +2,7 @@ package product_repo_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"testing"
@@ -14,10 +15,12 @@ import (
 	"github.com/Housiadas/backend-system/internal/core/domain/product"
 	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
 	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
 	"github.com/Housiadas/backend-system/internal/core/service/productcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
 	"github.com/Housiadas/backend-system/pkg/page"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 )
 
 func Test_Product(t *testing.T) {
@@ -175,14 +178,14 @@ func create(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
 			ExpResp: product.Product{
 				UserID:   sd.Users[0].ID,
 				Name:     name.MustParse("Guitar"),
-				Cost:     money.MustParse(10.34),
+				Cost:     money.MustParse("10.34", money.DefaultCurrency),
 				Quantity: quantity.MustParse(10),
 			},
 			ExcFunc: func(ctx context.Context) any {
 				np := product.NewProduct{
 					UserID:   sd.Users[0].ID,
 					Name:     name.MustParse("Guitar"),
-					Cost:     money.MustParse(10.34),
+					Cost:     money.MustParse("10.34", money.DefaultCurrency),
 					Quantity: quantity.MustParse(10),
 				}
 
@@ -221,7 +224,7 @@ func update(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
 				ID:          sd.Users[0].Products[0].ID,
 				UserID:      sd.Users[0].ID,
 				Name:        name.MustParse("Guitar"),
-				Cost:        money.MustParse(10.34),
+				Cost:        money.MustParse("10.34", money.DefaultCurrency),
 				Quantity:    quantity.MustParse(10),
 				DateCreated: sd.Users[0].Products[0].DateCreated,
 				DateUpdated: sd.Users[0].Products[0].DateCreated,
@@ -229,7 +232,7 @@ func update(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
 			ExcFunc: func(ctx context.Context) any {
 				up := product.UpdateProduct{
 					Name:     dbtest.NamePointer("Guitar"),
-					Cost:     dbtest.MoneyPointer(10.34),
+					Cost:     dbtest.MoneyPointer("10.34"),
 					Quantity: dbtest.QuantityPointer(10),
 				}
 
@@ -253,6 +256,65 @@ func update(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
+		{
+			Name:    "quantity",
+			ExpResp: []int{5, 7},
+			ExcFunc: func(ctx context.Context) any {
+				prd := sd.Users[0].Products[1]
+
+				// A new quantity replaces the stock received after the
+				// product was read, without one that stock is kept.
+				if _, err := busDomain.Stock.Move(ctx, stock.NewMovement{ProductID: prd.ID, Kind: stock.Receipt, Quantity: 2}); err != nil {
+					return err
+				}
+
+				up := product.UpdateProduct{
+					Quantity: dbtest.QuantityPointer(prd.Quantity.Value() + 5),
+				}
+
+				set, err := busDomain.Product.Update(ctx, prd, up)
+				if err != nil {
+					return err
+				}
+
+				if _, err := busDomain.Stock.Move(ctx, stock.NewMovement{ProductID: prd.ID, Kind: stock.Receipt, Quantity: 2}); err != nil {
+					return err
+				}
+
+				up = product.UpdateProduct{
+					Name: dbtest.NamePointer("Guitar"),
+				}
+
+				kept, err := busDomain.Product.Update(ctx, set, up)
+				if err != nil {
+					return err
+				}
+
+				return []int{set.Quantity.Value() - prd.Quantity.Value(), kept.Quantity.Value() - prd.Quantity.Value()}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "missing",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				prd := sd.Users[0].Products[1]
+				prd.ID = uuid.New()
+
+				up := product.UpdateProduct{
+					Quantity: dbtest.QuantityPointer(1),
+				}
+
+				_, err := busDomain.Product.Update(ctx, prd, up)
+
+				return errors.Is(err, product.ErrNotFound)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
 	}
 
 	return table
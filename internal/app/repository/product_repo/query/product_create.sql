@@ -1,3 +1,29 @@
-INSERT INTO products
-    (product_id, user_id, name, cost, quantity, date_created, date_updated)
-VALUES (:product_id, :user_id, :name, :cost, :quantity, :date_created, :date_updated)
\ No newline at end of file
+WITH product AS (
+    INSERT INTO products
+        (product_id, org_id, user_id, name, cost, currency, quantity, date_created, date_updated)
+        VALUES (:product_id, :org_id, :user_id, :name, :cost, :currency, :quantity, :date_created, :date_updated)
+        RETURNING product_id, org_id, cost, currency, quantity, date_created),
+     movement AS (
+         INSERT INTO stock_movements
+             (movement_id, org_id, product_id, kind, delta, quantity, date_created)
+             SELECT CAST(:movement_id AS UUID),
+                    org_id,
+                    product_id,
+                    CAST(:kind AS TEXT),
+                    quantity,
+                    quantity,
+                    date_created
+             FROM product
+             WHERE quantity > 0)
+INSERT
+INTO product_prices
+    (price_id, org_id, product_id, cost, currency, effective_from, date_applied, date_created)
+SELECT CAST(:price_id AS UUID),
+       org_id,
+       product_id,
+       cost,
+       currency,
+       date_created,
+       date_created,
+       date_created
+FROM product
\ No newline at end of file
@@ -1,3 +1,4 @@
 DELETE
 FROM products
-WHERE product_id = :product_id
\ No newline at end of file
+WHERE product_id = :product_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
@@ -1,7 +1,9 @@
 SELECT product_id,
+       org_id,
        user_id,
        name,
        cost,
+       currency,
        quantity,
        date_created,
        date_updated
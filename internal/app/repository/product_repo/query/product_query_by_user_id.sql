@@ -1,9 +1,12 @@
 SELECT product_id,
+       org_id,
        user_id,
        name,
        cost,
+       currency,
        quantity,
        date_created,
        date_updated
 FROM products
-WHERE user_id = :user_id
\ No newline at end of file
+WHERE user_id = :user_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
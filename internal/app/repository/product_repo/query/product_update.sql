@@ -1,7 +1,48 @@
-UPDATE
-    products
-SET "name"         = :name,
-    "cost"         = :cost,
-    "quantity"     = :quantity,
-    "date_updated" = :date_updated
-WHERE product_id = :product_id
\ No newline at end of file
+WITH previous AS (
+    SELECT product_id, cost, currency, quantity
+    FROM products
+    WHERE product_id = :product_id
+      AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
+        FOR UPDATE),
+     product AS (
+         UPDATE products
+             SET "name"         = :name,
+                 "cost"         = :cost,
+                 "currency"     = :currency,
+                 "quantity"     = COALESCE(CAST(:set_quantity AS INT), previous.quantity),
+                 "date_updated" = :date_updated
+             FROM previous
+             WHERE products.product_id = previous.product_id
+             RETURNING products.product_id, products.org_id, products.cost, products.currency, products.quantity,
+                 previous.cost AS previous_cost, previous.currency AS previous_currency,
+                 previous.quantity AS previous_quantity),
+     price AS (
+         INSERT
+             INTO product_prices
+                 (price_id, org_id, product_id, cost, currency, effective_from, date_applied, date_created)
+             SELECT CAST(:price_id AS UUID),
+                    org_id,
+                    product_id,
+                    cost,
+                    currency,
+                    CAST(:date_updated AS TIMESTAMP),
+                    CAST(:date_updated AS TIMESTAMP),
+                    CAST(:date_updated AS TIMESTAMP)
+             FROM product
+             WHERE cost <> previous_cost
+                OR currency <> previous_currency),
+     movement AS (
+         INSERT
+             INTO stock_movements
+                 (movement_id, org_id, product_id, kind, delta, quantity, date_created)
+             SELECT CAST(:movement_id AS UUID),
+                    org_id,
+                    product_id,
+                    CAST(:kind AS TEXT),
+                    quantity - previous_quantity,
+                    quantity,
+                    CAST(:date_updated AS TIMESTAMP)
+             FROM product
+             WHERE quantity <> previous_quantity)
+SELECT quantity
+FROM product
\ No newline at end of file
@@ -0,0 +1,65 @@
+package rbac_repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/rbac"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/pkg/pgsql/dbarray"
+)
+
+type roleDB struct {
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Permissions dbarray.String `db:"permissions"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toRoleDB(r rbac.Role) roleDB {
+	return roleDB{
+		Name:        r.Name.String(),
+		Description: r.Description,
+		Permissions: permission.ParseToString(r.Permissions),
+		DateCreated: r.DateCreated.UTC(),
+		DateUpdated: r.DateUpdated.UTC(),
+	}
+}
+
+func toRoleDomain(db roleDB) (rbac.Role, error) {
+	name, err := role.New(db.Name)
+	if err != nil {
+		return rbac.Role{}, fmt.Errorf("parse name: %w", err)
+	}
+
+	perms, err := permission.ParseMany(db.Permissions)
+	if err != nil {
+		return rbac.Role{}, fmt.Errorf("parse permissions: %w", err)
+	}
+
+	r := rbac.Role{
+		Name:        name,
+		Description: db.Description,
+		Permissions: perms,
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return r, nil
+}
+
+func toRolesDomain(dbs []roleDB) ([]rbac.Role, error) {
+	roles := make([]rbac.Role, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		roles[i], err = toRoleDomain(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return roles, nil
+}
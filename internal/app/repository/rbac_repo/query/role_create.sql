@@ -0,0 +1,9 @@
+WITH r AS (
+    INSERT INTO roles
+        (name, description, date_created, date_updated)
+    VALUES (:name, :description, :date_created, :date_updated)
+)
+INSERT
+INTO role_permissions
+    (role_name, permission)
+SELECT :name, unnest(CAST(:permissions AS TEXT[]))
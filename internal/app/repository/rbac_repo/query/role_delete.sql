@@ -0,0 +1,3 @@
+DELETE
+FROM roles
+WHERE name = :name
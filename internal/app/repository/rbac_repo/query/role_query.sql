@@ -0,0 +1,9 @@
+SELECT r.name,
+       r.description,
+       COALESCE(array_agg(p.permission ORDER BY p.permission) FILTER (WHERE p.permission IS NOT NULL), '{}') AS permissions,
+       r.date_created,
+       r.date_updated
+FROM roles r
+         LEFT JOIN role_permissions p ON p.role_name = r.name
+GROUP BY r.name
+ORDER BY r.name
@@ -0,0 +1,4 @@
+SELECT DISTINCT permission
+FROM role_permissions
+WHERE role_name = ANY (CAST(:roles AS TEXT[]))
+ORDER BY permission
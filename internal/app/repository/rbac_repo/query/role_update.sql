@@ -0,0 +1,17 @@
+WITH r AS (
+    UPDATE roles
+    SET "description"  = :description,
+        "date_updated" = :date_updated
+    WHERE name = :name
+),
+d AS (
+    DELETE
+    FROM role_permissions
+    WHERE role_name = :name
+      AND NOT (permission = ANY (CAST(:permissions AS TEXT[])))
+)
+INSERT
+INTO role_permissions
+    (role_name, permission)
+SELECT :name, unnest(CAST(:permissions AS TEXT[]))
+ON CONFLICT DO NOTHING
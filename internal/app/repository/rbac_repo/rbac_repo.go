@@ -0,0 +1,131 @@
+// Package rbac_repo contains role and permission related CRUD functionality.
+package rbac_repo
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/rbac"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+	"github.com/Housiadas/backend-system/pkg/pgsql/dbarray"
+)
+
+// queries
+var (
+	//go:embed query/role_create.sql
+	roleCreateSql string
+	//go:embed query/role_update.sql
+	roleUpdateSql string
+	//go:embed query/role_delete.sql
+	roleDeleteSql string
+	//go:embed query/role_query.sql
+	roleQuerySql string
+	//go:embed query/role_query_by_name.sql
+	roleQueryByNameSql string
+	//go:embed query/role_query_permissions.sql
+	roleQueryPermissionsSql string
+)
+
+// Store manages the set of APIs for role database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new role and its permissions into the database.
+func (s *Store) Create(ctx context.Context, r rbac.Role) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, roleCreateSql, toRoleDB(r)); err != nil {
+		if errors.Is(err, pgsql.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", rbac.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a role and its permissions in the database.
+func (s *Store) Update(ctx context.Context, r rbac.Role) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, roleUpdateSql, toRoleDB(r)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a role and its permissions from the database.
+func (s *Store) Delete(ctx context.Context, r rbac.Role) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, roleDeleteSql, toRoleDB(r)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves all the roles from the database.
+func (s *Store) Query(ctx context.Context) ([]rbac.Role, error) {
+	var dbRoles []roleDB
+	if err := pgsql.QuerySlice(ctx, s.log, s.db, roleQuerySql, &dbRoles); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toRolesDomain(dbRoles)
+}
+
+// QueryByName gets the specified role from the database.
+func (s *Store) QueryByName(ctx context.Context, name role.Role) (rbac.Role, error) {
+	data := struct {
+		Name string `db:"name"`
+	}{
+		Name: name.String(),
+	}
+
+	var dbRole roleDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, roleQueryByNameSql, data, &dbRole); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return rbac.Role{}, fmt.Errorf("db: %w", rbac.ErrNotFound)
+		}
+		return rbac.Role{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toRoleDomain(dbRole)
+}
+
+// QueryPermissions retrieves the union of the permissions granted to the
+// specified roles.
+func (s *Store) QueryPermissions(ctx context.Context, roles []role.Role) ([]permission.Permission, error) {
+	data := struct {
+		Roles dbarray.String `db:"roles"`
+	}{
+		Roles: role.ParseToString(roles),
+	}
+
+	var dbPerms []struct {
+		Permission string `db:"permission"`
+	}
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, roleQueryPermissionsSql, data, &dbPerms); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	values := make([]string, len(dbPerms))
+	for i, p := range dbPerms {
+		values[i] = p.Permission
+	}
+
+	return permission.ParseMany(values)
+}
@@ -0,0 +1,177 @@
+package rbac_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/rbac"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+func Test_Rbac(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Rbac")
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, crud(db.Core), "crud")
+	unitest.Run(t, permissions(db.Core), "permissions")
+}
+
+// =============================================================================
+
+func crud(busDomain dbtest.Core) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "create",
+			ExpResp: []string{"audit:read", "product:read"},
+			ExcFunc: func(ctx context.Context) any {
+				nr := rbac.NewRole{
+					Name:        role.MustParse("ADMIN"),
+					Description: "Duplicate",
+				}
+				if _, err := busDomain.Rbac.Create(ctx, nr); !errors.Is(err, rbac.ErrUniqueName) {
+					return fmt.Errorf("expected unique name: %w", err)
+				}
+
+				name, err := role.New("AUDITOR")
+				if err != nil {
+					return err
+				}
+
+				nr = rbac.NewRole{
+					Name:        name,
+					Description: "Reads the audit log",
+					Permissions: []permission.Permission{permission.ProductRead, permission.AuditRead},
+				}
+				if _, err := busDomain.Rbac.Create(ctx, nr); err != nil {
+					return err
+				}
+
+				// The role can be parsed once created.
+				if _, err := role.Parse("AUDITOR"); err != nil {
+					return err
+				}
+
+				r, err := busDomain.Rbac.QueryByName(ctx, name)
+				if err != nil {
+					return err
+				}
+
+				return permission.ParseToString(r.Permissions)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "update",
+			ExpResp: []string{"role:read", "user:read"},
+			ExcFunc: func(ctx context.Context) any {
+				name, err := role.New("SUPPORT")
+				if err != nil {
+					return err
+				}
+
+				nr := rbac.NewRole{
+					Name:        name,
+					Permissions: []permission.Permission{permission.UserRead, permission.UserWrite},
+				}
+				r, err := busDomain.Rbac.Create(ctx, nr)
+				if err != nil {
+					return err
+				}
+
+				ur := rbac.UpdateRole{
+					Permissions: []permission.Permission{permission.UserRead, permission.RoleRead},
+				}
+				if _, err := busDomain.Rbac.Update(ctx, r, ur); err != nil {
+					return err
+				}
+
+				r, err = busDomain.Rbac.QueryByName(ctx, name)
+				if err != nil {
+					return err
+				}
+
+				return permission.ParseToString(r.Permissions)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "delete",
+			ExpResp: rbac.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				admin, err := busDomain.Rbac.QueryByName(ctx, role.Admin)
+				if err != nil {
+					return err
+				}
+				if err := busDomain.Rbac.Delete(ctx, admin); !errors.Is(err, rbac.ErrBuiltIn) {
+					return fmt.Errorf("expected built-in: %w", err)
+				}
+
+				name, err := role.New("TEMPORARY")
+				if err != nil {
+					return err
+				}
+
+				r, err := busDomain.Rbac.Create(ctx, rbac.NewRole{Name: name})
+				if err != nil {
+					return err
+				}
+
+				if err := busDomain.Rbac.Delete(ctx, r); err != nil {
+					return err
+				}
+
+				if _, err := role.Parse("TEMPORARY"); err == nil {
+					return errors.New("expected the role to be unregistered")
+				}
+
+				_, err = busDomain.Rbac.QueryByName(ctx, name)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func permissions(busDomain dbtest.Core) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "effective",
+			ExpResp: []string{"product:read", "product:write", "audit:read", "role:read", "role:write", "service_account:read", "service_account:write", "user:read", "user:write"},
+			ExcFunc: func(ctx context.Context) any {
+				perms, err := busDomain.Rbac.Permissions(ctx, []role.Role{role.User, role.Admin})
+				if err != nil {
+					return err
+				}
+
+				return permission.ParseToString(perms)
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
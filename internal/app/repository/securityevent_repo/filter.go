@@ -0,0 +1,54 @@
+package securityevent_repo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+)
+
+func applyFilter(ctx context.Context, filter securityevent.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if orgID, ok := organization.Scoped(ctx); ok {
+		data["org_id"] = orgID
+		wc = append(wc, "org_id = :org_id")
+	}
+
+	if filter.Kind != nil {
+		data["kind"] = filter.Kind.String()
+		wc = append(wc, "kind = :kind")
+	}
+
+	if filter.ActorID != nil {
+		data["actor_id"] = filter.ActorID
+		wc = append(wc, "actor_id = :actor_id")
+	}
+
+	if filter.Outcome != nil {
+		data["outcome"] = filter.Outcome
+		wc = append(wc, "outcome = :outcome")
+	}
+
+	if filter.IP != nil {
+		data["ip"] = filter.IP
+		wc = append(wc, "ip = :ip")
+	}
+
+	if filter.Since != nil {
+		data["since"] = filter.Since
+		wc = append(wc, "timestamp >= :since")
+	}
+
+	if filter.Until != nil {
+		data["until"] = filter.Until
+		wc = append(wc, "timestamp <= :until")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
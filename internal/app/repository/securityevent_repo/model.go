@@ -0,0 +1,75 @@
+package securityevent_repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+)
+
+type eventDB struct {
+	ID        uuid.UUID     `db:"id"`
+	OrgID     uuid.UUID     `db:"org_id"`
+	Kind      string        `db:"kind"`
+	ActorID   uuid.NullUUID `db:"actor_id"`
+	Subject   string        `db:"subject"`
+	IP        string        `db:"ip"`
+	UserAgent string        `db:"user_agent"`
+	Outcome   string        `db:"outcome"`
+	Reason    string        `db:"reason"`
+	Timestamp time.Time     `db:"timestamp"`
+}
+
+func toDBEvent(bus securityevent.Event) eventDB {
+	return eventDB{
+		ID:        bus.ID,
+		OrgID:     bus.OrgID,
+		Kind:      bus.Kind.String(),
+		ActorID:   uuid.NullUUID{UUID: bus.ActorID, Valid: bus.ActorID != uuid.Nil},
+		Subject:   bus.Subject,
+		IP:        bus.IP,
+		UserAgent: bus.UserAgent,
+		Outcome:   bus.Outcome,
+		Reason:    bus.Reason,
+		Timestamp: bus.Timestamp.UTC(),
+	}
+}
+
+func toBusEvent(db eventDB) (securityevent.Event, error) {
+	kind, err := securityevent.ParseKind(db.Kind)
+	if err != nil {
+		return securityevent.Event{}, fmt.Errorf("parse kind: %w", err)
+	}
+
+	bus := securityevent.Event{
+		ID:        db.ID,
+		OrgID:     db.OrgID,
+		Kind:      kind,
+		ActorID:   db.ActorID.UUID,
+		Subject:   db.Subject,
+		IP:        db.IP,
+		UserAgent: db.UserAgent,
+		Outcome:   db.Outcome,
+		Reason:    db.Reason,
+		Timestamp: db.Timestamp.Local(),
+	}
+
+	return bus, nil
+}
+
+func toBusEvents(dbs []eventDB) ([]securityevent.Event, error) {
+	events := make([]securityevent.Event, len(dbs))
+
+	for i, db := range dbs {
+		evt, err := toBusEvent(db)
+		if err != nil {
+			return nil, err
+		}
+
+		events[i] = evt
+	}
+
+	return events, nil
+}
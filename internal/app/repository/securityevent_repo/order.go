@@ -0,0 +1,25 @@
+package securityevent_repo
+
+import (
+	"fmt"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/pkg/order"
+)
+
+var orderByFields = map[string]string{
+	securityevent.OrderByTimestamp: "timestamp",
+	securityevent.OrderByKind:      "kind",
+	securityevent.OrderByActorID:   "actor_id",
+	securityevent.OrderByOutcome:   "outcome",
+	securityevent.OrderByIP:        "ip",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,80 @@
+package securityevent_repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	confluent "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/pkg/kafka"
+)
+
+// Publisher publishes the events to a kafka topic, one json document per
+// event keyed by the actor so the events of a user stay in order.
+type Publisher struct {
+	producer kafka.Producer
+	topic    string
+}
+
+// NewPublisher constructs a publisher writing the events to the topic.
+func NewPublisher(producer kafka.Producer, topic string) *Publisher {
+	return &Publisher{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+// eventMessage is the document published for an event.
+type eventMessage struct {
+	ID        string `json:"id"`
+	OrgID     string `json:"org_id"`
+	Kind      string `json:"kind"`
+	ActorID   string `json:"actor_id,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	Outcome   string `json:"outcome"`
+	Reason    string `json:"reason,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Publish implements the securityevent.Publisher interface.
+func (p *Publisher) Publish(ctx context.Context, evt securityevent.Event) error {
+	msg := eventMessage{
+		ID:        evt.ID.String(),
+		OrgID:     evt.OrgID.String(),
+		Kind:      evt.Kind.String(),
+		Subject:   evt.Subject,
+		IP:        evt.IP,
+		UserAgent: evt.UserAgent,
+		Outcome:   evt.Outcome,
+		Reason:    evt.Reason,
+		Timestamp: evt.Timestamp.UTC().Format(time.RFC3339),
+	}
+
+	key := []byte(evt.Subject)
+	if evt.ActorID != uuid.Nil {
+		msg.ActorID = evt.ActorID.String()
+		key = []byte(msg.ActorID)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	err = p.producer.Produce(ctx, &confluent.Message{
+		TopicPartition: confluent.TopicPartition{Topic: &p.topic, Partition: confluent.PartitionAny},
+		Key:            key,
+		Value:          data,
+	})
+	if err != nil {
+		return fmt.Errorf("produce event: %w", err)
+	}
+
+	return nil
+}
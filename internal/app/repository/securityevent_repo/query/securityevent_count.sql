@@ -0,0 +1,2 @@
+SELECT count(1)
+FROM security_events
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO security_events
+(id, org_id, kind, actor_id, subject, ip, user_agent, outcome, reason, timestamp)
+VALUES (:id, :org_id, :kind, :actor_id, :subject, :ip, :user_agent, :outcome, :reason, :timestamp)
\ No newline at end of file
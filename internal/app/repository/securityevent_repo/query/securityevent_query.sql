@@ -0,0 +1,4 @@
+SELECT
+    id, org_id, kind, actor_id, subject, ip, user_agent, outcome, reason, timestamp
+FROM
+    security_events
\ No newline at end of file
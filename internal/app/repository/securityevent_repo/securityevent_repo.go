@@ -0,0 +1,98 @@
+// Package securityevent_repo contains security event related CRUD functionality.
+package securityevent_repo
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/securityevent_create.sql
+	eventCreateSql string
+	//go:embed query/securityevent_query.sql
+	eventQuerySql string
+	//go:embed query/securityevent_count.sql
+	eventCountSql string
+)
+
+// Store manages the set of APIs for security event database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the API for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new event into the database.
+func (s *Store) Create(ctx context.Context, evt securityevent.Event) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, eventCreateSql, toDBEvent(evt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing events from the database.
+func (s *Store) Query(
+	ctx context.Context,
+	filter securityevent.QueryFilter,
+	orderBy order.By,
+	page page.Page,
+) ([]securityevent.Event, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	buf := bytes.NewBufferString(eventQuerySql)
+	applyFilter(ctx, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbEvents []eventDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbEvents); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusEvents(dbEvents)
+}
+
+// Count returns the total number of events in the DB.
+func (s *Store) Count(ctx context.Context, filter securityevent.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	buf := bytes.NewBufferString(eventCountSql)
+	applyFilter(ctx, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
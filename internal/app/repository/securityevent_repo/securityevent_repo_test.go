@@ -0,0 +1,168 @@
+package securityevent_repo_test
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/lockout"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+func Test_SecurityEvent(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_SecurityEvent")
+
+	evts, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	unitest.Run(t, query(db.Core, evts), "query")
+}
+
+// =============================================================================
+
+func insertSeedData(core dbtest.Core) ([]securityevent.Event, error) {
+	ctx := securityevent.WithClient(context.Background(), securityevent.Client{
+		IP:        "10.0.0.1",
+		UserAgent: "test-agent",
+	})
+
+	usrs, err := usercore.TestSeedUsers(ctx, 1, role.User, core.User)
+	if err != nil {
+		return nil, fmt.Errorf("seeding users : %w", err)
+	}
+
+	nes := []securityevent.NewEvent{
+		{
+			Kind:    securityevent.Login,
+			ActorID: usrs[0].ID,
+			Subject: usrs[0].ID.String(),
+			Outcome: securityevent.OutcomeSuccess,
+		},
+		{
+			Kind:    securityevent.LoginFailed,
+			Subject: lockout.HashKey(lockout.EmailKey(mail.Address{Address: "unknown@example.com"})),
+			Outcome: securityevent.OutcomeFailure,
+			Reason:  "not found",
+		},
+	}
+
+	evts := make([]securityevent.Event, len(nes))
+	for i, ne := range nes {
+		evt, err := core.Security.Create(ctx, ne)
+		if err != nil {
+			return nil, fmt.Errorf("seeding security events : %w", err)
+		}
+
+		evts[i] = evt
+	}
+
+	return evts, nil
+}
+
+// =============================================================================
+
+func query(core dbtest.Core, evts []securityevent.Event) []unitest.Table {
+	orderBy := order.NewBy(securityevent.OrderByKind, order.ASC)
+
+	table := []unitest.Table{
+		{
+			Name:    "all",
+			ExpResp: evts,
+			ExcFunc: func(ctx context.Context) any {
+				resp, err := core.Security.Query(ctx, securityevent.QueryFilter{}, orderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: cmpEvents,
+		},
+		{
+			Name:    "kind",
+			ExpResp: evts[1:],
+			ExcFunc: func(ctx context.Context) any {
+				filter := securityevent.QueryFilter{
+					Kind: &securityevent.LoginFailed,
+				}
+
+				resp, err := core.Security.Query(ctx, filter, orderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: cmpEvents,
+		},
+		{
+			Name:    "actor",
+			ExpResp: evts[:1],
+			ExcFunc: func(ctx context.Context) any {
+				filter := securityevent.QueryFilter{
+					ActorID: &evts[0].ActorID,
+				}
+
+				resp, err := core.Security.Query(ctx, filter, orderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				return resp
+			},
+			CmpFunc: cmpEvents,
+		},
+		{
+			Name:    "count",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				filter := securityevent.QueryFilter{
+					Outcome: dbtest.StringPointer(securityevent.OutcomeFailure),
+				}
+
+				n, err := core.Security.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return n
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
+func cmpEvents(got any, exp any) string {
+	gotResp, exists := got.([]securityevent.Event)
+	if !exists {
+		return "error occurred"
+	}
+
+	expResp := exp.([]securityevent.Event)
+
+	for i := range gotResp {
+		if i < len(expResp) && gotResp[i].Timestamp.Format(time.RFC3339) == expResp[i].Timestamp.Format(time.RFC3339) {
+			expResp[i].Timestamp = gotResp[i].Timestamp
+		}
+	}
+
+	return cmp.Diff(gotResp, expResp)
+}
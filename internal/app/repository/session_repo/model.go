@@ -0,0 +1,52 @@
+package session_repo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/session"
+)
+
+type sessionDB struct {
+	ID          uuid.UUID `db:"session_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	TokenHash   string    `db:"token_hash"`
+	Revoked     bool      `db:"revoked"`
+	ExpiresAt   time.Time `db:"expires_at"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toSessionDB(sess session.Session) sessionDB {
+	return sessionDB{
+		ID:          sess.ID,
+		UserID:      sess.UserID,
+		TokenHash:   sess.TokenHash,
+		Revoked:     sess.Revoked,
+		ExpiresAt:   sess.ExpiresAt.UTC(),
+		DateCreated: sess.DateCreated.UTC(),
+		DateUpdated: sess.DateUpdated.UTC(),
+	}
+}
+
+func toSessionDomain(db sessionDB) session.Session {
+	return session.Session{
+		ID:          db.ID,
+		UserID:      db.UserID,
+		TokenHash:   db.TokenHash,
+		Revoked:     db.Revoked,
+		ExpiresAt:   db.ExpiresAt.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+}
+
+func toSessionsDomain(dbs []sessionDB) []session.Session {
+	sessions := make([]session.Session, len(dbs))
+	for i, db := range dbs {
+		sessions[i] = toSessionDomain(db)
+	}
+
+	return sessions
+}
@@ -0,0 +1,9 @@
+WITH purged AS (
+    DELETE
+    FROM revoked_tokens
+    WHERE expires_at < :date_created
+)
+INSERT
+INTO revoked_tokens (token_id, expires_at, date_created)
+VALUES (:token_id, :expires_at, :date_created)
+ON CONFLICT (token_id) DO NOTHING
\ No newline at end of file
@@ -0,0 +1,3 @@
+SELECT count(1)
+FROM revoked_tokens
+WHERE token_id = :token_id
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO sessions
+    (session_id, user_id, token_hash, revoked, expires_at, date_created, date_updated)
+VALUES (:session_id, :user_id, :token_hash, :revoked, :expires_at, :date_created, :date_updated)
\ No newline at end of file
@@ -0,0 +1,9 @@
+SELECT session_id,
+       user_id,
+       token_hash,
+       revoked,
+       expires_at,
+       date_created,
+       date_updated
+FROM sessions
+WHERE session_id = :session_id
\ No newline at end of file
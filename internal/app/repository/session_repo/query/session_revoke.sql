@@ -0,0 +1,5 @@
+UPDATE
+    sessions
+SET "revoked"      = TRUE,
+    "date_updated" = :date_updated
+WHERE session_id = :session_id
\ No newline at end of file
@@ -0,0 +1,6 @@
+UPDATE
+    sessions
+SET "revoked"      = TRUE,
+    "date_updated" = :date_updated
+WHERE user_id = :user_id
+  AND revoked = FALSE
\ No newline at end of file
@@ -0,0 +1,9 @@
+UPDATE
+    sessions
+SET "token_hash"   = :token_hash,
+    "expires_at"   = :expires_at,
+    "date_updated" = :date_updated
+WHERE session_id = :session_id
+  AND token_hash = :old_hash
+  AND NOT revoked
+RETURNING session_id
\ No newline at end of file
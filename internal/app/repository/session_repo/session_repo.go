@@ -0,0 +1,184 @@
+// Package session_repo contains session related CRUD functionality.
+package session_repo
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/session"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/session_create.sql
+	sessionCreateSql string
+	//go:embed query/session_update.sql
+	sessionUpdateSql string
+	//go:embed query/session_revoke.sql
+	sessionRevokeSql string
+	//go:embed query/session_revoke_by_user_id.sql
+	sessionRevokeByUserIdSql string
+	//go:embed query/session_query_by_id.sql
+	sessionQueryByIdSql string
+	//go:embed query/session_query_by_user_id.sql
+	sessionQueryByUserIdSql string
+	//go:embed query/revoked_token_create.sql
+	revokedTokenCreateSql string
+	//go:embed query/revoked_token_query.sql
+	revokedTokenQuerySql string
+)
+
+// Store manages the set of APIs for session database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// Create inserts a new session into the database.
+func (s *Store) Create(ctx context.Context, sess session.Session) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, sessionCreateSql, toSessionDB(sess)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the token of a session in the database, provided the
+// session still holds the old token and is not revoked. Otherwise the session
+// is reported as not found.
+func (s *Store) Update(ctx context.Context, sess session.Session, oldHash string) error {
+	data := struct {
+		sessionDB
+		OldHash string `db:"old_hash"`
+	}{
+		sessionDB: toSessionDB(sess),
+		OldHash:   oldHash,
+	}
+
+	var dest struct {
+		ID uuid.UUID `db:"session_id"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, sessionUpdateSql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", session.ErrNotFound)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke revokes a session in the database.
+func (s *Store) Revoke(ctx context.Context, sess session.Session) error {
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, sessionRevokeSql, toSessionDB(sess)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeByUserID revokes all the active sessions of a user.
+func (s *Store) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	data := struct {
+		UserID      string    `db:"user_id"`
+		DateUpdated time.Time `db:"date_updated"`
+	}{
+		UserID:      userID.String(),
+		DateUpdated: time.Now().UTC(),
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, sessionRevokeByUserIdSql, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified session from the database.
+func (s *Store) QueryByID(ctx context.Context, sessionID uuid.UUID) (session.Session, error) {
+	data := struct {
+		ID string `db:"session_id"`
+	}{
+		ID: sessionID.String(),
+	}
+
+	var dbSess sessionDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, sessionQueryByIdSql, data, &dbSess); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return session.Session{}, fmt.Errorf("db: %w", session.ErrNotFound)
+		}
+		return session.Session{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toSessionDomain(dbSess), nil
+}
+
+// QueryByUserID gets all the sessions of the specified user from the database.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]session.Session, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: userID.String(),
+	}
+
+	var dbSessions []sessionDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, sessionQueryByUserIdSql, data, &dbSessions); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toSessionsDomain(dbSessions), nil
+}
+
+// RevokeToken adds the token to the revocation list until it expires. The
+// tokens expired already are purged from the list.
+func (s *Store) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	data := struct {
+		TokenID     string    `db:"token_id"`
+		ExpiresAt   time.Time `db:"expires_at"`
+		DateCreated time.Time `db:"date_created"`
+	}{
+		TokenID:     tokenID,
+		ExpiresAt:   expiresAt.UTC(),
+		DateCreated: time.Now().UTC(),
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, revokedTokenCreateSql, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// IsTokenRevoked reports whether the token is in the revocation list.
+func (s *Store) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	data := struct {
+		TokenID string `db:"token_id"`
+	}{
+		TokenID: tokenID,
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, revokedTokenQuerySql, data, &count); err != nil {
+		return false, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count > 0, nil
+}
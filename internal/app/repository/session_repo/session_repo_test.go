@@ -0,0 +1,253 @@
+package session_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/session"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+)
+
+func Test_Session(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Session")
+
+	sd, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, rotate(db.Core, sd), "rotate")
+	unitest.Run(t, revoke(db.Core, sd), "revoke")
+}
+
+// =============================================================================
+
+func insertSeedData(busDomain dbtest.Core) (unitest.SeedData, error) {
+	ctx := context.Background()
+
+	usrs, err := usercore.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return unitest.SeedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := unitest.SeedData{
+		Users: []unitest.User{{User: usrs[0]}, {User: usrs[1]}},
+	}
+
+	return sd, nil
+}
+
+// =============================================================================
+
+func rotate(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
+	newSession := func(ctx context.Context) (session.Session, string, error) {
+		ns := session.NewSession{
+			UserID:    sd.Users[0].ID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		return busDomain.Session.Create(ctx, ns)
+	}
+
+	table := []unitest.Table{
+		{
+			Name:    "new-token",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				sess, token, err := newSession(ctx)
+				if err != nil {
+					return err
+				}
+
+				rotated, newToken, err := busDomain.Session.Rotate(ctx, token)
+				if err != nil {
+					return err
+				}
+
+				return rotated.ID == sess.ID && newToken != token
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "reused-token",
+			ExpResp: session.ErrRevoked,
+			ExcFunc: func(ctx context.Context) any {
+				_, token, err := newSession(ctx)
+				if err != nil {
+					return err
+				}
+
+				_, newToken, err := busDomain.Session.Rotate(ctx, token)
+				if err != nil {
+					return err
+				}
+
+				// Presenting the old token again revokes the session, so
+				// the newly issued token can't be used either.
+				if _, _, err := busDomain.Session.Rotate(ctx, token); !errors.Is(err, session.ErrInvalidToken) {
+					return fmt.Errorf("expected invalid token: %w", err)
+				}
+
+				_, _, err = busDomain.Session.Rotate(ctx, newToken)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+		{
+			Name:    "race",
+			ExpResp: 1,
+			ExcFunc: func(ctx context.Context) any {
+				_, token, err := newSession(ctx)
+				if err != nil {
+					return err
+				}
+
+				// Only one of the concurrent uses of the token is issued a
+				// new one, the others find it rotated already.
+				const uses = 5
+
+				var wg sync.WaitGroup
+				errs := make([]error, uses)
+				for i := range uses {
+					wg.Go(func() {
+						_, _, errs[i] = busDomain.Session.Rotate(ctx, token)
+					})
+				}
+				wg.Wait()
+
+				var rotated int
+				for _, err := range errs {
+					switch {
+					case err == nil:
+						rotated++
+					case !errors.Is(err, session.ErrInvalidToken) && !errors.Is(err, session.ErrRevoked):
+						return err
+					}
+				}
+
+				return rotated
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "expired",
+			ExpResp: session.ErrExpired,
+			ExcFunc: func(ctx context.Context) any {
+				ns := session.NewSession{
+					UserID:    sd.Users[0].ID,
+					ExpiresAt: time.Now().Add(-time.Minute),
+				}
+
+				_, token, err := busDomain.Session.Create(ctx, ns)
+				if err != nil {
+					return err
+				}
+
+				_, _, err = busDomain.Session.Rotate(ctx, token)
+				return err
+			},
+			CmpFunc: func(got any, exp any) string {
+				err, _ := got.(error)
+				if !errors.Is(err, exp.(error)) {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func revoke(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "revoke-all",
+			ExpResp: []bool{true, true, false},
+			ExcFunc: func(ctx context.Context) any {
+				var sessions []session.Session
+				for _, usr := range []unitest.User{sd.Users[1], sd.Users[1], sd.Users[0]} {
+					ns := session.NewSession{
+						UserID:    usr.ID,
+						ExpiresAt: time.Now().Add(time.Hour),
+					}
+
+					sess, _, err := busDomain.Session.Create(ctx, ns)
+					if err != nil {
+						return err
+					}
+					sessions = append(sessions, sess)
+				}
+
+				if err := busDomain.Session.RevokeAll(ctx, sd.Users[1].ID); err != nil {
+					return err
+				}
+
+				resp := make([]bool, len(sessions))
+				for i, sess := range sessions {
+					revoked, err := busDomain.Session.IsRevoked(ctx, sess.ID)
+					if err != nil {
+						return err
+					}
+					resp[i] = revoked
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "revoke-token",
+			ExpResp: []bool{true, false},
+			ExcFunc: func(ctx context.Context) any {
+				revoked, other := uuid.NewString(), uuid.NewString()
+
+				if err := busDomain.Session.RevokeToken(ctx, revoked, time.Now().Add(time.Hour)); err != nil {
+					return err
+				}
+
+				resp := make([]bool, 2)
+				for i, tokenID := range []string{revoked, other} {
+					ok, err := busDomain.Session.IsTokenRevoked(ctx, tokenID)
+					if err != nil {
+						return err
+					}
+					resp[i] = ok
+				}
+
+				return resp
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,39 @@
+package stock_repo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+)
+
+func applyFilter(ctx context.Context, filter stock.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if orgID, ok := organization.Scoped(ctx); ok {
+		data["org_id"] = orgID
+		wc = append(wc, "org_id = :org_id")
+	}
+
+	if filter.ProductID != nil {
+		data["product_id"] = filter.ProductID
+		wc = append(wc, "product_id = :product_id")
+	}
+
+	if filter.ReservationID != nil {
+		data["reservation_id"] = filter.ReservationID
+		wc = append(wc, "reservation_id = :reservation_id")
+	}
+
+	if filter.Kind != nil {
+		data["kind"] = filter.Kind.String()
+		wc = append(wc, "kind = :kind")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -0,0 +1,134 @@
+package stock_repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+)
+
+type movementDB struct {
+	ID            uuid.UUID     `db:"movement_id"`
+	OrgID         uuid.UUID     `db:"org_id"`
+	ProductID     uuid.UUID     `db:"product_id"`
+	ReservationID uuid.NullUUID `db:"reservation_id"`
+	Kind          string        `db:"kind"`
+	Delta         int           `db:"delta"`
+	Quantity      int           `db:"quantity"`
+	DateCreated   time.Time     `db:"date_created"`
+}
+
+func toDBMovement(bus stock.Movement) movementDB {
+	return movementDB{
+		ID:            bus.ID,
+		OrgID:         bus.OrgID,
+		ProductID:     bus.ProductID,
+		ReservationID: uuid.NullUUID{UUID: bus.ReservationID, Valid: bus.ReservationID != uuid.Nil},
+		Kind:          bus.Kind.String(),
+		Delta:         bus.Delta,
+		Quantity:      bus.Quantity,
+		DateCreated:   bus.DateCreated.UTC(),
+	}
+}
+
+func toBusMovement(db movementDB) (stock.Movement, error) {
+	kind, err := stock.ParseKind(db.Kind)
+	if err != nil {
+		return stock.Movement{}, fmt.Errorf("parse kind: %w", err)
+	}
+
+	bus := stock.Movement{
+		ID:            db.ID,
+		OrgID:         db.OrgID,
+		ProductID:     db.ProductID,
+		ReservationID: db.ReservationID.UUID,
+		Kind:          kind,
+		Delta:         db.Delta,
+		Quantity:      db.Quantity,
+		DateCreated:   db.DateCreated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusMovements(dbs []movementDB) ([]stock.Movement, error) {
+	bus := make([]stock.Movement, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusMovement(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+type reservationDB struct {
+	ID          uuid.UUID `db:"reservation_id"`
+	OrgID       uuid.UUID `db:"org_id"`
+	ProductID   uuid.UUID `db:"product_id"`
+	Quantity    int       `db:"quantity"`
+	Status      string    `db:"status"`
+	ExpiresAt   time.Time `db:"expires_at"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBReservation(bus stock.Reservation) reservationDB {
+	return reservationDB{
+		ID:          bus.ID,
+		OrgID:       bus.OrgID,
+		ProductID:   bus.ProductID,
+		Quantity:    bus.Quantity,
+		Status:      bus.Status.String(),
+		ExpiresAt:   bus.ExpiresAt.UTC(),
+		DateCreated: bus.DateCreated.UTC(),
+		DateUpdated: bus.DateUpdated.UTC(),
+	}
+}
+
+func toBusReservation(db reservationDB) (stock.Reservation, error) {
+	status, err := stock.ParseStatus(db.Status)
+	if err != nil {
+		return stock.Reservation{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	bus := stock.Reservation{
+		ID:          db.ID,
+		OrgID:       db.OrgID,
+		ProductID:   db.ProductID,
+		Quantity:    db.Quantity,
+		Status:      status,
+		ExpiresAt:   db.ExpiresAt.In(time.Local),
+		DateCreated: db.DateCreated.In(time.Local),
+		DateUpdated: db.DateUpdated.In(time.Local),
+	}
+
+	return bus, nil
+}
+
+func toBusReservations(dbs []reservationDB) ([]stock.Reservation, error) {
+	bus := make([]stock.Reservation, len(dbs))
+
+	for i, db := range dbs {
+		var err error
+		bus[i], err = toBusReservation(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+// stockDB is the stock of a product right after a movement.
+type stockDB struct {
+	OrgID    uuid.UUID `db:"org_id"`
+	Quantity int       `db:"quantity"`
+}
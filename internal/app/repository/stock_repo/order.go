@@ -0,0 +1,23 @@
+package stock_repo
+
+import (
+	"fmt"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/pkg/order"
+)
+
+var orderByFields = map[string]string{
+	stock.OrderByDateCreated: "date_created",
+	stock.OrderByKind:        "kind",
+	stock.OrderByDelta:       "delta",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,7 @@
+UPDATE stock_reservations
+SET status       = :committed,
+    date_updated = :date_updated
+WHERE reservation_id = :reservation_id
+  AND status = :pending
+  AND expires_at > :date_updated
+RETURNING reservation_id
\ No newline at end of file
@@ -0,0 +1,21 @@
+WITH product AS (
+    UPDATE products
+        SET quantity = quantity + :delta,
+            date_updated = :date_created
+        WHERE product_id = :product_id
+            AND quantity + :delta BETWEEN 0 AND :max_quantity
+            AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
+        RETURNING org_id, quantity)
+INSERT
+INTO stock_movements
+    (movement_id, org_id, product_id, reservation_id, kind, delta, quantity, date_created)
+SELECT CAST(:movement_id AS UUID),
+       product.org_id,
+       CAST(:product_id AS UUID),
+       NULL,
+       CAST(:kind AS TEXT),
+       CAST(:delta AS INT),
+       product.quantity,
+       CAST(:date_created AS TIMESTAMP)
+FROM product
+RETURNING org_id, quantity
\ No newline at end of file
@@ -0,0 +1,2 @@
+SELECT count(1)
+FROM stock_movements
\ No newline at end of file
@@ -0,0 +1,9 @@
+SELECT movement_id,
+       org_id,
+       product_id,
+       reservation_id,
+       kind,
+       delta,
+       quantity,
+       date_created
+FROM stock_movements
\ No newline at end of file
@@ -0,0 +1,28 @@
+WITH reservation AS (
+    UPDATE stock_reservations
+        SET status = :released,
+            date_updated = :date_created
+        WHERE reservation_id = :reservation_id
+            AND status = :pending
+        RETURNING reservation_id, org_id, product_id, quantity),
+     product AS (
+         UPDATE products
+             SET quantity = products.quantity + reservation.quantity,
+                 date_updated = :date_created
+             FROM reservation
+             WHERE products.product_id = reservation.product_id
+             RETURNING products.quantity)
+INSERT
+INTO stock_movements
+    (movement_id, org_id, product_id, reservation_id, kind, delta, quantity, date_created)
+SELECT CAST(:movement_id AS UUID),
+       reservation.org_id,
+       reservation.product_id,
+       reservation.reservation_id,
+       CAST(:kind AS TEXT),
+       reservation.quantity,
+       product.quantity,
+       CAST(:date_created AS TIMESTAMP)
+FROM reservation,
+     product
+RETURNING org_id, quantity
\ No newline at end of file
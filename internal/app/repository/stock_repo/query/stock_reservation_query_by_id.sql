@@ -0,0 +1,11 @@
+SELECT reservation_id,
+       org_id,
+       product_id,
+       quantity,
+       status,
+       expires_at,
+       date_created,
+       date_updated
+FROM stock_reservations
+WHERE reservation_id = :reservation_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
@@ -0,0 +1,13 @@
+SELECT reservation_id,
+       org_id,
+       product_id,
+       quantity,
+       status,
+       expires_at,
+       date_created,
+       date_updated
+FROM stock_reservations
+WHERE status = :pending
+  AND expires_at <= :now
+ORDER BY expires_at
+FETCH FIRST :limit ROWS ONLY
\ No newline at end of file
@@ -0,0 +1,35 @@
+WITH product AS (
+    UPDATE products
+        SET quantity = quantity - :quantity,
+            date_updated = :date_created
+        WHERE product_id = :product_id
+            AND quantity >= :quantity
+            AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
+        RETURNING org_id, quantity),
+     reservation AS (
+         INSERT INTO stock_reservations
+             (reservation_id, org_id, product_id, quantity, status, expires_at, date_created, date_updated)
+             SELECT CAST(:reservation_id AS UUID),
+                    product.org_id,
+                    CAST(:product_id AS UUID),
+                    CAST(:quantity AS INT),
+                    CAST(:status AS TEXT),
+                    CAST(:expires_at AS TIMESTAMP),
+                    CAST(:date_created AS TIMESTAMP),
+                    CAST(:date_updated AS TIMESTAMP)
+             FROM product
+             RETURNING reservation_id)
+INSERT
+INTO stock_movements
+    (movement_id, org_id, product_id, reservation_id, kind, delta, quantity, date_created)
+SELECT CAST(:movement_id AS UUID),
+       product.org_id,
+       CAST(:product_id AS UUID),
+       reservation.reservation_id,
+       CAST(:kind AS TEXT),
+       CAST(:delta AS INT),
+       product.quantity,
+       CAST(:date_created AS TIMESTAMP)
+FROM product,
+     reservation
+RETURNING org_id, quantity
\ No newline at end of file
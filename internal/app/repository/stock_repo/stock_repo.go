@@ -0,0 +1,281 @@
+// Package stock_repo contains stock related CRUD functionality. Every change
+// of the stock is a single statement updating the quantity of the product
+// and recording the movement, the row of the product is locked by the update
+// so the concurrent changes are applied one after the other.
+package stock_repo
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// queries
+var (
+	//go:embed query/stock_move.sql
+	stockMoveSql string
+	//go:embed query/stock_reserve.sql
+	stockReserveSql string
+	//go:embed query/stock_commit.sql
+	stockCommitSql string
+	//go:embed query/stock_release.sql
+	stockReleaseSql string
+	//go:embed query/stock_reservation_query_by_id.sql
+	stockReservationQueryByIdSql string
+	//go:embed query/stock_reservation_query_expired.sql
+	stockReservationQueryExpiredSql string
+	//go:embed query/stock_movement_query.sql
+	stockMovementQuerySql string
+	//go:embed query/stock_movement_count.sql
+	stockMovementCountSql string
+)
+
+// Store manages the set of APIs for stock database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// NewWithTx constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (stock.Storer, error) {
+	ec, err := pgsql.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	store := Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return &store, nil
+}
+
+// Move changes the quantity of the product by the delta of the movement and
+// records it. It returns the movement with the organization and the
+// quantity of the product populated.
+func (s *Store) Move(ctx context.Context, mov stock.Movement) (stock.Movement, error) {
+	data := struct {
+		movementDB
+		MaxQuantity int        `db:"max_quantity"`
+		ScopeOrgID  *uuid.UUID `db:"scope_org_id"`
+	}{
+		movementDB:  toDBMovement(mov),
+		MaxQuantity: quantity.Max,
+		ScopeOrgID:  organization.ScopeID(ctx),
+	}
+
+	var dest stockDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, stockMoveSql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return stock.Movement{}, fmt.Errorf("db: %w", stock.ErrInsufficientStock)
+		}
+		return stock.Movement{}, fmt.Errorf("db: %w", err)
+	}
+
+	mov.OrgID = dest.OrgID
+	mov.Quantity = dest.Quantity
+
+	return mov, nil
+}
+
+// Reserve takes the quantity of the reservation from the product, and
+// records the reservation along with the movement.
+func (s *Store) Reserve(ctx context.Context, res stock.Reservation, mov stock.Movement) (stock.Movement, error) {
+	data := struct {
+		reservationDB
+		MovementID uuid.UUID  `db:"movement_id"`
+		Kind       string     `db:"kind"`
+		Delta      int        `db:"delta"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+	}{
+		reservationDB: toDBReservation(res),
+		MovementID:    mov.ID,
+		Kind:          mov.Kind.String(),
+		Delta:         mov.Delta,
+		ScopeOrgID:    organization.ScopeID(ctx),
+	}
+
+	var dest stockDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, stockReserveSql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return stock.Movement{}, fmt.Errorf("db: %w", stock.ErrInsufficientStock)
+		}
+		return stock.Movement{}, fmt.Errorf("db: %w", err)
+	}
+
+	mov.OrgID = dest.OrgID
+	mov.Quantity = dest.Quantity
+
+	return mov, nil
+}
+
+// Commit marks the reservation as committed. The update only applies to a
+// pending reservation not expired yet, so it can't be both committed and
+// released by concurrent requests.
+func (s *Store) Commit(ctx context.Context, res stock.Reservation, now time.Time) error {
+	data := struct {
+		ID          uuid.UUID `db:"reservation_id"`
+		Pending     string    `db:"pending"`
+		Committed   string    `db:"committed"`
+		DateUpdated time.Time `db:"date_updated"`
+	}{
+		ID:          res.ID,
+		Pending:     stock.Pending.String(),
+		Committed:   stock.Committed.String(),
+		DateUpdated: now.UTC(),
+	}
+
+	var dest struct {
+		ID uuid.UUID `db:"reservation_id"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, stockCommitSql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return fmt.Errorf("db: %w", stock.ErrReservationClosed)
+		}
+		return fmt.Errorf("db: %w", err)
+	}
+
+	return nil
+}
+
+// Release marks the reservation as released and gives its quantity back to
+// the product. The update only applies to a pending reservation.
+func (s *Store) Release(ctx context.Context, res stock.Reservation, mov stock.Movement) (stock.Movement, error) {
+	data := struct {
+		ID          uuid.UUID `db:"reservation_id"`
+		MovementID  uuid.UUID `db:"movement_id"`
+		Kind        string    `db:"kind"`
+		Pending     string    `db:"pending"`
+		Released    string    `db:"released"`
+		DateCreated time.Time `db:"date_created"`
+	}{
+		ID:          res.ID,
+		MovementID:  mov.ID,
+		Kind:        mov.Kind.String(),
+		Pending:     stock.Pending.String(),
+		Released:    stock.Released.String(),
+		DateCreated: mov.DateCreated.UTC(),
+	}
+
+	var dest stockDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, stockReleaseSql, data, &dest); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return stock.Movement{}, fmt.Errorf("db: %w", stock.ErrReservationClosed)
+		}
+		return stock.Movement{}, fmt.Errorf("db: %w", err)
+	}
+
+	mov.OrgID = dest.OrgID
+	mov.Quantity = dest.Quantity
+
+	return mov, nil
+}
+
+// QueryReservationByID gets the specified reservation from the database.
+func (s *Store) QueryReservationByID(ctx context.Context, reservationID uuid.UUID) (stock.Reservation, error) {
+	data := struct {
+		ID         string     `db:"reservation_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+	}{
+		ID:         reservationID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+
+	var dbRes reservationDB
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, stockReservationQueryByIdSql, data, &dbRes); err != nil {
+		if errors.Is(err, pgsql.ErrDBNotFound) {
+			return stock.Reservation{}, fmt.Errorf("db: %w", stock.ErrReservationNotFound)
+		}
+		return stock.Reservation{}, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusReservation(dbRes)
+}
+
+// QueryExpired gets up to limit pending reservations that expired by now,
+// the ones expired first come first.
+func (s *Store) QueryExpired(ctx context.Context, now time.Time, limit int) ([]stock.Reservation, error) {
+	data := struct {
+		Pending string    `db:"pending"`
+		Now     time.Time `db:"now"`
+		Limit   int       `db:"limit"`
+	}{
+		Pending: stock.Pending.String(),
+		Now:     now.UTC(),
+		Limit:   limit,
+	}
+
+	var dbRess []reservationDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, stockReservationQueryExpiredSql, data, &dbRess); err != nil {
+		return nil, fmt.Errorf("db: %w", err)
+	}
+
+	return toBusReservations(dbRess)
+}
+
+// Query gets the movements from the database.
+func (s *Store) Query(ctx context.Context, filter stock.QueryFilter, orderBy order.By, page page.Page) ([]stock.Movement, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	buf := bytes.NewBufferString(stockMovementQuerySql)
+	applyFilter(ctx, filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbMovs []movementDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbMovs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toBusMovements(dbMovs)
+}
+
+// Count returns the total number of movements in the DB.
+func (s *Store) Count(ctx context.Context, filter stock.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	buf := bytes.NewBufferString(stockMovementCountSql)
+	applyFilter(ctx, filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgsql.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("db: %w", err)
+	}
+
+	return count.Count, nil
+}
@@ -0,0 +1,271 @@
+package stock_repo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+func Test_Stock(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_Stock")
+
+	prds, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	t.Run("concurrent", func(t *testing.T) {
+		concurrent(t, db.Core, prds[0])
+	})
+
+	t.Run("reservations", func(t *testing.T) {
+		reservations(t, db.Core, prds[1])
+	})
+
+	unitest.Run(t, history(db.Core, prds[1]), "history")
+}
+
+// =============================================================================
+
+func insertSeedData(core dbtest.Core) ([]product.Product, error) {
+	ctx := context.Background()
+
+	usrs, err := usercore.TestSeedUsers(ctx, 1, role.User, core.User)
+	if err != nil {
+		return nil, fmt.Errorf("seeding users : %w", err)
+	}
+
+	prds, err := productcore.TestGenerateSeedProducts(ctx, 2, core.Product, usrs[0].ID)
+	if err != nil {
+		return nil, fmt.Errorf("seeding products : %w", err)
+	}
+
+	// The stock starts from a known quantity, recorded as an adjustment.
+	for i, prd := range prds {
+		prds[i], err = core.Product.Update(ctx, prd, product.UpdateProduct{
+			Quantity: dbtest.QuantityPointer(10),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("seeding quantity : %w", err)
+		}
+	}
+
+	return prds, nil
+}
+
+// =============================================================================
+
+// concurrent reserves and sells more than the stock at the same time, the
+// stock must run out without dropping below zero.
+func concurrent(t *testing.T, core dbtest.Core, prd product.Product) {
+	ctx := context.Background()
+
+	const workers = 30
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var err error
+			switch i % 2 {
+			case 0:
+				_, err = core.Stock.Reserve(ctx, stock.NewReservation{ProductID: prd.ID, Quantity: 1})
+			default:
+				_, err = core.Stock.Move(ctx, stock.NewMovement{ProductID: prd.ID, Kind: stock.Sale, Quantity: 1})
+			}
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	var taken int
+	for err := range errs {
+		switch {
+		case err == nil:
+			taken++
+		case errors.Is(err, stock.ErrInsufficientStock):
+		default:
+			t.Fatalf("Should only run out of stock: %s", err)
+		}
+	}
+
+	if taken != 10 {
+		t.Fatalf("Should take exactly the stock: got %d, exp 10", taken)
+	}
+
+	got, err := core.Product.QueryByID(ctx, prd.ID)
+	if err != nil {
+		t.Fatalf("Should get the product: %s", err)
+	}
+
+	if got.Quantity.Value() != 0 {
+		t.Fatalf("Should have no stock left: got %d", got.Quantity.Value())
+	}
+
+	movs, err := core.Stock.Query(ctx, stock.QueryFilter{ProductID: &prd.ID}, stock.DefaultOrderBy, page.MustParse("1", "100"))
+	if err != nil {
+		t.Fatalf("Should get the movements: %s", err)
+	}
+
+	var sum int
+	for _, mov := range movs {
+		if mov.Quantity < 0 {
+			t.Fatalf("Should never drop below zero: movement %s left %d", mov.ID, mov.Quantity)
+		}
+		sum += mov.Delta
+	}
+
+	if sum != got.Quantity.Value() {
+		t.Fatalf("Should derive the quantity from the movements: got %d, exp %d", sum, got.Quantity.Value())
+	}
+
+	if _, err := core.Stock.Move(ctx, stock.NewMovement{ProductID: prd.ID, Kind: stock.Receipt, Quantity: quantity.Max + 1}); !errors.Is(err, stock.ErrInsufficientStock) {
+		t.Fatalf("Should refuse more than the maximum quantity: %v", err)
+	}
+}
+
+// reservations commits, releases and expires reservations.
+func reservations(t *testing.T, core dbtest.Core, prd product.Product) {
+	ctx := context.Background()
+
+	committed, err := core.Stock.Reserve(ctx, stock.NewReservation{ProductID: prd.ID, Quantity: 3})
+	if err != nil {
+		t.Fatalf("Should reserve: %s", err)
+	}
+
+	if _, err := core.Stock.Commit(ctx, committed); err != nil {
+		t.Fatalf("Should commit: %s", err)
+	}
+
+	committed, err = core.Stock.QueryReservationByID(ctx, committed.ID)
+	if err != nil {
+		t.Fatalf("Should get the reservation: %s", err)
+	}
+
+	if _, err := core.Stock.Release(ctx, committed); !errors.Is(err, stock.ErrReservationClosed) {
+		t.Fatalf("Should not release a committed reservation: %v", err)
+	}
+
+	released, err := core.Stock.Reserve(ctx, stock.NewReservation{ProductID: prd.ID, Quantity: 2})
+	if err != nil {
+		t.Fatalf("Should reserve: %s", err)
+	}
+
+	if _, err := core.Stock.Release(ctx, released); err != nil {
+		t.Fatalf("Should release: %s", err)
+	}
+
+	expired, err := core.Stock.Reserve(ctx, stock.NewReservation{ProductID: prd.ID, Quantity: 4, TTL: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Should reserve: %s", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, err := core.Stock.Commit(ctx, expired); !errors.Is(err, stock.ErrReservationExpired) {
+		t.Fatalf("Should not commit an expired reservation: %v", err)
+	}
+
+	n, err := core.Stock.ReleaseExpired(ctx)
+	if err != nil {
+		t.Fatalf("Should release the expired reservations: %s", err)
+	}
+
+	if n != 1 {
+		t.Fatalf("Should release the expired reservation: got %d", n)
+	}
+
+	got, err := core.Product.QueryByID(ctx, prd.ID)
+	if err != nil {
+		t.Fatalf("Should get the product: %s", err)
+	}
+
+	// Only the committed reservation keeps its stock.
+	if got.Quantity.Value() != 7 {
+		t.Fatalf("Should keep the committed stock: got %d, exp 7", got.Quantity.Value())
+	}
+}
+
+// =============================================================================
+
+func history(core dbtest.Core, prd product.Product) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "kinds",
+			ExpResp: []string{"ADJUSTMENT", "RESERVATION", "RESERVATION", "RELEASE", "RESERVATION", "RELEASE"},
+			ExcFunc: func(ctx context.Context) any {
+				filter := stock.QueryFilter{
+					ProductID: &prd.ID,
+				}
+
+				orderBy := order.NewBy(stock.OrderByDateCreated, order.ASC)
+
+				movs, err := core.Stock.Query(ctx, filter, orderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				kinds := make([]string, 0, len(movs))
+				for _, mov := range movs {
+					// The seeded products start with a random quantity.
+					if mov.Kind.Equal(stock.Receipt) {
+						continue
+					}
+					kinds = append(kinds, mov.Kind.String())
+				}
+
+				return kinds
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+		{
+			Name:    "reservation",
+			ExpResp: 2,
+			ExcFunc: func(ctx context.Context) any {
+				kind := stock.Release
+				filter := stock.QueryFilter{
+					ProductID: &prd.ID,
+					Kind:      &kind,
+				}
+
+				count, err := core.Stock.Count(ctx, filter)
+				if err != nil {
+					return err
+				}
+
+				return count
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
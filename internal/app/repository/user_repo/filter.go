@@ -2,28 +2,38 @@ package user_repo
 
 import (
 	"bytes"
-	"fmt"
+	"context"
 	"strings"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 )
 
-func applyFilter(filter user.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+func applyFilter(ctx context.Context, keys Keys, filter user.QueryFilter, data map[string]any, buf *bytes.Buffer) {
 	var wc []string
 
+	if orgID, ok := organization.Scoped(ctx); ok {
+		data["org_id"] = orgID
+		wc = append(wc, "org_id = :org_id")
+	}
+
 	if filter.ID != nil {
 		data["user_id"] = *filter.ID
 		wc = append(wc, "user_id = :user_id")
 	}
 
+	// The name and the email are encrypted, they are matched as a whole by
+	// their blind index or as they are for a user not encrypted yet.
 	if filter.Name != nil {
-		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "name LIKE :name")
+		data["name"] = filter.Name.String()
+		data["name_index"] = keys.nameIndex(filter.Name.String())
+		wc = append(wc, "(name_index = :name_index OR (data_key IS NULL AND lower(name) = lower(:name)))")
 	}
 
 	if filter.Email != nil {
-		data["email"] = (*filter.Email).String()
-		wc = append(wc, "email = :email")
+		data["email"] = filter.Email.Address
+		data["email_index"] = keys.emailIndex(filter.Email.Address)
+		wc = append(wc, "(email_index = :email_index OR (data_key IS NULL AND email = :email))")
 	}
 
 	if filter.StartCreatedDate != nil {
@@ -0,0 +1,77 @@
+package user_repo
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/pkg/encrypt"
+)
+
+// Keys protects the personal data of the users. The name, email and
+// department of a user are encrypted under a data key of its own, wrapped by
+// the master key of the envelope, and are looked up by their blind index.
+type Keys struct {
+	Envelope *encrypt.Envelope
+	Index    *encrypt.BlindIndex
+}
+
+// LoadKeys constructs the keys from the <kid>.key files of the file system,
+// the master key wraps the new data keys while the other keys found, apart
+// from the index key, unwrap the older ones.
+func LoadKeys(fsys fs.FS, masterKID string, indexKID string) (Keys, error) {
+	keys, err := encrypt.ReadKeys(fsys)
+	if err != nil {
+		return Keys{}, fmt.Errorf("reading keys: %w", err)
+	}
+
+	indexKey, exists := keys[indexKID]
+	if !exists {
+		return Keys{}, fmt.Errorf("index key[%s] not found", indexKID)
+	}
+	delete(keys, indexKID)
+
+	master, err := encrypt.New(masterKID, keys)
+	if err != nil {
+		return Keys{}, fmt.Errorf("master keyring: %w", err)
+	}
+
+	index, err := encrypt.NewBlindIndex(indexKey)
+	if err != nil {
+		return Keys{}, fmt.Errorf("blind index: %w", err)
+	}
+
+	k := Keys{
+		Envelope: encrypt.NewEnvelope(master),
+		Index:    index,
+	}
+
+	return k, nil
+}
+
+// DepartmentIndex returns the blind index of the department, the database
+// session of a manager holds it for the row level security policies to
+// compare with the one of the users.
+func (k Keys) DepartmentIndex(department string) string {
+	if department == "" {
+		return ""
+	}
+
+	return k.Index.Sum("department:" + department)
+}
+
+func (k Keys) emailIndex(email string) string {
+	return k.Index.Sum("email:" + strings.ToLower(email))
+}
+
+func (k Keys) nameIndex(name string) string {
+	return k.Index.Sum("name:" + strings.ToLower(name))
+}
+
+// columnData binds an encrypted value to the user and the column it is
+// stored in, so it can't be moved to another one.
+func columnData(userID uuid.UUID, column string) []byte {
+	return append(userID[:], column...)
+}
@@ -1,6 +1,7 @@
 package user_repo
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/mail"
@@ -9,41 +10,157 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 	"github.com/Housiadas/backend-system/internal/core/domain/role"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/pkg/pgsql/dbarray"
 )
 
 type userDB struct {
-	ID           uuid.UUID      `db:"user_id"`
-	Name         string         `db:"name"`
-	Email        string         `db:"email"`
-	Roles        dbarray.String `db:"roles"`
-	PasswordHash []byte         `db:"password_hash"`
-	Department   sql.NullString `db:"department"`
-	Enabled      bool           `db:"enabled"`
-	DateCreated  time.Time      `db:"date_created"`
-	DateUpdated  time.Time      `db:"date_updated"`
+	ID               uuid.UUID      `db:"user_id"`
+	OrgID            uuid.UUID      `db:"org_id"`
+	Name             string         `db:"name"`
+	Email            string         `db:"email"`
+	Roles            dbarray.String `db:"roles"`
+	PasswordHash     []byte         `db:"password_hash"`
+	Department       sql.NullString `db:"department"`
+	Enabled          bool           `db:"enabled"`
+	DateCreated      time.Time      `db:"date_created"`
+	DateUpdated      time.Time      `db:"date_updated"`
+	TokensValidAfter sql.NullTime   `db:"tokens_valid_after"`
+	EmailVerified    bool           `db:"email_verified"`
+
+	// The name, email and department are encrypted under the data key, the
+	// rows without one were stored before the encryption and are plaintext.
+	DataKey         sql.NullString `db:"data_key"`
+	EmailIndex      sql.NullString `db:"email_index"`
+	NameIndex       sql.NullString `db:"name_index"`
+	DepartmentIndex sql.NullString `db:"department_index"`
 }
 
-func toUserDB(usr user.User) userDB {
-	return userDB{
+func toUserDB(keys Keys, usr user.User) (userDB, error) {
+	dk, wrapped, err := keys.Envelope.GenerateDataKey(usr.ID[:])
+	if err != nil {
+		return userDB{}, err
+	}
+
+	seal := func(column string, value string) (string, error) {
+		ciphertext, err := dk.Encrypt([]byte(value), columnData(usr.ID, column))
+		if err != nil {
+			return "", fmt.Errorf("encrypt %s: %w", column, err)
+		}
+		return ciphertext, nil
+	}
+
+	nme, err := seal("name", usr.Name.String())
+	if err != nil {
+		return userDB{}, err
+	}
+
+	email, err := seal("email", usr.Email.Address)
+	if err != nil {
+		return userDB{}, err
+	}
+
+	var department, departmentIndex sql.NullString
+	if usr.Department.Valid() {
+		ciphertext, err := seal("department", usr.Department.String())
+		if err != nil {
+			return userDB{}, err
+		}
+
+		department = sql.NullString{String: ciphertext, Valid: true}
+		departmentIndex = sql.NullString{String: keys.DepartmentIndex(usr.Department.String()), Valid: true}
+	}
+
+	db := userDB{
 		ID:           usr.ID,
-		Name:         usr.Name.String(),
-		Email:        usr.Email.Address,
+		OrgID:        usr.OrgID,
+		Name:         nme,
+		Email:        email,
 		Roles:        role.ParseToString(usr.Roles),
 		PasswordHash: usr.PasswordHash,
-		Department: sql.NullString{
-			String: usr.Department.String(),
-			Valid:  usr.Department.Valid(),
+		Department:   department,
+		Enabled:      usr.Enabled,
+		DateCreated:  usr.DateCreated.UTC(),
+		DateUpdated:  usr.DateUpdated.UTC(),
+		TokensValidAfter: sql.NullTime{
+			Time:  usr.TokensValidAfter.UTC(),
+			Valid: !usr.TokensValidAfter.IsZero(),
 		},
-		Enabled:     usr.Enabled,
-		DateCreated: usr.DateCreated.UTC(),
-		DateUpdated: usr.DateUpdated.UTC(),
+		EmailVerified:   usr.EmailVerified,
+		DataKey:         sql.NullString{String: wrapped, Valid: true},
+		EmailIndex:      sql.NullString{String: keys.emailIndex(usr.Email.Address), Valid: true},
+		NameIndex:       sql.NullString{String: keys.nameIndex(usr.Name.String()), Valid: true},
+		DepartmentIndex: departmentIndex,
+	}
+
+	return db, nil
+}
+
+// scopedUserDB is a user along with the organization the query is scoped to,
+// a user of another organization is left untouched.
+type scopedUserDB struct {
+	userDB
+	ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+}
+
+func toScopedUserDB(ctx context.Context, keys Keys, usr user.User) (scopedUserDB, error) {
+	db, err := toUserDB(keys, usr)
+	if err != nil {
+		return scopedUserDB{}, err
 	}
+
+	sdb := scopedUserDB{
+		userDB:     db,
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+
+	return sdb, nil
+}
+
+// decrypt replaces the encrypted values of the user with their plaintext.
+func decrypt(keys Keys, db userDB) (userDB, error) {
+	if !db.DataKey.Valid {
+		return db, nil
+	}
+
+	dk, err := keys.Envelope.DataKey(db.DataKey.String, db.ID[:])
+	if err != nil {
+		return userDB{}, err
+	}
+
+	open := func(column string, ciphertext string) (string, error) {
+		plaintext, err := dk.Decrypt(ciphertext, columnData(db.ID, column))
+		if err != nil {
+			return "", fmt.Errorf("decrypt %s: %w", column, err)
+		}
+		return string(plaintext), nil
+	}
+
+	if db.Name, err = open("name", db.Name); err != nil {
+		return userDB{}, err
+	}
+
+	if db.Email, err = open("email", db.Email); err != nil {
+		return userDB{}, err
+	}
+
+	if db.Department.Valid {
+		if db.Department.String, err = open("department", db.Department.String); err != nil {
+			return userDB{}, err
+		}
+	}
+
+	return db, nil
 }
 
-func toUserDomain(db userDB) (user.User, error) {
+func toUserDomain(keys Keys, db userDB) (user.User, error) {
+	db, err := decrypt(keys, db)
+	if err != nil {
+		return user.User{}, err
+	}
+
 	addr := mail.Address{
 		Address: db.Email,
 	}
@@ -64,26 +181,32 @@ func toUserDomain(db userDB) (user.User, error) {
 	}
 
 	bus := user.User{
-		ID:           db.ID,
-		Name:         nme,
-		Email:        addr,
-		Roles:        roles,
-		PasswordHash: db.PasswordHash,
-		Enabled:      db.Enabled,
-		Department:   department,
-		DateCreated:  db.DateCreated.In(time.Local),
-		DateUpdated:  db.DateUpdated.In(time.Local),
+		ID:            db.ID,
+		OrgID:         db.OrgID,
+		Name:          nme,
+		Email:         addr,
+		Roles:         roles,
+		PasswordHash:  db.PasswordHash,
+		Enabled:       db.Enabled,
+		EmailVerified: db.EmailVerified,
+		Department:    department,
+		DateCreated:   db.DateCreated.In(time.Local),
+		DateUpdated:   db.DateUpdated.In(time.Local),
+	}
+
+	if db.TokensValidAfter.Valid {
+		bus.TokensValidAfter = db.TokensValidAfter.Time.In(time.Local)
 	}
 
 	return bus, nil
 }
 
-func toUsersDomain(dbs []userDB) ([]user.User, error) {
+func toUsersDomain(keys Keys, dbs []userDB) ([]user.User, error) {
 	bus := make([]user.User, len(dbs))
 
 	for i, db := range dbs {
 		var err error
-		bus[i], err = toUserDomain(db)
+		bus[i], err = toUserDomain(keys, db)
 		if err != nil {
 			return nil, err
 		}
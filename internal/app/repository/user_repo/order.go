@@ -9,8 +9,6 @@ import (
 
 var orderByFields = map[string]string{
 	user.OrderByID:      "user_id",
-	user.OrderByName:    "name",
-	user.OrderByEmail:   "email",
 	user.OrderByRoles:   "roles",
 	user.OrderByEnabled: "enabled",
 }
@@ -1,3 +1,5 @@
 INSERT INTO users
-(user_id, name, email, password_hash, roles, department, enabled, date_created, date_updated)
-VALUES (:user_id, :name, :email, :password_hash, :roles, :department, :enabled, :date_created, :date_updated)
\ No newline at end of file
+(user_id, org_id, name, email, password_hash, roles, department, enabled, email_verified, date_created, date_updated,
+ data_key, email_index, name_index, department_index)
+VALUES (:user_id, :org_id, :name, :email, :password_hash, :roles, :department, :enabled, :email_verified, :date_created, :date_updated,
+        :data_key, :email_index, :name_index, :department_index)
\ No newline at end of file
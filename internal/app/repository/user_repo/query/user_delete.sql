@@ -1,3 +1,4 @@
 DELETE
 FROM users
-WHERE user_id = :user_id
\ No newline at end of file
+WHERE user_id = :user_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
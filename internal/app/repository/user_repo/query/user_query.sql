@@ -1,4 +1,5 @@
 SELECT user_id,
+       org_id,
        name,
        email,
        password_hash,
@@ -6,5 +7,8 @@ SELECT user_id,
        department,
        enabled,
        date_created,
-       date_updated
+       date_updated,
+       tokens_valid_after,
+       email_verified,
+       data_key
 FROM users
\ No newline at end of file
@@ -1,4 +1,5 @@
 SELECT user_id,
+       org_id,
        name,
        email,
        password_hash,
@@ -6,6 +7,10 @@ SELECT user_id,
        department,
        enabled,
        date_created,
-       date_updated
+       date_updated,
+       tokens_valid_after,
+       email_verified,
+       data_key
 FROM users
-WHERE user_id = :user_id
\ No newline at end of file
+WHERE user_id = :user_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
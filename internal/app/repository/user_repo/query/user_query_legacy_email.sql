@@ -0,0 +1,5 @@
+SELECT count(1)
+FROM users
+WHERE data_key IS NULL
+  AND lower(email) = lower(:email)
+  AND user_id <> :user_id
\ No newline at end of file
@@ -0,0 +1,18 @@
+SELECT user_id,
+       org_id,
+       name,
+       email,
+       password_hash,
+       roles,
+       department,
+       enabled,
+       date_created,
+       date_updated,
+       tokens_valid_after,
+       email_verified,
+       data_key
+FROM users
+WHERE data_key IS NULL
+   OR split_part(data_key, ':', 1) <> :master_kid
+ORDER BY user_id
+FETCH FIRST :batch ROWS ONLY
\ No newline at end of file
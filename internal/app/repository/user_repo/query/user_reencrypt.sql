@@ -0,0 +1,11 @@
+UPDATE
+    users
+SET "name"             = :name,
+    "email"            = :email,
+    "department"       = :department,
+    "data_key"         = :data_key,
+    "email_index"      = :email_index,
+    "name_index"       = :name_index,
+    "department_index" = :department_index
+WHERE user_id = :user_id
+  AND data_key IS NOT DISTINCT FROM :prev_data_key
\ No newline at end of file
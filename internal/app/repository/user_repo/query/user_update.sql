@@ -1,10 +1,17 @@
 UPDATE
     users
-SET "name"          = :name,
-    "email"         = :email,
-    "roles"         = :roles,
-    "password_hash" = :password_hash,
-    "department"    = :department,
-    "enabled"       = :enabled,
-    "date_updated"  = :date_updated
-WHERE user_id = :user_id
\ No newline at end of file
+SET "name"               = :name,
+    "email"              = :email,
+    "roles"              = :roles,
+    "password_hash"      = :password_hash,
+    "department"         = :department,
+    "enabled"            = :enabled,
+    "date_updated"       = :date_updated,
+    "tokens_valid_after" = :tokens_valid_after,
+    "email_verified"     = :email_verified,
+    "data_key"           = :data_key,
+    "email_index"        = :email_index,
+    "name_index"         = :name_index,
+    "department_index"   = :department_index
+WHERE user_id = :user_id
+  AND (CAST(:scope_org_id AS UUID) IS NULL OR org_id = :scope_org_id)
\ No newline at end of file
@@ -4,6 +4,7 @@ package user_repo
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	_ "embed"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/pkg/logger"
 	"github.com/Housiadas/backend-system/pkg/order"
@@ -35,19 +37,28 @@ var (
 	userQueryByEmailSql string
 	//go:embed query/user_count.sql
 	userCountSql string
+	//go:embed query/user_query_unencrypted.sql
+	userQueryUnencryptedSql string
+	//go:embed query/user_reencrypt.sql
+	userReEncryptSql string
+	//go:embed query/user_query_legacy_email.sql
+	userQueryLegacyEmailSql string
 )
 
 // Store manages the set of APIs for userDB database access.
 type Store struct {
-	log *logger.Logger
-	db  sqlx.ExtContext
+	log  *logger.Logger
+	db   sqlx.ExtContext
+	keys Keys
 }
 
-// NewStore constructs the api for data access.
-func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+// NewStore constructs the api for data access, the personal data of the
+// users is encrypted with the keys.
+func NewStore(log *logger.Logger, db *sqlx.DB, keys Keys) *Store {
 	return &Store{
-		log: log,
-		db:  db,
+		log:  log,
+		db:   db,
+		keys: keys,
 	}
 }
 
@@ -60,8 +71,9 @@ func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (user.Storer, error) {
 	}
 
 	store := Store{
-		log: s.log,
-		db:  ec,
+		log:  s.log,
+		db:   ec,
+		keys: s.keys,
 	}
 
 	return &store, nil
@@ -69,7 +81,16 @@ func (s *Store) NewWithTx(tx pgsql.CommitRollbacker) (user.Storer, error) {
 
 // Create inserts a new userDB into the database.
 func (s *Store) Create(ctx context.Context, usr user.User) error {
-	if err := pgsql.NamedExecContext(ctx, s.log, s.db, userCreateSql, toUserDB(usr)); err != nil {
+	if err := s.checkLegacyEmail(ctx, usr); err != nil {
+		return err
+	}
+
+	dbUsr, err := toUserDB(s.keys, usr)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, userCreateSql, dbUsr); err != nil {
 		if errors.Is(err, pgsql.ErrDBDuplicatedEntry) {
 			return fmt.Errorf("namedexeccontext: %w", user.ErrUniqueEmail)
 		}
@@ -81,7 +102,16 @@ func (s *Store) Create(ctx context.Context, usr user.User) error {
 
 // Update replaces a userDB document in the database.
 func (s *Store) Update(ctx context.Context, usr user.User) error {
-	if err := pgsql.NamedExecContext(ctx, s.log, s.db, userUpdateSql, toUserDB(usr)); err != nil {
+	if err := s.checkLegacyEmail(ctx, usr); err != nil {
+		return err
+	}
+
+	dbUsr, err := toScopedUserDB(ctx, s.keys, usr)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, userUpdateSql, dbUsr); err != nil {
 		if errors.Is(err, pgsql.ErrDBDuplicatedEntry) {
 			return user.ErrUniqueEmail
 		}
@@ -91,9 +121,44 @@ func (s *Store) Update(ctx context.Context, usr user.User) error {
 	return nil
 }
 
+// checkLegacyEmail refuses the email of the user when another user not
+// encrypted yet holds it, the unique index of the email doesn't see these
+// users until they are encrypted. Every user is checked, not only the ones
+// the caller reaches.
+func (s *Store) checkLegacyEmail(ctx context.Context, usr user.User) error {
+	data := struct {
+		ID    uuid.UUID `db:"user_id"`
+		Email string    `db:"email"`
+	}{
+		ID:    usr.ID,
+		Email: usr.Email.Address,
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := pgsql.NamedQueryStruct(pgsql.WithoutCaller(ctx), s.log, s.db, userQueryLegacyEmailSql, data, &count); err != nil {
+		return fmt.Errorf("db: %w", err)
+	}
+
+	if count.Count > 0 {
+		return fmt.Errorf("legacy email: %w", user.ErrUniqueEmail)
+	}
+
+	return nil
+}
+
 // Delete removes a userDB from the database.
 func (s *Store) Delete(ctx context.Context, usr user.User) error {
-	if err := pgsql.NamedExecContext(ctx, s.log, s.db, userDeleteSql, toUserDB(usr)); err != nil {
+	data := struct {
+		ID         string     `db:"user_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
+	}{
+		ID:         usr.ID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
+	}
+
+	if err := pgsql.NamedExecContext(ctx, s.log, s.db, userDeleteSql, data); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -108,7 +173,7 @@ func (s *Store) Query(ctx context.Context, filter user.QueryFilter, orderBy orde
 	}
 
 	buf := bytes.NewBufferString(userQuerySql)
-	applyFilter(filter, data, buf)
+	applyFilter(ctx, s.keys, filter, data, buf)
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -123,14 +188,14 @@ func (s *Store) Query(ctx context.Context, filter user.QueryFilter, orderBy orde
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
-	return toUsersDomain(dbUsrs)
+	return toUsersDomain(s.keys, dbUsrs)
 }
 
 // Count returns the total number of users in the DB.
 func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error) {
 	data := map[string]any{}
 	buf := bytes.NewBufferString(userCountSql)
-	applyFilter(filter, data, buf)
+	applyFilter(ctx, s.keys, filter, data, buf)
 
 	var count struct {
 		Count int `db:"count"`
@@ -145,9 +210,11 @@ func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error)
 // QueryByID gets the specified userDB from the database.
 func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, error) {
 	data := struct {
-		ID string `db:"user_id"`
+		ID         string     `db:"user_id"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
 	}{
-		ID: userID.String(),
+		ID:         userID.String(),
+		ScopeOrgID: organization.ScopeID(ctx),
 	}
 
 	var dbUsr userDB
@@ -158,15 +225,20 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, err
 		return user.User{}, fmt.Errorf("db: %w", err)
 	}
 
-	return toUserDomain(dbUsr)
+	return toUserDomain(s.keys, dbUsr)
 }
 
-// QueryByEmail gets the specified userDB from the database by email.
+// QueryByEmail gets the specified userDB from the database by the blind
+// index of the email, or by the email of a user not encrypted yet.
 func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (user.User, error) {
 	data := struct {
-		Email string `db:"email"`
+		Email      string     `db:"email"`
+		EmailIndex string     `db:"email_index"`
+		ScopeOrgID *uuid.UUID `db:"scope_org_id"`
 	}{
-		Email: email.Address,
+		Email:      email.Address,
+		EmailIndex: s.keys.emailIndex(email.Address),
+		ScopeOrgID: organization.ScopeID(ctx),
 	}
 
 	var dbUsr userDB
@@ -177,5 +249,50 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (user.User
 		return user.User{}, fmt.Errorf("db: %w", err)
 	}
 
-	return toUserDomain(dbUsr)
+	return toUserDomain(s.keys, dbUsr)
+}
+
+// ReEncrypt encrypts again, under new data keys wrapped by the active master
+// key, up to batch users whose data key is wrapped by another master key or
+// who are not encrypted yet. It returns the number of users encrypted again:
+// the users done no longer match, so a run stopped halfway resumes where it
+// stopped and the work is over once it returns zero.
+func (s *Store) ReEncrypt(ctx context.Context, batch int) (int, error) {
+	data := map[string]any{
+		"master_kid": s.keys.Envelope.MasterKID(),
+		"batch":      batch,
+	}
+
+	var dbUsrs []userDB
+	if err := pgsql.NamedQuerySlice(ctx, s.log, s.db, userQueryUnencryptedSql, data, &dbUsrs); err != nil {
+		return 0, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	for _, dbUsr := range dbUsrs {
+		usr, err := toUserDomain(s.keys, dbUsr)
+		if err != nil {
+			return 0, fmt.Errorf("user[%s]: %w", dbUsr.ID, err)
+		}
+
+		newUsr, err := toUserDB(s.keys, usr)
+		if err != nil {
+			return 0, fmt.Errorf("user[%s]: encrypt: %w", dbUsr.ID, err)
+		}
+
+		// The user is left untouched if updated in the meantime, it is
+		// then already encrypted under the active master key.
+		reencrypt := struct {
+			userDB
+			PrevDataKey sql.NullString `db:"prev_data_key"`
+		}{
+			userDB:      newUsr,
+			PrevDataKey: dbUsr.DataKey,
+		}
+
+		if err := pgsql.NamedExecContext(ctx, s.log, s.db, userReEncryptSql, reencrypt); err != nil {
+			return 0, fmt.Errorf("user[%s]: namedexeccontext: %w", dbUsr.ID, err)
+		}
+	}
+
+	return len(dbUsrs), nil
 }
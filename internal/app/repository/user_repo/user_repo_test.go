@@ -2,22 +2,27 @@ package user_repo_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/mail"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
 
+	"github.com/Housiadas/backend-system/internal/app/repository/user_repo"
 	"github.com/Housiadas/backend-system/internal/common/dbtest"
 	"github.com/Housiadas/backend-system/internal/common/unitest"
 	"github.com/Housiadas/backend-system/internal/core/domain/name"
 	"github.com/Housiadas/backend-system/internal/core/domain/role"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/encrypt"
 	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/passhash"
 )
 
 func Test_User(t *testing.T) {
@@ -34,8 +39,11 @@ func Test_User(t *testing.T) {
 
 	unitest.Run(t, query(db.Core, sd), "query")
 	unitest.Run(t, create(db.Core), "create")
+	unitest.Run(t, legacyEmail(db, sd), "legacy-email")
 	unitest.Run(t, update(db.Core, sd), "update")
 	unitest.Run(t, deleteUser(db.Core, sd), "delete")
+	unitest.Run(t, rehash(db), "rehash")
+	unitest.Run(t, reencrypt(db, sd), "reencrypt")
 }
 
 // =============================================================================
@@ -103,11 +111,7 @@ func query(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
 			Name:    "all",
 			ExpResp: usrs,
 			ExcFunc: func(ctx context.Context) any {
-				filter := user.QueryFilter{
-					Name: dbtest.NamePointer("Name"),
-				}
-
-				resp, err := busDomain.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "10"))
+				resp, err := busDomain.User.Query(ctx, user.QueryFilter{}, user.DefaultOrderBy, page.MustParse("1", "10"))
 				if err != nil {
 					return err
 				}
@@ -162,6 +166,45 @@ func query(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
 					expResp.DateUpdated = gotResp.DateUpdated
 				}
 
+				return cmp.Diff(gotResp, expResp)
+			},
+		},
+		{
+			Name:    "byname",
+			ExpResp: sd.Users[0].User,
+			ExcFunc: func(ctx context.Context) any {
+				// The name is matched as a whole, regardless of the case.
+				filter := user.QueryFilter{
+					Name: dbtest.NamePointer(strings.ToUpper(sd.Users[0].Name.String())),
+				}
+
+				resp, err := busDomain.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "10"))
+				if err != nil {
+					return err
+				}
+
+				if len(resp) != 1 {
+					return fmt.Errorf("expected 1 user, got %d", len(resp))
+				}
+
+				return resp[0]
+			},
+			CmpFunc: func(got any, exp any) string {
+				gotResp, exists := got.(user.User)
+				if !exists {
+					return "error occurred"
+				}
+
+				expResp := exp.(user.User)
+
+				if gotResp.DateCreated.Format(time.RFC3339) == expResp.DateCreated.Format(time.RFC3339) {
+					expResp.DateCreated = gotResp.DateCreated
+				}
+
+				if gotResp.DateUpdated.Format(time.RFC3339) == expResp.DateUpdated.Format(time.RFC3339) {
+					expResp.DateUpdated = gotResp.DateUpdated
+				}
+
 				return cmp.Diff(gotResp, expResp)
 			},
 		},
@@ -205,8 +248,8 @@ func create(busDomain dbtest.Core) []unitest.Table {
 					return "error occurred"
 				}
 
-				if err := bcrypt.CompareHashAndPassword(gotResp.PasswordHash, []byte("123")); err != nil {
-					return err.Error()
+				if ok, err := passhash.Verify(string(gotResp.PasswordHash), "123"); !ok {
+					return fmt.Sprintf("password not verified: %v", err)
 				}
 
 				expResp := exp.(user.User)
@@ -224,6 +267,42 @@ func create(busDomain dbtest.Core) []unitest.Table {
 	return table
 }
 
+func legacyEmail(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "taken",
+			ExpResp: []bool{true, true},
+			ExcFunc: func(ctx context.Context) any {
+				// A user stored before the encryption, the reencrypt command
+				// didn't reach yet.
+				const q = `INSERT INTO users
+    (user_id, org_id, name, email, roles, password_hash, enabled, date_created, date_updated)
+VALUES ($1, $2, 'Legacy User', 'legacy.taken@example.com', '{USER}', 'hash', TRUE, now(), now())`
+				if _, err := db.DB.ExecContext(ctx, q, uuid.New(), sd.Users[0].OrgID); err != nil {
+					return err
+				}
+
+				_, err := db.Core.User.Create(ctx, user.NewUser{
+					Name:     name.MustParse("Legacy Again"),
+					Email:    mail.Address{Address: "Legacy.Taken@example.com"},
+					Roles:    []role.Role{role.User},
+					Password: "123",
+				})
+
+				email := mail.Address{Address: "legacy.taken@example.com"}
+				_, updErr := db.Core.User.Update(ctx, sd.Users[0].User, user.UpdateUser{Email: &email})
+
+				return []bool{errors.Is(err, user.ErrUniqueEmail), errors.Is(updErr, user.ErrUniqueEmail)}
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
+
 func update(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
 	email, _ := mail.ParseAddress("chris2@housi.com")
 
@@ -261,8 +340,8 @@ func update(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
 					return "error occurred"
 				}
 
-				if err := bcrypt.CompareHashAndPassword(gotResp.PasswordHash, []byte("1234")); err != nil {
-					return err.Error()
+				if ok, err := passhash.Verify(string(gotResp.PasswordHash), "1234"); !ok {
+					return fmt.Sprintf("password not verified: %v", err)
 				}
 
 				expResp := exp.(user.User)
@@ -312,3 +391,98 @@ func deleteUser(busDomain dbtest.Core, sd unitest.SeedData) []unitest.Table {
 
 	return table
 }
+
+func rehash(db *dbtest.Database) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "bcrypt",
+			ExpResp: "$argon2id$",
+			ExcFunc: func(ctx context.Context) any {
+				legacy := usercore.NewCoreWithHasher(db.Log, user_repo.NewStore(db.Log, db.DB, dbtest.UserKeys()), passhash.NewBcrypt(4))
+
+				nu := user.NewUser{
+					Name:     name.MustParse("Legacy User"),
+					Email:    mail.Address{Address: "legacy@example.com"},
+					Roles:    []role.Role{role.User},
+					Password: "legacy password",
+				}
+
+				usr, err := legacy.Create(ctx, nu)
+				if err != nil {
+					return err
+				}
+
+				if _, err := db.Core.User.Authenticate(ctx, usr.Email, "legacy password"); err != nil {
+					return err
+				}
+
+				got, err := db.Core.User.QueryByID(ctx, usr.ID)
+				if err != nil {
+					return err
+				}
+
+				return string(got.PasswordHash)[:len("$argon2id$")]
+			},
+			CmpFunc: func(got any, exp any) string {
+				if got != exp {
+					return fmt.Sprintf("got %v, exp %v", got, exp)
+				}
+				return ""
+			},
+		},
+	}
+
+	return table
+}
+
+func reencrypt(db *dbtest.Database, sd unitest.SeedData) []unitest.Table {
+	table := []unitest.Table{
+		{
+			Name:    "rotate",
+			ExpResp: sd.Users[0].Name.String(),
+			ExcFunc: func(ctx context.Context) any {
+				keys := dbtest.RotatedUserKeys()
+				store := user_repo.NewStore(db.Log, db.DB, keys)
+
+				var total int
+				for {
+					n, err := store.ReEncrypt(ctx, 2)
+					if err != nil {
+						return err
+					}
+
+					if n == 0 {
+						break
+					}
+					total += n
+				}
+
+				count, err := db.Core.User.Count(ctx, user.QueryFilter{})
+				if err != nil {
+					return err
+				}
+
+				if total != count {
+					return fmt.Errorf("expected %d users encrypted again, got %d", count, total)
+				}
+
+				// The previous master key no longer unwraps the data keys.
+				if _, err := db.Core.User.QueryByID(ctx, sd.Users[0].ID); !errors.Is(err, encrypt.ErrUnknownKey) {
+					return fmt.Errorf("expected an unknown key, got %v", err)
+				}
+
+				usr, err := usercore.NewCore(db.Log, store).QueryByEmail(ctx, sd.Users[0].Email)
+				if err != nil {
+					return err
+				}
+
+				return usr.Name.String()
+			},
+			CmpFunc: func(got any, exp any) string {
+				return cmp.Diff(got, exp)
+			},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,249 @@
+// Package account_usecase maintains the app layer api for the account core.
+package account_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/accountcore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	orderPck "github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+// App manages the set of app layer api functions for the account core. The
+// account is the one of the request, set by the account middleware. The
+// transfers run in serializable transactions of their own, so they are not
+// run in the transaction of the request.
+type App struct {
+	accountBus *accountcore.Core
+}
+
+// NewApp constructs an account app API for use.
+func NewApp(accountBus *accountcore.Core) *App {
+	return &App{
+		accountBus: accountBus,
+	}
+}
+
+// Create opens an account with no money for the caller.
+func (a *App) Create(ctx context.Context, app NewAccount) (Account, error) {
+	userID, err := ctxPck.GetUserID(ctx)
+	if err != nil {
+		return Account{}, errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	na, err := toBusNewAccount(userID, app)
+	if err != nil {
+		return Account{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	acct, err := a.accountBus.Create(ctx, na)
+	if err != nil {
+		return Account{}, toError(err, "create: userID[%s]: %s", userID)
+	}
+
+	return toAppAccount(acct), nil
+}
+
+// QueryByID returns the account of the request.
+func (a *App) QueryByID(ctx context.Context) (Account, error) {
+	acct, err := ctxPck.GetAccount(ctx)
+	if err != nil {
+		return Account{}, errs.Newf(errs.Internal, "querybyid: %s", err)
+	}
+
+	return toAppAccount(acct), nil
+}
+
+// Balance returns the balance the account of the request had at the time,
+// the current one when no time is given.
+func (a *App) Balance(ctx context.Context, at string) (Balance, error) {
+	acct, err := ctxPck.GetAccount(ctx)
+	if err != nil {
+		return Balance{}, errs.Newf(errs.Internal, "account missing in context: %s", err)
+	}
+
+	if at == "" {
+		return toAppBalance(acct, acct.Balance, time.Now()), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		return Balance{}, validation.NewFieldErrors("at", err)
+	}
+
+	balance, err := a.accountBus.BalanceAt(ctx, acct, t)
+	if err != nil {
+		return Balance{}, errs.Newf(errs.Internal, "balanceat: accountID[%s]: %s", acct.ID, err)
+	}
+
+	return toAppBalance(acct, balance, t), nil
+}
+
+// Transfer moves money from the account of the request to another one.
+func (a *App) Transfer(ctx context.Context, app NewTransfer) (Transfer, error) {
+	acct, err := ctxPck.GetAccount(ctx)
+	if err != nil {
+		return Transfer{}, errs.Newf(errs.Internal, "account missing in context: %s", err)
+	}
+
+	nt, err := toBusNewTransfer(acct, app)
+	if err != nil {
+		return Transfer{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	tr, ents, err := a.accountBus.Transfer(ctx, nt)
+	if err != nil {
+		return Transfer{}, toError(err, "transfer: accountID[%s]: %s", acct.ID)
+	}
+
+	return toAppTransfer(tr, ents), nil
+}
+
+// Deposit credits the account of the request.
+func (a *App) Deposit(ctx context.Context, app NewMovement) (Transfer, error) {
+	return a.move(ctx, app, a.accountBus.Deposit)
+}
+
+// Withdraw debits the account of the request.
+func (a *App) Withdraw(ctx context.Context, app NewMovement) (Transfer, error) {
+	return a.move(ctx, app, a.accountBus.Withdraw)
+}
+
+// Query returns a list of accounts with paging.
+func (a *App) Query(ctx context.Context, qp AppQueryParams) (page.Result[Account], error) {
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return page.Result[Account]{}, err.(*errs.Error)
+	}
+
+	return a.query(ctx, filter, qp)
+}
+
+// QueryByUser returns the accounts of the user of the request with paging.
+func (a *App) QueryByUser(ctx context.Context, qp AppQueryParams) (page.Result[Account], error) {
+	usr, err := ctxPck.GetUser(ctx)
+	if err != nil {
+		return page.Result[Account]{}, errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return page.Result[Account]{}, err.(*errs.Error)
+	}
+	filter.OwnerID = &usr.ID
+
+	return a.query(ctx, filter, qp)
+}
+
+// QueryEntries returns the statement of the account of the request with
+// paging.
+func (a *App) QueryEntries(ctx context.Context, qp EntryQueryParams) (page.Result[Entry], error) {
+	acct, err := ctxPck.GetAccount(ctx)
+	if err != nil {
+		return page.Result[Entry]{}, errs.Newf(errs.Internal, "account missing in context: %s", err)
+	}
+
+	p, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return page.Result[Entry]{}, validation.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseEntryFilter(acct.ID, qp)
+	if err != nil {
+		return page.Result[Entry]{}, err.(*errs.Error)
+	}
+
+	orderBy, err := orderPck.Parse(entryOrderByFields, qp.OrderBy, account.DefaultEntryOrderBy)
+	if err != nil {
+		return page.Result[Entry]{}, validation.NewFieldErrors("order", err)
+	}
+
+	ents, err := a.accountBus.QueryEntries(ctx, filter, orderBy, p)
+	if err != nil {
+		return page.Result[Entry]{}, errs.Newf(errs.Internal, "query entries: %s", err)
+	}
+
+	total, err := a.accountBus.CountEntries(ctx, filter)
+	if err != nil {
+		return page.Result[Entry]{}, errs.Newf(errs.Internal, "count entries: %s", err)
+	}
+
+	return page.NewResult(toAppEntries(ents), total, p), nil
+}
+
+func (a *App) query(ctx context.Context, filter account.QueryFilter, qp AppQueryParams) (page.Result[Account], error) {
+	p, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return page.Result[Account]{}, validation.NewFieldErrors("page", err)
+	}
+
+	orderBy, err := orderPck.Parse(orderByFields, qp.OrderBy, account.DefaultOrderBy)
+	if err != nil {
+		return page.Result[Account]{}, validation.NewFieldErrors("order", err)
+	}
+
+	accts, err := a.accountBus.Query(ctx, filter, orderBy, p)
+	if err != nil {
+		return page.Result[Account]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.accountBus.Count(ctx, filter)
+	if err != nil {
+		return page.Result[Account]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return page.NewResult(toAppAccounts(accts), total, p), nil
+}
+
+// move deposits to or withdraws from the account of the request.
+func (a *App) move(ctx context.Context, app NewMovement, fn func(context.Context, account.Account, money.Money) (account.Transfer, []account.Entry, error)) (Transfer, error) {
+	acct, err := ctxPck.GetAccount(ctx)
+	if err != nil {
+		return Transfer{}, errs.Newf(errs.Internal, "account missing in context: %s", err)
+	}
+
+	amount, err := money.Parse(app.Amount.String(), acct.Balance.Currency())
+	if err != nil {
+		return Transfer{}, errs.New(errs.InvalidArgument, fmt.Errorf("parse amount: %w", err))
+	}
+
+	tr, ents, err := fn(ctx, acct, amount)
+	if err != nil {
+		return Transfer{}, toError(err, "move: accountID[%s]: %s", acct.ID)
+	}
+
+	return toAppTransfer(tr, ents), nil
+}
+
+// toError maps the errors of the account core to the codes of the api.
+func toError(err error, format string, id any) error {
+	for _, target := range []error{account.ErrSameAccount, account.ErrInvalidAmount, account.ErrCurrencyMismatch} {
+		if errors.Is(err, target) {
+			return errs.New(errs.InvalidArgument, target)
+		}
+	}
+
+	switch {
+	case errors.Is(err, account.ErrNotFound):
+		return errs.New(errs.NotFound, account.ErrNotFound)
+	case errors.Is(err, user.ErrNotFound):
+		return errs.New(errs.NotFound, user.ErrNotFound)
+	case errors.Is(err, account.ErrUniqueCurrency):
+		return errs.New(errs.AlreadyExists, account.ErrUniqueCurrency)
+	case errors.Is(err, account.ErrUserDisabled):
+		return errs.New(errs.FailedPrecondition, account.ErrUserDisabled)
+	case errors.Is(err, account.ErrInsufficientFunds):
+		return errs.New(errs.FailedPrecondition, account.ErrInsufficientFunds)
+	}
+
+	return errs.Newf(errs.Internal, format, id, err)
+}
@@ -0,0 +1,113 @@
+package account_usecase
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+)
+
+type AppQueryParams struct {
+	Page     string
+	Rows     string
+	OrderBy  string
+	ID       string
+	OwnerID  string
+	Currency string
+}
+
+func parseFilter(qp AppQueryParams) (account.QueryFilter, error) {
+	var fieldErrors validation.FieldErrors
+	var filter account.QueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		switch err {
+		case nil:
+			filter.ID = &id
+		default:
+			fieldErrors.Add("account_id", err)
+		}
+	}
+
+	if qp.OwnerID != "" {
+		id, err := uuid.Parse(qp.OwnerID)
+		switch err {
+		case nil:
+			filter.OwnerID = &id
+		default:
+			fieldErrors.Add("owner_id", err)
+		}
+	}
+
+	if qp.Currency != "" {
+		currency, err := money.ParseCurrency(qp.Currency)
+		switch err {
+		case nil:
+			filter.Currency = &currency
+		default:
+			fieldErrors.Add("currency", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return account.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
+
+type EntryQueryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	TransferID       string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+func parseEntryFilter(accountID uuid.UUID, qp EntryQueryParams) (account.EntryFilter, error) {
+	var fieldErrors validation.FieldErrors
+	filter := account.EntryFilter{
+		AccountID: accountID,
+	}
+
+	if qp.TransferID != "" {
+		id, err := uuid.Parse(qp.TransferID)
+		switch err {
+		case nil:
+			filter.TransferID = &id
+		default:
+			fieldErrors.Add("transfer_id", err)
+		}
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		switch err {
+		case nil:
+			filter.StartCreatedDate = &t
+		default:
+			fieldErrors.Add("start_created_date", err)
+		}
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		switch err {
+		case nil:
+			filter.EndCreatedDate = &t
+		default:
+			fieldErrors.Add("end_created_date", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return account.EntryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
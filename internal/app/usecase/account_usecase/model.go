@@ -0,0 +1,251 @@
+package account_usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+)
+
+// Account represents an account of a user.
+type Account struct {
+	ID          string      `json:"id"`
+	OwnerID     string      `json:"ownerID,omitempty"`
+	Balance     json.Number `json:"balance"`
+	Currency    string      `json:"currency"`
+	DateCreated string      `json:"dateCreated"`
+	DateUpdated string      `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Account) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppAccount(acct account.Account) Account {
+	app := Account{
+		ID:          acct.ID.String(),
+		Balance:     json.Number(acct.Balance.Decimal()),
+		Currency:    acct.Balance.Currency().String(),
+		DateCreated: acct.DateCreated.Format(time.RFC3339),
+		DateUpdated: acct.DateUpdated.Format(time.RFC3339),
+	}
+
+	if !acct.IsClearing() {
+		app.OwnerID = acct.OwnerID.String()
+	}
+
+	return app
+}
+
+func toAppAccounts(accts []account.Account) []Account {
+	app := make([]Account, len(accts))
+	for i, acct := range accts {
+		app[i] = toAppAccount(acct)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// Balance represents the balance of an account at a time.
+type Balance struct {
+	AccountID string      `json:"accountID"`
+	Balance   json.Number `json:"balance"`
+	Currency  string      `json:"currency"`
+	At        string      `json:"at"`
+}
+
+// Encode implements the encoder interface.
+func (app Balance) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppBalance(acct account.Account, balance money.Money, at time.Time) Balance {
+	return Balance{
+		AccountID: acct.ID.String(),
+		Balance:   json.Number(balance.Decimal()),
+		Currency:  balance.Currency().String(),
+		At:        at.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// Entry represents a line of the statement of an account, the amount is
+// negative for a debit.
+type Entry struct {
+	ID          string      `json:"id"`
+	AccountID   string      `json:"accountID"`
+	TransferID  string      `json:"transferID"`
+	Amount      json.Number `json:"amount"`
+	Balance     json.Number `json:"balance"`
+	Currency    string      `json:"currency"`
+	DateCreated string      `json:"dateCreated"`
+}
+
+func toAppEntry(ent account.Entry) Entry {
+	return Entry{
+		ID:          ent.ID.String(),
+		AccountID:   ent.AccountID.String(),
+		TransferID:  ent.TransferID.String(),
+		Amount:      json.Number(ent.Amount.Decimal()),
+		Balance:     json.Number(ent.Balance.Decimal()),
+		Currency:    ent.Amount.Currency().String(),
+		DateCreated: ent.DateCreated.Format(time.RFC3339),
+	}
+}
+
+func toAppEntries(ents []account.Entry) []Entry {
+	app := make([]Entry, len(ents))
+	for i, ent := range ents {
+		app[i] = toAppEntry(ent)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// Transfer represents money moved between two accounts along with its debit
+// and credit entries.
+type Transfer struct {
+	ID            string      `json:"id"`
+	FromAccountID string      `json:"fromAccountID"`
+	ToAccountID   string      `json:"toAccountID"`
+	Amount        json.Number `json:"amount"`
+	Currency      string      `json:"currency"`
+	Entries       []Entry     `json:"entries"`
+	DateCreated   string      `json:"dateCreated"`
+}
+
+// Encode implements the encoder interface.
+func (app Transfer) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppTransfer(tr account.Transfer, ents []account.Entry) Transfer {
+	return Transfer{
+		ID:            tr.ID.String(),
+		FromAccountID: tr.FromAccountID.String(),
+		ToAccountID:   tr.ToAccountID.String(),
+		Amount:        json.Number(tr.Amount.Decimal()),
+		Currency:      tr.Amount.Currency().String(),
+		Entries:       toAppEntries(ents),
+		DateCreated:   tr.DateCreated.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// NewAccount defines the data needed to open an account. The account is in
+// the default currency when none is given.
+type NewAccount struct {
+	Currency string `json:"currency"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewAccount) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewAccount) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	return nil
+}
+
+func toBusNewAccount(ownerID uuid.UUID, app NewAccount) (account.NewAccount, error) {
+	currency := money.DefaultCurrency
+	if app.Currency != "" {
+		var err error
+		currency, err = money.ParseCurrency(app.Currency)
+		if err != nil {
+			return account.NewAccount{}, fmt.Errorf("parse currency: %w", err)
+		}
+	}
+
+	na := account.NewAccount{
+		OwnerID:  ownerID,
+		Currency: currency,
+	}
+
+	return na, nil
+}
+
+// =============================================================================
+
+// NewTransfer defines the data needed to move money to another account, the
+// amount is in the currency of the accounts.
+type NewTransfer struct {
+	ToAccountID string      `json:"toAccountID" validate:"required"`
+	Amount      json.Number `json:"amount" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewTransfer) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewTransfer) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	return nil
+}
+
+func toBusNewTransfer(from account.Account, app NewTransfer) (account.NewTransfer, error) {
+	toAccountID, err := uuid.Parse(app.ToAccountID)
+	if err != nil {
+		return account.NewTransfer{}, fmt.Errorf("parse toAccountID: %w", err)
+	}
+
+	amount, err := money.Parse(app.Amount.String(), from.Balance.Currency())
+	if err != nil {
+		return account.NewTransfer{}, fmt.Errorf("parse amount: %w", err)
+	}
+
+	nt := account.NewTransfer{
+		FromAccountID: from.ID,
+		ToAccountID:   toAccountID,
+		Amount:        amount,
+	}
+
+	return nt, nil
+}
+
+// =============================================================================
+
+// NewMovement defines the data needed to deposit money to an account or to
+// withdraw money from it, the amount is in the currency of the account.
+type NewMovement struct {
+	Amount json.Number `json:"amount" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewMovement) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewMovement) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package account_usecase
+
+import "github.com/Housiadas/backend-system/internal/core/domain/account"
+
+var orderByFields = map[string]string{
+	"account_id":   account.OrderByID,
+	"owner_id":     account.OrderByOwnerID,
+	"currency":     account.OrderByCurrency,
+	"balance":      account.OrderByBalance,
+	"date_created": account.OrderByDateCreated,
+}
+
+var entryOrderByFields = map[string]string{
+	"seq":    account.OrderByEntrySeq,
+	"amount": account.OrderByEntryAmount,
+}
@@ -0,0 +1,143 @@
+// Package apikey_usecase maintains the cli layer api for the api key core.
+package apikey_usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/apikey"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/service/apikeycore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// App manages the set of cli layer api functions for the api key core.
+type App struct {
+	apiKeyCore *apikeycore.Core
+}
+
+// NewApp constructs an api key cli API for use.
+func NewApp(apiKeyCore *apikeycore.Core) *App {
+	return &App{
+		apiKeyCore: apiKeyCore,
+	}
+}
+
+// CreateServiceAccount adds a new service account to the system.
+func (a *App) CreateServiceAccount(ctx context.Context, app NewServiceAccount) (ServiceAccount, error) {
+	nsa, err := toBusNewServiceAccount(ctx, app)
+	if err != nil {
+		return ServiceAccount{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	sa, err := a.apiKeyCore.CreateServiceAccount(ctx, nsa)
+	if err != nil {
+		if errors.Is(err, organization.ErrSuperAdminRole) {
+			return ServiceAccount{}, errs.New(errs.PermissionDenied, organization.ErrSuperAdminRole)
+		}
+		if errors.Is(err, apikey.ErrUniqueName) {
+			return ServiceAccount{}, errs.New(errs.Aborted, apikey.ErrUniqueName)
+		}
+		return ServiceAccount{}, errs.Newf(errs.Internal, "create: sa[%+v]: %s", nsa, err)
+	}
+
+	return toAppServiceAccount(sa), nil
+}
+
+// QueryServiceAccounts returns all the service accounts.
+func (a *App) QueryServiceAccounts(ctx context.Context) (ServiceAccounts, error) {
+	sas, err := a.apiKeyCore.QueryServiceAccounts(ctx)
+	if err != nil {
+		return nil, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	return toAppServiceAccounts(sas), nil
+}
+
+// CreateKey issues a new api key for a service account. The raw key is only
+// returned in this response.
+func (a *App) CreateKey(ctx context.Context, serviceAccountID string, app NewKey) (CreatedKey, error) {
+	saID, err := uuid.Parse(serviceAccountID)
+	if err != nil {
+		return CreatedKey{}, errs.Newf(errs.InvalidArgument, "parse: %s", err)
+	}
+
+	nk, err := toBusNewKey(saID, app)
+	if err != nil {
+		return CreatedKey{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	key, rawKey, err := a.apiKeyCore.CreateKey(ctx, nk)
+	if err != nil {
+		if errors.Is(err, apikey.ErrAccountNotFound) {
+			return CreatedKey{}, errs.New(errs.NotFound, apikey.ErrAccountNotFound)
+		}
+		return CreatedKey{}, errs.Newf(errs.Internal, "create: key[%+v]: %s", nk, err)
+	}
+
+	created := CreatedKey{
+		Key:    toAppKey(key),
+		APIKey: rawKey,
+	}
+
+	return created, nil
+}
+
+// QueryKeys returns the api keys of a service account.
+func (a *App) QueryKeys(ctx context.Context, serviceAccountID string) (Keys, error) {
+	saID, err := uuid.Parse(serviceAccountID)
+	if err != nil {
+		return nil, errs.Newf(errs.InvalidArgument, "parse: %s", err)
+	}
+
+	// The service account is read first, it must belong to the organization
+	// of the caller.
+	if _, err := a.apiKeyCore.QueryServiceAccountByID(ctx, saID); err != nil {
+		if errors.Is(err, apikey.ErrAccountNotFound) {
+			return nil, errs.New(errs.NotFound, apikey.ErrAccountNotFound)
+		}
+		return nil, errs.Newf(errs.Internal, "query: serviceAccountID[%s]: %s", saID, err)
+	}
+
+	keys, err := a.apiKeyCore.QueryKeys(ctx, saID)
+	if err != nil {
+		return nil, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	return toAppKeys(keys), nil
+}
+
+// RevokeKey permanently disables an api key of a service account.
+func (a *App) RevokeKey(ctx context.Context, serviceAccountID string, keyID string) error {
+	id, err := uuid.Parse(keyID)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "parse: %s", err)
+	}
+
+	key, err := a.apiKeyCore.QueryKeyByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, apikey.ErrNotFound) {
+			return errs.New(errs.NotFound, apikey.ErrNotFound)
+		}
+		return errs.Newf(errs.Internal, "querybyid: keyID[%s]: %s", keyID, err)
+	}
+
+	if key.ServiceAccountID.String() != serviceAccountID {
+		return errs.New(errs.NotFound, apikey.ErrNotFound)
+	}
+
+	if _, err := a.apiKeyCore.QueryServiceAccountByID(ctx, key.ServiceAccountID); err != nil {
+		if errors.Is(err, apikey.ErrAccountNotFound) {
+			return errs.New(errs.NotFound, apikey.ErrNotFound)
+		}
+		return errs.Newf(errs.Internal, "query: serviceAccountID[%s]: %s", key.ServiceAccountID, err)
+	}
+
+	if err := a.apiKeyCore.RevokeKey(ctx, key); err != nil {
+		return errs.Newf(errs.Internal, "revoke: keyID[%s]: %s", keyID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package apikey_usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/apikey"
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// ServiceAccount represents information about a service account.
+type ServiceAccount struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Roles       []string `json:"roles"`
+	Enabled     bool     `json:"enabled"`
+	CreatedBy   string   `json:"createdBy"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app ServiceAccount) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppServiceAccount(sa apikey.ServiceAccount) ServiceAccount {
+	return ServiceAccount{
+		ID:          sa.ID.String(),
+		Name:        sa.Name,
+		Description: sa.Description,
+		Roles:       role.ParseToString(sa.Roles),
+		Enabled:     sa.Enabled,
+		CreatedBy:   sa.CreatedBy.String(),
+		DateCreated: sa.DateCreated.Format(time.RFC3339),
+		DateUpdated: sa.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// ServiceAccounts represents a collection of service accounts.
+type ServiceAccounts []ServiceAccount
+
+// Encode implements the encoder interface.
+func (app ServiceAccounts) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppServiceAccounts(sas []apikey.ServiceAccount) ServiceAccounts {
+	app := make(ServiceAccounts, len(sas))
+	for i, sa := range sas {
+		app[i] = toAppServiceAccount(sa)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewServiceAccount defines the data needed to add a new service account.
+type NewServiceAccount struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Roles       []string `json:"roles" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewServiceAccount) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *NewServiceAccount) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewServiceAccount(ctx context.Context, app NewServiceAccount) (apikey.NewServiceAccount, error) {
+	userID, err := ctxPck.GetUserID(ctx)
+	if err != nil {
+		return apikey.NewServiceAccount{}, fmt.Errorf("getuserid: %w", err)
+	}
+
+	roles, err := role.ParseMany(app.Roles)
+	if err != nil {
+		return apikey.NewServiceAccount{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := apikey.NewServiceAccount{
+		Name:        app.Name,
+		Description: app.Description,
+		Roles:       roles,
+		CreatedBy:   userID,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// Key represents information about an api key. The raw key is never
+// returned, the prefix identifies it.
+type Key struct {
+	ID               string   `json:"id"`
+	ServiceAccountID string   `json:"serviceAccountId"`
+	Name             string   `json:"name"`
+	Prefix           string   `json:"prefix"`
+	Scopes           []string `json:"scopes"`
+	ExpiresAt        string   `json:"expiresAt,omitempty"`
+	LastUsedAt       string   `json:"lastUsedAt,omitempty"`
+	Revoked          bool     `json:"revoked"`
+	DateCreated      string   `json:"dateCreated"`
+	DateUpdated      string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Key) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppKey(key apikey.Key) Key {
+	app := Key{
+		ID:               key.ID.String(),
+		ServiceAccountID: key.ServiceAccountID.String(),
+		Name:             key.Name,
+		Prefix:           key.Prefix,
+		Scopes:           permission.ParseToString(key.Scopes),
+		Revoked:          key.Revoked,
+		DateCreated:      key.DateCreated.Format(time.RFC3339),
+		DateUpdated:      key.DateUpdated.Format(time.RFC3339),
+	}
+
+	if key.ExpiresAt != nil {
+		app.ExpiresAt = key.ExpiresAt.Format(time.RFC3339)
+	}
+	if key.LastUsedAt != nil {
+		app.LastUsedAt = key.LastUsedAt.Format(time.RFC3339)
+	}
+
+	return app
+}
+
+// Keys represents a collection of api keys.
+type Keys []Key
+
+// Encode implements the encoder interface.
+func (app Keys) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppKeys(keys []apikey.Key) Keys {
+	app := make(Keys, len(keys))
+	for i, key := range keys {
+		app[i] = toAppKey(key)
+	}
+
+	return app
+}
+
+// CreatedKey is returned when an api key is created. It is the only time the
+// raw key is available.
+type CreatedKey struct {
+	Key
+	APIKey string `json:"apiKey"`
+}
+
+// Encode implements the encoder interface.
+func (app CreatedKey) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// =============================================================================
+
+// NewKey defines the data needed to issue a new api key. A key is only granted
+// the permissions in its scopes that the roles of the account also grant.
+type NewKey struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewKey) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *NewKey) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	if app.ExpiresAt != nil && app.ExpiresAt.Before(time.Now()) {
+		return errs.Newf(errs.InvalidArgument, "validation: expiresAt must be in the future")
+	}
+
+	return nil
+}
+
+func toBusNewKey(serviceAccountID uuid.UUID, app NewKey) (apikey.NewKey, error) {
+	scopes, err := permission.ParseMany(app.Scopes)
+	if err != nil {
+		return apikey.NewKey{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := apikey.NewKey{
+		ServiceAccountID: serviceAccountID,
+		Name:             app.Name,
+		Scopes:           scopes,
+		ExpiresAt:        app.ExpiresAt,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,532 @@
+// Package auth_usecase maintains the app layer api for sessions and tokens.
+package auth_usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/lockout"
+	"github.com/Housiadas/backend-system/internal/core/domain/mfa"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/internal/core/domain/session"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/internal/core/service/lockoutcore"
+	"github.com/Housiadas/backend-system/internal/core/service/mfacore"
+	"github.com/Housiadas/backend-system/internal/core/service/securityeventcore"
+	"github.com/Housiadas/backend-system/internal/core/service/sessioncore"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// Default lifetimes used when none are configured.
+const (
+	defaultAccessTTL      = 15 * time.Minute
+	defaultRefreshTTL     = 7 * 24 * time.Hour
+	defaultChallengeTTL   = 5 * time.Minute
+	defaultImpersonateTTL = 15 * time.Minute
+)
+
+// App manages the set of app layer api functions for sessions and tokens.
+type App struct {
+	authCore     *authcore.Auth
+	userCore     *usercore.Core
+	sessionCore  *sessioncore.Core
+	mfaCore      *mfacore.Core
+	lockoutCore  *lockoutcore.Core
+	auditCore    *auditcore.Core
+	securityCore *securityeventcore.Core
+	ttl          TTL
+}
+
+// TTL holds the lifetimes of the tokens issued, the zero values fall back to
+// the defaults.
+type TTL struct {
+	Access      time.Duration
+	Refresh     time.Duration
+	Challenge   time.Duration
+	Impersonate time.Duration
+}
+
+// NewApp constructs an auth app API for use. The lockout core limits the
+// guesses of mfa codes and the audit core records the impersonations. The
+// security event core is optional, it records the tokens issued and the
+// failed mfa codes.
+func NewApp(
+	authCore *authcore.Auth,
+	userCore *usercore.Core,
+	sessionCore *sessioncore.Core,
+	mfaCore *mfacore.Core,
+	lockoutCore *lockoutcore.Core,
+	auditCore *auditcore.Core,
+	securityCore *securityeventcore.Core,
+	ttl TTL,
+) *App {
+	if ttl.Access == 0 {
+		ttl.Access = defaultAccessTTL
+	}
+
+	if ttl.Refresh == 0 {
+		ttl.Refresh = defaultRefreshTTL
+	}
+
+	if ttl.Challenge == 0 {
+		ttl.Challenge = defaultChallengeTTL
+	}
+
+	if ttl.Impersonate == 0 {
+		ttl.Impersonate = defaultImpersonateTTL
+	}
+
+	return &App{
+		authCore:     authCore,
+		userCore:     userCore,
+		sessionCore:  sessionCore,
+		mfaCore:      mfaCore,
+		lockoutCore:  lockoutCore,
+		auditCore:    auditCore,
+		securityCore: securityCore,
+		ttl:          ttl,
+	}
+}
+
+// Login starts a new session for an already authenticated user and issues a
+// short-lived access token along with a refresh token.
+func (a *App) Login(ctx context.Context, userID string) (Token, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return Token{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.userCore.QueryByID(ctx, uid)
+	if err != nil {
+		return Token{}, errs.Newf(errs.Internal, "login: userID[%s]: %s", uid, err)
+	}
+
+	ns := session.NewSession{
+		UserID:    usr.ID,
+		ExpiresAt: time.Now().Add(a.ttl.Refresh),
+	}
+
+	sess, refreshToken, err := a.sessionCore.Create(ctx, ns)
+	if err != nil {
+		return Token{}, errs.Newf(errs.Internal, "create session: userID[%s]: %s", uid, err)
+	}
+
+	return a.issue(ctx, usr, sess, refreshToken, "login")
+}
+
+// Refresh rotates the refresh token and issues a new access token for the
+// session. The user roles are read again so role changes are picked up.
+func (a *App) Refresh(ctx context.Context, app RefreshToken) (Token, error) {
+	sess, refreshToken, err := a.sessionCore.Rotate(ctx, app.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, session.ErrInvalidToken),
+			errors.Is(err, session.ErrRevoked),
+			errors.Is(err, session.ErrExpired):
+			return Token{}, errs.New(errs.Unauthenticated, err)
+		}
+		return Token{}, errs.Newf(errs.Internal, "refresh: %s", err)
+	}
+
+	usr, err := a.userCore.QueryByID(ctx, sess.UserID)
+	if err != nil {
+		return Token{}, errs.Newf(errs.Internal, "refresh: userID[%s]: %s", sess.UserID, err)
+	}
+
+	if !usr.Enabled {
+		if err := a.sessionCore.Revoke(ctx, sess); err != nil {
+			return Token{}, errs.Newf(errs.Internal, "revoke: sessionID[%s]: %s", sess.ID, err)
+		}
+		return Token{}, errs.New(errs.Unauthenticated, errors.New("user disabled"))
+	}
+
+	return a.issue(ctx, usr, sess, refreshToken, "refresh")
+}
+
+// Logout revokes the token used for the request and its session.
+func (a *App) Logout(ctx context.Context) error {
+	claims := ctxPck.GetClaims(ctx)
+	if claims.SessionID == "" {
+		return errs.New(errs.FailedPrecondition, errors.New("token is not bound to a session"))
+	}
+
+	// The token is refused right away, not only once it expires, even
+	// where the revoked session is still cached as active.
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := a.sessionCore.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return errs.Newf(errs.Internal, "logout: tokenID[%s]: %s", claims.ID, err)
+		}
+	}
+
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	sess, err := a.sessionCore.QueryByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "logout: sessionID[%s]: %s", sessionID, err)
+	}
+
+	if err := a.sessionCore.Revoke(ctx, sess); err != nil {
+		return errs.Newf(errs.Internal, "logout: sessionID[%s]: %s", sessionID, err)
+	}
+
+	return nil
+}
+
+// RevokeAll revokes every session of the user found in the context.
+func (a *App) RevokeAll(ctx context.Context) error {
+	usr, err := ctxPck.GetUser(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	if err := a.sessionCore.RevokeAll(ctx, usr.ID); err != nil {
+		return errs.Newf(errs.Internal, "revokeall: userID[%s]: %s", usr.ID, err)
+	}
+
+	return nil
+}
+
+// Challenge issues an mfa challenge token for an authenticated user when the
+// user is enrolled in mfa or the policies require it. It reports false when
+// the user can log in with the password alone.
+func (a *App) Challenge(ctx context.Context, userID string) (Challenge, bool, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return Challenge{}, false, errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.userCore.QueryByID(ctx, uid)
+	if err != nil {
+		return Challenge{}, false, errs.Newf(errs.Internal, "challenge: userID[%s]: %s", uid, err)
+	}
+
+	enrolled, err := a.mfaCore.IsEnrolled(ctx, usr.ID)
+	if err != nil {
+		return Challenge{}, false, errs.Newf(errs.Internal, "challenge: userID[%s]: %s", uid, err)
+	}
+
+	roles := role.ParseToString(usr.Roles)
+
+	if !enrolled && !a.authCore.MFARequired(ctx, roles) {
+		return Challenge{}, false, nil
+	}
+
+	now := time.Now().UTC()
+
+	claims := authcore.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   usr.ID.String(),
+			Issuer:    a.authCore.Issuer(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(a.ttl.Challenge)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Purpose: authcore.PurposeMFA,
+		Org:     usr.OrgID.String(),
+	}
+
+	token, err := a.authCore.GenerateToken(claims)
+	if err != nil {
+		return Challenge{}, false, errs.Newf(errs.Internal, "generating token: %s", err)
+	}
+
+	ch := Challenge{
+		MFARequired:    true,
+		MFAEnrolled:    enrolled,
+		ChallengeToken: token,
+		ExpiresIn:      int(a.ttl.Challenge.Seconds()),
+	}
+
+	return ch, true, nil
+}
+
+// MFAEnroll starts the mfa enrolment of the user in the context and returns
+// the secret to add to an authenticator app.
+func (a *App) MFAEnroll(ctx context.Context) (Enrolment, error) {
+	userID, err := ctxPck.GetUserID(ctx)
+	if err != nil {
+		return Enrolment{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	usr, err := a.userCore.QueryByID(ctx, userID)
+	if err != nil {
+		return Enrolment{}, errs.Newf(errs.Internal, "enroll: userID[%s]: %s", userID, err)
+	}
+
+	enr, err := a.mfaCore.Enroll(ctx, usr)
+	if err != nil {
+		if errors.Is(err, mfa.ErrAlreadyEnrolled) {
+			return Enrolment{}, errs.New(errs.FailedPrecondition, err)
+		}
+		return Enrolment{}, errs.Newf(errs.Internal, "enroll: userID[%s]: %s", userID, err)
+	}
+
+	return toAppEnrolment(enr), nil
+}
+
+// MFAConfirm completes the mfa enrolment of the user in the context with a
+// code from the authenticator app and returns the recovery codes. The codes
+// are never shown again.
+func (a *App) MFAConfirm(ctx context.Context, app MFACode) (RecoveryCodes, error) {
+	userID, err := ctxPck.GetUserID(ctx)
+	if err != nil {
+		return RecoveryCodes{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	var codes []string
+	err = a.guard(ctx, userID, func() error {
+		var err error
+		codes, err = a.mfaCore.Confirm(ctx, userID, app.Code)
+		return err
+	})
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+
+	return RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// MFAVerify exchanges a challenge token and a code from the authenticator app,
+// or a recovery code, for the tokens of a new session. A code is accepted
+// only once, so right after confirming the enrolment the next code is needed.
+func (a *App) MFAVerify(ctx context.Context, app MFAVerify) (Token, error) {
+	claims, err := a.authCore.ValidateChallenge(ctx, app.ChallengeToken)
+	if err != nil {
+		return Token{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return Token{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	err = a.guard(ctx, userID, func() error {
+		return a.mfaCore.Verify(ctx, userID, app.Code)
+	})
+	if err != nil {
+		orgID, _ := uuid.Parse(claims.Org)
+		a.record(ctx, securityevent.NewEvent{
+			Kind:    securityevent.LoginFailed,
+			ActorID: userID,
+			Subject: claims.Subject,
+			Outcome: securityevent.OutcomeFailure,
+			Reason:  err.Error(),
+			OrgID:   orgID,
+		})
+		return Token{}, err
+	}
+
+	return a.Login(ctx, userID.String())
+}
+
+// =============================================================================
+
+// guard runs a check of an mfa code, counting the invalid codes against the
+// user so they can't be guessed.
+func (a *App) guard(ctx context.Context, userID uuid.UUID, check func() error) error {
+	key := lockout.MFAKey(userID)
+
+	if err := a.lockoutCore.Check(ctx, key); err != nil {
+		if errors.Is(err, lockout.ErrLocked) {
+			return errs.New(errs.TooManyRequests, lockout.ErrLocked)
+		}
+		return errs.Newf(errs.Internal, "check: %s", err)
+	}
+
+	if err := check(); err != nil {
+		switch {
+		case errors.Is(err, mfa.ErrInvalidCode):
+			if err := a.lockoutCore.Fail(ctx, key); err != nil {
+				return errs.Newf(errs.Internal, "fail: %s", err)
+			}
+			return errs.New(errs.Unauthenticated, err)
+
+		case errors.Is(err, mfa.ErrNotFound),
+			errors.Is(err, mfa.ErrNotConfirmed),
+			errors.Is(err, mfa.ErrAlreadyEnrolled):
+			return errs.New(errs.FailedPrecondition, err)
+		}
+		return errs.Newf(errs.Internal, "mfa: userID[%s]: %s", userID, err)
+	}
+
+	if err := a.lockoutCore.Succeed(ctx, key); err != nil {
+		return errs.Newf(errs.Internal, "succeed: %s", err)
+	}
+
+	return nil
+}
+
+// Impersonate issues a short-lived token acting as the user for the admin in
+// the context. The token carries the admin as the actor (act) and is bound to
+// the session of the admin, ending the session ends the impersonation. It is
+// not refreshable and can't be used to impersonate again.
+func (a *App) Impersonate(ctx context.Context, userID string) (Impersonation, error) {
+	claims := ctxPck.GetClaims(ctx)
+	if claims.Impersonated() {
+		return Impersonation{}, errs.New(errs.PermissionDenied, errors.New("impersonation tokens can't impersonate"))
+	}
+
+	actorID, err := ctxPck.GetUserID(ctx)
+	if err != nil {
+		return Impersonation{}, errs.New(errs.Unauthenticated, err)
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return Impersonation{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	if uid == actorID {
+		return Impersonation{}, errs.New(errs.InvalidArgument, errors.New("can't impersonate yourself"))
+	}
+
+	usr, err := a.userCore.QueryByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return Impersonation{}, errs.New(errs.NotFound, err)
+		}
+		return Impersonation{}, errs.Newf(errs.Internal, "impersonate: userID[%s]: %s", uid, err)
+	}
+
+	if !usr.Enabled {
+		return Impersonation{}, errs.New(errs.FailedPrecondition, errors.New("user disabled"))
+	}
+
+	// The token carries the roles of the user, an admin can't gain through
+	// it what they don't hold already, like the cross tenant access of a
+	// super admin.
+	for _, r := range usr.Roles {
+		if r.Equal(role.Admin) || r.Equal(role.SuperAdmin) {
+			return Impersonation{}, errs.Newf(errs.PermissionDenied, "can't impersonate a user with the %s role", r)
+		}
+	}
+
+	if err := a.authCore.AuthorizeRoles(ctx, claims, usr.Roles); err != nil {
+		if errors.Is(err, authcore.ErrRoleNotCovered) {
+			return Impersonation{}, errs.New(errs.PermissionDenied, err)
+		}
+		return Impersonation{}, errs.Newf(errs.Internal, "impersonate: userID[%s]: %s", uid, err)
+	}
+
+	now := time.Now().UTC()
+
+	ic := authcore.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   usr.ID.String(),
+			Issuer:    a.authCore.Issuer(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(a.ttl.Impersonate)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Roles:     role.ParseToString(usr.Roles),
+		SessionID: claims.SessionID,
+		Actor:     &authcore.Actor{Subject: actorID.String()},
+		Org:       usr.OrgID.String(),
+		Dept:      authcore.Department(usr.Department),
+	}
+
+	token, err := a.authCore.GenerateToken(ic)
+	if err != nil {
+		return Impersonation{}, errs.Newf(errs.Internal, "generating token: %s", err)
+	}
+
+	na := audit.NewAudit{
+		ObjID:     usr.ID,
+		ObjEntity: entity.User,
+		ObjName:   name.MustParse("impersonation"),
+		ActorID:   actorID,
+		Action:    "started",
+		Data:      struct{ TokenID string }{TokenID: ic.ID},
+		Message:   "impersonation started",
+	}
+
+	// The token is only handed out once the impersonation is recorded.
+	if _, err := a.auditCore.Create(context.WithoutCancel(ctx), na); err != nil {
+		return Impersonation{}, errs.Newf(errs.Internal, "audit: userID[%s]: %s", usr.ID, err)
+	}
+
+	a.record(ctx, securityevent.NewEvent{
+		Kind:    securityevent.TokenIssued,
+		ActorID: actorID,
+		Subject: usr.ID.String(),
+		Outcome: securityevent.OutcomeSuccess,
+		Reason:  "impersonation",
+		OrgID:   usr.OrgID,
+	})
+
+	imp := Impersonation{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresIn: int(a.ttl.Impersonate.Seconds()),
+		Subject:   usr.ID.String(),
+		Actor:     actorID.String(),
+	}
+
+	return imp, nil
+}
+
+// issue generates an access token bound to the session. The reason tells the
+// security event how the token was obtained.
+func (a *App) issue(ctx context.Context, usr user.User, sess session.Session, refreshToken string, reason string) (Token, error) {
+	now := time.Now().UTC()
+
+	claims := authcore.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   usr.ID.String(),
+			Issuer:    a.authCore.Issuer(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(a.ttl.Access)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Roles:     role.ParseToString(usr.Roles),
+		SessionID: sess.ID.String(),
+		Org:       usr.OrgID.String(),
+		Dept:      authcore.Department(usr.Department),
+	}
+
+	token, err := a.authCore.GenerateToken(claims)
+	if err != nil {
+		return Token{}, errs.Newf(errs.Internal, "generating token: %s", err)
+	}
+
+	a.record(ctx, securityevent.NewEvent{
+		Kind:    securityevent.TokenIssued,
+		ActorID: usr.ID,
+		Subject: usr.ID.String(),
+		Outcome: securityevent.OutcomeSuccess,
+		Reason:  reason,
+		OrgID:   usr.OrgID,
+	})
+
+	return Token{
+		Token:        token,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(a.ttl.Access.Seconds()),
+	}, nil
+}
+
+// record records the security event when the security event core is set.
+func (a *App) record(ctx context.Context, ne securityevent.NewEvent) {
+	if a.securityCore != nil {
+		a.securityCore.Record(ctx, ne)
+	}
+}
@@ -0,0 +1,146 @@
+package auth_usecase
+
+import (
+	"encoding/json"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/mfa"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// Token represents the access and refresh tokens issued for a session.
+type Token struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// Encode implements the encoder interface.
+func (t Token) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(t)
+	return data, "application/json", err
+}
+
+// Impersonation represents the token issued to an admin acting as a user.
+type Impersonation struct {
+	Token     string `json:"token"`
+	TokenType string `json:"token_type"`
+	ExpiresIn int    `json:"expires_in"`
+	Subject   string `json:"subject"`
+	Actor     string `json:"actor"`
+}
+
+// Encode implements the encoder interface.
+func (i Impersonation) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(i)
+	return data, "application/json", err
+}
+
+// RefreshToken defines the data needed to refresh a session.
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// Encode implements the encoder interface.
+func (app *RefreshToken) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *RefreshToken) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+// Challenge is returned instead of the tokens when the user has to pass the
+// second factor. The challenge token is exchanged for the tokens along with a
+// code from the authenticator app.
+type Challenge struct {
+	MFARequired    bool   `json:"mfa_required"`
+	MFAEnrolled    bool   `json:"mfa_enrolled"`
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int    `json:"expires_in"`
+}
+
+// Encode implements the encoder interface.
+func (c Challenge) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(c)
+	return data, "application/json", err
+}
+
+// Enrolment represents the secret to add to an authenticator app.
+type Enrolment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Encode implements the encoder interface.
+func (e Enrolment) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(e)
+	return data, "application/json", err
+}
+
+func toAppEnrolment(enr mfa.Enrolment) Enrolment {
+	return Enrolment{
+		Secret: enr.Secret,
+		URI:    enr.URI,
+	}
+}
+
+// RecoveryCodes represents the single use codes to pass the second factor
+// when the authenticator app is lost.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Encode implements the encoder interface.
+func (rc RecoveryCodes) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(rc)
+	return data, "application/json", err
+}
+
+// MFACode defines the data needed to confirm an mfa enrolment.
+type MFACode struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// Encode implements the encoder interface.
+func (app *MFACode) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *MFACode) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+// MFAVerify defines the data needed to pass the second factor.
+type MFAVerify struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// Encode implements the encoder interface.
+func (app *MFAVerify) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *MFAVerify) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
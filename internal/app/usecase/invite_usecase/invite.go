@@ -0,0 +1,368 @@
+// Package invite_usecase maintains the app layer api for the invitations of
+// new users and the verification of their email.
+package invite_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/core/domain/invite"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/password"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/internal/core/service/invitecore"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/notifier"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// defaultVerifyTTL is how long a verification token is valid when none is
+// configured.
+const defaultVerifyTTL = 24 * time.Hour
+
+// Config represents the settings of the invitations and the verification.
+// The tokens are sent with the notifier, as a query parameter of the urls.
+type Config struct {
+	InviteURL string
+	VerifyURL string
+	VerifyTTL time.Duration
+}
+
+// App manages the set of app layer api functions for the invitations.
+type App struct {
+	log        *logger.Logger
+	authCore   *authcore.Auth
+	userCore   *usercore.Core
+	inviteCore *invitecore.Core
+	notifier   notifier.Notifier
+	policy     password.Policy
+	cfg        Config
+}
+
+// NewApp constructs an invite app API for use. The password set when an
+// invitation is accepted must satisfy the policy.
+func NewApp(
+	log *logger.Logger,
+	authCore *authcore.Auth,
+	userCore *usercore.Core,
+	inviteCore *invitecore.Core,
+	notifier notifier.Notifier,
+	policy password.Policy,
+	cfg Config,
+) *App {
+	if cfg.VerifyTTL == 0 {
+		cfg.VerifyTTL = defaultVerifyTTL
+	}
+
+	return &App{
+		log:        log,
+		authCore:   authCore,
+		userCore:   userCore,
+		inviteCore: inviteCore,
+		notifier:   notifier,
+		policy:     policy,
+		cfg:        cfg,
+	}
+}
+
+// newWithTx constructs a new App value with the core apis
+// using a store transaction that was created via middleware.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := pgsql.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userCore, err := a.userCore.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteCore, err := a.inviteCore.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := *a
+	app.userCore = userCore
+	app.inviteCore = inviteCore
+
+	return &app, nil
+}
+
+// Create invites an email to join with the roles. The invitation link is sent
+// to the email.
+func (a *App) Create(ctx context.Context, app NewInvite) (Invite, error) {
+	ni, err := toBusNewInvite(app)
+	if err != nil {
+		return Invite{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	adminID, err := ctxPck.GetUserID(ctx)
+	if err != nil {
+		return Invite{}, errs.Newf(errs.Unauthenticated, "invite: %s", err)
+	}
+	ni.InvitedBy = adminID
+
+	if _, err := a.userCore.QueryByEmail(ctx, ni.Email); err == nil {
+		return Invite{}, errs.New(errs.Aborted, user.ErrUniqueEmail)
+	} else if !errors.Is(err, user.ErrNotFound) {
+		return Invite{}, errs.Newf(errs.Internal, "invite: email[%s]: %s", ni.Email.Address, err)
+	}
+
+	inv, err := a.inviteCore.Create(ctx, ni)
+	if err != nil {
+		if errors.Is(err, organization.ErrSuperAdminRole) {
+			return Invite{}, errs.New(errs.PermissionDenied, organization.ErrSuperAdminRole)
+		}
+		return Invite{}, errs.Newf(errs.Internal, "invite: email[%s]: %s", ni.Email.Address, err)
+	}
+
+	token, err := a.token(inv.ID, "", authcore.PurposeInvite, inv.ExpiresAt)
+	if err != nil {
+		return Invite{}, errs.Newf(errs.Internal, "generating token: %s", err)
+	}
+
+	msg := notifier.Message{
+		To:      inv.Email.Address,
+		Subject: "You are invited",
+		Body: fmt.Sprintf(
+			"Follow the link to set your password and activate your account, it expires in %s:\n\n%s",
+			a.inviteCore.TTL().Round(time.Minute), link(a.cfg.InviteURL, token),
+		),
+	}
+
+	if err := a.notifier.Notify(ctx, msg); err != nil {
+		return Invite{}, errs.Newf(errs.Internal, "notify: inviteID[%s]: %s", inv.ID, err)
+	}
+
+	return toAppInvite(inv), nil
+}
+
+// Accept creates the account of an invitee with the password they set. The
+// email is verified, the invitation was received with it.
+func (a *App) Accept(ctx context.Context, app AcceptInvite) error {
+	if err := a.policy.Check(app.Password); err != nil {
+		return errs.Newf(errs.InvalidArgument, "password: %s", err)
+	}
+
+	nme, err := name.Parse(app.Name)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "parse: %s", err)
+	}
+
+	department, err := name.ParseNull(app.Department)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "parse: %s", err)
+	}
+
+	claims, err := a.authCore.ValidateToken(ctx, app.Token, authcore.PurposeInvite)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid invite token: %s", err)
+	}
+
+	inviteID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid invite token: %s", err)
+	}
+
+	a, err = a.newWithTx(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	inv, err := a.inviteCore.QueryPending(ctx, inviteID)
+	if err != nil {
+		switch {
+		case errors.Is(err, invite.ErrNotFound),
+			errors.Is(err, invite.ErrExpired),
+			errors.Is(err, invite.ErrAccepted):
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "accept: inviteID[%s]: %s", inviteID, err)
+	}
+
+	nu := user.NewUser{
+		Name:          nme,
+		Email:         inv.Email,
+		Roles:         inv.Roles,
+		Department:    department,
+		Password:      app.Password,
+		EmailVerified: true,
+		OrgID:         inv.OrgID,
+	}
+
+	// The invite is marked accepted first, only while it is pending, so a
+	// concurrent accept fails before creating a user. Both happen in the
+	// transaction of the request, the invite stays pending when the user
+	// can't be created.
+	if _, err := a.inviteCore.Accept(ctx, inv); err != nil {
+		if errors.Is(err, invite.ErrAccepted) {
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "accept: inviteID[%s]: %s", inv.ID, err)
+	}
+
+	if _, err := a.userCore.Create(ctx, nu); err != nil {
+		if errors.Is(err, user.ErrUniqueEmail) {
+			return errs.New(errs.Aborted, user.ErrUniqueEmail)
+		}
+		return errs.Newf(errs.Internal, "accept: inviteID[%s]: %s", inv.ID, err)
+	}
+
+	return nil
+}
+
+// Verify marks the email of a user as verified with a verification token. A
+// token sent to an email the user no longer has is refused.
+func (a *App) Verify(ctx context.Context, app VerifyEmail) error {
+	claims, err := a.authCore.ValidateToken(ctx, app.Token, authcore.PurposeVerifyEmail)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid verification token: %s", err)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid verification token: %s", err)
+	}
+
+	usr, err := a.userCore.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return errs.New(errs.InvalidArgument, errors.New("invalid verification token"))
+		}
+		return errs.Newf(errs.Internal, "verify: userID[%s]: %s", userID, err)
+	}
+
+	if usr.Email.Address != claims.Email {
+		return errs.New(errs.InvalidArgument, errors.New("verification token issued for another email"))
+	}
+
+	if usr.EmailVerified {
+		return nil
+	}
+
+	if _, err := a.userCore.VerifyEmail(ctx, usr); err != nil {
+		return errs.Newf(errs.Internal, "verify: userID[%s]: %s", usr.ID, err)
+	}
+
+	return nil
+}
+
+// Resend sends the verification again to the user with the email. It succeeds
+// whether the email is known or not, so it can't be used to find out which
+// emails have an account.
+func (a *App) Resend(ctx context.Context, app ResendVerification) error {
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "parse: %s", err)
+	}
+
+	usr, err := a.userCore.QueryByEmail(ctx, *addr)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil
+		}
+		return errs.Newf(errs.Internal, "resend: email[%s]: %s", addr.Address, err)
+	}
+
+	if !usr.Enabled || usr.EmailVerified {
+		return nil
+	}
+
+	// A failed delivery is not reported to the caller, it would tell the
+	// email has an account.
+	if err := a.sendVerification(ctx, usr); err != nil {
+		a.log.Error(ctx, "email verification", "status", "sending", "userID", usr.ID, "msg", err)
+	}
+
+	return nil
+}
+
+// SendVerification sends the verification to a user whose email is not
+// verified yet.
+func (a *App) SendVerification(ctx context.Context, userID string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err := a.userCore.QueryByID(ctx, uid)
+	if err != nil {
+		return errs.Newf(errs.Internal, "send verification: userID[%s]: %s", uid, err)
+	}
+
+	if usr.EmailVerified {
+		return nil
+	}
+
+	if err := a.sendVerification(ctx, usr); err != nil {
+		return errs.Newf(errs.Internal, "send verification: userID[%s]: %s", uid, err)
+	}
+
+	return nil
+}
+
+func (a *App) sendVerification(ctx context.Context, usr user.User) error {
+	token, err := a.token(usr.ID, usr.Email.Address, authcore.PurposeVerifyEmail, time.Now().Add(a.cfg.VerifyTTL))
+	if err != nil {
+		return fmt.Errorf("generating token: %w", err)
+	}
+
+	msg := notifier.Message{
+		To:      usr.Email.Address,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Follow the link to verify your email, it expires in %s:\n\n%s",
+			a.cfg.VerifyTTL.Round(time.Minute), link(a.cfg.VerifyURL, token),
+		),
+	}
+
+	if err := a.notifier.Notify(ctx, msg); err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+
+	return nil
+}
+
+// token generates a token signed for the purpose, the subject is the id.
+func (a *App) token(id uuid.UUID, email string, purpose string, expiresAt time.Time) (string, error) {
+	claims := authcore.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   id.String(),
+			Issuer:    a.authCore.Issuer(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+		Purpose: purpose,
+		Email:   email,
+	}
+
+	return a.authCore.GenerateToken(claims)
+}
+
+// link returns the url with the token as a query parameter.
+func link(rawURL string, token string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return token
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,147 @@
+package invite_usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/invite"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// Invite represents an invitation sent to an email.
+type Invite struct {
+	ID          string   `json:"id"`
+	Email       string   `json:"email"`
+	Roles       []string `json:"roles"`
+	InvitedBy   string   `json:"invitedBy"`
+	ExpiresAt   string   `json:"expiresAt"`
+	DateCreated string   `json:"dateCreated"`
+}
+
+// Encode implements the encoder interface.
+func (app Invite) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppInvite(bus invite.Invite) Invite {
+	return Invite{
+		ID:          bus.ID.String(),
+		Email:       bus.Email.Address,
+		Roles:       role.ParseToString(bus.Roles),
+		InvitedBy:   bus.InvitedBy.String(),
+		ExpiresAt:   bus.ExpiresAt.Format(time.RFC3339),
+		DateCreated: bus.DateCreated.Format(time.RFC3339),
+	}
+}
+
+// =============================================================================
+
+// NewInvite defines the data needed to invite an email.
+type NewInvite struct {
+	Email string   `json:"email" validate:"required,email"`
+	Roles []string `json:"roles" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewInvite) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *NewInvite) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewInvite(app NewInvite) (invite.NewInvite, error) {
+	roles, err := role.ParseMany(app.Roles)
+	if err != nil {
+		return invite.NewInvite{}, fmt.Errorf("parse: %w", err)
+	}
+
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return invite.NewInvite{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := invite.NewInvite{
+		Email: *addr,
+		Roles: roles,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// AcceptInvite defines the data needed to accept an invitation and create the
+// account.
+type AcceptInvite struct {
+	Token           string `json:"token" validate:"required"`
+	Name            string `json:"name" validate:"required"`
+	Department      string `json:"department"`
+	Password        string `json:"password" validate:"required"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"eqfield=Password"`
+}
+
+// Decode implements the decoder interface.
+func (app *AcceptInvite) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *AcceptInvite) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// VerifyEmail defines the data needed to verify an email.
+type VerifyEmail struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *VerifyEmail) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *VerifyEmail) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+// ResendVerification defines the data needed to send the verification again.
+type ResendVerification struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Decode implements the decoder interface.
+func (app *ResendVerification) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *ResendVerification) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package order_usecase
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+)
+
+type AppQueryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	ID               string
+	UserID           string
+	Status           string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+func parseFilter(qp AppQueryParams) (order.QueryFilter, error) {
+	var fieldErrors validation.FieldErrors
+	var filter order.QueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		switch err {
+		case nil:
+			filter.ID = &id
+		default:
+			fieldErrors.Add("order_id", err)
+		}
+	}
+
+	if qp.UserID != "" {
+		id, err := uuid.Parse(qp.UserID)
+		switch err {
+		case nil:
+			filter.UserID = &id
+		default:
+			fieldErrors.Add("user_id", err)
+		}
+	}
+
+	if qp.Status != "" {
+		status, err := order.ParseStatus(qp.Status)
+		switch err {
+		case nil:
+			filter.Status = &status
+		default:
+			fieldErrors.Add("status", err)
+		}
+	}
+
+	if qp.StartCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
+		switch err {
+		case nil:
+			filter.StartCreatedDate = &t
+		default:
+			fieldErrors.Add("start_created_date", err)
+		}
+	}
+
+	if qp.EndCreatedDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndCreatedDate)
+		switch err {
+		case nil:
+			filter.EndCreatedDate = &t
+		default:
+			fieldErrors.Add("end_created_date", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return order.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
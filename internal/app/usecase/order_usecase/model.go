@@ -0,0 +1,133 @@
+package order_usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
+)
+
+// Order represents an order of products.
+type Order struct {
+	ID          string      `json:"id"`
+	UserID      string      `json:"userID"`
+	Status      string      `json:"status"`
+	Total       json.Number `json:"total"`
+	Currency    string      `json:"currency"`
+	Items       []Item      `json:"items"`
+	DateCreated string      `json:"dateCreated"`
+	DateUpdated string      `json:"dateUpdated"`
+}
+
+// Item represents a product in an order, the product is empty once it was
+// removed.
+type Item struct {
+	ID        string      `json:"id"`
+	ProductID string      `json:"productID,omitempty"`
+	Name      string      `json:"name"`
+	Price     json.Number `json:"price"`
+	Quantity  int         `json:"quantity"`
+	Total     json.Number `json:"total"`
+}
+
+// Encode implements the encoder interface.
+func (app Order) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppOrder(ord order.Order) Order {
+	items := make([]Item, len(ord.Items))
+	for i, itm := range ord.Items {
+		items[i] = Item{
+			ID:       itm.ID.String(),
+			Name:     itm.Name.String(),
+			Price:    json.Number(itm.Price.Decimal()),
+			Quantity: itm.Quantity.Value(),
+			Total:    json.Number(itm.Total.Decimal()),
+		}
+
+		if itm.ProductID != uuid.Nil {
+			items[i].ProductID = itm.ProductID.String()
+		}
+	}
+
+	return Order{
+		ID:          ord.ID.String(),
+		UserID:      ord.UserID.String(),
+		Status:      ord.Status.String(),
+		Total:       json.Number(ord.Total.Decimal()),
+		Currency:    ord.Total.Currency().String(),
+		Items:       items,
+		DateCreated: ord.DateCreated.Format(time.RFC3339),
+		DateUpdated: ord.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppOrders(ords []order.Order) []Order {
+	app := make([]Order, len(ords))
+	for i, ord := range ords {
+		app[i] = toAppOrder(ord)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewOrder defines the data needed to place an order.
+type NewOrder struct {
+	Items []NewItem `json:"items" validate:"required,min=1,dive"`
+}
+
+// NewItem defines the product and the quantity ordered.
+type NewItem struct {
+	ProductID string `json:"productID" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"required,gte=1"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewOrder) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewOrder) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	return nil
+}
+
+func toBusNewOrder(userID uuid.UUID, app NewOrder) (order.NewOrder, error) {
+	items := make([]order.NewItem, len(app.Items))
+	for i, itm := range app.Items {
+		productID, err := uuid.Parse(itm.ProductID)
+		if err != nil {
+			return order.NewOrder{}, fmt.Errorf("parse productID: %w", err)
+		}
+
+		q, err := quantity.Parse(itm.Quantity)
+		if err != nil {
+			return order.NewOrder{}, fmt.Errorf("parse quantity: %w", err)
+		}
+
+		items[i] = order.NewItem{
+			ProductID: productID,
+			Quantity:  q,
+		}
+	}
+
+	no := order.NewOrder{
+		UserID: userID,
+		Items:  items,
+	}
+
+	return no, nil
+}
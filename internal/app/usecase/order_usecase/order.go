@@ -0,0 +1,207 @@
+// Package order_usecase maintains the app layer api for the order core.
+package order_usecase
+
+import (
+	"context"
+	"errors"
+
+	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/internal/core/service/ordercore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	orderPck "github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// App manages the set of app layer api functions for the order core. The
+// order is the one of the request, set by the order middleware.
+type App struct {
+	orderBus *ordercore.Core
+}
+
+// NewApp constructs an order app API for use.
+func NewApp(orderBus *ordercore.Core) *App {
+	return &App{
+		orderBus: orderBus,
+	}
+}
+
+// newWithTx constructs a new App value with the core apis
+// using a store transaction that was created via middleware.
+func (a *App) newWithTx(ctx context.Context) (*App, error) {
+	tx, err := pgsql.GetTran(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBus, err := a.orderBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	app := App{
+		orderBus: orderBus,
+	}
+
+	return &app, nil
+}
+
+// Create places an order for the caller, taking the stock of the products
+// under a single transaction.
+func (a *App) Create(ctx context.Context, app NewOrder) (Order, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Order{}, errs.New(errs.Internal, err)
+	}
+
+	userID, err := ctxPck.GetUserID(ctx)
+	if err != nil {
+		return Order{}, errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	no, err := toBusNewOrder(userID, app)
+	if err != nil {
+		return Order{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	ord, err := a.orderBus.Create(ctx, no)
+	if err != nil {
+		return Order{}, toError(err, "create: userID[%s]: %s", userID)
+	}
+
+	return toAppOrder(ord), nil
+}
+
+// Pay marks the order of the request as paid.
+func (a *App) Pay(ctx context.Context) (Order, error) {
+	return a.updateStatus(ctx, order.Paid)
+}
+
+// Ship marks the order of the request as shipped.
+func (a *App) Ship(ctx context.Context) (Order, error) {
+	return a.updateStatus(ctx, order.Shipped)
+}
+
+// Cancel cancels the order of the request and gives the stock of its
+// products back.
+func (a *App) Cancel(ctx context.Context) (Order, error) {
+	return a.updateStatus(ctx, order.Cancelled)
+}
+
+// QueryByID returns the order of the request.
+func (a *App) QueryByID(ctx context.Context) (Order, error) {
+	ord, err := ctxPck.GetOrder(ctx)
+	if err != nil {
+		return Order{}, errs.Newf(errs.Internal, "querybyid: %s", err)
+	}
+
+	return toAppOrder(ord), nil
+}
+
+// Query returns a list of orders with paging.
+func (a *App) Query(ctx context.Context, qp AppQueryParams) (page.Result[Order], error) {
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return page.Result[Order]{}, err.(*errs.Error)
+	}
+
+	return a.query(ctx, filter, qp)
+}
+
+// QueryByUser returns the orders placed by the user of the request with
+// paging.
+func (a *App) QueryByUser(ctx context.Context, qp AppQueryParams) (page.Result[Order], error) {
+	usr, err := ctxPck.GetUser(ctx)
+	if err != nil {
+		return page.Result[Order]{}, errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return page.Result[Order]{}, err.(*errs.Error)
+	}
+	filter.UserID = &usr.ID
+
+	return a.query(ctx, filter, qp)
+}
+
+func (a *App) query(ctx context.Context, filter order.QueryFilter, qp AppQueryParams) (page.Result[Order], error) {
+	p, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return page.Result[Order]{}, validation.NewFieldErrors("page", err)
+	}
+
+	orderBy, err := orderPck.Parse(orderByFields, qp.OrderBy, order.DefaultOrderBy)
+	if err != nil {
+		return page.Result[Order]{}, validation.NewFieldErrors("order", err)
+	}
+
+	ords, err := a.orderBus.Query(ctx, filter, orderBy, p)
+	if err != nil {
+		return page.Result[Order]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.orderBus.Count(ctx, filter)
+	if err != nil {
+		return page.Result[Order]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return page.NewResult(toAppOrders(ords), total, p), nil
+}
+
+// updateStatus moves the order of the request to the status under a single
+// transaction, the caller is recorded as the actor.
+func (a *App) updateStatus(ctx context.Context, status order.Status) (Order, error) {
+	a, err := a.newWithTx(ctx)
+	if err != nil {
+		return Order{}, errs.New(errs.Internal, err)
+	}
+
+	ord, err := ctxPck.GetOrder(ctx)
+	if err != nil {
+		return Order{}, errs.Newf(errs.Internal, "order missing in context: %s", err)
+	}
+
+	actorID, err := ctxPck.GetUserID(ctx)
+	if err != nil {
+		return Order{}, errs.Newf(errs.Internal, "user missing in context: %s", err)
+	}
+
+	updOrd, err := a.orderBus.UpdateStatus(ctx, ord, order.UpdateStatus{
+		Status:  status,
+		ActorID: actorID,
+	})
+	if err != nil {
+		return Order{}, toError(err, "updatestatus: orderID[%s]: %s", ord.ID)
+	}
+
+	return toAppOrder(updOrd), nil
+}
+
+// toError maps the errors of the order core to the codes of the api.
+func toError(err error, format string, id any) error {
+	for _, target := range []error{order.ErrNoItems, order.ErrDuplicateItem, order.ErrCurrencyMismatch} {
+		if errors.Is(err, target) {
+			return errs.New(errs.InvalidArgument, target)
+		}
+	}
+
+	switch {
+	case errors.Is(err, product.ErrNotFound):
+		return errs.New(errs.NotFound, product.ErrNotFound)
+	case errors.Is(err, order.ErrUserDisabled):
+		return errs.New(errs.FailedPrecondition, order.ErrUserDisabled)
+	case errors.Is(err, stock.ErrInsufficientStock):
+		return errs.New(errs.FailedPrecondition, stock.ErrInsufficientStock)
+	case errors.Is(err, order.ErrInvalidTransition):
+		return errs.New(errs.FailedPrecondition, order.ErrInvalidTransition)
+	case errors.Is(err, order.ErrStatusChanged):
+		return errs.New(errs.Aborted, order.ErrStatusChanged)
+	}
+
+	return errs.Newf(errs.Internal, format, id, err)
+}
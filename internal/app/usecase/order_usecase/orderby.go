@@ -0,0 +1,11 @@
+package order_usecase
+
+import "github.com/Housiadas/backend-system/internal/core/domain/order"
+
+var orderByFields = map[string]string{
+	"order_id":     order.OrderByID,
+	"user_id":      order.OrderByUserID,
+	"status":       order.OrderByStatus,
+	"total":        order.OrderByTotal,
+	"date_created": order.OrderByDateCreated,
+}
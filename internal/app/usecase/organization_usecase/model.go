@@ -0,0 +1,84 @@
+package organization_usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// AppQueryParams represents the paging of the organizations.
+type AppQueryParams struct {
+	Page string
+	Rows string
+}
+
+// Organization represents information about an organization.
+type Organization struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Organization) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppOrganization(org organization.Organization) Organization {
+	return Organization{
+		ID:          org.ID.String(),
+		Name:        org.Name.String(),
+		DateCreated: org.DateCreated.Format(time.RFC3339),
+		DateUpdated: org.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppOrganizations(orgs []organization.Organization) []Organization {
+	app := make([]Organization, len(orgs))
+	for i, org := range orgs {
+		app[i] = toAppOrganization(org)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewOrganization defines the data needed to add a new organization.
+type NewOrganization struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewOrganization) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *NewOrganization) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewOrganization(app NewOrganization) (organization.NewOrganization, error) {
+	nme, err := name.Parse(app.Name)
+	if err != nil {
+		return organization.NewOrganization{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := organization.NewOrganization{
+		Name: nme,
+	}
+
+	return bus, nil
+}
@@ -0,0 +1,84 @@
+// Package organization_usecase maintains the app layer api for the
+// organization core.
+package organization_usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/service/organizationcore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+// App manages the set of app layer api functions for the organization core.
+type App struct {
+	organizationCore *organizationcore.Core
+}
+
+// NewApp constructs an organization app API for use.
+func NewApp(organizationCore *organizationcore.Core) *App {
+	return &App{
+		organizationCore: organizationCore,
+	}
+}
+
+// Create adds a new organization to the system.
+func (a *App) Create(ctx context.Context, app NewOrganization) (Organization, error) {
+	no, err := toBusNewOrganization(app)
+	if err != nil {
+		return Organization{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	org, err := a.organizationCore.Create(ctx, no)
+	if err != nil {
+		if errors.Is(err, organization.ErrUniqueName) {
+			return Organization{}, errs.New(errs.Aborted, organization.ErrUniqueName)
+		}
+		return Organization{}, errs.Newf(errs.Internal, "create: org[%+v]: %s", no, err)
+	}
+
+	return toAppOrganization(org), nil
+}
+
+// Query returns a list of organizations with paging.
+func (a *App) Query(ctx context.Context, qp AppQueryParams) (page.Result[Organization], error) {
+	p, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return page.Result[Organization]{}, validation.NewFieldErrors("page", err)
+	}
+
+	orgs, err := a.organizationCore.Query(ctx, p)
+	if err != nil {
+		return page.Result[Organization]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.organizationCore.Count(ctx)
+	if err != nil {
+		return page.Result[Organization]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return page.NewResult(toAppOrganizations(orgs), total, p), nil
+}
+
+// QueryByID returns an organization by its ID.
+func (a *App) QueryByID(ctx context.Context, id string) (Organization, error) {
+	orgID, err := uuid.Parse(id)
+	if err != nil {
+		return Organization{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	org, err := a.organizationCore.QueryByID(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, organization.ErrNotFound) {
+			return Organization{}, errs.New(errs.NotFound, organization.ErrNotFound)
+		}
+		return Organization{}, errs.Newf(errs.Internal, "querybyid: orgID[%s]: %s", orgID, err)
+	}
+
+	return toAppOrganization(org), nil
+}
@@ -0,0 +1,51 @@
+package password_usecase
+
+import (
+	"encoding/json"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// ForgotPassword defines the data needed to request a password reset.
+type ForgotPassword struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Encode implements the encoder interface.
+func (app *ForgotPassword) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *ForgotPassword) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+// ResetPassword defines the data needed to set a new password with a reset
+// token.
+type ResetPassword struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"eqfield=Password"`
+}
+
+// Encode implements the encoder interface.
+func (app *ResetPassword) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *ResetPassword) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+// Package password_usecase maintains the app layer api for the self-service
+// password reset.
+package password_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/password"
+	"github.com/Housiadas/backend-system/internal/core/domain/passwordreset"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/passwordresetcore"
+	"github.com/Housiadas/backend-system/internal/core/service/sessioncore"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/notifier"
+)
+
+// App manages the set of app layer api functions for the password reset.
+type App struct {
+	log         *logger.Logger
+	userCore    *usercore.Core
+	sessionCore *sessioncore.Core
+	resetCore   *passwordresetcore.Core
+	notifier    notifier.Notifier
+	policy      password.Policy
+	resetURL    string
+}
+
+// NewApp constructs a password app API for use. The reset token is sent to
+// the user with the notifier, as a query parameter of the reset url. The new
+// password must satisfy the policy.
+func NewApp(
+	log *logger.Logger,
+	userCore *usercore.Core,
+	sessionCore *sessioncore.Core,
+	resetCore *passwordresetcore.Core,
+	notifier notifier.Notifier,
+	policy password.Policy,
+	resetURL string,
+) *App {
+	return &App{
+		log:         log,
+		userCore:    userCore,
+		sessionCore: sessionCore,
+		resetCore:   resetCore,
+		notifier:    notifier,
+		policy:      policy,
+		resetURL:    resetURL,
+	}
+}
+
+// Forgot sends a reset token to the user with the email. It succeeds whether
+// the email is known or not, so it can't be used to find out which emails
+// have an account.
+func (a *App) Forgot(ctx context.Context, app ForgotPassword) error {
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "parse: %s", err)
+	}
+
+	usr, err := a.userCore.QueryByEmail(ctx, *addr)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil
+		}
+		return errs.Newf(errs.Internal, "forgot: email[%s]: %s", addr.Address, err)
+	}
+
+	if !usr.Enabled {
+		return nil
+	}
+
+	_, token, err := a.resetCore.Create(ctx, usr.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "forgot: userID[%s]: %s", usr.ID, err)
+	}
+
+	msg := notifier.Message{
+		To:      usr.Email.Address,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Follow the link to set a new password, it expires in %s:\n\n%s",
+			a.resetCore.TTL().Round(time.Minute), a.link(token),
+		),
+	}
+
+	// A failed delivery is not reported to the caller, it would tell the
+	// email has an account.
+	if err := a.notifier.Notify(ctx, msg); err != nil {
+		a.log.Error(ctx, "password reset", "status", "notifying", "userID", usr.ID, "msg", err)
+	}
+
+	return nil
+}
+
+// Reset sets a new password with a reset token. Every session of the user is
+// revoked and the tokens issued before are rejected.
+func (a *App) Reset(ctx context.Context, app ResetPassword) error {
+	if err := a.policy.Check(app.Password); err != nil {
+		return errs.Newf(errs.InvalidArgument, "password: %s", err)
+	}
+
+	r, err := a.resetCore.Redeem(ctx, app.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, passwordreset.ErrInvalidToken),
+			errors.Is(err, passwordreset.ErrExpired),
+			errors.Is(err, passwordreset.ErrUsed):
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "reset: %s", err)
+	}
+
+	usr, err := a.userCore.QueryByID(ctx, r.UserID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "reset: userID[%s]: %s", r.UserID, err)
+	}
+
+	if _, err := a.userCore.ResetPassword(ctx, usr, app.Password); err != nil {
+		return errs.Newf(errs.Internal, "reset: userID[%s]: %s", usr.ID, err)
+	}
+
+	if err := a.sessionCore.RevokeAll(ctx, usr.ID); err != nil {
+		return errs.Newf(errs.Internal, "revokeall: userID[%s]: %s", usr.ID, err)
+	}
+
+	return nil
+}
+
+// link returns the url to reset the password with the token.
+func (a *App) link(token string) string {
+	u, err := url.Parse(a.resetURL)
+	if err != nil {
+		return token
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,63 @@
+package price_usecase
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+)
+
+type AppQueryParams struct {
+	Page               string
+	Rows               string
+	OrderBy            string
+	Applied            string
+	StartEffectiveDate string
+	EndEffectiveDate   string
+}
+
+func parseFilter(productID uuid.UUID, qp AppQueryParams) (price.QueryFilter, error) {
+	var fieldErrors validation.FieldErrors
+	filter := price.QueryFilter{
+		ProductID: &productID,
+	}
+
+	if qp.Applied != "" {
+		applied, err := strconv.ParseBool(qp.Applied)
+		switch err {
+		case nil:
+			filter.Applied = &applied
+		default:
+			fieldErrors.Add("applied", err)
+		}
+	}
+
+	if qp.StartEffectiveDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartEffectiveDate)
+		switch err {
+		case nil:
+			filter.StartEffectiveDate = &t
+		default:
+			fieldErrors.Add("start_effective_date", err)
+		}
+	}
+
+	if qp.EndEffectiveDate != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndEffectiveDate)
+		switch err {
+		case nil:
+			filter.EndEffectiveDate = &t
+		default:
+			fieldErrors.Add("end_effective_date", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return price.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,113 @@
+package price_usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
+)
+
+// Price represents the cost of a product from the time it takes effect. The
+// date applied is empty while the price is scheduled.
+type Price struct {
+	ID            string      `json:"id"`
+	ProductID     string      `json:"productID"`
+	Cost          json.Number `json:"cost"`
+	Currency      string      `json:"currency"`
+	EffectiveFrom string      `json:"effectiveFrom"`
+	DateApplied   string      `json:"dateApplied,omitempty"`
+	DateCreated   string      `json:"dateCreated"`
+}
+
+// Encode implements the encoder interface.
+func (app Price) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppPrice(pr price.Price) Price {
+	app := Price{
+		ID:            pr.ID.String(),
+		ProductID:     pr.ProductID.String(),
+		Cost:          json.Number(pr.Cost.Decimal()),
+		Currency:      pr.Cost.Currency().String(),
+		EffectiveFrom: pr.EffectiveFrom.Format(time.RFC3339),
+		DateCreated:   pr.DateCreated.Format(time.RFC3339),
+	}
+
+	if pr.IsApplied() {
+		app.DateApplied = pr.DateApplied.Format(time.RFC3339)
+	}
+
+	return app
+}
+
+func toAppPrices(prs []price.Price) []Price {
+	app := make([]Price, len(prs))
+	for i, pr := range prs {
+		app[i] = toAppPrice(pr)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewPrice defines the data needed to schedule a price of a product. The cost
+// is in the current currency of the product when none is given.
+type NewPrice struct {
+	Cost          json.Number `json:"cost" validate:"required"`
+	Currency      string      `json:"currency"`
+	EffectiveFrom string      `json:"effectiveFrom" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewPrice) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewPrice) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	return nil
+}
+
+func toBusNewPrice(prd product.Product, app NewPrice) (price.NewPrice, error) {
+	currency := prd.Cost.Currency()
+	if app.Currency != "" {
+		cur, err := money.ParseCurrency(app.Currency)
+		if err != nil {
+			return price.NewPrice{}, fmt.Errorf("parse currency: %w", err)
+		}
+		currency = cur
+	}
+
+	cost, err := money.Parse(app.Cost.String(), currency)
+	if err != nil {
+		return price.NewPrice{}, validation.NewFieldErrors("cost", err)
+	}
+
+	if err := product.CheckCost(cost); err != nil {
+		return price.NewPrice{}, validation.NewFieldErrors("cost", err)
+	}
+
+	effectiveFrom, err := time.Parse(time.RFC3339, app.EffectiveFrom)
+	if err != nil {
+		return price.NewPrice{}, fmt.Errorf("parse effective from: %w", err)
+	}
+
+	np := price.NewPrice{
+		ProductID:     prd.ID,
+		Cost:          cost,
+		EffectiveFrom: effectiveFrom,
+	}
+
+	return np, nil
+}
@@ -0,0 +1,9 @@
+package price_usecase
+
+import "github.com/Housiadas/backend-system/internal/core/domain/price"
+
+var orderByFields = map[string]string{
+	"effective_from": price.OrderByEffectiveFrom,
+	"cost":           price.OrderByCost,
+	"date_created":   price.OrderByDateCreated,
+}
@@ -0,0 +1,123 @@
+// Package price_usecase maintains the app layer api for the price history of
+// the products.
+package price_usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+	"github.com/Housiadas/backend-system/internal/core/service/pricecore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+// App manages the set of app layer api functions for the price core. The
+// product is the one of the request, set by the product middleware.
+type App struct {
+	priceBus *pricecore.Core
+}
+
+// NewApp constructs a price app API for use.
+func NewApp(priceBus *pricecore.Core) *App {
+	return &App{
+		priceBus: priceBus,
+	}
+}
+
+// Schedule sets a price of the product to take effect later on.
+func (a *App) Schedule(ctx context.Context, app NewPrice) (Price, error) {
+	prd, err := ctxPck.GetProduct(ctx)
+	if err != nil {
+		return Price{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	np, err := toBusNewPrice(prd, app)
+	if err != nil {
+		return Price{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	pr, err := a.priceBus.Schedule(ctx, prd, np)
+	if err != nil {
+		return Price{}, toError(err, "schedule: productID[%s]: %s", prd.ID)
+	}
+
+	return toAppPrice(pr), nil
+}
+
+// PriceAt returns the price of the product in effect at the time, now when
+// no time is given.
+func (a *App) PriceAt(ctx context.Context, at string) (Price, error) {
+	prd, err := ctxPck.GetProduct(ctx)
+	if err != nil {
+		return Price{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	t := time.Now()
+	if at != "" {
+		t, err = time.Parse(time.RFC3339, at)
+		if err != nil {
+			return Price{}, validation.NewFieldErrors("at", err)
+		}
+	}
+
+	pr, err := a.priceBus.PriceAt(ctx, prd.ID, t)
+	if err != nil {
+		return Price{}, toError(err, "priceat: productID[%s]: %s", prd.ID)
+	}
+
+	return toAppPrice(pr), nil
+}
+
+// Query returns the price history of the product, scheduled prices included.
+func (a *App) Query(ctx context.Context, qp AppQueryParams) (page.Result[Price], error) {
+	prd, err := ctxPck.GetProduct(ctx)
+	if err != nil {
+		return page.Result[Price]{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	p, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return page.Result[Price]{}, validation.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(prd.ID, qp)
+	if err != nil {
+		return page.Result[Price]{}, err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, price.DefaultOrderBy)
+	if err != nil {
+		return page.Result[Price]{}, validation.NewFieldErrors("order", err)
+	}
+
+	prs, err := a.priceBus.Query(ctx, filter, orderBy, p)
+	if err != nil {
+		return page.Result[Price]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.priceBus.Count(ctx, filter)
+	if err != nil {
+		return page.Result[Price]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return page.NewResult(toAppPrices(prs), total, p), nil
+}
+
+// toError maps the errors of the price core to the codes of the api.
+func toError(err error, format string, id any) error {
+	switch {
+	case errors.Is(err, price.ErrNotFound):
+		return errs.New(errs.NotFound, price.ErrNotFound)
+	case errors.Is(err, price.ErrInvalidCost):
+		return errs.New(errs.InvalidArgument, price.ErrInvalidCost)
+	case errors.Is(err, price.ErrNotInFuture):
+		return errs.New(errs.InvalidArgument, price.ErrNotInFuture)
+	}
+
+	return errs.Newf(errs.Internal, format, id, err)
+}
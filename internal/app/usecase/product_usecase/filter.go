@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
 	"github.com/Housiadas/backend-system/internal/core/domain/name"
 	"github.com/Housiadas/backend-system/internal/core/domain/product"
 )
@@ -17,6 +18,7 @@ type AppQueryParams struct {
 	ID       string
 	Name     string
 	Cost     string
+	Currency string
 	Quantity string
 }
 
@@ -44,8 +46,20 @@ func parseFilter(qp AppQueryParams) (product.QueryFilter, error) {
 		}
 	}
 
+	currency := money.DefaultCurrency
+	if qp.Currency != "" {
+		cur, err := money.ParseCurrency(qp.Currency)
+		switch err {
+		case nil:
+			currency = cur
+			filter.Currency = &cur
+		default:
+			fieldErrors.Add("currency", err)
+		}
+	}
+
 	if qp.Cost != "" {
-		cst, err := strconv.ParseFloat(qp.Cost, 64)
+		cst, err := money.Parse(qp.Cost, currency)
 		switch err {
 		case nil:
 			filter.Cost = &cst
@@ -15,14 +15,17 @@ import (
 )
 
 // The Product represents information about an individual product.
+// The cost is a JSON number holding the exact decimal, it is never read
+// as a floating point number.
 type Product struct {
-	ID          string  `json:"id"`
-	UserID      string  `json:"userID"`
-	Name        string  `json:"name"`
-	Cost        float64 `json:"cost"`
-	Quantity    int     `json:"quantity"`
-	DateCreated string  `json:"dateCreated"`
-	DateUpdated string  `json:"dateUpdated"`
+	ID          string      `json:"id"`
+	UserID      string      `json:"userID"`
+	Name        string      `json:"name"`
+	Cost        json.Number `json:"cost"`
+	Currency    string      `json:"currency"`
+	Quantity    int         `json:"quantity"`
+	DateCreated string      `json:"dateCreated"`
+	DateUpdated string      `json:"dateUpdated"`
 }
 
 // Encode implements the encoder interface.
@@ -36,7 +39,8 @@ func toAppProduct(prd product.Product) Product {
 		ID:          prd.ID.String(),
 		UserID:      prd.UserID.String(),
 		Name:        prd.Name.String(),
-		Cost:        prd.Cost.Value(),
+		Cost:        json.Number(prd.Cost.Decimal()),
+		Currency:    prd.Cost.Currency().String(),
 		Quantity:    prd.Quantity.Value(),
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
@@ -54,11 +58,13 @@ func toAppProducts(prds []product.Product) []Product {
 
 // =============================================================================
 
-// NewProduct defines the data needed to add a new product.
+// NewProduct defines the data needed to add a new product. The cost is in
+// the default currency when none is given.
 type NewProduct struct {
-	Name     string  `json:"name" validate:"required"`
-	Cost     float64 `json:"cost" validate:"required,gte=0"`
-	Quantity int     `json:"quantity" validate:"required,gte=1"`
+	Name     string      `json:"name" validate:"required"`
+	Cost     json.Number `json:"cost" validate:"required"`
+	Currency string      `json:"currency"`
+	Quantity int         `json:"quantity" validate:"required,gte=1"`
 }
 
 // Decode implements the decoder interface.
@@ -86,9 +92,22 @@ func toBusNewProduct(ctx context.Context, app NewProduct) (product.NewProduct, e
 		return product.NewProduct{}, fmt.Errorf("parse name: %w", err)
 	}
 
-	cost, err := money.Parse(app.Cost)
+	currency := money.DefaultCurrency
+	if app.Currency != "" {
+		currency, err = money.ParseCurrency(app.Currency)
+		if err != nil {
+			return product.NewProduct{}, fmt.Errorf("parse currency: %w", err)
+		}
+	}
+
+	// The cost is checked once parsed, as an exact decimal of the currency.
+	cost, err := money.Parse(app.Cost.String(), currency)
 	if err != nil {
-		return product.NewProduct{}, fmt.Errorf("parse cost: %w", err)
+		return product.NewProduct{}, validation.NewFieldErrors("cost", err)
+	}
+
+	if err := product.CheckCost(cost); err != nil {
+		return product.NewProduct{}, validation.NewFieldErrors("cost", err)
 	}
 
 	q, err := quantity.Parse(app.Quantity)
@@ -108,11 +127,13 @@ func toBusNewProduct(ctx context.Context, app NewProduct) (product.NewProduct, e
 
 // =============================================================================
 
-// UpdateProduct defines the data needed to update a product.
+// UpdateProduct defines the data needed to update a product. The cost is in
+// the currency of the product when none is given.
 type UpdateProduct struct {
-	Name     *string  `json:"name"`
-	Cost     *float64 `json:"cost" validate:"omitempty,gte=0"`
-	Quantity *int     `json:"quantity" validate:"omitempty,gte=1"`
+	Name     *string      `json:"name"`
+	Cost     *json.Number `json:"cost"`
+	Currency *string      `json:"currency"`
+	Quantity *int         `json:"quantity" validate:"omitempty,gte=1"`
 }
 
 // Decode implements the decoder interface.
@@ -129,7 +150,7 @@ func (app *UpdateProduct) Validate() error {
 	return nil
 }
 
-func toBusUpdateProduct(app UpdateProduct) (product.UpdateProduct, error) {
+func toBusUpdateProduct(app UpdateProduct, cost money.Money) (product.UpdateProduct, error) {
 	var nme *namePck.Name
 	if app.Name != nil {
 		nm, err := namePck.Parse(*app.Name)
@@ -139,17 +160,36 @@ func toBusUpdateProduct(app UpdateProduct) (product.UpdateProduct, error) {
 		nme = &nm
 	}
 
-	var cost *money.Money
-	if app.Cost != nil {
-		cst, err := money.Parse(*app.Cost)
+	// Changing the currency alone keeps the amount of the cost.
+	currency := cost.Currency()
+	if app.Currency != nil {
+		cur, err := money.ParseCurrency(*app.Currency)
 		if err != nil {
 			return product.UpdateProduct{}, fmt.Errorf("parse: %w", err)
 		}
-		cost = &cst
+		currency = cur
 	}
 
-	var qnt *quantity.Quantity
+	value := cost.Decimal()
 	if app.Cost != nil {
+		value = app.Cost.String()
+	}
+
+	var cst *money.Money
+	if app.Cost != nil || app.Currency != nil {
+		c, err := money.Parse(value, currency)
+		if err != nil {
+			return product.UpdateProduct{}, validation.NewFieldErrors("cost", err)
+		}
+
+		if err := product.CheckCost(c); err != nil {
+			return product.UpdateProduct{}, validation.NewFieldErrors("cost", err)
+		}
+		cst = &c
+	}
+
+	var qnt *quantity.Quantity
+	if app.Quantity != nil {
 		qn, err := quantity.Parse(*app.Quantity)
 		if err != nil {
 			return product.UpdateProduct{}, fmt.Errorf("parse: %w", err)
@@ -159,7 +199,7 @@ func toBusUpdateProduct(app UpdateProduct) (product.UpdateProduct, error) {
 
 	bus := product.UpdateProduct{
 		Name:     nme,
-		Cost:     cost,
+		Cost:     cst,
 		Quantity: qnt,
 	}
 
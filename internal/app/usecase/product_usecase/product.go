@@ -3,9 +3,11 @@ package product_usecase
 
 import (
 	"context"
+	"errors"
 
 	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
 	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
 	"github.com/Housiadas/backend-system/internal/core/service/productcore"
 	"github.com/Housiadas/backend-system/pkg/errs"
 	"github.com/Housiadas/backend-system/pkg/order"
@@ -41,18 +43,21 @@ func (a *App) Create(ctx context.Context, app NewProduct) (Product, error) {
 
 // Update updates an existing product.
 func (a *App) Update(ctx context.Context, app UpdateProduct) (Product, error) {
-	up, err := toBusUpdateProduct(app)
+	prd, err := ctxPck.GetProduct(ctx)
 	if err != nil {
-		return Product{}, errs.New(errs.InvalidArgument, err)
+		return Product{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
 	}
 
-	prd, err := ctxPck.GetProduct(ctx)
+	up, err := toBusUpdateProduct(app, prd.Cost)
 	if err != nil {
-		return Product{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+		return Product{}, errs.New(errs.InvalidArgument, err)
 	}
 
 	updPrd, err := a.productBus.Update(ctx, prd, up)
 	if err != nil {
+		if errors.Is(err, product.ErrNotFound) {
+			return Product{}, errs.New(errs.NotFound, product.ErrNotFound)
+		}
 		return Product{}, errs.Newf(errs.Internal, "update: productID[%s] up[%+v]: %s", prd.ID, app, err)
 	}
 
@@ -0,0 +1,139 @@
+package rbac_usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/rbac"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// Role represents information about a role and its permissions.
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Role) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppRole(r rbac.Role) Role {
+	return Role{
+		Name:        r.Name.String(),
+		Description: r.Description,
+		Permissions: permission.ParseToString(r.Permissions),
+		DateCreated: r.DateCreated.Format(time.RFC3339),
+		DateUpdated: r.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// Roles represents a collection of roles.
+type Roles []Role
+
+// Encode implements the encoder interface.
+func (app Roles) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppRoles(roles []rbac.Role) Roles {
+	app := make(Roles, len(roles))
+	for i, r := range roles {
+		app[i] = toAppRole(r)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewRole defines the data needed to add a new role.
+type NewRole struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewRole) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *NewRole) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+func toBusNewRole(app NewRole) (rbac.NewRole, error) {
+	name, err := role.New(app.Name)
+	if err != nil {
+		return rbac.NewRole{}, fmt.Errorf("parse: %w", err)
+	}
+
+	perms, err := permission.ParseMany(app.Permissions)
+	if err != nil {
+		return rbac.NewRole{}, fmt.Errorf("parse: %w", err)
+	}
+
+	bus := rbac.NewRole{
+		Name:        name,
+		Description: app.Description,
+		Permissions: perms,
+	}
+
+	return bus, nil
+}
+
+// =============================================================================
+
+// UpdateRole defines the data needed to update a role.
+type UpdateRole struct {
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// Decode implements the decoder interface.
+func (app *UpdateRole) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app *UpdateRole) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return errs.Newf(errs.InvalidArgument, "validation: %s", err)
+	}
+
+	return nil
+}
+
+func toBusUpdateRole(app UpdateRole) (rbac.UpdateRole, error) {
+	var perms []permission.Permission
+	if app.Permissions != nil {
+		var err error
+		perms, err = permission.ParseMany(app.Permissions)
+		if err != nil {
+			return rbac.UpdateRole{}, fmt.Errorf("parse: %w", err)
+		}
+	}
+
+	bus := rbac.UpdateRole{
+		Description: app.Description,
+		Permissions: perms,
+	}
+
+	return bus, nil
+}
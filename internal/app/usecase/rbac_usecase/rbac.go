@@ -0,0 +1,116 @@
+// Package rbac_usecase maintains the cli layer api for the rbac core.
+package rbac_usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/rbac"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/service/rbaccore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+)
+
+// App manages the set of cli layer api functions for the rbac core.
+type App struct {
+	rbacCore *rbaccore.Core
+}
+
+// NewApp constructs a rbac cli API for use.
+func NewApp(rbacCore *rbaccore.Core) *App {
+	return &App{
+		rbacCore: rbacCore,
+	}
+}
+
+// Create adds a new role to the system.
+func (a *App) Create(ctx context.Context, app NewRole) (Role, error) {
+	nr, err := toBusNewRole(app)
+	if err != nil {
+		return Role{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	r, err := a.rbacCore.Create(ctx, nr)
+	if err != nil {
+		if errors.Is(err, rbac.ErrUniqueName) {
+			return Role{}, errs.New(errs.Aborted, rbac.ErrUniqueName)
+		}
+		return Role{}, errs.Newf(errs.Internal, "create: role[%+v]: %s", nr, err)
+	}
+
+	return toAppRole(r), nil
+}
+
+// Update updates an existing role.
+func (a *App) Update(ctx context.Context, name string, app UpdateRole) (Role, error) {
+	ur, err := toBusUpdateRole(app)
+	if err != nil {
+		return Role{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	r, err := a.queryByName(ctx, name)
+	if err != nil {
+		return Role{}, err
+	}
+
+	updRole, err := a.rbacCore.Update(ctx, r, ur)
+	if err != nil {
+		return Role{}, errs.Newf(errs.Internal, "update: name[%s] ur[%+v]: %s", name, app, err)
+	}
+
+	return toAppRole(updRole), nil
+}
+
+// Delete removes a role from the system.
+func (a *App) Delete(ctx context.Context, name string) error {
+	r, err := a.queryByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if err := a.rbacCore.Delete(ctx, r); err != nil {
+		if errors.Is(err, rbac.ErrBuiltIn) {
+			return errs.New(errs.FailedPrecondition, rbac.ErrBuiltIn)
+		}
+		return errs.Newf(errs.Internal, "delete: name[%s]: %s", name, err)
+	}
+
+	return nil
+}
+
+// Query returns all the roles.
+func (a *App) Query(ctx context.Context) (Roles, error) {
+	roles, err := a.rbacCore.Query(ctx)
+	if err != nil {
+		return nil, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	return toAppRoles(roles), nil
+}
+
+// QueryByName returns a role by its name.
+func (a *App) QueryByName(ctx context.Context, name string) (Role, error) {
+	r, err := a.queryByName(ctx, name)
+	if err != nil {
+		return Role{}, err
+	}
+
+	return toAppRole(r), nil
+}
+
+func (a *App) queryByName(ctx context.Context, name string) (rbac.Role, error) {
+	rn, err := role.New(name)
+	if err != nil {
+		return rbac.Role{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	r, err := a.rbacCore.QueryByName(ctx, rn)
+	if err != nil {
+		if errors.Is(err, rbac.ErrNotFound) {
+			return rbac.Role{}, errs.New(errs.NotFound, rbac.ErrNotFound)
+		}
+		return rbac.Role{}, errs.Newf(errs.Internal, "querybyname: name[%s]: %s", name, err)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,81 @@
+package securityevent_usecase
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+)
+
+type AppQueryParams struct {
+	Page    string
+	Rows    string
+	OrderBy string
+	Kind    string
+	ActorID string
+	Outcome string
+	IP      string
+	Since   string
+	Until   string
+}
+
+func parseFilter(qp AppQueryParams) (securityevent.QueryFilter, error) {
+	var fieldErrors validation.FieldErrors
+	var filter securityevent.QueryFilter
+
+	if qp.Kind != "" {
+		kind, err := securityevent.ParseKind(qp.Kind)
+		switch err {
+		case nil:
+			filter.Kind = &kind
+		default:
+			fieldErrors.Add("kind", err)
+		}
+	}
+
+	if qp.ActorID != "" {
+		id, err := uuid.Parse(qp.ActorID)
+		switch err {
+		case nil:
+			filter.ActorID = &id
+		default:
+			fieldErrors.Add("actor_id", err)
+		}
+	}
+
+	if qp.Outcome != "" {
+		filter.Outcome = &qp.Outcome
+	}
+
+	if qp.IP != "" {
+		filter.IP = &qp.IP
+	}
+
+	if qp.Since != "" {
+		t, err := time.Parse(time.RFC3339, qp.Since)
+		switch err {
+		case nil:
+			filter.Since = &t
+		default:
+			fieldErrors.Add("since", err)
+		}
+	}
+
+	if qp.Until != "" {
+		t, err := time.Parse(time.RFC3339, qp.Until)
+		switch err {
+		case nil:
+			filter.Until = &t
+		default:
+			fieldErrors.Add("until", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return securityevent.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,57 @@
+package securityevent_usecase
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+)
+
+// SecurityEvent represents information about an individual security event.
+type SecurityEvent struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	ActorID   string `json:"actorID,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"userAgent"`
+	Outcome   string `json:"outcome"`
+	Reason    string `json:"reason,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Encode implements the encoder interface.
+func (app SecurityEvent) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppSecurityEvent(evt securityevent.Event) SecurityEvent {
+	app := SecurityEvent{
+		ID:        evt.ID.String(),
+		Kind:      evt.Kind.String(),
+		Subject:   evt.Subject,
+		IP:        evt.IP,
+		UserAgent: evt.UserAgent,
+		Outcome:   evt.Outcome,
+		Reason:    evt.Reason,
+		Timestamp: evt.Timestamp.Format(time.RFC3339),
+	}
+
+	if evt.ActorID != uuid.Nil {
+		app.ActorID = evt.ActorID.String()
+	}
+
+	return app
+}
+
+func toAppSecurityEvents(events []securityevent.Event) []SecurityEvent {
+	app := make([]SecurityEvent, len(events))
+	for i, evt := range events {
+		app[i] = toAppSecurityEvent(evt)
+	}
+
+	return app
+}
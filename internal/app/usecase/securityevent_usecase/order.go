@@ -0,0 +1,11 @@
+package securityevent_usecase
+
+import "github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+
+var orderByFields = map[string]string{
+	"timestamp": securityevent.OrderByTimestamp,
+	"kind":      securityevent.OrderByKind,
+	"actor_id":  securityevent.OrderByActorID,
+	"outcome":   securityevent.OrderByOutcome,
+	"ip":        securityevent.OrderByIP,
+}
@@ -0,0 +1,54 @@
+// Package securityevent_usecase maintains the app layer api for the security
+// event domain.
+package securityevent_usecase
+
+import (
+	"context"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/internal/core/service/securityeventcore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+type App struct {
+	securityEventCore *securityeventcore.Core
+}
+
+func NewApp(core *securityeventcore.Core) *App {
+	return &App{
+		securityEventCore: core,
+	}
+}
+
+// Query returns the security events matching the parameters.
+func (a *App) Query(ctx context.Context, qp AppQueryParams) (page.Result[SecurityEvent], error) {
+	p, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return page.Result[SecurityEvent]{}, validation.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return page.Result[SecurityEvent]{}, err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, securityevent.DefaultOrderBy)
+	if err != nil {
+		return page.Result[SecurityEvent]{}, validation.NewFieldErrors("order", err)
+	}
+
+	events, err := a.securityEventCore.Query(ctx, filter, orderBy, p)
+	if err != nil {
+		return page.Result[SecurityEvent]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.securityEventCore.Count(ctx, filter)
+	if err != nil {
+		return page.Result[SecurityEvent]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return page.NewResult(toAppSecurityEvents(events), total, p), nil
+}
@@ -0,0 +1,26 @@
+package sso_usecase
+
+import (
+	"encoding/json"
+)
+
+// Login is returned to start a login, the user is sent to the authorization
+// url of the identity provider.
+type Login struct {
+	AuthorizationURL string `json:"authorization_url"`
+	ExpiresIn        int    `json:"expires_in"`
+}
+
+// Encode implements the encoder interface.
+func (l Login) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(l)
+	return data, "application/json", err
+}
+
+// Callback defines the data the identity provider redirects back with.
+type Callback struct {
+	Code             string
+	State            string
+	Error            string
+	ErrorDescription string
+}
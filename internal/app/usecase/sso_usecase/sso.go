@@ -0,0 +1,304 @@
+// Package sso_usecase maintains the app layer api for the single sign-on with
+// an OpenID Connect identity provider.
+package sso_usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/encrypt"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/oidc"
+)
+
+// defaultStateTTL is how long a login can take when none is configured.
+const defaultStateTTL = 10 * time.Minute
+
+// stateAAD binds the encrypted login state to its use.
+var stateAAD = []byte("sso state")
+
+// Config represents the settings of the single sign-on.
+//
+// RolesClaim names the claim of the ID token listing the groups of the user,
+// they are mapped to roles with RoleMapping. The groups are matched case
+// insensitively. A user with no mapped group gets the DefaultRoles. A user
+// unknown by email is created in the organization OrgID when Provision is
+// set. The email must be verified by the provider unless AllowUnverifiedEmail
+// is set.
+type Config struct {
+	OrgID                uuid.UUID
+	RolesClaim           string
+	RoleMapping          map[string]string
+	DefaultRoles         []string
+	Provision            bool
+	AllowUnverifiedEmail bool
+	StateTTL             time.Duration
+}
+
+// App manages the set of app layer api functions for the single sign-on.
+type App struct {
+	log      *logger.Logger
+	userCore *usercore.Core
+	provider *oidc.Provider
+	keyring  *encrypt.Keyring
+	cfg      Config
+}
+
+// NewApp constructs a sso app API for use. The state of a login in progress
+// is encrypted with the keyring and kept by the browser.
+func NewApp(log *logger.Logger, userCore *usercore.Core, provider *oidc.Provider, keyring *encrypt.Keyring, cfg Config) *App {
+	if cfg.StateTTL == 0 {
+		cfg.StateTTL = defaultStateTTL
+	}
+
+	mapping := make(map[string]string, len(cfg.RoleMapping))
+	for group, r := range cfg.RoleMapping {
+		mapping[strings.ToLower(group)] = r
+	}
+	cfg.RoleMapping = mapping
+
+	return &App{
+		log:      log,
+		userCore: userCore,
+		provider: provider,
+		keyring:  keyring,
+		cfg:      cfg,
+	}
+}
+
+// StateTTL returns how long a login can take.
+func (a *App) StateTTL() time.Duration {
+	return a.cfg.StateTTL
+}
+
+// state is the login in progress, kept encrypted by the browser until the
+// identity provider redirects back.
+type state struct {
+	oidc.AuthRequest
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Start starts a login and returns the authorization url along with the
+// encrypted state to keep until the callback.
+func (a *App) Start(ctx context.Context) (Login, string, error) {
+	ar, err := oidc.NewAuthRequest()
+	if err != nil {
+		return Login{}, "", errs.Newf(errs.Internal, "start: %s", err)
+	}
+
+	data, err := json.Marshal(state{
+		AuthRequest: ar,
+		ExpiresAt:   time.Now().Add(a.cfg.StateTTL),
+	})
+	if err != nil {
+		return Login{}, "", errs.Newf(errs.Internal, "start: %s", err)
+	}
+
+	sealed, err := a.keyring.Encrypt(data, stateAAD)
+	if err != nil {
+		return Login{}, "", errs.Newf(errs.Internal, "start: %s", err)
+	}
+
+	login := Login{
+		AuthorizationURL: a.provider.AuthCodeURL(ar),
+		ExpiresIn:        int(a.cfg.StateTTL.Seconds()),
+	}
+
+	return login, sealed, nil
+}
+
+// Callback completes a login with the code the identity provider redirected
+// back with. The ID token is verified and the user is looked up by email, or
+// created. The user is returned to issue the tokens for.
+func (a *App) Callback(ctx context.Context, app Callback, sealed string) (user.User, error) {
+	if app.Error != "" {
+		return user.User{}, errs.Newf(errs.Unauthenticated, "identity provider: %s: %s", app.Error, app.ErrorDescription)
+	}
+
+	if app.Code == "" || app.State == "" {
+		return user.User{}, errs.New(errs.InvalidArgument, errors.New("missing code or state"))
+	}
+
+	st, err := a.open(sealed)
+	if err != nil {
+		return user.User{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	if !st.CheckState(app.State) {
+		return user.User{}, errs.New(errs.InvalidArgument, errors.New("state mismatch"))
+	}
+
+	raw, err := a.provider.Exchange(ctx, app.Code, st.Verifier)
+	if err != nil {
+		return user.User{}, errs.Newf(errs.Unauthenticated, "exchange: %s", err)
+	}
+
+	idt, err := a.provider.Verify(ctx, raw, st.Nonce)
+	if err != nil {
+		return user.User{}, errs.Newf(errs.Unauthenticated, "verify: %s", err)
+	}
+
+	if !idt.EmailVerified && !a.cfg.AllowUnverifiedEmail {
+		return user.User{}, errs.New(errs.PermissionDenied, errors.New("email not verified by the identity provider"))
+	}
+
+	addr, err := mail.ParseAddress(idt.Email)
+	if err != nil {
+		return user.User{}, errs.Newf(errs.PermissionDenied, "email: %s", err)
+	}
+
+	usr, err := a.userCore.QueryByEmail(ctx, *addr)
+	switch {
+	case err == nil:
+		if !usr.Enabled {
+			return user.User{}, errs.New(errs.Unauthenticated, errors.New("user disabled"))
+		}
+
+		if idt.EmailVerified && !usr.EmailVerified {
+			if usr, err = a.userCore.VerifyEmail(ctx, usr); err != nil {
+				return user.User{}, errs.Newf(errs.Internal, "verify email: userID[%s]: %s", usr.ID, err)
+			}
+		}
+
+		return usr, nil
+
+	case !errors.Is(err, user.ErrNotFound):
+		return user.User{}, errs.Newf(errs.Internal, "callback: email[%s]: %s", addr.Address, err)
+	}
+
+	if !a.cfg.Provision {
+		return user.User{}, errs.New(errs.PermissionDenied, errors.New("no account for the email"))
+	}
+
+	return a.provision(ctx, *addr, idt)
+}
+
+// provision creates the user logging in for the first time, with the roles
+// mapped from the groups. The password is random, the user logs in with the
+// identity provider or resets it.
+func (a *App) provision(ctx context.Context, addr mail.Address, idt oidc.IDToken) (user.User, error) {
+	roles, err := a.roles(idt)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	nme, err := name.Parse(toName(idt.Name, addr))
+	if err != nil {
+		return user.User{}, errs.Newf(errs.InvalidArgument, "name: %s", err)
+	}
+
+	nu := user.NewUser{
+		Name:          nme,
+		Email:         addr,
+		Roles:         roles,
+		Password:      rand.Text(),
+		EmailVerified: idt.EmailVerified,
+		OrgID:         a.cfg.OrgID,
+	}
+
+	usr, err := a.userCore.Create(ctx, nu)
+	if err != nil {
+		if errors.Is(err, user.ErrUniqueEmail) {
+			return user.User{}, errs.New(errs.Aborted, user.ErrUniqueEmail)
+		}
+		return user.User{}, errs.Newf(errs.Internal, "provision: email[%s]: %s", addr.Address, err)
+	}
+
+	return usr, nil
+}
+
+// roles maps the groups of the ID token to roles.
+func (a *App) roles(idt oidc.IDToken) ([]role.Role, error) {
+	var names []string
+	if a.cfg.RolesClaim != "" {
+		for _, group := range idt.Strings(a.cfg.RolesClaim) {
+			if r, ok := a.cfg.RoleMapping[strings.ToLower(group)]; ok && !contains(names, r) {
+				names = append(names, r)
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		names = a.cfg.DefaultRoles
+	}
+
+	if len(names) == 0 {
+		return nil, errs.New(errs.PermissionDenied, errors.New("no role mapped for the user"))
+	}
+
+	roles, err := role.ParseMany(names)
+	if err != nil {
+		return nil, errs.Newf(errs.Internal, "role mapping: %s", err)
+	}
+
+	return roles, nil
+}
+
+// open decrypts the state of the login in progress.
+func (a *App) open(sealed string) (state, error) {
+	if sealed == "" {
+		return state{}, errors.New("no login in progress")
+	}
+
+	data, err := a.keyring.Decrypt(sealed, stateAAD)
+	if err != nil {
+		return state{}, fmt.Errorf("state: %w", err)
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return state{}, fmt.Errorf("state: %w", err)
+	}
+
+	if time.Now().After(st.ExpiresAt) {
+		return state{}, errors.New("login expired")
+	}
+
+	return st, nil
+}
+
+// invalidName matches the characters a name can't hold.
+var invalidName = regexp.MustCompile(`[^a-zA-Z0-9' -]+`)
+
+// toName derives a valid name from the name claim, or the email when the
+// claim is missing.
+func toName(claim string, addr mail.Address) string {
+	n := claim
+	if n == "" {
+		n, _, _ = strings.Cut(addr.Address, "@")
+	}
+
+	n = strings.TrimSpace(invalidName.ReplaceAllString(n, " "))
+	if len(n) > 20 {
+		n = strings.TrimSpace(n[:20])
+	}
+
+	for len(n) < 3 {
+		n += "-"
+	}
+
+	return n
+}
+
+func contains(values []string, v string) bool {
+	for _, e := range values {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package stock_usecase
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+)
+
+type AppQueryParams struct {
+	Page          string
+	Rows          string
+	OrderBy       string
+	ReservationID string
+	Kind          string
+}
+
+func parseFilter(productID uuid.UUID, qp AppQueryParams) (stock.QueryFilter, error) {
+	var fieldErrors validation.FieldErrors
+	filter := stock.QueryFilter{
+		ProductID: &productID,
+	}
+
+	if qp.ReservationID != "" {
+		id, err := uuid.Parse(qp.ReservationID)
+		switch err {
+		case nil:
+			filter.ReservationID = &id
+		default:
+			fieldErrors.Add("reservation_id", err)
+		}
+	}
+
+	if qp.Kind != "" {
+		kind, err := stock.ParseKind(qp.Kind)
+		switch err {
+		case nil:
+			filter.Kind = &kind
+		default:
+			fieldErrors.Add("kind", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return stock.QueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,153 @@
+package stock_usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+)
+
+// Movement represents a change of the stock of a product.
+type Movement struct {
+	ID            string `json:"id"`
+	ProductID     string `json:"productID"`
+	ReservationID string `json:"reservationID,omitempty"`
+	Kind          string `json:"kind"`
+	Delta         int    `json:"delta"`
+	Quantity      int    `json:"quantity"`
+	DateCreated   string `json:"dateCreated"`
+}
+
+// Encode implements the encoder interface.
+func (app Movement) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppMovement(mov stock.Movement) Movement {
+	app := Movement{
+		ID:          mov.ID.String(),
+		ProductID:   mov.ProductID.String(),
+		Kind:        mov.Kind.String(),
+		Delta:       mov.Delta,
+		Quantity:    mov.Quantity,
+		DateCreated: mov.DateCreated.Format(time.RFC3339),
+	}
+
+	if mov.ReservationID != uuid.Nil {
+		app.ReservationID = mov.ReservationID.String()
+	}
+
+	return app
+}
+
+func toAppMovements(movs []stock.Movement) []Movement {
+	app := make([]Movement, len(movs))
+	for i, mov := range movs {
+		app[i] = toAppMovement(mov)
+	}
+
+	return app
+}
+
+// =============================================================================
+
+// NewMovement defines the data needed to receive, adjust or sell stock. The
+// quantity of an adjustment is negative to remove stock.
+type NewMovement struct {
+	Kind     string `json:"kind" validate:"required,oneof=RECEIPT ADJUSTMENT SALE"`
+	Quantity int    `json:"quantity" validate:"required"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewMovement) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewMovement) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	return nil
+}
+
+func toBusNewMovement(productID uuid.UUID, app NewMovement) (stock.NewMovement, error) {
+	kind, err := stock.ParseKind(app.Kind)
+	if err != nil {
+		return stock.NewMovement{}, fmt.Errorf("parse kind: %w", err)
+	}
+
+	nm := stock.NewMovement{
+		ProductID: productID,
+		Kind:      kind,
+		Quantity:  app.Quantity,
+	}
+
+	return nm, nil
+}
+
+// =============================================================================
+
+// Reservation represents stock set aside until it expires.
+type Reservation struct {
+	ID          string `json:"id"`
+	ProductID   string `json:"productID"`
+	Quantity    int    `json:"quantity"`
+	Status      string `json:"status"`
+	ExpiresAt   string `json:"expiresAt"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+// Encode implements the encoder interface.
+func (app Reservation) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppReservation(res stock.Reservation) Reservation {
+	return Reservation{
+		ID:          res.ID.String(),
+		ProductID:   res.ProductID.String(),
+		Quantity:    res.Quantity,
+		Status:      res.Status.String(),
+		ExpiresAt:   res.ExpiresAt.Format(time.RFC3339),
+		DateCreated: res.DateCreated.Format(time.RFC3339),
+		DateUpdated: res.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// NewReservation defines the data needed to reserve stock. The ttl is in
+// seconds, the reservation expires after the default one when it is zero.
+type NewReservation struct {
+	Quantity int `json:"quantity" validate:"required,gte=1"`
+	TTL      int `json:"ttl" validate:"gte=0"`
+}
+
+// Decode implements the decoder interface.
+func (app *NewReservation) Decode(data []byte) error {
+	return json.Unmarshal(data, app)
+}
+
+// Validate checks the data in the model is considered clean.
+func (app NewReservation) Validate() error {
+	if err := validation.Check(app); err != nil {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	return nil
+}
+
+func toBusNewReservation(productID uuid.UUID, app NewReservation) stock.NewReservation {
+	return stock.NewReservation{
+		ProductID: productID,
+		Quantity:  app.Quantity,
+		TTL:       time.Duration(app.TTL) * time.Second,
+	}
+}
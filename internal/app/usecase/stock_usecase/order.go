@@ -0,0 +1,9 @@
+package stock_usecase
+
+import "github.com/Housiadas/backend-system/internal/core/domain/stock"
+
+var orderByFields = map[string]string{
+	"date_created": stock.OrderByDateCreated,
+	"kind":         stock.OrderByKind,
+	"delta":        stock.OrderByDelta,
+}
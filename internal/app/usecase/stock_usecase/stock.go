@@ -0,0 +1,174 @@
+// Package stock_usecase maintains the app layer api for the stock of the
+// products.
+package stock_usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
+	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/internal/core/service/stockcore"
+	"github.com/Housiadas/backend-system/pkg/errs"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+// App manages the set of app layer api functions for the stock core. The
+// product is the one of the request, set by the product middleware.
+type App struct {
+	stockBus *stockcore.Core
+}
+
+// NewApp constructs a stock app API for use.
+func NewApp(stockBus *stockcore.Core) *App {
+	return &App{
+		stockBus: stockBus,
+	}
+}
+
+// Move receives, adjusts or sells stock of the product.
+func (a *App) Move(ctx context.Context, app NewMovement) (Movement, error) {
+	prd, err := ctxPck.GetProduct(ctx)
+	if err != nil {
+		return Movement{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	nm, err := toBusNewMovement(prd.ID, app)
+	if err != nil {
+		return Movement{}, errs.New(errs.InvalidArgument, err)
+	}
+
+	mov, err := a.stockBus.Move(ctx, nm)
+	if err != nil {
+		return Movement{}, toError(err, "move: productID[%s]: %s", prd.ID)
+	}
+
+	return toAppMovement(mov), nil
+}
+
+// Reserve sets stock of the product aside until the reservation expires.
+func (a *App) Reserve(ctx context.Context, app NewReservation) (Reservation, error) {
+	prd, err := ctxPck.GetProduct(ctx)
+	if err != nil {
+		return Reservation{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	res, err := a.stockBus.Reserve(ctx, toBusNewReservation(prd.ID, app))
+	if err != nil {
+		return Reservation{}, toError(err, "reserve: productID[%s]: %s", prd.ID)
+	}
+
+	return toAppReservation(res), nil
+}
+
+// Commit keeps the stock of a reservation of the product as sold.
+func (a *App) Commit(ctx context.Context, reservationID string) (Reservation, error) {
+	res, err := a.queryReservation(ctx, reservationID)
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	res, err = a.stockBus.Commit(ctx, res)
+	if err != nil {
+		return Reservation{}, toError(err, "commit: reservationID[%s]: %s", reservationID)
+	}
+
+	return toAppReservation(res), nil
+}
+
+// Release gives the stock of a reservation back to the product.
+func (a *App) Release(ctx context.Context, reservationID string) (Reservation, error) {
+	res, err := a.queryReservation(ctx, reservationID)
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	res, err = a.stockBus.Release(ctx, res)
+	if err != nil {
+		return Reservation{}, toError(err, "release: reservationID[%s]: %s", reservationID)
+	}
+
+	return toAppReservation(res), nil
+}
+
+// Query returns the history of the movements of the product.
+func (a *App) Query(ctx context.Context, qp AppQueryParams) (page.Result[Movement], error) {
+	prd, err := ctxPck.GetProduct(ctx)
+	if err != nil {
+		return page.Result[Movement]{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	p, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return page.Result[Movement]{}, validation.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(prd.ID, qp)
+	if err != nil {
+		return page.Result[Movement]{}, err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, stock.DefaultOrderBy)
+	if err != nil {
+		return page.Result[Movement]{}, validation.NewFieldErrors("order", err)
+	}
+
+	movs, err := a.stockBus.Query(ctx, filter, orderBy, p)
+	if err != nil {
+		return page.Result[Movement]{}, errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := a.stockBus.Count(ctx, filter)
+	if err != nil {
+		return page.Result[Movement]{}, errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return page.NewResult(toAppMovements(movs), total, p), nil
+}
+
+// queryReservation finds the reservation, it must be one of the product of
+// the request.
+func (a *App) queryReservation(ctx context.Context, reservationID string) (stock.Reservation, error) {
+	prd, err := ctxPck.GetProduct(ctx)
+	if err != nil {
+		return stock.Reservation{}, errs.Newf(errs.Internal, "product missing in context: %s", err)
+	}
+
+	id, err := uuid.Parse(reservationID)
+	if err != nil {
+		return stock.Reservation{}, errs.Newf(errs.InvalidArgument, "parse: %s", err)
+	}
+
+	res, err := a.stockBus.QueryReservationByID(ctx, id)
+	if err != nil {
+		return stock.Reservation{}, toError(err, "querybyid: reservationID[%s]: %s", reservationID)
+	}
+
+	if res.ProductID != prd.ID {
+		return stock.Reservation{}, errs.New(errs.NotFound, stock.ErrReservationNotFound)
+	}
+
+	return res, nil
+}
+
+// toError maps the errors of the stock core to the codes of the api.
+func toError(err error, format string, id any) error {
+	switch {
+	case errors.Is(err, stock.ErrInvalidMovement):
+		return errs.New(errs.InvalidArgument, stock.ErrInvalidMovement)
+	case errors.Is(err, stock.ErrReservationNotFound):
+		return errs.New(errs.NotFound, stock.ErrReservationNotFound)
+	case errors.Is(err, stock.ErrInsufficientStock):
+		return errs.New(errs.FailedPrecondition, stock.ErrInsufficientStock)
+	case errors.Is(err, stock.ErrReservationExpired):
+		return errs.New(errs.FailedPrecondition, stock.ErrReservationExpired)
+	case errors.Is(err, stock.ErrReservationClosed):
+		return errs.New(errs.FailedPrecondition, stock.ErrReservationClosed)
+	}
+
+	return errs.Newf(errs.Internal, format, id, err)
+}
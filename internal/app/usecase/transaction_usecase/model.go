@@ -9,6 +9,7 @@ import (
 	"github.com/Housiadas/backend-system/internal/common/validation"
 	"github.com/Housiadas/backend-system/internal/core/domain/money"
 	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/password"
 	"github.com/Housiadas/backend-system/internal/core/domain/product"
 	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
 	"github.com/Housiadas/backend-system/internal/core/domain/role"
@@ -18,13 +19,14 @@ import (
 
 // Product represents an individual product.
 type Product struct {
-	ID          string  `json:"id"`
-	UserID      string  `json:"userID"`
-	Name        string  `json:"name"`
-	Cost        float64 `json:"cost"`
-	Quantity    int     `json:"quantity"`
-	DateCreated string  `json:"dateCreated"`
-	DateUpdated string  `json:"dateUpdated"`
+	ID          string      `json:"id"`
+	UserID      string      `json:"userID"`
+	Name        string      `json:"name"`
+	Cost        json.Number `json:"cost"`
+	Currency    string      `json:"currency"`
+	Quantity    int         `json:"quantity"`
+	DateCreated string      `json:"dateCreated"`
+	DateUpdated string      `json:"dateUpdated"`
 }
 
 // Encode implements the encoder interface.
@@ -38,7 +40,8 @@ func toAppProduct(prd product.Product) Product {
 		ID:          prd.ID.String(),
 		UserID:      prd.UserID.String(),
 		Name:        prd.Name.String(),
-		Cost:        prd.Cost.Value(),
+		Cost:        json.Number(prd.Cost.Decimal()),
+		Currency:    prd.Cost.Currency().String(),
 		Quantity:    prd.Quantity.Value(),
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
@@ -89,7 +92,11 @@ func (app NewUser) Validate() error {
 	return nil
 }
 
-func toBusNewUser(app NewUser) (user.NewUser, error) {
+func toBusNewUser(app NewUser, policy password.Policy) (user.NewUser, error) {
+	if err := policy.Check(app.Password); err != nil {
+		return user.NewUser{}, fmt.Errorf("password: %w", err)
+	}
+
 	roles, err := role.ParseMany(app.Roles)
 	if err != nil {
 		return user.NewUser{}, fmt.Errorf("parse: %w", err)
@@ -125,9 +132,10 @@ func toBusNewUser(app NewUser) (user.NewUser, error) {
 
 // NewProduct is what we require from clients when adding a Product.
 type NewProduct struct {
-	Name     string  `json:"name" validate:"required"`
-	Cost     float64 `json:"cost" validate:"required,gte=0"`
-	Quantity int     `json:"quantity" validate:"required,gte=1"`
+	Name     string      `json:"name" validate:"required"`
+	Cost     json.Number `json:"cost" validate:"required"`
+	Currency string      `json:"currency"`
+	Quantity int         `json:"quantity" validate:"required,gte=1"`
 }
 
 // Validate checks the data in the model is considered clean.
@@ -145,9 +153,21 @@ func toBusNewProduct(app NewProduct) (product.NewProduct, error) {
 		return product.NewProduct{}, fmt.Errorf("parse: %w", err)
 	}
 
-	cost, err := money.Parse(app.Cost)
+	currency := money.DefaultCurrency
+	if app.Currency != "" {
+		currency, err = money.ParseCurrency(app.Currency)
+		if err != nil {
+			return product.NewProduct{}, fmt.Errorf("parse currency: %w", err)
+		}
+	}
+
+	cost, err := money.Parse(app.Cost.String(), currency)
 	if err != nil {
-		return product.NewProduct{}, fmt.Errorf("parse cost: %w", err)
+		return product.NewProduct{}, validation.NewFieldErrors("cost", err)
+	}
+
+	if err := product.CheckCost(cost); err != nil {
+		return product.NewProduct{}, validation.NewFieldErrors("cost", err)
 	}
 
 	q, err := quantity.Parse(app.Quantity)
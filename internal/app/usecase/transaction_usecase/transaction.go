@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/password"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/internal/core/service/productcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
@@ -16,13 +17,16 @@ import (
 type App struct {
 	userBus    *usercore.Core
 	productBus *productcore.Core
+	policy     password.Policy
 }
 
-// NewApp constructs a tran cli API for use.
-func NewApp(userBus *usercore.Core, productBus *productcore.Core) *App {
+// NewApp constructs a tran cli API for use. The passwords of the new users
+// must satisfy the policy.
+func NewApp(userBus *usercore.Core, productBus *productcore.Core, policy password.Policy) *App {
 	return &App{
 		userBus:    userBus,
 		productBus: productBus,
+		policy:     policy,
 	}
 }
 
@@ -47,6 +51,7 @@ func (a *App) newWithTx(ctx context.Context) (*App, error) {
 	app := App{
 		userBus:    userBus,
 		productBus: productBus,
+		policy:     a.policy,
 	}
 
 	return &app, nil
@@ -64,7 +69,7 @@ func (a *App) Create(ctx context.Context, nt NewTran) (Product, error) {
 		return Product{}, errs.New(errs.InvalidArgument, err)
 	}
 
-	nu, err := toBusNewUser(nt.User)
+	nu, err := toBusNewUser(nt.User, a.policy)
 	if err != nil {
 		return Product{}, errs.New(errs.InvalidArgument, err)
 	}
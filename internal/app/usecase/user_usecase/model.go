@@ -8,6 +8,7 @@ import (
 
 	"github.com/Housiadas/backend-system/internal/common/validation"
 	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/password"
 	"github.com/Housiadas/backend-system/internal/core/domain/role"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/pkg/errs"
@@ -41,15 +42,16 @@ func (app *AuthenticateUser) Validate() error {
 
 // User represents information about an individual user.
 type User struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	Email        string   `json:"email"`
-	Roles        []string `json:"roles"`
-	PasswordHash []byte   `json:"-"`
-	Department   string   `json:"department"`
-	Enabled      bool     `json:"enabled"`
-	DateCreated  string   `json:"dateCreated"`
-	DateUpdated  string   `json:"dateUpdated"`
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Email         string   `json:"email"`
+	Roles         []string `json:"roles"`
+	PasswordHash  []byte   `json:"-"`
+	Department    string   `json:"department"`
+	Enabled       bool     `json:"enabled"`
+	EmailVerified bool     `json:"emailVerified"`
+	DateCreated   string   `json:"dateCreated"`
+	DateUpdated   string   `json:"dateUpdated"`
 }
 
 // Encode implements the encoder interface.
@@ -65,15 +67,16 @@ func toAppUser(bus user.User) User {
 	}
 
 	return User{
-		ID:           bus.ID.String(),
-		Name:         bus.Name.String(),
-		Email:        bus.Email.Address,
-		Roles:        roles,
-		PasswordHash: bus.PasswordHash,
-		Department:   bus.Department.String(),
-		Enabled:      bus.Enabled,
-		DateCreated:  bus.DateCreated.Format(time.RFC3339),
-		DateUpdated:  bus.DateUpdated.Format(time.RFC3339),
+		ID:            bus.ID.String(),
+		Name:          bus.Name.String(),
+		Email:         bus.Email.Address,
+		Roles:         roles,
+		PasswordHash:  bus.PasswordHash,
+		Department:    bus.Department.String(),
+		Enabled:       bus.Enabled,
+		EmailVerified: bus.EmailVerified,
+		DateCreated:   bus.DateCreated.Format(time.RFC3339),
+		DateUpdated:   bus.DateUpdated.Format(time.RFC3339),
 	}
 }
 
@@ -120,7 +123,11 @@ func (app *NewUser) Validate() error {
 	return nil
 }
 
-func toBusNewUser(app NewUser) (user.NewUser, error) {
+func toBusNewUser(app NewUser, policy password.Policy) (user.NewUser, error) {
+	if err := policy.Check(app.Password); err != nil {
+		return user.NewUser{}, fmt.Errorf("password: %w", err)
+	}
+
 	roles, err := role.ParseMany(app.Roles)
 	if err != nil {
 		return user.NewUser{}, fmt.Errorf("parse: %w", err)
@@ -219,7 +226,13 @@ func (app *UpdateUser) Validate() error {
 	return nil
 }
 
-func toBusUpdateUser(app UpdateUser) (user.UpdateUser, error) {
+func toBusUpdateUser(app UpdateUser, policy password.Policy) (user.UpdateUser, error) {
+	if app.Password != nil {
+		if err := policy.Check(*app.Password); err != nil {
+			return user.UpdateUser{}, fmt.Errorf("password: %w", err)
+		}
+	}
+
 	var addr *mail.Address
 	if app.Email != nil {
 		var err error
@@ -9,8 +9,6 @@ var defaultOrderBy = order.NewBy("user_id", order.ASC)
 
 var orderByFields = map[string]string{
 	"user_id": user.OrderByID,
-	"name":    user.OrderByName,
-	"email":   user.OrderByEmail,
 	"roles":   user.OrderByRoles,
 	"enabled": user.OrderByEnabled,
 }
@@ -8,8 +8,14 @@ import (
 
 	ctxPck "github.com/Housiadas/backend-system/internal/common/context"
 	"github.com/Housiadas/backend-system/internal/common/validation"
+	"github.com/Housiadas/backend-system/internal/core/domain/lockout"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/password"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/internal/core/service/lockoutcore"
+	"github.com/Housiadas/backend-system/internal/core/service/securityeventcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
 	"github.com/Housiadas/backend-system/pkg/errs"
 	"github.com/Housiadas/backend-system/pkg/order"
@@ -18,8 +24,11 @@ import (
 
 // App manages the set of cli layer api functions for the user core.
 type App struct {
-	authCore *authcore.Auth
-	userCore *usercore.Core
+	authCore     *authcore.Auth
+	userCore     *usercore.Core
+	lockoutCore  *lockoutcore.Core
+	securityCore *securityeventcore.Core
+	policy       password.Policy
 }
 
 // NewApp constructs a user cli API for use.
@@ -29,23 +38,37 @@ func NewApp(userBus *usercore.Core) *App {
 	}
 }
 
-// NewAppWithAuth constructs a user cli API for use.
-func NewAppWithAuth(userBus *usercore.Core, authbus *authcore.Auth) *App {
+// NewAppWithAuth constructs a user cli API for use. The lockout and security
+// event cores are optional, without them failed logins are not limited nor
+// recorded. The new passwords must satisfy the policy.
+func NewAppWithAuth(
+	userBus *usercore.Core,
+	authbus *authcore.Auth,
+	lockoutBus *lockoutcore.Core,
+	securityBus *securityeventcore.Core,
+	policy password.Policy,
+) *App {
 	return &App{
-		authCore: authbus,
-		userCore: userBus,
+		authCore:     authbus,
+		userCore:     userBus,
+		lockoutCore:  lockoutBus,
+		securityCore: securityBus,
+		policy:       policy,
 	}
 }
 
 // Create adds a new user to the system.
 func (a *App) Create(ctx context.Context, app NewUser) (User, error) {
-	nc, err := toBusNewUser(app)
+	nc, err := toBusNewUser(app, a.policy)
 	if err != nil {
 		return User{}, errs.New(errs.InvalidArgument, err)
 	}
 
 	usr, err := a.userCore.Create(ctx, nc)
 	if err != nil {
+		if errors.Is(err, organization.ErrSuperAdminRole) {
+			return User{}, errs.New(errs.PermissionDenied, organization.ErrSuperAdminRole)
+		}
 		if errors.Is(err, user.ErrUniqueEmail) {
 			return User{}, errs.New(errs.Aborted, user.ErrUniqueEmail)
 		}
@@ -57,7 +80,7 @@ func (a *App) Create(ctx context.Context, app NewUser) (User, error) {
 
 // Update updates an existing user.
 func (a *App) Update(ctx context.Context, app UpdateUser) (User, error) {
-	uu, err := toBusUpdateUser(app)
+	uu, err := toBusUpdateUser(app, a.policy)
 	if err != nil {
 		return User{}, errs.New(errs.InvalidArgument, err)
 	}
@@ -69,6 +92,9 @@ func (a *App) Update(ctx context.Context, app UpdateUser) (User, error) {
 
 	updUsr, err := a.userCore.Update(ctx, usr, uu)
 	if err != nil {
+		if errors.Is(err, organization.ErrSuperAdminRole) {
+			return User{}, errs.New(errs.PermissionDenied, organization.ErrSuperAdminRole)
+		}
 		return User{}, errs.Newf(errs.Internal, "update: userID[%s] uu[%+v]: %s", usr.ID, uu, err)
 	}
 
@@ -89,6 +115,9 @@ func (a *App) UpdateRole(ctx context.Context, app UpdateUserRole) (User, error)
 
 	updUsr, err := a.userCore.Update(ctx, usr, uu)
 	if err != nil {
+		if errors.Is(err, organization.ErrSuperAdminRole) {
+			return User{}, errs.New(errs.PermissionDenied, organization.ErrSuperAdminRole)
+		}
 		return User{}, errs.Newf(errs.Internal, "updaterole: userID[%s] uu[%+v]: %s", usr.ID, uu, err)
 	}
 
@@ -149,21 +178,127 @@ func (a *App) QueryByID(ctx context.Context) (User, error) {
 	return toAppUser(usr), nil
 }
 
-// Authenticate provides an API to authenticate the user.
-func (a *App) Authenticate(ctx context.Context, authUser AuthenticateUser) (User, error) {
+// Authenticate provides an API to authenticate the user. Failed attempts are
+// counted against the email and the ip address of the caller, once too many
+// fail the login is refused before the password is checked.
+func (a *App) Authenticate(ctx context.Context, authUser AuthenticateUser, ip string) (User, error) {
 	addr, err := mail.ParseAddress(authUser.Email)
 	if err != nil {
 		return User{}, validation.NewFieldErrors("email", err)
 	}
 
-	usr, err := a.userCore.Authenticate(ctx, *addr, authUser.Password)
+	usr, err := a.authenticate(ctx, *addr, authUser.Password, ip)
 	if err != nil {
+		// The email may not belong to any user, the hash of its lockout key
+		// is recorded rather than the email.
+		a.record(ctx, securityevent.NewEvent{
+			Kind:    securityevent.LoginFailed,
+			ActorID: usr.ID,
+			Subject: lockout.HashKey(lockout.EmailKey(*addr)),
+			Outcome: securityevent.OutcomeFailure,
+			Reason:  err.Error(),
+			OrgID:   usr.OrgID,
+		})
 		return User{}, err
 	}
 
+	a.record(ctx, securityevent.NewEvent{
+		Kind:    securityevent.Login,
+		ActorID: usr.ID,
+		Subject: usr.ID.String(),
+		Outcome: securityevent.OutcomeSuccess,
+		OrgID:   usr.OrgID,
+	})
+
 	return toAppUser(usr), nil
 }
 
+// authenticate checks the credentials, counting the failures against the
+// email and the ip address when the lockout is configured. The user is
+// returned along with the error of the email verification rule.
+func (a *App) authenticate(ctx context.Context, addr mail.Address, pass string, ip string) (user.User, error) {
+	if a.lockoutCore == nil {
+		usr, err := a.userCore.Authenticate(ctx, addr, pass)
+		if err != nil {
+			return user.User{}, err
+		}
+		return usr, a.verified(ctx, usr)
+	}
+
+	keys := []string{lockout.EmailKey(addr)}
+	if ip != "" {
+		keys = append(keys, lockout.IPKey(ip))
+	}
+
+	if err := a.lockoutCore.Check(ctx, keys...); err != nil {
+		if errors.Is(err, lockout.ErrLocked) {
+			return user.User{}, errs.New(errs.TooManyRequests, lockout.ErrLocked)
+		}
+		return user.User{}, errs.Newf(errs.Internal, "check: %s", err)
+	}
+
+	usr, err := a.userCore.Authenticate(ctx, addr, pass)
+	if err != nil {
+		if errors.Is(err, user.ErrAuthenticationFailure) {
+			if err := a.lockoutCore.Fail(ctx, keys...); err != nil {
+				return user.User{}, errs.Newf(errs.Internal, "fail: %s", err)
+			}
+		}
+		return user.User{}, err
+	}
+
+	if err := a.lockoutCore.Succeed(ctx, keys[0]); err != nil {
+		return user.User{}, errs.Newf(errs.Internal, "succeed: %s", err)
+	}
+
+	return usr, a.verified(ctx, usr)
+}
+
+// verified refuses the login of a user the email verification rule refuses,
+// the tokens would be rejected anyway.
+func (a *App) verified(ctx context.Context, usr user.User) error {
+	if a.authCore != nil {
+		if err := a.authCore.EmailVerified(ctx, usr); err != nil {
+			return errs.New(errs.FailedPrecondition, err)
+		}
+	}
+
+	return nil
+}
+
+// record records the security event when the security event core is set.
+func (a *App) record(ctx context.Context, ne securityevent.NewEvent) {
+	if a.securityCore != nil {
+		a.securityCore.Record(ctx, ne)
+	}
+}
+
+// Unlock lifts the login lockout of the user in the context.
+func (a *App) Unlock(ctx context.Context) error {
+	if a.lockoutCore == nil {
+		return errs.Newf(errs.Internal, "lockout not configured")
+	}
+
+	usr, err := ctxPck.GetUser(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "unlock: %s", err)
+	}
+
+	actorID, err := ctxPck.GetUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	if err := a.lockoutCore.Unlock(ctx, lockout.EmailKey(usr.Email), usr.ID, actorID); err != nil {
+		if errors.Is(err, lockout.ErrNotFound) {
+			return errs.Newf(errs.FailedPrecondition, "user[%s] is not locked", usr.ID)
+		}
+		return errs.Newf(errs.Internal, "unlock: userID[%s]: %s", usr.ID, err)
+	}
+
+	return nil
+}
+
 // Token provides an API token for the authenticated user.
 func (a *App) Token(ctx context.Context) (Token, error) {
 	if a.authCore == nil {
@@ -52,6 +52,9 @@ func (at *Test) Run(t *testing.T, table []Table, testName string) {
 			}
 
 			r.Header.Set("authorization", "Bearer "+tt.Token)
+			for key, value := range tt.Header {
+				r.Header.Set(key, value)
+			}
 			at.Mux.ServeHTTP(w, r)
 
 			if w.Code != tt.StatusCode {
@@ -101,6 +104,8 @@ func Token(userCore *usercore.Core, ath *authcore.Auth, email string) string {
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
 		Roles: role.ParseToString(dbUsr.Roles),
+		Org:   dbUsr.OrgID.String(),
+		Dept:  authcore.Department(dbUsr.Department),
 	}
 
 	token, err := ath.GenerateToken(claims)
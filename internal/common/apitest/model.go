@@ -20,11 +20,13 @@ type SeedData struct {
 	Admins []User
 }
 
-// Table represents fields needed for running an api test.
+// Table represents fields needed for running an api test. The headers are set
+// after the token, like an api key in place of it.
 type Table struct {
 	Name       string
 	URL        string
 	Token      string
+	Header     map[string]string
 	Method     string
 	StatusCode int
 	Input      any
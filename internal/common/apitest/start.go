@@ -11,20 +11,28 @@ import (
 	cfg "github.com/Housiadas/backend-system/internal/config"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/notifier"
 	"github.com/Housiadas/backend-system/pkg/otel"
 )
 
-// StartTest initialized the system to run a test.
-func StartTest(t *testing.T, testName string) (*Test, error) {
+// StartTest initialized the system to run a test. The options change the
+// configuration of the handlers, like to enable the single sign-on.
+func StartTest(t *testing.T, testName string, options ...func(*handlers.Config)) (*Test, error) {
 	db := dbtest.New(t, testName)
 
 	// auth
-	auth := authcore.New(authcore.Config{
-		Log:       db.Log,
-		DB:        db.DB,
-		KeyLookup: &KeyStore{},
-		Userbus:   usercore.NewCore(db.Log, user_repo.NewStore(db.Log, db.DB)),
+	auth, err := authcore.New(authcore.Config{
+		Log:        db.Log,
+		DB:         db.DB,
+		KeyLookup:  &KeyStore{},
+		Userbus:    usercore.NewCore(db.Log, user_repo.NewStore(db.Log, db.DB, dbtest.UserKeys())),
+		Sessionbus: db.Core.Session,
+		Rbacbus:    db.Core.Rbac,
+		APIKeybus:  db.Core.APIKey,
 	})
+	if err != nil {
+		return nil, fmt.Errorf("constructing auth: %w", err)
+	}
 
 	// tracer
 	traceProvider, teardown, err := otel.InitTracing(otel.Config{
@@ -46,18 +54,39 @@ func StartTest(t *testing.T, testName string) (*Test, error) {
 	tracer := traceProvider.Tracer("Core Name")
 
 	// Initialize handlers
-	h := handlers.New(handlers.Config{
-		ServiceName: "Test Service Name",
-		Build:       "Test",
-		Cors:        cfg.CorsSettings{},
-		DB:          db.DB,
-		Log:         db.Log,
-		Tracer:      tracer,
-		AuditCore:   db.Core.Audit,
-		AuthCore:    auth,
-		UserCore:    db.Core.User,
-		ProductCore: db.Core.Product,
-	})
+	hc := handlers.Config{
+		ServiceName:  "Test Service Name",
+		Build:        "Test",
+		Cors:         cfg.CorsSettings{},
+		DB:           db.DB,
+		Log:          db.Log,
+		Tracer:       tracer,
+		AuditCore:    db.Core.Audit,
+		AuthCore:     auth,
+		UserCore:     db.Core.User,
+		ProductCore:  db.Core.Product,
+		SessionCore:  db.Core.Session,
+		RbacCore:     db.Core.Rbac,
+		APIKeyCore:   db.Core.APIKey,
+		LockoutCore:  db.Core.Lockout,
+		MFACore:      db.Core.MFA,
+		ResetCore:    db.Core.Reset,
+		InviteCore:   db.Core.Invite,
+		OrgCore:      db.Core.Org,
+		SecurityCore: db.Core.Security,
+		StockCore:    db.Core.Stock,
+		OrderCore:    db.Core.Order,
+		AccountCore:  db.Core.Account,
+		PriceCore:    db.Core.Price,
+		Notifier:     notifier.NewLog(db.Log),
+		DeptIndex:    dbtest.UserKeys().DepartmentIndex,
+	}
+
+	for _, option := range options {
+		option(&hc)
+	}
+
+	h := handlers.New(hc)
 
 	return New(db, auth, h.Routes()), nil
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
 	"github.com/Housiadas/backend-system/internal/core/domain/product"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/internal/core/service/authcore"
@@ -20,6 +22,8 @@ const (
 	userIDKey  ctxKey = "userIDKey"
 	userKey    ctxKey = "userKey"
 	productKey ctxKey = "productKey"
+	orderKey   ctxKey = "orderKey"
+	accountKey ctxKey = "accountKey"
 )
 
 func SetRequestID(ctx context.Context, reqId string) context.Context {
@@ -100,3 +104,31 @@ func GetProduct(ctx context.Context) (product.Product, error) {
 
 	return v, nil
 }
+
+func SetOrder(ctx context.Context, ord order.Order) context.Context {
+	return context.WithValue(ctx, orderKey, ord)
+}
+
+// GetOrder returns the order from the context.
+func GetOrder(ctx context.Context) (order.Order, error) {
+	v, ok := ctx.Value(orderKey).(order.Order)
+	if !ok {
+		return order.Order{}, errors.New("order not found in context")
+	}
+
+	return v, nil
+}
+
+func SetAccount(ctx context.Context, acct account.Account) context.Context {
+	return context.WithValue(ctx, accountKey, acct)
+}
+
+// GetAccount returns the account from the context.
+func GetAccount(ctx context.Context) (account.Account, error) {
+	v, ok := ctx.Value(accountKey).(account.Account)
+	if !ok {
+		return account.Account{}, errors.New("account not found in context")
+	}
+
+	return v, nil
+}
@@ -3,30 +3,142 @@ package dbtest
 import (
 	"github.com/jmoiron/sqlx"
 
+	"github.com/Housiadas/backend-system/internal/app/repository/account_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/apikey_repo"
 	"github.com/Housiadas/backend-system/internal/app/repository/audit_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/invite_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/lockout_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/mfa_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/order_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/organization_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/passwordreset_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/price_repo"
 	"github.com/Housiadas/backend-system/internal/app/repository/product_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/rbac_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/securityevent_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/session_repo"
+	"github.com/Housiadas/backend-system/internal/app/repository/stock_repo"
 	"github.com/Housiadas/backend-system/internal/app/repository/user_repo"
+	"github.com/Housiadas/backend-system/internal/core/service/accountcore"
+	"github.com/Housiadas/backend-system/internal/core/service/apikeycore"
 	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
+	"github.com/Housiadas/backend-system/internal/core/service/invitecore"
+	"github.com/Housiadas/backend-system/internal/core/service/lockoutcore"
+	"github.com/Housiadas/backend-system/internal/core/service/mfacore"
+	"github.com/Housiadas/backend-system/internal/core/service/ordercore"
+	"github.com/Housiadas/backend-system/internal/core/service/organizationcore"
+	"github.com/Housiadas/backend-system/internal/core/service/passwordresetcore"
+	"github.com/Housiadas/backend-system/internal/core/service/pricecore"
 	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/rbaccore"
+	"github.com/Housiadas/backend-system/internal/core/service/securityeventcore"
+	"github.com/Housiadas/backend-system/internal/core/service/sessioncore"
+	"github.com/Housiadas/backend-system/internal/core/service/stockcore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/encrypt"
 	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/passhash"
 )
 
+// testKey is the encryption key used by the tests.
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+// testIndexKey is the key of the blind indexes used by the tests.
+var testIndexKey = []byte("fedcba9876543210fedcba9876543210")
+
+// testRotatedKey is the master key the users are encrypted again with.
+var testRotatedKey = []byte("abcdef0123456789abcdef0123456789")
+
+// UserKeys returns the keys encrypting the personal data of the users in the
+// tests, the stores of the users constructed by a test must use them.
+func UserKeys() user_repo.Keys {
+	return userKeys("test", map[string][]byte{"test": testKey})
+}
+
+// RotatedUserKeys returns the keys of UserKeys after a new master key was
+// generated, the previous one is kept to unwrap the older data keys.
+func RotatedUserKeys() user_repo.Keys {
+	return userKeys("rotated", map[string][]byte{"test": testKey, "rotated": testRotatedKey})
+}
+
+func userKeys(masterKID string, keys map[string][]byte) user_repo.Keys {
+	master, err := encrypt.New(masterKID, keys)
+	if err != nil {
+		panic(err)
+	}
+
+	index, err := encrypt.NewBlindIndex(testIndexKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return user_repo.Keys{
+		Envelope: encrypt.NewEnvelope(master),
+		Index:    index,
+	}
+}
+
 // Core represents all the internal core apis needed for testing.
 type Core struct {
-	Audit   *auditcore.Core
-	User    *usercore.Core
-	Product *productcore.Core
+	Audit    *auditcore.Core
+	User     *usercore.Core
+	Product  *productcore.Core
+	Session  *sessioncore.Core
+	Rbac     *rbaccore.Core
+	APIKey   *apikeycore.Core
+	Lockout  *lockoutcore.Core
+	MFA      *mfacore.Core
+	Reset    *passwordresetcore.Core
+	Invite   *invitecore.Core
+	Org      *organizationcore.Core
+	Security *securityeventcore.Core
+	Stock    *stockcore.Core
+	Order    *ordercore.Core
+	Account  *accountcore.Core
+	Price    *pricecore.Core
 }
 
 func newCore(log *logger.Logger, db *sqlx.DB) Core {
 	auditCore := auditcore.NewCore(log, audit_repo.NewStore(log, db))
-	userBus := usercore.NewCore(log, user_repo.NewStore(log, db))
+	// The passwords are hashed with cheap parameters to keep the tests fast.
+	hasher := passhash.NewArgon2id(passhash.Argon2Params{Memory: 1024, Time: 1, Threads: 1})
+	userBus := usercore.NewCoreWithHasher(log, user_repo.NewStore(log, db, UserKeys()), hasher)
 	productBus := productcore.NewCore(log, userBus, product_repo.NewStore(log, db))
+	sessionBus := sessioncore.NewCore(log, session_repo.NewStore(log, db))
+	rbacBus := rbaccore.NewCore(log, rbac_repo.NewStore(log, db))
+	apiKeyBus := apikeycore.NewCore(log, apikey_repo.NewStore(log, db))
+	lockoutBus := lockoutcore.NewCore(log, auditCore, lockout_repo.NewStore(log, db), lockoutcore.Policy{})
+
+	keyring, err := encrypt.New("test", map[string][]byte{"test": testKey})
+	if err != nil {
+		panic(err)
+	}
+	mfaBus := mfacore.NewCore(log, mfa_repo.NewStore(log, db), keyring, "Test")
+	resetBus := passwordresetcore.NewCore(log, passwordreset_repo.NewStore(log, db), 0)
+	inviteBus := invitecore.NewCore(log, invite_repo.NewStore(log, db), 0)
+	orgBus := organizationcore.NewCore(log, organization_repo.NewStore(log, db))
+	securityBus := securityeventcore.NewCore(log, securityevent_repo.NewStore(log, db), nil)
+	stockBus := stockcore.NewCore(log, stock_repo.NewStore(log, db), 0)
+	priceBus := pricecore.NewCore(log, price_repo.NewStore(log, db))
+	orderBus := ordercore.NewCore(log, userBus, productBus, stockBus, priceBus, auditCore, order_repo.NewStore(log, db))
+	accountBus := accountcore.NewCore(log, userBus, account_repo.NewStore(log, db))
 
 	return Core{
-		Audit:   auditCore,
-		User:    userBus,
-		Product: productBus,
+		Audit:    auditCore,
+		User:     userBus,
+		Product:  productBus,
+		Session:  sessionBus,
+		Rbac:     rbacBus,
+		APIKey:   apiKeyBus,
+		Lockout:  lockoutBus,
+		MFA:      mfaBus,
+		Reset:    resetBus,
+		Invite:   inviteBus,
+		Org:      orgBus,
+		Security: securityBus,
+		Stock:    stockBus,
+		Order:    orderBus,
+		Account:  accountBus,
+		Price:    priceBus,
 	}
 }
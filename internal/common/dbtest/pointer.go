@@ -1,6 +1,8 @@
 package dbtest
 
 import (
+	"encoding/json"
+
 	"github.com/Housiadas/backend-system/internal/core/domain/money"
 	"github.com/Housiadas/backend-system/internal/core/domain/name"
 	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
@@ -50,11 +52,19 @@ func NameNullPointer(value string) *name.Null {
 	return &n
 }
 
-// MoneyPointer is a helper to get a *Money from a float. It's in the tests
-// package because we normally don't want to deal with pointers to basic usecase,
-// but it's useful in some tests.
-func MoneyPointer(value float64) *money.Money {
-	m := money.MustParse(value)
+// NumberPointer is a helper to get a *json.Number from a decimal string. It is
+// in the tests package because we normally don't want to deal with pointers to
+// basic usecase, but it's useful in some tests.
+func NumberPointer(value string) *json.Number {
+	n := json.Number(value)
+	return &n
+}
+
+// MoneyPointer is a helper to get a *Money of the default currency from a
+// decimal string. It's in the tests package because we normally don't want to
+// deal with pointers to basic usecase, but it's useful in some tests.
+func MoneyPointer(value string) *money.Money {
+	m := money.MustParse(value, money.DefaultCurrency)
 	return &m
 }
 
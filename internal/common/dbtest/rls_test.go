@@ -0,0 +1,439 @@
+package dbtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/app/repository/product_repo"
+	"github.com/Housiadas/backend-system/internal/common/dbtest"
+	"github.com/Housiadas/backend-system/internal/common/unitest"
+	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
+	"github.com/Housiadas/backend-system/internal/core/service/authcore"
+	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// The tests run the statements as the caller, the way the Bearer middleware
+// does with the row level security enabled, but skip the middleware and the
+// tenant scope: only the policies of the database restrict the rows.
+func Test_RowLevelSecurity(t *testing.T) {
+	t.Parallel()
+
+	db := dbtest.New(t, "Test_RowLevelSecurity")
+
+	sd, err := insertSeedData(db.Core)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	app := db.App(t)
+
+	// -------------------------------------------------------------------------
+
+	unitest.Run(t, owner(app, sd), "owner")
+	unitest.Run(t, admin(app, sd), "admin")
+	unitest.Run(t, manager(app, sd), "manager")
+	unitest.Run(t, tenant(app, sd), "tenant")
+	unitest.Run(t, ordering(app, sd), "ordering")
+}
+
+// =============================================================================
+
+type seedData struct {
+	users   []unitest.User
+	admin   unitest.User
+	manager unitest.User
+	outside unitest.User
+}
+
+func insertSeedData(busDomain dbtest.Core) (seedData, error) {
+	ctx := context.Background()
+
+	usrs, err := usercore.TestSeedUsers(ctx, 2, role.User, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding users : %w", err)
+	}
+
+	sd := seedData{
+		users: make([]unitest.User, len(usrs)),
+	}
+
+	for i, usr := range usrs {
+		prds, err := productcore.TestGenerateSeedProducts(ctx, 2, busDomain.Product, usr.ID)
+		if err != nil {
+			return seedData{}, fmt.Errorf("seeding products : %w", err)
+		}
+
+		adts, err := auditcore.TestSeedAudits(ctx, 2, usr.ID, entity.Product, "create", busDomain.Audit)
+		if err != nil {
+			return seedData{}, fmt.Errorf("seeding audits : %w", err)
+		}
+
+		sd.users[i] = unitest.User{User: usr, Products: prds, Audits: adts}
+	}
+
+	admins, err := usercore.TestSeedUsers(ctx, 1, role.Admin, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding admins : %w", err)
+	}
+	sd.admin = unitest.User{User: admins[0]}
+
+	// The manager of the department of the first user.
+	managers, err := usercore.TestSeedUsers(ctx, 1, role.Manager, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding managers : %w", err)
+	}
+
+	mgr, err := busDomain.User.Update(ctx, managers[0], user.UpdateUser{Department: &usrs[0].Department})
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding manager department : %w", err)
+	}
+	sd.manager = unitest.User{User: mgr}
+
+	// The admin of another organization.
+	org, err := busDomain.Org.Create(ctx, organization.NewOrganization{Name: name.MustParse("Outside")})
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding organization : %w", err)
+	}
+
+	outside, err := usercore.TestSeedUsers(organization.WithScope(ctx, org.ID), 1, role.Admin, busDomain.User)
+	if err != nil {
+		return seedData{}, fmt.Errorf("seeding outside admin : %w", err)
+	}
+	sd.outside = unitest.User{User: outside[0]}
+
+	return sd, nil
+}
+
+func session(ctx context.Context, usr user.User) context.Context {
+	return pgsql.WithSession(ctx, pgsql.Session{
+		UserID:     usr.ID.String(),
+		Roles:      role.ParseToString(usr.Roles),
+		OrgID:      usr.OrgID.String(),
+		Department: dbtest.UserKeys().DepartmentIndex(authcore.Department(usr.Department)),
+	})
+}
+
+// =============================================================================
+
+func owner(app *dbtest.Database, sd seedData) []unitest.Table {
+	usr, other := sd.users[0], sd.users[1]
+
+	table := []unitest.Table{
+		{
+			Name:    "own-rows",
+			ExpResp: []int{1, 2, 2},
+			ExcFunc: func(ctx context.Context) any {
+				ctx = session(ctx, usr.User)
+
+				usrs, err := app.Core.User.Count(ctx, user.QueryFilter{})
+				if err != nil {
+					return err
+				}
+
+				prds, err := app.Core.Product.Count(ctx, product.QueryFilter{})
+				if err != nil {
+					return err
+				}
+
+				adts, err := app.Core.Audit.Count(ctx, audit.QueryFilter{})
+				if err != nil {
+					return err
+				}
+
+				return []int{usrs, prds, adts}
+			},
+			CmpFunc: cmpValue,
+		},
+		{
+			Name:    "read-other-product",
+			ExpResp: product.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := app.Core.Product.QueryByID(session(ctx, usr.User), other.Products[0].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "read-other-user",
+			ExpResp: user.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := app.Core.User.QueryByID(session(ctx, usr.User), other.ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "write-other-product",
+			ExpResp: []string{other.Products[0].Name.String(), other.Products[1].ID.String()},
+			ExcFunc: func(ctx context.Context) any {
+				ctx = session(ctx, usr.User)
+
+				// The rows of another user are not reached, the statements
+				// change nothing.
+				nme := name.MustParse("Changed")
+				if _, err := app.Core.Product.Update(ctx, other.Products[0], product.UpdateProduct{Name: &nme}); err != nil {
+					return err
+				}
+				if err := app.Core.Product.Delete(ctx, other.Products[1]); err != nil {
+					return err
+				}
+
+				prd0, err := app.Core.Product.QueryByID(context.Background(), other.Products[0].ID)
+				if err != nil {
+					return err
+				}
+
+				prd1, err := app.Core.Product.QueryByID(context.Background(), other.Products[1].ID)
+				if err != nil {
+					return err
+				}
+
+				return []string{prd0.Name.String(), prd1.ID.String()}
+			},
+			CmpFunc: cmpValue,
+		},
+		{
+			Name:    "insert-for-other",
+			ExpResp: true,
+			ExcFunc: func(ctx context.Context) any {
+				now := time.Now()
+
+				prd := other.Products[0]
+				prd.ID = uuid.New()
+				prd.DateCreated = now
+				prd.DateUpdated = now
+
+				// The store is used directly, as a code path skipping the
+				// core would.
+				store := product_repo.NewStore(app.Log, app.DB)
+				err := store.Create(session(ctx, usr.User), prd)
+
+				return err != nil
+			},
+			CmpFunc: cmpValue,
+		},
+		{
+			Name:    "transaction",
+			ExpResp: product.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				tx, err := app.DB.Beginx()
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+
+				prdCore, err := app.Core.Product.NewWithTx(tx)
+				if err != nil {
+					return err
+				}
+
+				if _, err := prdCore.QueryByID(session(ctx, usr.User), usr.Products[0].ID); err != nil {
+					return err
+				}
+
+				_, err = prdCore.QueryByID(session(ctx, usr.User), other.Products[0].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+	}
+
+	return table
+}
+
+func admin(app *dbtest.Database, sd seedData) []unitest.Table {
+	other := sd.users[1]
+
+	table := []unitest.Table{
+		{
+			Name:    "read-any-product",
+			ExpResp: other.Products[0].ID.String(),
+			ExcFunc: func(ctx context.Context) any {
+				prd, err := app.Core.Product.QueryByID(session(ctx, sd.admin.User), other.Products[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return prd.ID.String()
+			},
+			CmpFunc: cmpValue,
+		},
+	}
+
+	return table
+}
+
+func manager(app *dbtest.Database, sd seedData) []unitest.Table {
+	usr, other := sd.users[0], sd.users[1]
+
+	table := []unitest.Table{
+		{
+			Name:    "read-department-product",
+			ExpResp: usr.Products[0].ID.String(),
+			ExcFunc: func(ctx context.Context) any {
+				prd, err := app.Core.Product.QueryByID(session(ctx, sd.manager.User), usr.Products[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return prd.ID.String()
+			},
+			CmpFunc: cmpValue,
+		},
+		{
+			Name:    "read-other-department-product",
+			ExpResp: product.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := app.Core.Product.QueryByID(session(ctx, sd.manager.User), other.Products[0].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "write-department-product",
+			ExpResp: usr.Products[0].Name.String(),
+			ExcFunc: func(ctx context.Context) any {
+				// The manager only reads, the update reaches no row.
+				nme := name.MustParse("Changed")
+				if _, err := app.Core.Product.Update(session(ctx, sd.manager.User), usr.Products[0], product.UpdateProduct{Name: &nme}); err != nil {
+					return err
+				}
+
+				prd, err := app.Core.Product.QueryByID(context.Background(), usr.Products[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return prd.Name.String()
+			},
+			CmpFunc: cmpValue,
+		},
+	}
+
+	return table
+}
+
+func tenant(app *dbtest.Database, sd seedData) []unitest.Table {
+	other := sd.users[1]
+
+	table := []unitest.Table{
+		{
+			Name:    "read-other-organization",
+			ExpResp: product.ErrNotFound,
+			ExcFunc: func(ctx context.Context) any {
+				_, err := app.Core.Product.QueryByID(session(ctx, sd.outside.User), other.Products[0].ID)
+				return err
+			},
+			CmpFunc: cmpError,
+		},
+		{
+			Name:    "no-session",
+			ExpResp: other.Products[0].ID.String(),
+			ExcFunc: func(ctx context.Context) any {
+				prd, err := app.Core.Product.QueryByID(ctx, other.Products[0].ID)
+				if err != nil {
+					return err
+				}
+
+				return prd.ID.String()
+			},
+			CmpFunc: cmpValue,
+		},
+	}
+
+	return table
+}
+
+func ordering(app *dbtest.Database, sd seedData) []unitest.Table {
+	usr, other := sd.users[0], sd.users[1]
+
+	table := []unitest.Table{
+		{
+			Name:    "order-other-product",
+			ExpResp: []int{1, 0},
+			ExcFunc: func(ctx context.Context) any {
+				prd := other.Products[0]
+
+				// The statements without a session are not restricted.
+				mov, err := app.Core.Stock.Move(ctx, stock.NewMovement{ProductID: prd.ID, Kind: stock.Receipt, Quantity: 1})
+				if err != nil {
+					return err
+				}
+
+				// The product can't be reached by the caller, the order
+				// takes its stock and gives it back once cancelled anyway.
+				tx, err := pgsql.NewBeginner(app.DB).Begin()
+				if err != nil {
+					return err
+				}
+				defer tx.Rollback()
+
+				ordCore, err := app.Core.Order.NewWithTx(tx)
+				if err != nil {
+					return err
+				}
+
+				ctx = session(ctx, usr.User)
+
+				ord, err := ordCore.Create(ctx, order.NewOrder{
+					UserID: usr.ID,
+					Items:  []order.NewItem{{ProductID: prd.ID, Quantity: quantity.MustParse(1)}},
+				})
+				if err != nil {
+					return err
+				}
+
+				ord, err = ordCore.UpdateStatus(ctx, ord, order.UpdateStatus{Status: order.Cancelled, ActorID: usr.ID})
+				if err != nil {
+					return err
+				}
+
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+
+				got, err := app.Core.Product.QueryByID(context.Background(), prd.ID)
+				if err != nil {
+					return err
+				}
+
+				return []int{len(ord.Items), mov.Quantity - got.Quantity.Value()}
+			},
+			CmpFunc: cmpValue,
+		},
+	}
+
+	return table
+}
+
+// =============================================================================
+
+func cmpValue(got any, exp any) string {
+	return cmp.Diff(got, exp)
+}
+
+func cmpError(got any, exp any) string {
+	err, _ := got.(error)
+	if !errors.Is(err, exp.(error)) {
+		return fmt.Sprintf("got %v, exp %v", got, exp)
+	}
+	return ""
+}
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/json"
 	"reflect"
 	"strings"
 
@@ -36,4 +37,20 @@ func init() {
 
 		return name
 	})
+
+	// Validate numbers kept as exact decimals by their value, so that the
+	// numeric tags like gte work on them.
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		n, ok := field.Interface().(json.Number)
+		if !ok || n == "" {
+			return nil
+		}
+
+		f, err := n.Float64()
+		if err != nil {
+			return nil
+		}
+
+		return f
+	}, json.Number(""))
 }
@@ -1,5 +1,115 @@
 package config
 
+import "time"
+
+// Auth configures the authentication. The users are cached for UserCacheTTL
+// when checked on every call, zero disables the cache. ActiveKID is the key of
+// the KeysFolder signing the tokens, LegacyKID the one the tokens carrying no
+// kid were signed with.
 type Auth struct {
-	KeysFolder string
+	KeysFolder       string
+	ActiveKID        string
+	LegacyKID        string
+	Issuer           string
+	AccessTokenTTL   time.Duration
+	RefreshTokenTTL  time.Duration
+	ImpersonationTTL time.Duration
+	UserCacheTTL     time.Duration
+	PolicyPath       string
+	DecisionLog      string
+	Lockout          Lockout
+	MFA              MFA
+	PasswordReset    PasswordReset
+	Password         Password
+	Invite           Invite
+	Verification     Verification
+	OIDC             OIDC
+	TrustedIssuers   []TrustedIssuer
+}
+
+// TrustedIssuer is another deployment of the service whose access tokens are
+// accepted, verified with the keys published at its JWKSURL. The tokens must
+// be issued for the Audience, their roles are mapped with RoleMapping, the
+// ones not mapped are dropped, and they act in the Organization.
+type TrustedIssuer struct {
+	Issuer       string
+	JWKSURL      string
+	Audience     string
+	RoleMapping  map[string]string
+	Organization string
+}
+
+// Lockout configures the protection of the login against brute force.
+type Lockout struct {
+	MaxFailures   int
+	MaxIPFailures int
+	Backoff       time.Duration
+	Duration      time.Duration
+}
+
+// MFA configures the multi-factor authentication.
+type MFA struct {
+	Issuer       string
+	ChallengeTTL time.Duration
+}
+
+// PasswordReset configures the self-service password reset. The reset token
+// is sent as a query parameter of URL.
+type PasswordReset struct {
+	TTL time.Duration
+	URL string
+}
+
+// Invite configures the invitations of new users. The invite token is sent as
+// a query parameter of URL.
+type Invite struct {
+	TTL time.Duration
+	URL string
+}
+
+// Verification configures the email verification. With Required the users
+// the email verification rule refuses are not authenticated. The verification
+// token is sent as a query parameter of URL.
+type Verification struct {
+	Required bool
+	TTL      time.Duration
+	URL      string
+}
+
+// OIDC configures the single sign-on with an OpenID Connect provider, it is
+// enabled when Issuer is set. The groups listed in the RolesClaim of the ID
+// token are mapped to roles with RoleMapping, a user with no mapped group gets
+// the DefaultRoles. With Provision a user unknown by email is created on the
+// first login, in the Organization, the default one when empty.
+type OIDC struct {
+	Issuer               string
+	ClientID             string
+	ClientSecret         string
+	RedirectURL          string
+	Scopes               []string
+	RolesClaim           string
+	RoleMapping          map[string]string
+	DefaultRoles         []string
+	Provision            bool
+	Organization         string
+	AllowUnverifiedEmail bool
+	StateTTL             time.Duration
+}
+
+// Password configures how the passwords are hashed and the policy a new
+// password must satisfy. Algorithm is either "argon2id" or "bcrypt", Memory
+// is in KiB. BannedPath points to a file listing a banned password per line.
+type Password struct {
+	Algorithm     string
+	Memory        uint32
+	Time          uint32
+	Threads       uint8
+	BcryptCost    int
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	BannedPath    string
 }
@@ -7,15 +7,19 @@ import (
 // Config stores all configuration of the application.
 // The values are read by viper from a config file or environment variable.
 type Config struct {
-	App     App
-	Version Version
-	DB      DB
-	Http    Http
-	Grpc    Grpc
-	Auth    Auth
-	Kafka   Kafka
-	Tempo   Tempo
-	Cors    CorsSettings
+	App        App
+	Version    Version
+	DB         DB
+	Http       Http
+	Grpc       Grpc
+	Auth       Auth
+	Encryption Encryption
+	Notifier   Notifier
+	Stock      Stock
+	Price      Price
+	Kafka      Kafka
+	Tempo      Tempo
+	Cors       CorsSettings
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -9,4 +9,9 @@ type DB struct {
 	MaxIdleConns       int
 	ConnectionIdleTime string
 	DisableTLS         bool
+
+	// RowLevelSecurity runs the statements of the authenticated requests as
+	// the caller, so the database restricts the rows they reach. The user
+	// must not be a superuser, the policies don't apply to one.
+	RowLevelSecurity bool
 }
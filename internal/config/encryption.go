@@ -0,0 +1,17 @@
+package config
+
+// Encryption configures the keys encrypting the sensitive data at rest. Keys
+// maps a key id to a base64 encoded 32 byte key, new data is encrypted with
+// the ActiveKey while the others are kept to decrypt older data.
+//
+// The personal data of the users is encrypted with keys read from the
+// <kid>.key files of the auth keys folder. The MasterKey wraps the data keys
+// of the new rows, the other keys found unwrap the older ones until the rows
+// are encrypted again. The IndexKey computes the blind indexes the encrypted
+// values are looked up by and must never change.
+type Encryption struct {
+	ActiveKey string
+	Keys      map[string]string
+	MasterKey string
+	IndexKey  string
+}
@@ -7,4 +7,8 @@ type Kafka struct {
 	LogLevel         int
 	MaxMessageBytes  int
 	SessionTimeout   int
+
+	// SecurityEventsTopic is the topic the security events are published
+	// to for a SIEM, they are only stored when it is empty.
+	SecurityEventsTopic string
 }
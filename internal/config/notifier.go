@@ -0,0 +1,8 @@
+package config
+
+// Notifier configures the delivery of the messages sent to the users. Kind is
+// either "log" or "file", the file notifier appends the messages to Path.
+type Notifier struct {
+	Kind string
+	Path string
+}
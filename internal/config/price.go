@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+// Price configures the scheduled prices of the products, the ones that took
+// effect are applied to the products every ApplyInterval.
+type Price struct {
+	ApplyInterval time.Duration
+}
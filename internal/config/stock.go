@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+// Stock configures the reservations of the stock of the products. A
+// reservation holds the stock for ReservationTTL unless it asks for another
+// one, and the expired reservations are released every ReleaseInterval.
+type Stock struct {
+	ReservationTTL  time.Duration
+	ReleaseInterval time.Duration
+}
@@ -0,0 +1,95 @@
+// Package account represents the accounts of the users and the transfers of
+// money between them. Every transfer is kept as two entries, the debit of an
+// account and the credit of the other, so the entries always balance.
+package account
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+)
+
+// Set of error variables for account operations.
+var (
+	ErrNotFound          = errors.New("account not found")
+	ErrUniqueCurrency    = errors.New("account already exists in currency")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrSameAccount       = errors.New("transfer to the same account")
+	ErrInvalidAmount     = errors.New("amount not valid")
+	ErrCurrencyMismatch  = errors.New("accounts in different currencies")
+	ErrUserDisabled      = errors.New("user disabled")
+)
+
+// Account represents an account of a user holding money of a currency, a
+// user has at most one account per currency. The clearing account of a
+// currency has no owner: the money deposited comes from it and the money
+// withdrawn goes to it, so its balance may drop below zero.
+type Account struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	OwnerID     uuid.UUID
+	Balance     money.Money
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// IsClearing reports whether the account is the clearing account of its
+// currency.
+func (a Account) IsClearing() bool {
+	return a.OwnerID == uuid.Nil
+}
+
+// NewAccount is what we require to open an account.
+type NewAccount struct {
+	OwnerID  uuid.UUID
+	Currency money.Currency
+}
+
+// Transfer represents money moved from an account to another.
+type Transfer struct {
+	ID            uuid.UUID
+	OrgID         uuid.UUID
+	FromAccountID uuid.UUID
+	ToAccountID   uuid.UUID
+	Amount        money.Money
+	DateCreated   time.Time
+}
+
+// NewTransfer is what we require to move money between accounts.
+type NewTransfer struct {
+	FromAccountID uuid.UUID
+	ToAccountID   uuid.UUID
+	Amount        money.Money
+}
+
+// Entry represents the side of a transfer on one of its accounts. The amount
+// is negative for the debit of the account the money is taken from, and the
+// balance is the one of the account right after the entry.
+type Entry struct {
+	ID          uuid.UUID
+	AccountID   uuid.UUID
+	TransferID  uuid.UUID
+	Amount      money.Money
+	Balance     money.Money
+	DateCreated time.Time
+}
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ID       *uuid.UUID
+	OwnerID  *uuid.UUID
+	Currency *money.Currency
+}
+
+// EntryFilter holds the available fields a query of the entries of an
+// account can be filtered on.
+type EntryFilter struct {
+	AccountID        uuid.UUID
+	TransferID       *uuid.UUID
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+}
@@ -0,0 +1,25 @@
+package account
+
+import "github.com/Housiadas/backend-system/pkg/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.ASC)
+
+// DefaultEntryOrderBy represents the default way we sort the entries, the
+// latest first.
+var DefaultEntryOrderBy = order.NewBy(OrderByEntrySeq, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "a"
+	OrderByOwnerID     = "b"
+	OrderByCurrency    = "c"
+	OrderByBalance     = "d"
+	OrderByDateCreated = "e"
+)
+
+// Set of fields that the entries can be ordered by.
+const (
+	OrderByEntrySeq    = "a"
+	OrderByEntryAmount = "b"
+)
@@ -0,0 +1,30 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data. A transfer takes the money from an account and gives it to
+// the other at once, it fails with ErrInsufficientFunds when the balance of
+// an account other than a clearing one would drop below zero.
+type Storer interface {
+	NewWithTx(tx pgsql.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, acct Account) error
+	Transfer(ctx context.Context, tr Transfer) ([]Entry, error)
+	QueryByID(ctx context.Context, accountID uuid.UUID) (Account, error)
+	QueryClearing(ctx context.Context, orgID uuid.UUID, currency money.Currency, now time.Time) (Account, error)
+	QueryBalanceAt(ctx context.Context, acct Account, at time.Time) (money.Money, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Account, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryEntries(ctx context.Context, filter EntryFilter, orderBy order.By, page page.Page) ([]Entry, error)
+	CountEntries(ctx context.Context, filter EntryFilter) (int, error)
+}
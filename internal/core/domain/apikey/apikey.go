@@ -0,0 +1,70 @@
+package apikey
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+// Set of error variables for api key operations.
+var (
+	ErrNotFound        = errors.New("api key not found")
+	ErrInvalidKey      = errors.New("invalid api key")
+	ErrRevoked         = errors.New("api key revoked")
+	ErrExpired         = errors.New("api key expired")
+	ErrDisabled        = errors.New("service account disabled")
+	ErrUniqueName      = errors.New("service account already exists")
+	ErrAccountNotFound = errors.New("service account not found")
+)
+
+// ServiceAccount represents a non human principal used for machine to
+// machine access. It authenticates with the api keys it owns.
+type ServiceAccount struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	Name        string
+	Description string
+	Roles       []role.Role
+	Enabled     bool
+	CreatedBy   uuid.UUID
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewServiceAccount contains information needed to create a new service account.
+type NewServiceAccount struct {
+	Name        string
+	Description string
+	Roles       []role.Role
+	CreatedBy   uuid.UUID
+}
+
+// Key represents an api key owned by a service account. Only the hash of the
+// key is stored, the prefix identifies the key and is safe to display. The
+// scopes narrow down the permissions granted by the roles of the account.
+type Key struct {
+	ID               uuid.UUID
+	ServiceAccountID uuid.UUID
+	Name             string
+	Prefix           string
+	KeyHash          string
+	Scopes           []permission.Permission
+	ExpiresAt        *time.Time
+	LastUsedAt       *time.Time
+	Revoked          bool
+	DateCreated      time.Time
+	DateUpdated      time.Time
+}
+
+// NewKey contains information needed to create a new api key. A nil
+// ExpiresAt creates a key that does not expire.
+type NewKey struct {
+	ServiceAccountID uuid.UUID
+	Name             string
+	Scopes           []permission.Permission
+	ExpiresAt        *time.Time
+}
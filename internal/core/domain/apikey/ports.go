@@ -0,0 +1,19 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Storer interface declares the behavior this package needs to persist and retrieve data.
+type Storer interface {
+	CreateServiceAccount(ctx context.Context, sa ServiceAccount) error
+	QueryServiceAccounts(ctx context.Context) ([]ServiceAccount, error)
+	QueryServiceAccountByID(ctx context.Context, serviceAccountID uuid.UUID) (ServiceAccount, error)
+	CreateKey(ctx context.Context, key Key) error
+	UpdateKey(ctx context.Context, key Key) error
+	QueryKeys(ctx context.Context, serviceAccountID uuid.UUID) ([]Key, error)
+	QueryKeyByID(ctx context.Context, keyID uuid.UUID) (Key, error)
+	QueryKeyByPrefix(ctx context.Context, prefix string) (Key, error)
+}
@@ -13,6 +13,7 @@ import (
 // Audit represents information about an individual audit record.
 type Audit struct {
 	ID        uuid.UUID
+	OrgID     uuid.UUID
 	ObjID     uuid.UUID
 	ObjEntity entity.Entity
 	ObjName   name.Name
@@ -32,6 +33,10 @@ type NewAudit struct {
 	Action    string
 	Data      any
 	Message   string
+
+	// OrgID is the organization the record belongs to, the one of the
+	// caller is used when the context is scoped to one.
+	OrgID uuid.UUID
 }
 
 // QueryFilter holds the available fields a query can be filtered on.
@@ -6,6 +6,9 @@ import "fmt"
 var (
 	User    = newEntity("USER")
 	Product = newEntity("PRODUCT")
+	Auth    = newEntity("AUTH")
+	Order   = newEntity("ORDER")
+	Account = newEntity("ACCOUNT")
 )
 
 // Set of known entities.
@@ -0,0 +1,41 @@
+// Package invite represents the invitations of an email to join with a set of
+// roles.
+package invite
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+// Set of error variables for invite operations.
+var (
+	ErrNotFound = errors.New("invite not found")
+	ErrExpired  = errors.New("invite expired")
+	ErrAccepted = errors.New("invite already accepted")
+)
+
+// Invite represents an invitation sent by an admin. The user is created when
+// the invitee accepts it and sets their password.
+type Invite struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	Email       mail.Address
+	Roles       []role.Role
+	InvitedBy   uuid.UUID
+	ExpiresAt   time.Time
+	AcceptedAt  *time.Time
+	DateCreated time.Time
+}
+
+// NewInvite contains information needed to invite an email.
+type NewInvite struct {
+	Email     mail.Address
+	Roles     []role.Role
+	InvitedBy uuid.UUID
+	OrgID     uuid.UUID
+}
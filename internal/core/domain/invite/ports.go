@@ -0,0 +1,18 @@
+package invite
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// Storer interface declares the behavior this package needs to persist and retrieve data.
+type Storer interface {
+	NewWithTx(tx pgsql.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, inv Invite) error
+	Accept(ctx context.Context, inviteID uuid.UUID, acceptedAt time.Time) error
+	QueryByID(ctx context.Context, inviteID uuid.UUID) (Invite, error)
+}
@@ -0,0 +1,67 @@
+// Package lockout represents the failed login attempts counted against an
+// email or an ip address.
+package lockout
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for lockout operations.
+var (
+	ErrNotFound = errors.New("login attempts not found")
+	ErrLocked   = errors.New("too many failed login attempts")
+)
+
+// Attempts represents the recent failed logins for a key. A key is locked
+// until LockedUntil once too many logins failed.
+type Attempts struct {
+	Key         string
+	Failures    int
+	LastFailure time.Time
+	LockedUntil *time.Time
+}
+
+const (
+	emailPrefix = "email:"
+	ipPrefix    = "ip:"
+	mfaPrefix   = "mfa:"
+)
+
+// EmailKey returns the key counting the failed logins for an email.
+func EmailKey(email mail.Address) string {
+	return emailPrefix + strings.ToLower(email.Address)
+}
+
+// IPKey returns the key counting the failed logins from an ip address.
+func IPKey(ip string) string {
+	return ipPrefix + ip
+}
+
+// MFAKey returns the key counting the failed mfa codes of a user.
+func MFAKey(userID uuid.UUID) string {
+	return mfaPrefix + userID.String()
+}
+
+// IsIPKey reports whether the key counts the failed logins from an ip address.
+func IsIPKey(key string) bool {
+	return strings.HasPrefix(key, ipPrefix)
+}
+
+// HashKey hides the email or the ip address of a key for the records kept of
+// it, the kind of key is kept so they stay readable.
+func HashKey(key string) string {
+	kind, value, found := strings.Cut(key, ":")
+	if !found {
+		kind, value = "", key
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	return kind + ":" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,14 @@
+package lockout
+
+import (
+	"context"
+	"time"
+)
+
+// Storer interface declares the behavior this package needs to persist and retrieve data.
+type Storer interface {
+	QueryByKey(ctx context.Context, key string) (Attempts, error)
+	Fail(ctx context.Context, key string, now time.Time, since time.Time) (Attempts, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+}
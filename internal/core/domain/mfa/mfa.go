@@ -0,0 +1,37 @@
+// Package mfa represents the multi-factor authentication settings of a user.
+package mfa
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for mfa operations.
+var (
+	ErrNotFound        = errors.New("mfa not enrolled")
+	ErrNotConfirmed    = errors.New("mfa enrolment not confirmed")
+	ErrAlreadyEnrolled = errors.New("mfa already enrolled")
+	ErrInvalidCode     = errors.New("invalid mfa code")
+)
+
+// MFA represents the totp enrolment of a user. The secret is encrypted and
+// only the hashes of the recovery codes are kept. LastUsedStep is the time
+// step of the last code accepted, a code is never accepted twice.
+type MFA struct {
+	UserID        uuid.UUID
+	Secret        string
+	Confirmed     bool
+	LastUsedStep  int64
+	RecoveryCodes []string
+	DateCreated   time.Time
+	DateUpdated   time.Time
+}
+
+// Enrolment is returned when a user starts enrolling, it holds what the user
+// needs to set up an authenticator app.
+type Enrolment struct {
+	Secret string
+	URI    string
+}
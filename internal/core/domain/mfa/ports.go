@@ -0,0 +1,14 @@
+package mfa
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Storer interface declares the behavior this package needs to persist and retrieve data.
+type Storer interface {
+	Upsert(ctx context.Context, m MFA) error
+	Update(ctx context.Context, m MFA) error
+	QueryByUserID(ctx context.Context, userID uuid.UUID) (MFA, error)
+}
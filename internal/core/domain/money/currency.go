@@ -0,0 +1,80 @@
+package money
+
+import "fmt"
+
+// The set of currencies supported, along with the number of digits of their
+// minor unit as defined by ISO 4217.
+var (
+	USD = newCurrency("USD", 2)
+	EUR = newCurrency("EUR", 2)
+	GBP = newCurrency("GBP", 2)
+	CHF = newCurrency("CHF", 2)
+	JPY = newCurrency("JPY", 0)
+	KRW = newCurrency("KRW", 0)
+	KWD = newCurrency("KWD", 3)
+	BHD = newCurrency("BHD", 3)
+)
+
+// DefaultCurrency is the currency of the amounts given without one, like
+// the ones stored before the currencies were supported.
+var DefaultCurrency = USD
+
+// Set of known currencies.
+var currencies = make(map[string]Currency)
+
+// Currency represents an ISO 4217 currency.
+type Currency struct {
+	code   string
+	digits int
+}
+
+func newCurrency(code string, digits int) Currency {
+	c := Currency{code, digits}
+	currencies[code] = c
+	return c
+}
+
+// String returns the ISO 4217 code of the currency.
+func (c Currency) String() string {
+	return c.code
+}
+
+// Digits returns the number of digits of the minor unit of the currency,
+// the amounts are rounded to it.
+func (c Currency) Digits() int {
+	return c.digits
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (c Currency) Equal(c2 Currency) bool {
+	return c.code == c2.code
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (c Currency) MarshalText() ([]byte, error) {
+	return []byte(c.code), nil
+}
+
+// =============================================================================
+
+// ParseCurrency parses the ISO 4217 code and returns a currency if one
+// exists.
+func ParseCurrency(code string) (Currency, error) {
+	c, exists := currencies[code]
+	if !exists {
+		return Currency{}, fmt.Errorf("invalid currency %q", code)
+	}
+
+	return c, nil
+}
+
+// MustParseCurrency parses the ISO 4217 code and returns a currency if one
+// exists. If an error occurs the function panics.
+func MustParseCurrency(code string) Currency {
+	c, err := ParseCurrency(code)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
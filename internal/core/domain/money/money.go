@@ -1,49 +1,316 @@
-// Package money represents money in the system.
+// Package money represents money in the system. An amount is held exactly,
+// as an integer number of the minor units of its currency, like the cents of
+// the dollar, so adding amounts never introduces rounding errors.
 package money
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 )
 
-// Money represents money in the system.
+// Set of error variables for the arithmetic.
+var (
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+	ErrOverflow         = errors.New("amount out of range")
+)
+
+// Money represents an amount of a currency.
 type Money struct {
-	value float64
+	amount   int64
+	currency Currency
+}
+
+// New returns the money of the amount given in minor units of the currency.
+func New(amount int64, currency Currency) Money {
+	return Money{amount, currency}
 }
 
-// Value returns the float value of the money.
-func (m Money) Value() float64 {
-	return m.value
+// Zero returns no money of the currency.
+func Zero(currency Currency) Money {
+	return Money{0, currency}
 }
 
-// String returns the value of the money.
+// Amount returns the amount in minor units of the currency.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+// Currency returns the currency of the money.
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// Decimal returns the amount as a decimal with the digits of the currency,
+// like 10.50 for ten dollars and a half.
+func (m Money) Decimal() string {
+	digits := m.currency.digits
+
+	abs := new(big.Int).Abs(big.NewInt(m.amount)).String()
+	if len(abs) <= digits {
+		abs = strings.Repeat("0", digits-len(abs)+1) + abs
+	}
+
+	var sign string
+	if m.amount < 0 {
+		sign = "-"
+	}
+
+	if digits == 0 {
+		return sign + abs
+	}
+
+	return sign + abs[:len(abs)-digits] + "." + abs[len(abs)-digits:]
+}
+
+// String returns the amount along with the currency, like 10.50 USD.
 func (m Money) String() string {
-	return fmt.Sprintf("%.2f", m.value)
+	return m.Decimal() + " " + m.currency.code
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.amount < 0
 }
 
 // Equal provides support for the go-cmp package and testing.
 func (m Money) Equal(m2 Money) bool {
-	return m.value == m2.value
+	return m.amount == m2.amount && m.currency.Equal(m2.currency)
+}
+
+// Add returns the sum of the money of the same currency.
+func (m Money) Add(m2 Money) (Money, error) {
+	if !m.currency.Equal(m2.currency) {
+		return Money{}, fmt.Errorf("add %s to %s: %w", m2.currency, m.currency, ErrCurrencyMismatch)
+	}
+
+	sum := new(big.Int).Add(big.NewInt(m.amount), big.NewInt(m2.amount))
+
+	return fromBig(sum, m.currency)
+}
+
+// Sub returns the difference of the money of the same currency.
+func (m Money) Sub(m2 Money) (Money, error) {
+	if !m.currency.Equal(m2.currency) {
+		return Money{}, fmt.Errorf("subtract %s from %s: %w", m2.currency, m.currency, ErrCurrencyMismatch)
+	}
+
+	diff := new(big.Int).Sub(big.NewInt(m.amount), big.NewInt(m2.amount))
+
+	return fromBig(diff, m.currency)
+}
+
+// Mul returns the money multiplied by the quantity, like the cost of a
+// number of items.
+func (m Money) Mul(quantity int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(quantity))
+
+	return fromBig(product, m.currency)
+}
+
+// Allocate splits the money in shares proportional to the ratios without
+// losing a minor unit: the remainder of the division is given a minor unit
+// at a time to the first shares.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("allocate: no ratios")
+	}
+
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("allocate: negative ratio %d", ratio)
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+
+	if total.Sign() == 0 {
+		return nil, errors.New("allocate: ratios sum to zero")
+	}
+
+	amount := big.NewInt(m.amount)
+
+	shares := make([]Money, len(ratios))
+	remainder := m.amount
+	for i, ratio := range ratios {
+		// The share is truncated towards zero, the remainder has the sign of
+		// the amount.
+		share := new(big.Int).Mul(amount, big.NewInt(ratio))
+		share.Quo(share, total)
+
+		shares[i] = Money{share.Int64(), m.currency}
+		remainder -= share.Int64()
+	}
+
+	unit := int64(1)
+	if remainder < 0 {
+		unit = -1
+	}
+
+	for i := 0; remainder != 0; i++ {
+		if ratios[i%len(ratios)] == 0 {
+			continue
+		}
+		shares[i%len(ratios)].amount += unit
+		remainder -= unit
+	}
+
+	return shares, nil
+}
+
+// Split splits the money in n shares as equal as possible.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("split: invalid number of shares %d", n)
+	}
+
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return m.Allocate(ratios...)
 }
 
 // =============================================================================
 
-// Parse parses the float value and returns a money if the value complies
-// with the rules for money.
-func Parse(value float64) (Money, error) {
-	if value < 0 || value > 1_000_000 {
-		return Money{}, fmt.Errorf("invalid money %.2f", value)
+// moneyJSON is the form of the money in JSON, the amount is a decimal string
+// so it is not read as a floating point number.
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{
+		Amount:   m.Decimal(),
+		Currency: m.currency.code,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var mj moneyJSON
+	if err := json.Unmarshal(data, &mj); err != nil {
+		return err
+	}
+
+	currency, err := ParseCurrency(mj.Currency)
+	if err != nil {
+		return err
 	}
 
-	return Money{value}, nil
+	money, err := Parse(mj.Amount, currency)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
+
+// =============================================================================
+
+// Parse parses the decimal value and returns the money of the currency. The
+// value must be exact: it can't have more decimals than the currency, apart
+// from trailing zeros.
+func Parse(value string, currency Currency) (Money, error) {
+	return parse(value, currency, false)
+}
+
+// Round parses the decimal value and returns the money of the currency, the
+// value is rounded to the digits of the currency half to even, the way
+// banks do, so the roundings of many amounts do not add up to a bias.
+func Round(value string, currency Currency) (Money, error) {
+	return parse(value, currency, true)
 }
 
-// MustParse parses the string value and returns a money if the value
-// complies with the rules for money. If an error occurs the function panics.
-func MustParse(value float64) Money {
-	money, err := Parse(value)
+// MustParse parses the decimal value and returns the money of the currency.
+// If an error occurs the function panics.
+func MustParse(value string, currency Currency) Money {
+	money, err := Parse(value, currency)
 	if err != nil {
 		panic(err)
 	}
 
 	return money
 }
+
+func parse(value string, currency Currency, round bool) (Money, error) {
+	s := strings.TrimSpace(value)
+
+	var negative bool
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if (whole == "" && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("invalid money %q", value)
+	}
+
+	// The digits beyond the minor unit of the currency.
+	var rest string
+	if len(fraction) > currency.digits {
+		fraction, rest = fraction[:currency.digits], fraction[currency.digits:]
+	}
+	fraction += strings.Repeat("0", currency.digits-len(fraction))
+
+	amount, ok := new(big.Int).SetString("0"+whole+fraction, 10)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid money %q", value)
+	}
+
+	if strings.Trim(rest, "0") != "" {
+		if !round {
+			return Money{}, fmt.Errorf("invalid money %q: more than %d decimals for %s", value, currency.digits, currency.code)
+		}
+
+		// Half to even: up above the half, and at the half only when the
+		// amount is odd.
+		half := "5" + strings.Repeat("0", len(rest)-1)
+		if c := strings.Compare(rest, half); c > 0 || (c == 0 && amount.Bit(0) == 1) {
+			amount.Add(amount, big.NewInt(1))
+		}
+	}
+
+	if negative {
+		amount.Neg(amount)
+	}
+
+	money, err := fromBig(amount, currency)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid money %q: %w", value, err)
+	}
+
+	return money, nil
+}
+
+func fromBig(amount *big.Int, currency Currency) (Money, error) {
+	if !amount.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{amount.Int64(), currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,45 +1,36 @@
 package money
 
 import (
+	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 )
 
 func TestMoney_Equal(t *testing.T) {
-	type fields struct {
-		value float64
-	}
-
-	type args struct {
-		m2 Money
-	}
-
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   bool
+		name string
+		m    Money
+		m2   Money
+		want bool
 	}{
 		{
 			name: "Equal",
-			fields: fields{
-				value: 100,
-			},
-			args: args{
-				m2: Money{
-					value: 100,
-				},
-			},
+			m:    New(100, USD),
+			m2:   New(100, USD),
 			want: true,
 		},
+		{
+			name: "OtherCurrency",
+			m:    New(100, USD),
+			m2:   New(100, EUR),
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := Money{
-				value: tt.fields.value,
-			}
-			if got := m.Equal(tt.args.m2); got != tt.want {
+			if got := tt.m.Equal(tt.m2); got != tt.want {
 				t.Errorf("Equal() = %v, want %v", got, tt.want)
 			}
 		})
@@ -47,164 +38,300 @@ func TestMoney_Equal(t *testing.T) {
 }
 
 func TestMoney_String(t *testing.T) {
-	type fields struct {
-		value float64
-	}
-
-	type args struct {
-		m2 Money
-	}
-
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   string
+		name string
+		m    Money
+		want string
 	}{
 		{
 			name: "String",
-			fields: fields{
-				value: 100.55,
-			},
-			args: args{
-				m2: Money{
-					value: 100.55,
-				},
-			},
-			want: "100.55",
+			m:    New(10055, USD),
+			want: "100.55 USD",
+		},
+		{
+			name: "Cents",
+			m:    New(5, EUR),
+			want: "0.05 EUR",
+		},
+		{
+			name: "Negative",
+			m:    New(-1050, USD),
+			want: "-10.50 USD",
+		},
+		{
+			name: "NoMinorUnit",
+			m:    New(1500, JPY),
+			want: "1500 JPY",
+		},
+		{
+			name: "ThreeDigits",
+			m:    New(1005, KWD),
+			want: "1.005 KWD",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := Money{
-				value: tt.fields.value,
-			}
-			if got := m.String(); got != tt.want {
+			if got := tt.m.String(); got != tt.want {
 				t.Errorf("String() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestMoney_Value(t *testing.T) {
-	type fields struct {
-		value float64
-	}
-
-	type args struct {
-		m2 Money
+func TestMustParse(t *testing.T) {
+	if got, want := MustParse("100000", USD), New(10_000_000, USD); !reflect.DeepEqual(got, want) {
+		t.Errorf("MustParse() = %v, want %v", got, want)
 	}
+}
 
+func TestParse(t *testing.T) {
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   float64
+		name     string
+		value    string
+		currency Currency
+		want     Money
+		wantErr  bool
 	}{
 		{
-			name: "Value",
-			fields: fields{
-				value: 100.55,
-			},
-			args: args{
-				m2: Money{
-					value: 100.55,
-				},
-			},
-			want: 100.55,
+			name:     "Parse",
+			value:    "10.34",
+			currency: USD,
+			want:     New(1034, USD),
+		},
+		{
+			name:     "TrailingZeros",
+			value:    "10.3400",
+			currency: USD,
+			want:     New(1034, USD),
+		},
+		{
+			name:     "Whole",
+			value:    "-7",
+			currency: EUR,
+			want:     New(-700, EUR),
+		},
+		{
+			name:     "Fraction",
+			value:    ".5",
+			currency: USD,
+			want:     New(50, USD),
+		},
+		{
+			name:     "NoMinorUnit",
+			value:    "1500",
+			currency: JPY,
+			want:     New(1500, JPY),
+		},
+		{
+			name:     "TooPrecise",
+			value:    "10.345",
+			currency: USD,
+			wantErr:  true,
+		},
+		{
+			name:     "NotDecimal",
+			value:    "1e3",
+			currency: USD,
+			wantErr:  true,
+		},
+		{
+			name:     "Empty",
+			value:    "",
+			currency: USD,
+			wantErr:  true,
+		},
+		{
+			name:     "Overflow",
+			value:    "100000000000000000000",
+			currency: USD,
+			wantErr:  true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := Money{
-				value: tt.fields.value,
+			got, err := Parse(tt.value, tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
 			}
-			if got := m.Value(); got != tt.want {
-				t.Errorf("Value() = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestMustParse(t *testing.T) {
-	type args struct {
-		m Money
-	}
-
+func TestRound(t *testing.T) {
 	tests := []struct {
-		name string
-		args args
-		want Money
+		name     string
+		value    string
+		currency Currency
+		want     Money
 	}{
 		{
-			name: "MustParse",
-			args: args{
-				m: Money{
-					value: 100_000,
-				},
-			},
-			want: Money{
-				value: 100_000,
-			},
+			name:     "Down",
+			value:    "10.344",
+			currency: USD,
+			want:     New(1034, USD),
+		},
+		{
+			name:     "Up",
+			value:    "10.346",
+			currency: USD,
+			want:     New(1035, USD),
+		},
+		{
+			name:     "HalfToEvenDown",
+			value:    "10.345",
+			currency: USD,
+			want:     New(1034, USD),
+		},
+		{
+			name:     "HalfToEvenUp",
+			value:    "10.355",
+			currency: USD,
+			want:     New(1036, USD),
+		},
+		{
+			name:     "AboveHalf",
+			value:    "10.3451",
+			currency: USD,
+			want:     New(1035, USD),
+		},
+		{
+			name:     "Negative",
+			value:    "-0.125",
+			currency: USD,
+			want:     New(-12, USD),
+		},
+		{
+			name:     "NoMinorUnit",
+			value:    "2.5",
+			currency: JPY,
+			want:     New(2, JPY),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := MustParse(tt.args.m.value); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("MustParse() = %v, want %v", got, tt.want)
+			got, err := Round(tt.value, tt.currency)
+			if err != nil {
+				t.Fatalf("Round() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Round() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestParse(t *testing.T) {
-	type args struct {
-		m Money
+func TestMoney_Arithmetic(t *testing.T) {
+	// Floating point numbers can't hold 0.10 and 0.20 exactly.
+	sum, err := MustParse("0.10", USD).Add(MustParse("0.20", USD))
+	if err != nil || !sum.Equal(MustParse("0.30", USD)) {
+		t.Errorf("Add() = %v %v, want 0.30 USD", sum, err)
+	}
+
+	diff, err := MustParse("0.10", USD).Sub(MustParse("0.30", USD))
+	if err != nil || !diff.Equal(MustParse("-0.20", USD)) {
+		t.Errorf("Sub() = %v %v, want -0.20 USD", diff, err)
+	}
+
+	total, err := MustParse("19.99", USD).Mul(3)
+	if err != nil || !total.Equal(MustParse("59.97", USD)) {
+		t.Errorf("Mul() = %v %v, want 59.97 USD", total, err)
+	}
+
+	if _, err := New(1, USD).Add(New(1, EUR)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+
+	if _, err := New(1<<62, USD).Mul(4); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Mul() error = %v, want %v", err, ErrOverflow)
 	}
+}
 
+func TestMoney_Allocate(t *testing.T) {
 	tests := []struct {
-		name    string
-		args    args
-		want    Money
-		wantErr bool
+		name   string
+		m      Money
+		ratios []int64
+		want   []Money
 	}{
 		{
-			name: "Parse",
-			args: args{
-				m: Money{
-					value: 100_000,
-				},
-			},
-			want: Money{
-				value: 100_000,
-			},
-			wantErr: false,
+			name:   "Even",
+			m:      New(100, USD),
+			ratios: []int64{1, 1},
+			want:   []Money{New(50, USD), New(50, USD)},
+		},
+		{
+			name:   "Remainder",
+			m:      New(100, USD),
+			ratios: []int64{1, 1, 1},
+			want:   []Money{New(34, USD), New(33, USD), New(33, USD)},
 		},
 		{
-			name: "NotParse",
-			args: args{
-				m: Money{
-					value: 10_000_000,
-				},
-			},
-			want:    Money{},
-			wantErr: true,
+			name:   "Ratios",
+			m:      New(5, USD),
+			ratios: []int64{3, 7},
+			want:   []Money{New(2, USD), New(3, USD)},
+		},
+		{
+			name:   "ZeroRatio",
+			m:      New(101, USD),
+			ratios: []int64{0, 1, 1},
+			want:   []Money{New(0, USD), New(51, USD), New(50, USD)},
+		},
+		{
+			name:   "Negative",
+			m:      New(-100, USD),
+			ratios: []int64{1, 1, 1},
+			want:   []Money{New(-34, USD), New(-33, USD), New(-33, USD)},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := Parse(tt.args.m.value)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			got, err := tt.m.Allocate(tt.ratios...)
+			if err != nil {
+				t.Fatalf("Allocate() error = %v", err)
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+				t.Errorf("Allocate() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
+
+	shares, err := New(1000, JPY).Split(3)
+	if err != nil || !reflect.DeepEqual(shares, []Money{New(334, JPY), New(333, JPY), New(333, JPY)}) {
+		t.Errorf("Split() got = %v %v", shares, err)
+	}
+
+	if _, err := New(100, USD).Allocate(0, 0); err == nil {
+		t.Error("Allocate() should refuse ratios summing to zero")
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	m := MustParse("1234567890.12", EUR)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if got, want := string(data), `{"amount":"1234567890.12","currency":"EUR"}`; got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+
+	var got Money
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !got.Equal(m) {
+		t.Errorf("Unmarshal() = %v, want %v", got, m)
+	}
 }
@@ -0,0 +1,80 @@
+// Package order represents the orders of the products placed by the users.
+// Placing an order takes the stock of its products, and cancelling it gives
+// the stock back.
+package order
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
+)
+
+// Set of error variables for order operations.
+var (
+	ErrNotFound          = errors.New("order not found")
+	ErrNoItems           = errors.New("order has no items")
+	ErrDuplicateItem     = errors.New("product ordered more than once")
+	ErrCurrencyMismatch  = errors.New("products priced in different currencies")
+	ErrInvalidTransition = errors.New("status change not allowed")
+	ErrStatusChanged     = errors.New("order status changed in the meantime")
+	ErrUserDisabled      = errors.New("user disabled")
+)
+
+// Order represents an order of products. The total is the sum of the totals
+// of its items.
+type Order struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	UserID      uuid.UUID
+	Status      Status
+	Total       money.Money
+	Items       []Item
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// Item represents a product in an order. The name and the price are the ones
+// of the product when the order was placed, the product is zero once it was
+// removed.
+type Item struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	Name      name.Name
+	Price     money.Money
+	Quantity  quantity.Quantity
+	Total     money.Money
+}
+
+// NewOrder is what we require to place an order.
+type NewOrder struct {
+	UserID uuid.UUID
+	Items  []NewItem
+}
+
+// NewItem is what we require to order a product.
+type NewItem struct {
+	ProductID uuid.UUID
+	Quantity  quantity.Quantity
+}
+
+// UpdateStatus is what we require to change the status of an order, the
+// actor is recorded in the audit.
+type UpdateStatus struct {
+	Status  Status
+	ActorID uuid.UUID
+}
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ID               *uuid.UUID
+	UserID           *uuid.UUID
+	Status           *Status
+	StartCreatedDate *time.Time
+	EndCreatedDate   *time.Time
+}
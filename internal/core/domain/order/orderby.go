@@ -0,0 +1,15 @@
+package order
+
+import "github.com/Housiadas/backend-system/pkg/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByID          = "a"
+	OrderByUserID      = "b"
+	OrderByStatus      = "c"
+	OrderByTotal       = "d"
+	OrderByDateCreated = "e"
+)
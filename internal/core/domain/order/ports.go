@@ -0,0 +1,23 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data.
+type Storer interface {
+	NewWithTx(tx pgsql.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, ord Order) error
+	UpdateStatus(ctx context.Context, ord Order, to Status, now time.Time) error
+	QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Order, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+}
@@ -0,0 +1,68 @@
+package order
+
+import "fmt"
+
+// The set of statuses of an order.
+var (
+	Pending   = newStatus("PENDING")
+	Paid      = newStatus("PAID")
+	Shipped   = newStatus("SHIPPED")
+	Cancelled = newStatus("CANCELLED")
+)
+
+// transitions holds the statuses an order may move to from each status. A
+// shipped order can't be cancelled anymore.
+var transitions = map[Status][]Status{
+	Pending: {Paid, Cancelled},
+	Paid:    {Shipped, Cancelled},
+}
+
+// Set of known statuses.
+var statuses = make(map[string]Status)
+
+// Status represents the status of an order.
+type Status struct {
+	value string
+}
+
+func newStatus(status string) Status {
+	s := Status{status}
+	statuses[status] = s
+	return s
+}
+
+// String returns the name of the status.
+func (s Status) String() string {
+	return s.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (s Status) Equal(s2 Status) bool {
+	return s.value == s2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.value), nil
+}
+
+// CanMoveTo reports whether an order may move from the status to the other.
+func (s Status) CanMoveTo(s2 Status) bool {
+	for _, to := range transitions[s] {
+		if to.Equal(s2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseStatus parses the string value and returns a status if one exists.
+func ParseStatus(value string) (Status, error) {
+	status, exists := statuses[value]
+	if !exists {
+		return Status{}, fmt.Errorf("invalid status %q", value)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,27 @@
+package order
+
+import "testing"
+
+func Test_CanMoveTo(t *testing.T) {
+	tests := []struct {
+		from Status
+		to   Status
+		exp  bool
+	}{
+		{from: Pending, to: Paid, exp: true},
+		{from: Pending, to: Cancelled, exp: true},
+		{from: Pending, to: Shipped},
+		{from: Paid, to: Shipped, exp: true},
+		{from: Paid, to: Cancelled, exp: true},
+		{from: Paid, to: Pending},
+		{from: Shipped, to: Cancelled},
+		{from: Cancelled, to: Pending},
+		{from: Cancelled, to: Paid},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanMoveTo(tt.to); got != tt.exp {
+			t.Errorf("%s to %s: got %t, exp %t", tt.from, tt.to, got, tt.exp)
+		}
+	}
+}
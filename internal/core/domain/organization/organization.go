@@ -0,0 +1,36 @@
+// Package organization represents the organizations, the tenants the users,
+// products and audit records belong to.
+package organization
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound   = errors.New("organization not found")
+	ErrUniqueName = errors.New("organization name already exists")
+)
+
+// DefaultID is the organization created along with the table, the data that
+// existed before the organizations belongs to it. It is also the organization
+// of the data created outside a tenant, like by the command line.
+var DefaultID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Organization represents a tenant of the system.
+type Organization struct {
+	ID          uuid.UUID
+	Name        name.Name
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewOrganization contains information needed to create a new organization.
+type NewOrganization struct {
+	Name name.Name
+}
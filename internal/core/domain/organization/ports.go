@@ -0,0 +1,17 @@
+package organization
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+// Storer interface declares the behavior this package needs to persist and retrieve data.
+type Storer interface {
+	Create(ctx context.Context, org Organization) error
+	Query(ctx context.Context, page page.Page) ([]Organization, error)
+	Count(ctx context.Context) (int, error)
+	QueryByID(ctx context.Context, orgID uuid.UUID) (Organization, error)
+}
@@ -0,0 +1,67 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+// ErrSuperAdminRole is returned for a caller scoped to an organization
+// granting the super admin role, it would let a tenant reach the others.
+var ErrSuperAdminRole = errors.New("super admin role can't be granted within an organization")
+
+type ctxKey int
+
+const scopeKey ctxKey = 1
+
+// WithScope returns a context scoped to the organization, the stores only
+// read and change the data of the organization with it.
+func WithScope(ctx context.Context, orgID uuid.UUID) context.Context {
+	return context.WithValue(ctx, scopeKey, orgID)
+}
+
+// Scoped returns the organization the context is scoped to. A context that is
+// not scoped, like the one of a super admin or of an internal call, reaches
+// the data of every organization.
+func Scoped(ctx context.Context) (uuid.UUID, bool) {
+	orgID, ok := ctx.Value(scopeKey).(uuid.UUID)
+	return orgID, ok
+}
+
+// ScopeID returns the organization the context is scoped to as a query
+// parameter, nil when it is not scoped.
+func ScopeID(ctx context.Context) *uuid.UUID {
+	if orgID, ok := Scoped(ctx); ok {
+		return &orgID
+	}
+	return nil
+}
+
+// Resolve returns the organization new data belongs to: the one of the
+// scope, else the one given, else the default organization. A scoped caller
+// can't create data in another organization.
+func Resolve(ctx context.Context, orgID uuid.UUID) uuid.UUID {
+	if scoped, ok := Scoped(ctx); ok {
+		return scoped
+	}
+
+	if orgID != uuid.Nil {
+		return orgID
+	}
+
+	return DefaultID
+}
+
+// CheckRoles refuses the super admin role to a caller scoped to an
+// organization.
+func CheckRoles(ctx context.Context, roles []role.Role) error {
+	if _, ok := Scoped(ctx); ok && slices.Contains(roles, role.SuperAdmin) {
+		return ErrSuperAdminRole
+	}
+
+	return nil
+}
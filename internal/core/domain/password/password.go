@@ -0,0 +1,103 @@
+// Package password represents the policy a new password must satisfy.
+package password
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Set of error variables for the password policy.
+var (
+	ErrTooShort = errors.New("password too short")
+	ErrTooLong  = errors.New("password too long")
+	ErrClasses  = errors.New("password is missing a required character class")
+	ErrBanned   = errors.New("password is too common")
+)
+
+// Policy represents the rules a new password must satisfy. The zero value
+// accepts every password. Banned holds the lowercased passwords refused.
+type Policy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	Banned        map[string]struct{}
+}
+
+// Check reports the first rule of the policy the password breaks. The length
+// is counted in characters, not bytes.
+func (p Policy) Check(password string) error {
+	n := len([]rune(password))
+
+	if p.MinLength > 0 && n < p.MinLength {
+		return fmt.Errorf("%w: minimum %d characters", ErrTooShort, p.MinLength)
+	}
+
+	if p.MaxLength > 0 && n > p.MaxLength {
+		return fmt.Errorf("%w: maximum %d characters", ErrTooLong, p.MaxLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r), unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	var missing []string
+	if p.RequireUpper && !upper {
+		missing = append(missing, "uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		missing = append(missing, "lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		missing = append(missing, "digit")
+	}
+	if p.RequireSymbol && !symbol {
+		missing = append(missing, "symbol")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrClasses, strings.Join(missing, ", "))
+	}
+
+	if _, exists := p.Banned[strings.ToLower(password)]; exists {
+		return ErrBanned
+	}
+
+	return nil
+}
+
+// ReadBanned reads a list of banned passwords, one per line. Empty lines and
+// lines starting with # are skipped.
+func ReadBanned(r io.Reader) (map[string]struct{}, error) {
+	banned := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		banned[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading banned passwords: %w", err)
+	}
+
+	return banned, nil
+}
@@ -0,0 +1,46 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_Check(t *testing.T) {
+	banned, err := ReadBanned(strings.NewReader("# common\nPassword1!\n\nletmein\n"))
+	if err != nil {
+		t.Fatalf("Should be able to read the banned passwords: %s", err)
+	}
+
+	p := Policy{
+		MinLength:    8,
+		MaxLength:    64,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+		Banned:       banned,
+	}
+
+	tt := []struct {
+		password string
+		exp      error
+	}{
+		{"Short1", ErrTooShort},
+		{strings.Repeat("Aa1", 22), ErrTooLong},
+		{"alllowercase1", ErrClasses},
+		{"NoDigitsHere", ErrClasses},
+		{"PASSWORD1!", ErrClasses},
+		{"PassWord1!", ErrBanned},
+		{"Corr3ct horse", nil},
+	}
+
+	for _, tc := range tt {
+		if err := p.Check(tc.password); !errors.Is(err, tc.exp) {
+			t.Errorf("%q: got %v, exp %v", tc.password, err, tc.exp)
+		}
+	}
+
+	if err := (Policy{}).Check("1"); err != nil {
+		t.Fatalf("Should accept every password with the zero policy: %s", err)
+	}
+}
@@ -0,0 +1,29 @@
+// Package passwordreset represents the single use tokens letting a user set a
+// new password without knowing the current one.
+package passwordreset
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for password reset operations.
+var (
+	ErrNotFound     = errors.New("password reset not found")
+	ErrInvalidToken = errors.New("invalid reset token")
+	ErrExpired      = errors.New("reset token expired")
+	ErrUsed         = errors.New("reset token already used")
+)
+
+// Reset represents a password reset requested by a user. A reset is backed by
+// a token sent to the user, only the hash of the token is stored.
+type Reset struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	TokenHash   string
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+	DateCreated time.Time
+}
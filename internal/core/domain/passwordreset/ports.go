@@ -0,0 +1,16 @@
+package passwordreset
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Storer interface declares the behavior this package needs to persist and retrieve data.
+type Storer interface {
+	Create(ctx context.Context, r Reset) error
+	Use(ctx context.Context, resetID uuid.UUID, usedAt time.Time) error
+	UseByUserID(ctx context.Context, userID uuid.UUID, usedAt time.Time) error
+	QueryByID(ctx context.Context, resetID uuid.UUID) (Reset, error)
+}
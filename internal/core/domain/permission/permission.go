@@ -0,0 +1,95 @@
+// Package permission represents the permission type in the system.
+package permission
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// The set of permissions checked by the system. Roles can be granted any
+// permission in the form resource:action.
+var (
+	UserRead     = MustParse("user:read")
+	UserWrite    = MustParse("user:write")
+	ProductRead  = MustParse("product:read")
+	ProductWrite = MustParse("product:write")
+	AuditRead    = MustParse("audit:read")
+	RoleRead     = MustParse("role:read")
+	RoleWrite    = MustParse("role:write")
+
+	ServiceAccountRead  = MustParse("service_account:read")
+	ServiceAccountWrite = MustParse("service_account:write")
+
+	OrganizationRead  = MustParse("organization:read")
+	OrganizationWrite = MustParse("organization:write")
+
+	SecurityEventRead = MustParse("security_event:read")
+)
+
+var permissionRegEx = regexp.MustCompile("^[a-z][a-z_]{1,31}:[a-z][a-z_]{1,31}$")
+
+// =============================================================================
+
+// Permission represents a permission in the system.
+type Permission struct {
+	value string
+}
+
+// String returns the value of the permission.
+func (p Permission) String() string {
+	return p.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (p Permission) Equal(p2 Permission) bool {
+	return p.value == p2.value
+}
+
+// =============================================================================
+
+// Parse parses the string value and returns a permission if the value
+// complies with the resource:action form.
+func Parse(value string) (Permission, error) {
+	if !permissionRegEx.MatchString(value) {
+		return Permission{}, fmt.Errorf("invalid permission %q", value)
+	}
+
+	return Permission{value}, nil
+}
+
+// MustParse parses the string value and returns a permission if the value
+// complies with the rules. If an error occurs, the function panics.
+func MustParse(value string) Permission {
+	p, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// ParseToString takes a collection of permissions and converts them to
+// a slice of string.
+func ParseToString(perms []Permission) []string {
+	values := make([]string, len(perms))
+	for i, p := range perms {
+		values[i] = p.String()
+	}
+
+	return values
+}
+
+// ParseMany takes a collection of strings and converts them to a slice
+// of permissions.
+func ParseMany(values []string) ([]Permission, error) {
+	perms := make([]Permission, len(values))
+	for i, value := range values {
+		p, err := Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		perms[i] = p
+	}
+
+	return perms, nil
+}
@@ -0,0 +1,13 @@
+package price
+
+import "github.com/Housiadas/backend-system/pkg/order"
+
+// DefaultOrderBy represents the default way we sort, the latest first.
+var DefaultOrderBy = order.NewBy(OrderByEffectiveFrom, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByEffectiveFrom = "a"
+	OrderByCost          = "b"
+	OrderByDateCreated   = "c"
+)
@@ -0,0 +1,26 @@
+package price
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data. Applying a price sets the cost of the product and marks the
+// price applied at once, it fails with ErrAlreadyApplied when it was applied
+// in the meantime.
+type Storer interface {
+	NewWithTx(tx pgsql.CommitRollbacker) (Storer, error)
+	Create(ctx context.Context, pr Price) error
+	Apply(ctx context.Context, pr Price, now time.Time) error
+	QueryAt(ctx context.Context, productID uuid.UUID, at time.Time) (Price, error)
+	QueryDue(ctx context.Context, now time.Time, limit int) ([]Price, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Price, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+}
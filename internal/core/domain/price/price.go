@@ -0,0 +1,55 @@
+// Package price represents the history of the prices of the products. Every
+// cost a product had is kept with the time it took effect from, and a price
+// may be scheduled to take effect later on.
+package price
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+)
+
+// Set of error variables for price operations.
+var (
+	ErrNotFound       = errors.New("price not found")
+	ErrInvalidCost    = errors.New("cost not valid")
+	ErrNotInFuture    = errors.New("effective date not in the future")
+	ErrAlreadyApplied = errors.New("price already applied")
+)
+
+// Price represents the cost of a product from the time it takes effect until
+// the next price of the product does. A scheduled price is applied to the
+// product once due, the date applied is zero until then.
+type Price struct {
+	ID            uuid.UUID
+	OrgID         uuid.UUID
+	ProductID     uuid.UUID
+	Cost          money.Money
+	EffectiveFrom time.Time
+	DateApplied   time.Time
+	DateCreated   time.Time
+}
+
+// IsApplied reports whether the cost of the product was set to the price.
+func (p Price) IsApplied() bool {
+	return !p.DateApplied.IsZero()
+}
+
+// NewPrice is what we require to schedule a price of a product.
+type NewPrice struct {
+	ProductID     uuid.UUID
+	Cost          money.Money
+	EffectiveFrom time.Time
+}
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ProductID          *uuid.UUID
+	Applied            *bool
+	StartEffectiveDate *time.Time
+	EndEffectiveDate   *time.Time
+}
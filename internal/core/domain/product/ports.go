@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/quantity"
 	"github.com/Housiadas/backend-system/pkg/order"
 	"github.com/Housiadas/backend-system/pkg/page"
 	"github.com/Housiadas/backend-system/pkg/pgsql"
@@ -14,7 +15,7 @@ import (
 type Storer interface {
 	NewWithTx(tx pgsql.CommitRollbacker) (Storer, error)
 	Create(ctx context.Context, prd Product) error
-	Update(ctx context.Context, prd Product) error
+	Update(ctx context.Context, prd Product, qnt *quantity.Quantity) (Product, error)
 	Delete(ctx context.Context, prd Product) error
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Product, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
@@ -2,6 +2,7 @@ package product
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,9 +24,32 @@ var (
 	ErrInvalidCost  = errors.New("cost not valid")
 )
 
+// maxCostDigits is the number of digits of the whole part of the costs the
+// products and their prices hold, as NUMERIC(19, 4).
+const maxCostDigits = 15
+
+// CheckCost checks the cost is not negative and fits in the products.
+func CheckCost(cost money.Money) error {
+	if cost.IsNegative() {
+		return fmt.Errorf("%w: must be 0 or greater", ErrInvalidCost)
+	}
+
+	limit := int64(1)
+	for range maxCostDigits + cost.Currency().Digits() {
+		limit *= 10
+	}
+
+	if cost.Amount() >= limit {
+		return fmt.Errorf("%w: must be less than 1e%d", ErrInvalidCost, maxCostDigits)
+	}
+
+	return nil
+}
+
 // Product represents an individual product.
 type Product struct {
 	ID          uuid.UUID
+	OrgID       uuid.UUID
 	UserID      uuid.UUID
 	Name        name.Name
 	Cost        money.Money
@@ -59,6 +83,7 @@ type UpdateProduct struct {
 type QueryFilter struct {
 	ID       *uuid.UUID
 	Name     *name.Name
-	Cost     *float64
+	Cost     *money.Money
+	Currency *money.Currency
 	Quantity *int
 }
@@ -5,6 +5,9 @@ import (
 	"fmt"
 )
 
+// Max is the largest quantity.
+const Max = 1_000_000
+
 // Quantity represents a quantity in the system.
 type Quantity struct {
 	value int
@@ -30,7 +33,7 @@ func (q Quantity) Equal(q2 Quantity) bool {
 // Parse parses the float value and returns a quantity if the value complies
 // with the rules for quantity.
 func Parse(value int) (Quantity, error) {
-	if value < 0 || value > 1_000_000 {
+	if value < 0 || value > Max {
 		return Quantity{}, fmt.Errorf("invalid quantity %d", value)
 	}
 
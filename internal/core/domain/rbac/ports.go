@@ -0,0 +1,18 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+// Storer interface declares the behavior this package needs to persist and retrieve data.
+type Storer interface {
+	Create(ctx context.Context, r Role) error
+	Update(ctx context.Context, r Role) error
+	Delete(ctx context.Context, r Role) error
+	Query(ctx context.Context) ([]Role, error)
+	QueryByName(ctx context.Context, name role.Role) (Role, error)
+	QueryPermissions(ctx context.Context, roles []role.Role) ([]permission.Permission, error)
+}
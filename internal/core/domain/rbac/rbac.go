@@ -0,0 +1,39 @@
+package rbac
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+// Set of error variables for role operations.
+var (
+	ErrNotFound   = errors.New("role not found")
+	ErrUniqueName = errors.New("role already exists")
+	ErrBuiltIn    = errors.New("built-in role can't be deleted")
+)
+
+// Role represents a role along with the permissions granted to it.
+type Role struct {
+	Name        role.Role
+	Description string
+	Permissions []permission.Permission
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewRole contains information needed to create a new role.
+type NewRole struct {
+	Name        role.Role
+	Description string
+	Permissions []permission.Permission
+}
+
+// UpdateRole contains information needed to update a role. Fields that are
+// not set (nil) are left unchanged, the permissions replace the current set.
+type UpdateRole struct {
+	Description *string
+	Permissions []permission.Permission
+}
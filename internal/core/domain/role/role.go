@@ -1,18 +1,44 @@
 // Package role represents the role type in the system.
 package role
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
 
-// The set of roles that can be used.
+// The set of built-in roles, these always exist and can't be removed.
 var (
-	Admin = newRole("ADMIN")
-	User  = newRole("USER")
+	Admin      = newRole("ADMIN")
+	SuperAdmin = newRole("SUPER_ADMIN")
+	Manager    = newRole("MANAGER")
+	User       = newRole("USER")
 )
 
+var roleRegEx = regexp.MustCompile("^[A-Z][A-Z0-9_]{1,31}$")
+
 // =============================================================================
 
-// Set of known roles.
-var roles = make(map[string]Role)
+// Set of known roles. Roles created at runtime are added with Register, the
+// lookup resolves the ones unknown to this instance of the service, like a
+// role created by another instance. The names the lookup doesn't find are
+// not looked up again for missTTL.
+var (
+	mu       sync.RWMutex
+	roles    = make(map[string]Role)
+	builtins = make(map[string]Role)
+	lookup   func(value string) bool
+	misses   = make(map[string]time.Time)
+)
+
+// The names not found are remembered briefly, a role created by another
+// instance in the meantime is found once they are forgotten. Past maxMisses
+// they are all forgotten, so unknown names can't grow the set for ever.
+const (
+	missTTL   = 10 * time.Second
+	maxMisses = 1024
+)
 
 // Role represents a role in the system.
 type Role struct {
@@ -22,6 +48,7 @@ type Role struct {
 func newRole(role string) Role {
 	r := Role{role}
 	roles[role] = r
+	builtins[role] = r
 	return r
 }
 
@@ -35,15 +62,87 @@ func (r Role) Equal(r2 Role) bool {
 	return r.value == r2.value
 }
 
+// IsBuiltIn reports whether the role is one of the built-in roles.
+func (r Role) IsBuiltIn() bool {
+	_, exists := builtins[r.value]
+	return exists
+}
+
 // =============================================================================
 
+// New validates the value as a role name without adding it to the set of
+// known roles.
+func New(value string) (Role, error) {
+	if !roleRegEx.MatchString(value) {
+		return Role{}, fmt.Errorf("invalid role %q", value)
+	}
+
+	return Role{value}, nil
+}
+
+// Register adds a role created at runtime to the set of known roles so it
+// can be parsed.
+func Register(r Role) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	roles[r.value] = r
+	delete(misses, r.value)
+}
+
+// Unregister removes a role created at runtime from the set of known roles.
+// The built-in roles are never removed.
+func Unregister(r Role) {
+	if r.IsBuiltIn() {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	delete(roles, r.value)
+}
+
+// SetLookup sets how a role unknown to this instance is resolved. A role the
+// lookup finds is registered, so it is only looked up once.
+func SetLookup(fn func(value string) bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	lookup = fn
+	clear(misses)
+}
+
 // Parse parses the string value and returns a role if one exists.
 func Parse(value string) (Role, error) {
+	mu.RLock()
 	role, exists := roles[value]
-	if !exists {
+	fn := lookup
+	missed, isMiss := misses[value]
+	mu.RUnlock()
+
+	if exists {
+		return role, nil
+	}
+
+	if fn == nil || !roleRegEx.MatchString(value) || (isMiss && time.Since(missed) < missTTL) {
+		return Role{}, fmt.Errorf("invalid role %q", value)
+	}
+
+	if !fn(value) {
+		mu.Lock()
+		if len(misses) >= maxMisses {
+			clear(misses)
+		}
+		misses[value] = time.Now()
+		mu.Unlock()
+
 		return Role{}, fmt.Errorf("invalid role %q", value)
 	}
 
+	role = Role{value}
+	Register(role)
+
 	return role, nil
 }
 
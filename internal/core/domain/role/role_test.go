@@ -0,0 +1,46 @@
+package role
+
+import "testing"
+
+func Test_ParseLookup(t *testing.T) {
+	if _, err := Parse("AUDITOR"); err == nil {
+		t.Fatal("Should not parse a role unknown without a lookup")
+	}
+
+	var lookups int
+	SetLookup(func(value string) bool {
+		lookups++
+		return value == "AUDITOR"
+	})
+	defer SetLookup(nil)
+
+	r, err := Parse("AUDITOR")
+	if err != nil {
+		t.Fatalf("Should parse a role created by another instance: %s", err)
+	}
+	defer Unregister(r)
+
+	if _, err := Parse("AUDITOR"); err != nil || lookups != 1 {
+		t.Fatalf("Should look a role up once: lookups %d: %v", lookups, err)
+	}
+
+	if _, err := Parse("UNKNOWN"); err == nil {
+		t.Fatal("Should not parse a role the lookup doesn't find")
+	}
+
+	if _, err := Parse("bad role"); err == nil || lookups != 2 {
+		t.Fatalf("Should not look up an invalid name: lookups %d: %v", lookups, err)
+	}
+
+	if _, err := Parse("UNKNOWN"); err == nil || lookups != 2 {
+		t.Fatalf("Should not look up a name just not found again: lookups %d: %v", lookups, err)
+	}
+
+	created := Role{"UNKNOWN"}
+	Register(created)
+	defer Unregister(created)
+
+	if _, err := Parse("UNKNOWN"); err != nil {
+		t.Fatalf("Should parse a role registered after it was not found: %s", err)
+	}
+}
@@ -0,0 +1,26 @@
+package securityevent
+
+import "context"
+
+type ctxKey int
+
+const clientKey ctxKey = 1
+
+// Client identifies where a request comes from, the http and grpc apis set
+// it for every request.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// WithClient sets the client of the request.
+func WithClient(ctx context.Context, c Client) context.Context {
+	return context.WithValue(ctx, clientKey, c)
+}
+
+// GetClient returns the client of the request, the zero value when none was
+// set, like for the command line.
+func GetClient(ctx context.Context) Client {
+	c, _ := ctx.Value(clientKey).(Client)
+	return c
+}
@@ -0,0 +1,15 @@
+package securityevent
+
+import "github.com/Housiadas/backend-system/pkg/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByTimestamp, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByTimestamp = "a"
+	OrderByKind      = "b"
+	OrderByActorID   = "c"
+	OrderByOutcome   = "d"
+	OrderByIP        = "e"
+)
@@ -0,0 +1,21 @@
+package securityevent
+
+import (
+	"context"
+
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+// Storer interface declares the behavior this package needs to persist and retrieve data.
+type Storer interface {
+	Create(ctx context.Context, event Event) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Event, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+}
+
+// Publisher declares the behavior for forwarding the events to another
+// system, like a SIEM.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
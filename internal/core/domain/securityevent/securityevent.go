@@ -0,0 +1,56 @@
+// Package securityevent represents the events relevant to the security of the
+// system, like the logins and the permission denials.
+package securityevent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of the outcomes of an event.
+const (
+	OutcomeSuccess = "SUCCESS"
+	OutcomeFailure = "FAILURE"
+)
+
+// Event represents a single security event. The actor is the user the event
+// is about, it is unknown for a failed login with an unknown email and the
+// Subject then holds the hash of the lockout key of the email presented.
+type Event struct {
+	ID        uuid.UUID
+	OrgID     uuid.UUID
+	Kind      Kind
+	ActorID   uuid.UUID
+	Subject   string
+	IP        string
+	UserAgent string
+	Outcome   string
+	Reason    string
+	Timestamp time.Time
+}
+
+// NewEvent represents the information needed to record an event. The ip
+// address and the user agent are read from the client of the context.
+type NewEvent struct {
+	Kind    Kind
+	ActorID uuid.UUID
+	Subject string
+	Outcome string
+	Reason  string
+
+	// OrgID is the organization the event belongs to, the one of the caller
+	// is used when the context is scoped to one.
+	OrgID uuid.UUID
+}
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	Kind    *Kind
+	ActorID *uuid.UUID
+	Outcome *string
+	IP      *string
+	Since   *time.Time
+	Until   *time.Time
+}
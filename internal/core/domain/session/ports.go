@@ -0,0 +1,20 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Storer interface declares the behavior this package needs to persist and retrieve data.
+type Storer interface {
+	Create(ctx context.Context, sess Session) error
+	Update(ctx context.Context, sess Session, oldHash string) error
+	Revoke(ctx context.Context, sess Session) error
+	RevokeByUserID(ctx context.Context, userID uuid.UUID) error
+	QueryByID(ctx context.Context, sessionID uuid.UUID) (Session, error)
+	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Session, error)
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
@@ -0,0 +1,34 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for session operations.
+var (
+	ErrNotFound     = errors.New("session not found")
+	ErrRevoked      = errors.New("session revoked")
+	ErrExpired      = errors.New("session expired")
+	ErrInvalidToken = errors.New("invalid refresh token")
+)
+
+// Session represents a server side login session. A session is backed by a
+// refresh token, only the hash of the token is stored.
+type Session struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	TokenHash   string
+	Revoked     bool
+	ExpiresAt   time.Time
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewSession contains information needed to create a new session.
+type NewSession struct {
+	UserID    uuid.UUID
+	ExpiresAt time.Time
+}
@@ -0,0 +1,52 @@
+package stock
+
+import "fmt"
+
+// The set of kinds of movements. Reserve is the movement taking the stock of
+// a reservation.
+var (
+	Receipt    = newKind("RECEIPT")
+	Adjustment = newKind("ADJUSTMENT")
+	Sale       = newKind("SALE")
+	Reserve    = newKind("RESERVATION")
+	Release    = newKind("RELEASE")
+)
+
+// Set of known kinds.
+var kinds = make(map[string]Kind)
+
+// Kind represents the kind of stock movement.
+type Kind struct {
+	value string
+}
+
+func newKind(kind string) Kind {
+	k := Kind{kind}
+	kinds[kind] = k
+	return k
+}
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	return k.value
+}
+
+// Equal provides support for the go-cmp package and testing.
+func (k Kind) Equal(k2 Kind) bool {
+	return k.value == k2.value
+}
+
+// MarshalText provides support for logging and any marshal needs.
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.value), nil
+}
+
+// ParseKind parses the string value and returns a kind if one exists.
+func ParseKind(value string) (Kind, error) {
+	kind, exists := kinds[value]
+	if !exists {
+		return Kind{}, fmt.Errorf("invalid kind %q", value)
+	}
+
+	return kind, nil
+}
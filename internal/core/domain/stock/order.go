@@ -0,0 +1,13 @@
+package stock
+
+import "github.com/Housiadas/backend-system/pkg/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Set of fields that the results can be ordered by.
+const (
+	OrderByDateCreated = "a"
+	OrderByKind        = "b"
+	OrderByDelta       = "c"
+)
@@ -0,0 +1,28 @@
+package stock
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// Storer interface declares the behavior this package needs to persist and
+// retrieve data. Every change of the stock updates the quantity of the
+// product and records the movement at once, it fails with
+// ErrInsufficientStock when the quantity would drop below zero.
+type Storer interface {
+	NewWithTx(tx pgsql.CommitRollbacker) (Storer, error)
+	Move(ctx context.Context, mov Movement) (Movement, error)
+	Reserve(ctx context.Context, res Reservation, mov Movement) (Movement, error)
+	Commit(ctx context.Context, res Reservation, now time.Time) error
+	Release(ctx context.Context, res Reservation, mov Movement) (Movement, error)
+	QueryReservationByID(ctx context.Context, reservationID uuid.UUID) (Reservation, error)
+	QueryExpired(ctx context.Context, now time.Time, limit int) ([]Reservation, error)
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Movement, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+}
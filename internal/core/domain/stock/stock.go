@@ -0,0 +1,74 @@
+// Package stock represents the stock of the products as a ledger of the
+// movements that changed it. The quantity of a product is the stock available,
+// the sum of its movements, and it never drops below zero.
+package stock
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of error variables for stock operations.
+var (
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrInvalidMovement     = errors.New("movement not valid")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationExpired  = errors.New("reservation expired")
+	ErrReservationClosed   = errors.New("reservation already committed or released")
+)
+
+// Movement represents a change of the stock of a product. The delta is
+// positive when stock is added, and the quantity is the stock available right
+// after the movement. A reservation and its release refer to the
+// reservation.
+type Movement struct {
+	ID            uuid.UUID
+	OrgID         uuid.UUID
+	ProductID     uuid.UUID
+	ReservationID uuid.UUID
+	Kind          Kind
+	Delta         int
+	Quantity      int
+	DateCreated   time.Time
+}
+
+// NewMovement is what we require to receive, adjust or sell stock. The
+// quantity is always positive for a receipt or a sale, and an adjustment adds
+// it or, when negative, removes it.
+type NewMovement struct {
+	ProductID uuid.UUID
+	Kind      Kind
+	Quantity  int
+}
+
+// Reservation represents stock set aside for a buyer until it expires. The
+// stock is taken from the product when reserved, a release gives it back and
+// a commit keeps it as sold.
+type Reservation struct {
+	ID          uuid.UUID
+	OrgID       uuid.UUID
+	ProductID   uuid.UUID
+	Quantity    int
+	Status      Status
+	ExpiresAt   time.Time
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewReservation is what we require to reserve stock. The reservation
+// expires after the ttl, the default one when zero.
+type NewReservation struct {
+	ProductID uuid.UUID
+	Quantity  int
+	TTL       time.Duration
+}
+
+// QueryFilter holds the available fields a query can be filtered on.
+// We are using pointer semantics because the With API mutates the value.
+type QueryFilter struct {
+	ProductID     *uuid.UUID
+	ReservationID *uuid.UUID
+	Kind          *Kind
+}
@@ -5,11 +5,10 @@ import "github.com/Housiadas/backend-system/pkg/order"
 // DefaultOrderBy represents the default way we sort.
 var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
 
-// Set of fields that the results can be ordered by.
+// Set of fields that the results can be ordered by. The name and the email
+// are encrypted, so the results can't be ordered by them.
 const (
 	OrderByID      = "user_id"
-	OrderByName    = "name"
-	OrderByEmail   = "email"
 	OrderByRoles   = "roles"
 	OrderByEnabled = "enabled"
 )
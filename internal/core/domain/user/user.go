@@ -21,6 +21,7 @@ var (
 // User represents information about an individual user.
 type User struct {
 	ID           uuid.UUID
+	OrgID        uuid.UUID
 	Name         name.Name
 	Email        mail.Address
 	Roles        []role.Role
@@ -29,6 +30,14 @@ type User struct {
 	Enabled      bool
 	DateCreated  time.Time
 	DateUpdated  time.Time
+
+	// EmailVerified reports the user proved to own the email. It is reset
+	// when the email changes.
+	EmailVerified bool
+
+	// TokensValidAfter rejects the tokens issued before it, it is set when
+	// the password is reset. The zero value accepts every token.
+	TokensValidAfter time.Time
 }
 
 // NewUser contains information needed to create a new user.
@@ -38,6 +47,14 @@ type NewUser struct {
 	Roles      []role.Role
 	Department name.Null
 	Password   string
+
+	// OrgID is the organization of the user, the one of the caller is used
+	// when the context is scoped to one.
+	OrgID uuid.UUID
+
+	// EmailVerified is set for a user whose email is already proven, like
+	// one accepting an invitation sent to it.
+	EmailVerified bool
 }
 
 // UpdateUser contains information needed to update a user.
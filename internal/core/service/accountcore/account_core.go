@@ -0,0 +1,244 @@
+// Package accountcore provides internal access to the accounts of the users
+// and the transfers of money between them.
+package accountcore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/otel"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// Core manages the set of APIs for account access.
+type Core struct {
+	log     *logger.Logger
+	userBus *usercore.Core
+	storer  account.Storer
+}
+
+// NewCore constructs an account internal API for use.
+func NewCore(log *logger.Logger, userBus *usercore.Core, storer account.Storer) *Core {
+	return &Core{
+		log:     log,
+		userBus: userBus,
+		storer:  storer,
+	}
+}
+
+// NewWithTx constructs a new internal value that will use the
+// specified transaction in any store-related calls.
+func (c *Core) NewWithTx(tx pgsql.CommitRollbacker) (*Core, error) {
+	storer, err := c.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := c.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	core := Core{
+		log:     c.log,
+		userBus: userBus,
+		storer:  storer,
+	}
+
+	return &core, nil
+}
+
+// Create opens a new account with no money for the user.
+func (c *Core) Create(ctx context.Context, na account.NewAccount) (account.Account, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.create")
+	defer span.End()
+
+	usr, err := c.userBus.QueryByID(ctx, na.OwnerID)
+	if err != nil {
+		return account.Account{}, fmt.Errorf("user.querybyid: %s: %w", na.OwnerID, err)
+	}
+
+	if !usr.Enabled {
+		return account.Account{}, account.ErrUserDisabled
+	}
+
+	now := time.Now()
+
+	acct := account.Account{
+		ID:          uuid.New(),
+		OrgID:       usr.OrgID,
+		OwnerID:     na.OwnerID,
+		Balance:     money.Zero(na.Currency),
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, acct); err != nil {
+		return account.Account{}, fmt.Errorf("create: %w", err)
+	}
+
+	return acct, nil
+}
+
+// Transfer moves money between two accounts of the same currency and
+// organization, and returns the debit and the credit entries.
+func (c *Core) Transfer(ctx context.Context, nt account.NewTransfer) (account.Transfer, []account.Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.transfer")
+	defer span.End()
+
+	if nt.FromAccountID == nt.ToAccountID {
+		return account.Transfer{}, nil, account.ErrSameAccount
+	}
+
+	if nt.Amount.IsZero() || nt.Amount.IsNegative() {
+		return account.Transfer{}, nil, account.ErrInvalidAmount
+	}
+
+	from, err := c.storer.QueryByID(ctx, nt.FromAccountID)
+	if err != nil {
+		return account.Transfer{}, nil, fmt.Errorf("query: fromAccountID[%s]: %w", nt.FromAccountID, err)
+	}
+
+	to, err := c.storer.QueryByID(ctx, nt.ToAccountID)
+	if err != nil {
+		return account.Transfer{}, nil, fmt.Errorf("query: toAccountID[%s]: %w", nt.ToAccountID, err)
+	}
+
+	return c.transfer(ctx, from, to, nt.Amount)
+}
+
+// Deposit credits the account with money from the clearing account of its
+// currency.
+func (c *Core) Deposit(ctx context.Context, acct account.Account, amount money.Money) (account.Transfer, []account.Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.deposit")
+	defer span.End()
+
+	clearing, err := c.storer.QueryClearing(ctx, acct.OrgID, acct.Balance.Currency(), time.Now())
+	if err != nil {
+		return account.Transfer{}, nil, fmt.Errorf("query clearing: %w", err)
+	}
+
+	return c.transfer(ctx, clearing, acct, amount)
+}
+
+// Withdraw debits the account, the money goes to the clearing account of its
+// currency.
+func (c *Core) Withdraw(ctx context.Context, acct account.Account, amount money.Money) (account.Transfer, []account.Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.withdraw")
+	defer span.End()
+
+	clearing, err := c.storer.QueryClearing(ctx, acct.OrgID, acct.Balance.Currency(), time.Now())
+	if err != nil {
+		return account.Transfer{}, nil, fmt.Errorf("query clearing: %w", err)
+	}
+
+	return c.transfer(ctx, acct, clearing, amount)
+}
+
+func (c *Core) transfer(ctx context.Context, from account.Account, to account.Account, amount money.Money) (account.Transfer, []account.Entry, error) {
+	if amount.IsZero() || amount.IsNegative() {
+		return account.Transfer{}, nil, account.ErrInvalidAmount
+	}
+
+	if !from.Balance.Currency().Equal(amount.Currency()) || !to.Balance.Currency().Equal(amount.Currency()) {
+		return account.Transfer{}, nil, account.ErrCurrencyMismatch
+	}
+
+	// The accounts of another organization are never reached.
+	if from.OrgID != to.OrgID {
+		return account.Transfer{}, nil, fmt.Errorf("toAccountID[%s]: %w", to.ID, account.ErrNotFound)
+	}
+
+	tr := account.Transfer{
+		ID:            uuid.New(),
+		OrgID:         from.OrgID,
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        amount,
+		DateCreated:   time.Now(),
+	}
+
+	entries, err := c.storer.Transfer(ctx, tr)
+	if err != nil {
+		return account.Transfer{}, nil, fmt.Errorf("transfer: fromAccountID[%s] toAccountID[%s]: %w", from.ID, to.ID, err)
+	}
+
+	return tr, entries, nil
+}
+
+// QueryByID finds the account by the specified ID.
+func (c *Core) QueryByID(ctx context.Context, accountID uuid.UUID) (account.Account, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.querybyid")
+	defer span.End()
+
+	acct, err := c.storer.QueryByID(ctx, accountID)
+	if err != nil {
+		return account.Account{}, fmt.Errorf("query: accountID[%s]: %w", accountID, err)
+	}
+
+	return acct, nil
+}
+
+// BalanceAt returns the balance the account had at the time.
+func (c *Core) BalanceAt(ctx context.Context, acct account.Account, at time.Time) (money.Money, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.balanceat")
+	defer span.End()
+
+	balance, err := c.storer.QueryBalanceAt(ctx, acct, at)
+	if err != nil {
+		return money.Money{}, fmt.Errorf("query balance: accountID[%s]: %w", acct.ID, err)
+	}
+
+	return balance, nil
+}
+
+// Query retrieves a list of existing accounts.
+func (c *Core) Query(ctx context.Context, filter account.QueryFilter, orderBy order.By, page page.Page) ([]account.Account, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.query")
+	defer span.End()
+
+	accts, err := c.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return accts, nil
+}
+
+// Count returns the total number of accounts.
+func (c *Core) Count(ctx context.Context, filter account.QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.count")
+	defer span.End()
+
+	return c.storer.Count(ctx, filter)
+}
+
+// QueryEntries retrieves the statement of an account, its entries.
+func (c *Core) QueryEntries(ctx context.Context, filter account.EntryFilter, orderBy order.By, page page.Page) ([]account.Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.queryentries")
+	defer span.End()
+
+	entries, err := c.storer.QueryEntries(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountEntries returns the total number of entries of an account.
+func (c *Core) CountEntries(ctx context.Context, filter account.EntryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.accountcore.countentries")
+	defer span.End()
+
+	return c.storer.CountEntries(ctx, filter)
+}
@@ -0,0 +1,242 @@
+package accountcore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/account"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+type memStore struct {
+	accounts map[uuid.UUID]account.Account
+	entries  []account.Entry
+}
+
+func newMemStore(accts ...account.Account) *memStore {
+	s := memStore{
+		accounts: make(map[uuid.UUID]account.Account),
+	}
+	for _, acct := range accts {
+		s.accounts[acct.ID] = acct
+	}
+
+	return &s
+}
+
+func (s *memStore) NewWithTx(_ pgsql.CommitRollbacker) (account.Storer, error) {
+	return s, nil
+}
+
+func (s *memStore) Create(_ context.Context, acct account.Account) error {
+	s.accounts[acct.ID] = acct
+	return nil
+}
+
+func (s *memStore) Transfer(_ context.Context, tr account.Transfer) ([]account.Entry, error) {
+	from, to := s.accounts[tr.FromAccountID], s.accounts[tr.ToAccountID]
+
+	debit, err := from.Balance.Sub(tr.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if debit.IsNegative() && !from.IsClearing() {
+		return nil, account.ErrInsufficientFunds
+	}
+
+	credit, err := to.Balance.Add(tr.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	from.Balance, to.Balance = debit, credit
+	s.accounts[from.ID], s.accounts[to.ID] = from, to
+
+	ents := []account.Entry{
+		{ID: uuid.New(), AccountID: from.ID, TransferID: tr.ID, Amount: money.New(-tr.Amount.Amount(), tr.Amount.Currency()), Balance: debit},
+		{ID: uuid.New(), AccountID: to.ID, TransferID: tr.ID, Amount: tr.Amount, Balance: credit},
+	}
+	s.entries = append(s.entries, ents...)
+
+	return ents, nil
+}
+
+func (s *memStore) QueryByID(_ context.Context, accountID uuid.UUID) (account.Account, error) {
+	acct, ok := s.accounts[accountID]
+	if !ok {
+		return account.Account{}, account.ErrNotFound
+	}
+
+	return acct, nil
+}
+
+func (s *memStore) QueryClearing(_ context.Context, orgID uuid.UUID, currency money.Currency, now time.Time) (account.Account, error) {
+	for _, acct := range s.accounts {
+		if acct.IsClearing() && acct.OrgID == orgID && acct.Balance.Currency().Equal(currency) {
+			return acct, nil
+		}
+	}
+
+	acct := account.Account{
+		ID:          uuid.New(),
+		OrgID:       orgID,
+		Balance:     money.Zero(currency),
+		DateCreated: now,
+		DateUpdated: now,
+	}
+	s.accounts[acct.ID] = acct
+
+	return acct, nil
+}
+
+func (s *memStore) QueryBalanceAt(_ context.Context, acct account.Account, _ time.Time) (money.Money, error) {
+	return s.accounts[acct.ID].Balance, nil
+}
+
+func (s *memStore) Query(_ context.Context, _ account.QueryFilter, _ order.By, _ page.Page) ([]account.Account, error) {
+	accts := make([]account.Account, 0, len(s.accounts))
+	for _, acct := range s.accounts {
+		accts = append(accts, acct)
+	}
+
+	return accts, nil
+}
+
+func (s *memStore) Count(_ context.Context, _ account.QueryFilter) (int, error) {
+	return len(s.accounts), nil
+}
+
+func (s *memStore) QueryEntries(_ context.Context, filter account.EntryFilter, _ order.By, _ page.Page) ([]account.Entry, error) {
+	var ents []account.Entry
+	for _, ent := range s.entries {
+		if ent.AccountID == filter.AccountID {
+			ents = append(ents, ent)
+		}
+	}
+
+	return ents, nil
+}
+
+func (s *memStore) CountEntries(ctx context.Context, filter account.EntryFilter) (int, error) {
+	ents, err := s.QueryEntries(ctx, filter, account.DefaultEntryOrderBy, page.Page{})
+	return len(ents), err
+}
+
+func newTestCore(store account.Storer) *Core {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", func(context.Context) string { return "" }, func(context.Context) string { return "" })
+
+	return NewCore(log, nil, store)
+}
+
+func newAccount(orgID uuid.UUID, balance money.Money) account.Account {
+	return account.Account{
+		ID:      uuid.New(),
+		OrgID:   orgID,
+		OwnerID: uuid.New(),
+		Balance: balance,
+	}
+}
+
+func Test_Transfer(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	usd := newAccount(orgID, money.MustParse("50", money.USD))
+	eur := newAccount(orgID, money.MustParse("50", money.EUR))
+	other := newAccount(uuid.New(), money.MustParse("50", money.USD))
+
+	tests := []struct {
+		name   string
+		from   account.Account
+		to     account.Account
+		amount money.Money
+		err    error
+	}{
+		{name: "transfer", from: usd, to: newAccount(orgID, money.Zero(money.USD)), amount: money.MustParse("20", money.USD)},
+		{name: "whole-balance", from: usd, to: newAccount(orgID, money.Zero(money.USD)), amount: money.MustParse("50", money.USD)},
+		{name: "overdraft", from: usd, to: newAccount(orgID, money.Zero(money.USD)), amount: money.MustParse("50.01", money.USD), err: account.ErrInsufficientFunds},
+		{name: "same-account", from: usd, to: usd, amount: money.MustParse("1", money.USD), err: account.ErrSameAccount},
+		{name: "zero", from: usd, to: newAccount(orgID, money.Zero(money.USD)), amount: money.Zero(money.USD), err: account.ErrInvalidAmount},
+		{name: "negative", from: usd, to: newAccount(orgID, money.Zero(money.USD)), amount: money.MustParse("-1", money.USD), err: account.ErrInvalidAmount},
+		{name: "currency", from: usd, to: eur, amount: money.MustParse("1", money.USD), err: account.ErrCurrencyMismatch},
+		{name: "organization", from: usd, to: other, amount: money.MustParse("1", money.USD), err: account.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemStore(tt.from, tt.to)
+			core := newTestCore(store)
+
+			_, ents, err := core.Transfer(ctx, account.NewTransfer{FromAccountID: tt.from.ID, ToAccountID: tt.to.ID, Amount: tt.amount})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Should get the expected error: got %v, exp %v", err, tt.err)
+			}
+
+			if tt.err != nil {
+				return
+			}
+
+			sum, err := ents[0].Amount.Add(ents[1].Amount)
+			if err != nil || !sum.IsZero() {
+				t.Fatalf("Should balance the entries: got %s %s", ents[0].Amount, ents[1].Amount)
+			}
+
+			exp, _ := tt.from.Balance.Sub(tt.amount)
+			if !store.accounts[tt.from.ID].Balance.Equal(exp) {
+				t.Fatalf("Should debit the account: got %s, exp %s", store.accounts[tt.from.ID].Balance, exp)
+			}
+		})
+	}
+}
+
+func Test_DepositWithdraw(t *testing.T) {
+	ctx := context.Background()
+
+	acct := newAccount(uuid.New(), money.Zero(money.USD))
+	store := newMemStore(acct)
+	core := newTestCore(store)
+
+	if _, _, err := core.Withdraw(ctx, acct, money.MustParse("1", money.USD)); !errors.Is(err, account.ErrInsufficientFunds) {
+		t.Fatalf("Should refuse to withdraw from an empty account: %v", err)
+	}
+
+	if _, _, err := core.Deposit(ctx, acct, money.MustParse("30", money.USD)); err != nil {
+		t.Fatalf("Should deposit: %s", err)
+	}
+
+	if _, _, err := core.Withdraw(ctx, acct, money.MustParse("10", money.USD)); err != nil {
+		t.Fatalf("Should withdraw: %s", err)
+	}
+
+	if _, _, err := core.Deposit(ctx, acct, money.MustParse("1", money.EUR)); !errors.Is(err, account.ErrCurrencyMismatch) {
+		t.Fatalf("Should refuse a deposit in another currency: %v", err)
+	}
+
+	// The money of the accounts, the clearing one included, always sums to
+	// zero.
+	sum := money.Zero(money.USD)
+	for _, a := range store.accounts {
+		var err error
+		if sum, err = sum.Add(a.Balance); err != nil {
+			t.Fatalf("Should add the balances: %s", err)
+		}
+	}
+
+	if !sum.IsZero() {
+		t.Fatalf("Should keep the money: got %s", sum)
+	}
+
+	if got := store.accounts[acct.ID].Balance.Decimal(); got != "20.00" {
+		t.Fatalf("Should hold the deposits less the withdrawals: got %s", got)
+	}
+}
@@ -0,0 +1,271 @@
+// Package apikeycore provides internal access to the service accounts and the
+// api keys they use for machine to machine access.
+package apikeycore
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/apikey"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/otel"
+)
+
+// keyPrefix marks a string as an api key issued by this service.
+const keyPrefix = "bsk"
+
+// lastUsedInterval limits how often the last use of a key is written, so a
+// busy key does not cause a write on every call.
+const lastUsedInterval = time.Minute
+
+// Core manages the set of APIs for service account and api key access.
+type Core struct {
+	log    *logger.Logger
+	storer apikey.Storer
+}
+
+// NewCore constructs an api key internal API for use.
+func NewCore(log *logger.Logger, storer apikey.Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// CreateServiceAccount adds a new service account to the system.
+func (c *Core) CreateServiceAccount(ctx context.Context, nsa apikey.NewServiceAccount) (apikey.ServiceAccount, error) {
+	ctx, span := otel.AddSpan(ctx, "business.apikeycore.createserviceaccount")
+	defer span.End()
+
+	if err := organization.CheckRoles(ctx, nsa.Roles); err != nil {
+		return apikey.ServiceAccount{}, err
+	}
+
+	now := time.Now()
+
+	sa := apikey.ServiceAccount{
+		ID:          uuid.New(),
+		OrgID:       organization.Resolve(ctx, uuid.Nil),
+		Name:        nsa.Name,
+		Description: nsa.Description,
+		Roles:       nsa.Roles,
+		Enabled:     true,
+		CreatedBy:   nsa.CreatedBy,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.CreateServiceAccount(ctx, sa); err != nil {
+		return apikey.ServiceAccount{}, fmt.Errorf("create: %w", err)
+	}
+
+	return sa, nil
+}
+
+// QueryServiceAccounts retrieves all the service accounts.
+func (c *Core) QueryServiceAccounts(ctx context.Context) ([]apikey.ServiceAccount, error) {
+	ctx, span := otel.AddSpan(ctx, "business.apikeycore.queryserviceaccounts")
+	defer span.End()
+
+	sas, err := c.storer.QueryServiceAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return sas, nil
+}
+
+// QueryServiceAccountByID finds the service account by the specified ID.
+func (c *Core) QueryServiceAccountByID(ctx context.Context, serviceAccountID uuid.UUID) (apikey.ServiceAccount, error) {
+	ctx, span := otel.AddSpan(ctx, "business.apikeycore.queryserviceaccountbyid")
+	defer span.End()
+
+	sa, err := c.storer.QueryServiceAccountByID(ctx, serviceAccountID)
+	if err != nil {
+		return apikey.ServiceAccount{}, fmt.Errorf("query: serviceAccountID[%s]: %w", serviceAccountID, err)
+	}
+
+	return sa, nil
+}
+
+// CreateKey issues a new api key for a service account and returns the raw
+// key along with it. The raw key is only returned here, just its hash is
+// stored.
+func (c *Core) CreateKey(ctx context.Context, nk apikey.NewKey) (apikey.Key, string, error) {
+	ctx, span := otel.AddSpan(ctx, "business.apikeycore.createkey")
+	defer span.End()
+
+	if _, err := c.storer.QueryServiceAccountByID(ctx, nk.ServiceAccountID); err != nil {
+		return apikey.Key{}, "", fmt.Errorf("query: serviceAccountID[%s]: %w", nk.ServiceAccountID, err)
+	}
+
+	prefix, secret, err := newKey()
+	if err != nil {
+		return apikey.Key{}, "", err
+	}
+
+	now := time.Now()
+
+	key := apikey.Key{
+		ID:               uuid.New(),
+		ServiceAccountID: nk.ServiceAccountID,
+		Name:             nk.Name,
+		Prefix:           prefix,
+		KeyHash:          hashSecret(secret),
+		Scopes:           nk.Scopes,
+		ExpiresAt:        nk.ExpiresAt,
+		DateCreated:      now,
+		DateUpdated:      now,
+	}
+
+	if err := c.storer.CreateKey(ctx, key); err != nil {
+		return apikey.Key{}, "", fmt.Errorf("create: %w", err)
+	}
+
+	return key, toRawKey(prefix, secret), nil
+}
+
+// QueryKeys retrieves the api keys of the specified service account.
+func (c *Core) QueryKeys(ctx context.Context, serviceAccountID uuid.UUID) ([]apikey.Key, error) {
+	ctx, span := otel.AddSpan(ctx, "business.apikeycore.querykeys")
+	defer span.End()
+
+	keys, err := c.storer.QueryKeys(ctx, serviceAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("query: serviceAccountID[%s]: %w", serviceAccountID, err)
+	}
+
+	return keys, nil
+}
+
+// QueryKeyByID finds the api key by the specified ID.
+func (c *Core) QueryKeyByID(ctx context.Context, keyID uuid.UUID) (apikey.Key, error) {
+	ctx, span := otel.AddSpan(ctx, "business.apikeycore.querykeybyid")
+	defer span.End()
+
+	key, err := c.storer.QueryKeyByID(ctx, keyID)
+	if err != nil {
+		return apikey.Key{}, fmt.Errorf("query: keyID[%s]: %w", keyID, err)
+	}
+
+	return key, nil
+}
+
+// RevokeKey permanently disables the specified api key.
+func (c *Core) RevokeKey(ctx context.Context, key apikey.Key) error {
+	ctx, span := otel.AddSpan(ctx, "business.apikeycore.revokekey")
+	defer span.End()
+
+	key.Revoked = true
+	key.DateUpdated = time.Now()
+
+	if err := c.storer.UpdateKey(ctx, key); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	return nil
+}
+
+// Authenticate validates a raw api key and returns the service account owning
+// it along with the key.
+func (c *Core) Authenticate(ctx context.Context, rawKey string) (apikey.ServiceAccount, apikey.Key, error) {
+	ctx, span := otel.AddSpan(ctx, "business.apikeycore.authenticate")
+	defer span.End()
+
+	prefix, secret, err := parseRawKey(rawKey)
+	if err != nil {
+		return apikey.ServiceAccount{}, apikey.Key{}, err
+	}
+
+	key, err := c.storer.QueryKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, apikey.ErrNotFound) {
+			return apikey.ServiceAccount{}, apikey.Key{}, apikey.ErrInvalidKey
+		}
+		return apikey.ServiceAccount{}, apikey.Key{}, fmt.Errorf("query: prefix[%s]: %w", prefix, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.KeyHash)) != 1 {
+		return apikey.ServiceAccount{}, apikey.Key{}, apikey.ErrInvalidKey
+	}
+
+	if key.Revoked {
+		return apikey.ServiceAccount{}, apikey.Key{}, apikey.ErrRevoked
+	}
+
+	now := time.Now()
+
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return apikey.ServiceAccount{}, apikey.Key{}, apikey.ErrExpired
+	}
+
+	sa, err := c.storer.QueryServiceAccountByID(ctx, key.ServiceAccountID)
+	if err != nil {
+		return apikey.ServiceAccount{}, apikey.Key{}, fmt.Errorf("query: serviceAccountID[%s]: %w", key.ServiceAccountID, err)
+	}
+
+	if !sa.Enabled {
+		return apikey.ServiceAccount{}, apikey.Key{}, apikey.ErrDisabled
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > lastUsedInterval {
+		key.LastUsedAt = &now
+		if err := c.storer.UpdateKey(ctx, key); err != nil {
+			c.log.Error(ctx, "apikeycore", "status", "updating last use", "keyID", key.ID, "msg", err)
+		}
+	}
+
+	return sa, key, nil
+}
+
+// =============================================================================
+
+// newKey generates the public prefix identifying a key and its secret.
+func newKey() (string, string, error) {
+	p := make([]byte, 4)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", fmt.Errorf("generating prefix: %w", err)
+	}
+
+	s := make([]byte, 32)
+	if _, err := rand.Read(s); err != nil {
+		return "", "", fmt.Errorf("generating secret: %w", err)
+	}
+
+	return hex.EncodeToString(p), base64.RawURLEncoding.EncodeToString(s), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// toRawKey builds an api key in the form bsk_<prefix>_<secret>.
+func toRawKey(prefix string, secret string) string {
+	return keyPrefix + "_" + prefix + "_" + secret
+}
+
+func parseRawKey(rawKey string) (string, string, error) {
+	rest, ok := strings.CutPrefix(rawKey, keyPrefix+"_")
+	if !ok {
+		return "", "", apikey.ErrInvalidKey
+	}
+
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 8 || secret == "" {
+		return "", "", apikey.ErrInvalidKey
+	}
+
+	return prefix, secret, nil
+}
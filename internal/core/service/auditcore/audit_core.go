@@ -9,10 +9,12 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 	"github.com/Housiadas/backend-system/pkg/logger"
 	"github.com/Housiadas/backend-system/pkg/order"
 	"github.com/Housiadas/backend-system/pkg/otel"
 	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
 )
 
 // Core manages the set of APIs for audit access.
@@ -29,6 +31,22 @@ func NewCore(log *logger.Logger, storer audit.Storer) *Core {
 	}
 }
 
+// NewWithTx constructs a new internal value that will use the
+// specified transaction in any store-related calls.
+func (b *Core) NewWithTx(tx pgsql.CommitRollbacker) (*Core, error) {
+	storer, err := b.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	core := Core{
+		log:    b.log,
+		storer: storer,
+	}
+
+	return &core, nil
+}
+
 // Create adds a new audit record to the system.
 func (b *Core) Create(ctx context.Context, na audit.NewAudit) (audit.Audit, error) {
 	ctx, span := otel.AddSpan(ctx, "business.auditbus.create")
@@ -41,6 +59,7 @@ func (b *Core) Create(ctx context.Context, na audit.NewAudit) (audit.Audit, erro
 
 	aud := audit.Audit{
 		ID:        uuid.New(),
+		OrgID:     organization.Resolve(ctx, na.OrgID),
 		ObjID:     na.ObjID,
 		ObjEntity: na.ObjEntity,
 		ObjName:   na.ObjName,
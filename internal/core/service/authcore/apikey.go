@@ -0,0 +1,39 @@
+package authcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+// authenticateAPIKey validates an api key and builds the claims of the
+// service account owning it. The subject is the service account and the
+// scopes of the key narrow down the permissions granted by its roles.
+func (a *Auth) authenticateAPIKey(ctx context.Context, rawKey string) (Claims, error) {
+	if a.apiKeyBus == nil {
+		return Claims{}, errors.New("api keys are not supported")
+	}
+
+	sa, key, err := a.apiKeyBus.Authenticate(ctx, rawKey)
+	if err != nil {
+		return Claims{}, fmt.Errorf("authentication failed : %w", err)
+	}
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: sa.ID.String(),
+			Issuer:  a.issuer,
+			ID:      key.ID.String(),
+		},
+		Roles:  role.ParseToString(sa.Roles),
+		Scopes: permission.ParseToString(key.Scopes),
+		Org:    sa.OrgID.String(),
+	}
+
+	return claims, nil
+}
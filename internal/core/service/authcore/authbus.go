@@ -7,11 +7,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/open-policy-agent/opa/rego"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/service/apikeycore"
+	"github.com/Housiadas/backend-system/internal/core/service/rbaccore"
+	"github.com/Housiadas/backend-system/internal/core/service/sessioncore"
 	"github.com/Housiadas/backend-system/internal/core/service/usercore"
 	"github.com/Housiadas/backend-system/pkg/logger"
 )
@@ -19,47 +26,165 @@ import (
 // Claims represent the authorization claims transmitted via a JWT.
 type Claims struct {
 	jwt.RegisteredClaims
-	Roles []string `json:"roles"`
+	Roles     []string `json:"roles"`
+	SessionID string   `json:"sid,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
+	Purpose   string   `json:"purpose,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Actor     *Actor   `json:"act,omitempty"`
+	Org       string   `json:"org,omitempty"`
+	Dept      string   `json:"dept,omitempty"`
 }
 
+// Actor identifies who acts on behalf of the subject of a token, the admin
+// impersonating a user.
+type Actor struct {
+	Subject string `json:"sub"`
+}
+
+// Impersonated reports whether the token was issued to impersonate the
+// subject.
+func (c Claims) Impersonated() bool {
+	return c.Actor != nil
+}
+
+// PurposeMFA marks the challenge token issued after the password was checked
+// for a user who still has to pass the second factor. It is only accepted to
+// complete the mfa, never as an access token.
+const PurposeMFA = "mfa"
+
+// PurposeInvite marks the token of an invitation link, the subject is the
+// invite id.
+const PurposeInvite = "invite"
+
+// PurposeVerifyEmail marks the token of an email verification link, the
+// subject is the user id and the email claim the verified email.
+const PurposeVerifyEmail = "verify_email"
+
 // KeyLookup declares a method set of behavior for looking up
 // private and public keys for JWT use. The return could be a
-// PEM encoded string or a JWS based key.
+// PEM encoded string or a JWS based key. Keys are identified by
+// a key id (kid) and the active kid is the one used for signing.
+// The legacy kid is the one the tokens carrying no kid were signed
+// with. KIDs lists every key valid for verification and Algorithm
+// reports the signing algorithm (RS256, ES256 or EdDSA) of a key.
 type KeyLookup interface {
-	PrivateKey() (key string, err error)
-	PublicKey() (key string, err error)
+	ActiveKID() (kid string, err error)
+	LegacyKID() (kid string, err error)
+	KIDs() []string
+	PrivateKey(kid string) (key string, err error)
+	PublicKey(kid string) (key string, err error)
+	Algorithm(kid string) (alg string, err error)
+}
+
+// validMethods is the set of signing algorithms supported, the one used for a
+// token is chosen from the type of the signing key.
+var validMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
 }
 
-// Config represents information required to initialize auth.
+// Config represents information required to initialize auth. The
+// Sessionbus is optional, without it tokens are not checked for revocation.
+// The Rbacbus resolves the permissions of the roles, without it no permission
+// is granted. The APIKeybus is optional, without it api keys are rejected.
+// The PolicyPath is optional too, it points to a directory of
+// rego files or an OPA bundle tarball overriding the embedded policies.
+// Decisions are recorded with the logger unless another DecisionSink is
+// provided. With RequireVerifiedEmail the users the email verification rule
+// refuses are not authenticated. TrustedIssuers maps the issuers of other
+// deployments to how their access tokens are accepted, without checking a
+// session or a user here, these being known to the issuer only.
 type Config struct {
-	Log       *logger.Logger
-	DB        *sqlx.DB
-	KeyLookup KeyLookup
-	Issuer    string
-	Userbus   *usercore.Core
+	Log          *logger.Logger
+	DB           *sqlx.DB
+	KeyLookup    KeyLookup
+	Issuer       string
+	Userbus      *usercore.Core
+	Sessionbus   *sessioncore.Core
+	Rbacbus      *rbaccore.Core
+	APIKeybus    *apikeycore.Core
+	PolicyPath   string
+	DecisionSink DecisionSink
+
+	RequireVerifiedEmail bool
+	TrustedIssuers       map[string]TrustedIssuer
+}
+
+// TrustedIssuer is another deployment whose access tokens are accepted, they
+// are verified with the keys of its KeyLookup and must be issued for the
+// Audience. Their roles are only kept when RoleMapping maps them to a role of
+// this service, and they act in the organization OrgID whatever the
+// organization they claim.
+type TrustedIssuer struct {
+	KeyLookup   KeyLookup
+	Audience    string
+	RoleMapping map[string]string
+	OrgID       uuid.UUID
 }
 
 // Auth is used to authenticate clients. It can generate a token for a
 // set of user claims and recreate the claims by parsing the token.
 type Auth struct {
-	keyLookup KeyLookup
-	userBus   *usercore.Core
-	method    jwt.SigningMethod
-	parser    *jwt.Parser
-	issuer    string
+	log        *logger.Logger
+	keyLookup  KeyLookup
+	userBus    *usercore.Core
+	sessionBus *sessioncore.Core
+	rbacBus    *rbaccore.Core
+	apiKeyBus  *apikeycore.Core
+	parser     *jwt.Parser
+	issuer     string
+	policyPath string
+	policies   atomic.Pointer[policySet]
+	sink       DecisionSink
+
+	requireVerifiedEmail bool
+	trustedIssuers       map[string]TrustedIssuer
 }
 
-// New creates an Auth to support authentication/authorization.
-func New(cfg Config) *Auth {
+// New creates an Auth to support authentication/authorization. The policies
+// are compiled once here and evaluated from their prepared queries.
+func New(cfg Config) (*Auth, error) {
 	a := Auth{
-		keyLookup: cfg.KeyLookup,
-		userBus:   cfg.Userbus,
-		method:    jwt.GetSigningMethod(jwt.SigningMethodRS256.Name),
-		parser:    jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name})),
-		issuer:    cfg.Issuer,
+		log:        cfg.Log,
+		keyLookup:  cfg.KeyLookup,
+		userBus:    cfg.Userbus,
+		sessionBus: cfg.Sessionbus,
+		rbacBus:    cfg.Rbacbus,
+		apiKeyBus:  cfg.APIKeybus,
+		parser:     jwt.NewParser(jwt.WithValidMethods(validMethods)),
+		issuer:     cfg.Issuer,
+		policyPath: cfg.PolicyPath,
+		sink:       cfg.DecisionSink,
+
+		requireVerifiedEmail: cfg.RequireVerifiedEmail,
+		trustedIssuers:       cfg.TrustedIssuers,
 	}
 
-	return &a
+	// The tokens of a trusted issuer are pinned to an organization, a role
+	// working across the organizations can't be given to them.
+	for issuer, ti := range a.trustedIssuers {
+		if ti.KeyLookup == nil || ti.Audience == "" || ti.OrgID == uuid.Nil {
+			return nil, fmt.Errorf("trusted issuer[%s]: keys, audience and organization are required", issuer)
+		}
+
+		for _, r := range ti.RoleMapping {
+			if r == role.SuperAdmin.String() {
+				return nil, fmt.Errorf("trusted issuer[%s]: role %s can't be granted", issuer, r)
+			}
+		}
+	}
+
+	if a.sink == nil && cfg.Log != nil {
+		a.sink = NewLogSink(cfg.Log)
+	}
+
+	if err := a.ReloadPolicies(context.Background()); err != nil {
+		return nil, fmt.Errorf("loading policies: %w", err)
+	}
+
+	return &a, nil
 }
 
 // Issuer provides the configured issuer used to authenticate tokens.
@@ -67,20 +192,29 @@ func (a *Auth) Issuer() string {
 	return a.issuer
 }
 
-// opaPolicyEvaluation asks opa to evaluate the token against the specified token
-// policy and public key.
-func (a *Auth) opaPolicyEvaluation(ctx context.Context, opaPolicy string, rule string, input any) error {
-	query := fmt.Sprintf("x = data.%s.%s", opaPackage, rule)
+// opaPolicyEvaluation asks opa to evaluate the input against the prepared
+// query of the specified rule. Every evaluation is recorded as a decision.
+func (a *Auth) opaPolicyEvaluation(ctx context.Context, rule string, input map[string]any) (err error) {
+	ps := a.policies.Load()
 
-	q, err := rego.New(
-		rego.Query(query),
-		rego.Module("policy.rego", opaPolicy),
-	).PrepareForEval(ctx)
-	if err != nil {
-		return err
+	if a.sink != nil {
+		defer func(started time.Time) {
+			a.sink.Record(ctx, newDecision(rule, input, ps.revision, started, err))
+		}(time.Now())
+	}
+
+	return evaluate(ctx, ps, rule, input)
+}
+
+// evaluate runs the prepared query of the rule and reports an error unless
+// the rule allows the input.
+func evaluate(ctx context.Context, ps *policySet, rule string, input map[string]any, opts ...rego.EvalOption) error {
+	q, exists := ps.queries[rule]
+	if !exists {
+		return fmt.Errorf("unknown rule[%s]", rule)
 	}
 
-	results, err := q.Eval(ctx, rego.EvalInput(input))
+	results, err := q.Eval(ctx, append(opts, rego.EvalInput(input))...)
 	if err != nil {
 		return fmt.Errorf("query: %w", err)
 	}
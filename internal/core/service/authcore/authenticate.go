@@ -4,41 +4,224 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+
+	"github.com/google/uuid"
 )
 
 // Authenticate processes the token to validation the sender's token is valid.
+// Service accounts authenticate with an api key instead of a token, using the
+// ApiKey scheme. Tokens issued for a single purpose, like an mfa challenge,
+// are refused.
 func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, error) {
+	claims, err := a.authenticate(ctx, bearerToken)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if claims.Purpose != "" {
+		return Claims{}, fmt.Errorf("token issued for %s only", claims.Purpose)
+	}
+
+	return claims, nil
+}
+
+// AuthenticateMFA accepts an access token as well as an mfa challenge token,
+// so a user can complete the mfa with either.
+func (a *Auth) AuthenticateMFA(ctx context.Context, bearerToken string) (Claims, error) {
+	claims, err := a.authenticate(ctx, bearerToken)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if claims.Purpose != "" && claims.Purpose != PurposeMFA {
+		return Claims{}, fmt.Errorf("token issued for %s only", claims.Purpose)
+	}
+
+	return claims, nil
+}
+
+// ValidateChallenge checks an mfa challenge token and returns its claims.
+func (a *Auth) ValidateChallenge(ctx context.Context, challengeToken string) (Claims, error) {
+	claims, err := a.authenticate(ctx, "Bearer "+challengeToken)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if claims.Purpose != PurposeMFA {
+		return Claims{}, errors.New("not an mfa challenge token")
+	}
+
+	return claims, nil
+}
+
+// ValidateToken checks a token issued for the purpose, like an invitation, and
+// returns its claims. Unlike the access tokens, the subject is not required to
+// be an enabled user.
+func (a *Auth) ValidateToken(ctx context.Context, token string, purpose string) (Claims, error) {
+	claims, err := a.verify(ctx, token)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if claims.Purpose != purpose {
+		return Claims{}, fmt.Errorf("not a %s token", purpose)
+	}
+
+	return claims, nil
+}
+
+// MFARequired reports whether the policies require the roles to pass a
+// second factor.
+func (a *Auth) MFARequired(ctx context.Context, roles []string) bool {
+	input := map[string]any{
+		"Roles": roles,
+	}
+
+	return a.opaPolicyEvaluation(ctx, RuleMFARequired, input) == nil
+}
+
+func (a *Auth) authenticate(ctx context.Context, bearerToken string) (Claims, error) {
 	parts := strings.Split(bearerToken, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
+	if len(parts) != 2 || (parts[0] != "Bearer" && parts[0] != "ApiKey") {
 		return Claims{}, errors.New("expected authorization header format: Bearer <token>")
 	}
 
+	if parts[0] == "ApiKey" {
+		return a.authenticateAPIKey(ctx, parts[1])
+	}
+
+	if issuer, ti, trusted := a.trustedIssuer(parts[1]); trusted {
+		return a.authenticateTrusted(ctx, parts[1], issuer, ti)
+	}
+
+	claims, err := a.verify(ctx, parts[1])
+	if err != nil {
+		return Claims{}, err
+	}
+
+	// Check the session backing this token has not been revoked.
+	if err := a.isSessionActive(ctx, claims); err != nil {
+		return Claims{}, fmt.Errorf("session not active : %w", err)
+	}
+
+	// Check the database for this user to verify they are still enabled.
+	if err := a.isUserEnabled(ctx, claims); err != nil {
+		return Claims{}, fmt.Errorf("user not enabled : %w", err)
+	}
+
+	return claims, nil
+}
+
+// verify checks the signature, issuer and expiry of the token and returns its
+// claims.
+func (a *Auth) verify(ctx context.Context, tokenStr string) (Claims, error) {
+	return a.verifyIssuer(ctx, tokenStr, a.issuer, "", a.keyLookup)
+}
+
+// trustedIssuer finds the trusted issuer of the token, when the token was
+// issued by a trusted issuer other than this service.
+func (a *Auth) trustedIssuer(tokenStr string) (string, TrustedIssuer, bool) {
+	if len(a.trustedIssuers) == 0 {
+		return "", TrustedIssuer{}, false
+	}
+
 	var claims Claims
-	_, _, err := a.parser.ParseUnverified(parts[1], &claims)
+	if _, _, err := a.parser.ParseUnverified(tokenStr, &claims); err != nil {
+		return "", TrustedIssuer{}, false
+	}
+
+	if claims.Issuer == a.issuer {
+		return "", TrustedIssuer{}, false
+	}
+
+	ti, exists := a.trustedIssuers[claims.Issuer]
+
+	return claims.Issuer, ti, exists
+}
+
+// authenticateTrusted accepts an access token of a trusted issuer. The
+// sessions and users behind it are only known to the issuer, so its roles
+// are narrowed to the ones mapped and it is pinned to the organization of
+// the issuer instead.
+func (a *Auth) authenticateTrusted(ctx context.Context, tokenStr string, issuer string, ti TrustedIssuer) (Claims, error) {
+	claims, err := a.verifyIssuer(ctx, tokenStr, issuer, ti.Audience, ti.KeyLookup)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if claims.Purpose != "" {
+		return Claims{}, fmt.Errorf("token of issuer[%s] issued for %s only", issuer, claims.Purpose)
+	}
+
+	if !claims.VerifyAudience(ti.Audience, true) {
+		return Claims{}, fmt.Errorf("token of issuer[%s] not issued for audience[%s]", issuer, ti.Audience)
+	}
+
+	if _, err := uuid.Parse(claims.Subject); err != nil {
+		return Claims{}, fmt.Errorf("token of issuer[%s]: parsing subject: %w", issuer, err)
+	}
+
+	var roles []string
+	for _, r := range claims.Roles {
+		if mapped, exists := ti.RoleMapping[r]; exists && !slices.Contains(roles, mapped) {
+			roles = append(roles, mapped)
+		}
+	}
+
+	if len(roles) == 0 {
+		return Claims{}, fmt.Errorf("token of issuer[%s] holds no mapped role", issuer)
+	}
+
+	claims.Roles = roles
+	claims.Org = ti.OrgID.String()
+
+	return claims, nil
+}
+
+// verifyIssuer checks the token was signed by a key of the issuer, and checks
+// its expiry and, when one is given, its audience.
+func (a *Auth) verifyIssuer(ctx context.Context, tokenStr string, issuer string, audience string, keyLookup KeyLookup) (Claims, error) {
+	var claims Claims
+	token, _, err := a.parser.ParseUnverified(tokenStr, &claims)
 	if err != nil {
 		return Claims{}, fmt.Errorf("error parsing token: %w", err)
 	}
 
-	pem, err := a.keyLookup.PublicKey()
+	// Tokens issued before key rotation was supported carry no kid, these
+	// are verified against the legacy key they were signed with.
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == "" {
+		kid, err = keyLookup.LegacyKID()
+		if err != nil {
+			return Claims{}, fmt.Errorf("failed to fetch legacy kid: %w", err)
+		}
+	}
+
+	pem, err := keyLookup.PublicKey(kid)
 	if err != nil {
 		return Claims{}, fmt.Errorf("failed to fetch public key: %w", err)
 	}
 
+	// The algorithm is pinned to the one of the key, so a token can't pick
+	// a different algorithm than the key was issued for.
+	alg, err := keyLookup.Algorithm(kid)
+	if err != nil {
+		return Claims{}, fmt.Errorf("failed to fetch algorithm: %w", err)
+	}
+
 	input := map[string]any{
 		"Key":   pem,
-		"Token": parts[1],
-		"ISS":   a.issuer,
+		"Alg":   alg,
+		"Token": tokenStr,
+		"ISS":   issuer,
+		"AUD":   audience,
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, regoAuthentication, RuleAuthenticate, input); err != nil {
+	if err := a.opaPolicyEvaluation(ctx, RuleAuthenticate, input); err != nil {
 		return Claims{}, fmt.Errorf("authentication failed : %w", err)
 	}
 
-	// Check the database for this user to verify they are still enabled.
-	if err := a.isUserEnabled(ctx, claims); err != nil {
-		return Claims{}, fmt.Errorf("user not enabled : %w", err)
-	}
-
 	return claims, nil
 }
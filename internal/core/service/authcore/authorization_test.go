@@ -0,0 +1,125 @@
+package authcore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+type ruleCase struct {
+	name    string
+	rule    string
+	claims  Claims
+	userID  uuid.UUID
+	res     Resource
+	allowed bool
+}
+
+func Test_Authorization_Rules(t *testing.T) {
+	a, err := New(Config{})
+	if err != nil {
+		t.Fatalf("Should be able to create auth: %s", err)
+	}
+
+	owner := uuid.New()
+	other := uuid.New()
+
+	product := func(dept string) Resource {
+		return Resource{Type: "PRODUCT", Department: dept}
+	}
+
+	manager := Claims{Roles: []string{"MANAGER"}, Dept: "Sales"}
+	manager.Subject = other.String()
+
+	user := Claims{Roles: []string{"USER"}, Dept: "Sales"}
+	user.Subject = owner.String()
+
+	stranger := Claims{Roles: []string{"USER"}, Dept: "Sales"}
+	stranger.Subject = other.String()
+
+	// A role created through the roles api, known once registered.
+	auditorRole, err := role.New("AUDITOR")
+	if err != nil {
+		t.Fatalf("Should be able to create the role: %s", err)
+	}
+	role.Register(auditorRole)
+	defer role.Unregister(auditorRole)
+
+	auditor := Claims{Roles: []string{"AUDITOR"}}
+	auditor.Subject = owner.String()
+
+	table := []ruleCase{
+		{name: "any-manager", rule: RuleAny, claims: manager, allowed: true},
+		{name: "admin-only-manager", rule: RuleAdminOnly, claims: manager},
+		{name: "user-only-manager", rule: RuleUserOnly, claims: manager},
+		{name: "subject-owner", rule: RuleAdminSubjectOrManager, claims: user, userID: owner, res: product("Sales"), allowed: true},
+		{name: "subject-stranger", rule: RuleAdminSubjectOrManager, claims: stranger, userID: owner, res: product("Sales")},
+		{name: "admin", rule: RuleAdminSubjectOrManager, claims: Claims{Roles: []string{"ADMIN"}}, userID: owner, res: product("Sales"), allowed: true},
+		{name: "manager-same-department", rule: RuleAdminSubjectOrManager, claims: manager, userID: owner, res: product("Sales"), allowed: true},
+		{name: "manager-other-department", rule: RuleAdminSubjectOrManager, claims: manager, userID: owner, res: product("Marketing")},
+		{name: "manager-no-department", rule: RuleAdminSubjectOrManager, claims: Claims{Roles: []string{"MANAGER"}}, userID: owner, res: product("")},
+		{name: "manager-other-resource", rule: RuleAdminSubjectOrManager, claims: manager, userID: owner, res: Resource{Type: "USER", Department: "Sales"}},
+		{name: "manager-not-subject", rule: RuleAdminOrSubject, claims: manager, userID: owner, res: product("Sales")},
+		{name: "any-created-role", rule: RuleAny, claims: auditor, allowed: true},
+		{name: "subject-created-role", rule: RuleAdminOrSubject, claims: auditor, userID: owner, allowed: true},
+		{name: "any-unknown-role", rule: RuleAny, claims: Claims{Roles: []string{"UNKNOWN"}}},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			input := authorizeInput(tt.claims, tt.userID, tt.res, nil)
+
+			err := a.opaPolicyEvaluation(context.Background(), tt.rule, input)
+			if allowed := err == nil; allowed != tt.allowed {
+				t.Fatalf("Should get allowed %t for %s: got %t: %v", tt.allowed, tt.rule, allowed, err)
+			}
+		})
+	}
+}
+
+func Test_Authorization_Scopes(t *testing.T) {
+	a, err := New(Config{})
+	if err != nil {
+		t.Fatalf("Should be able to create auth: %s", err)
+	}
+
+	admin := Claims{Roles: []string{"ADMIN"}}
+	admin.Subject = uuid.NewString()
+
+	key := admin
+	key.Scopes = []string{"product:read"}
+
+	table := []struct {
+		name    string
+		claims  Claims
+		perm    string
+		allowed bool
+	}{
+		{name: "unscoped", claims: admin, allowed: true},
+		{name: "scoped-no-permission", claims: key},
+		{name: "scoped-out-of-scope", claims: key, perm: "user:write"},
+		{name: "scoped-in-scope", claims: key, perm: "product:read", allowed: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.perm != "" {
+				ctx = WithPermission(ctx, tt.perm)
+			}
+
+			err := a.Authorize(ctx, tt.claims, uuid.New(), RuleAdminOnly)
+			if allowed := err == nil; allowed != tt.allowed {
+				t.Fatalf("Should get allowed %t: got %t: %v", tt.allowed, allowed, err)
+			}
+
+			if !tt.allowed && tt.claims.Scopes != nil && !errors.Is(err, ErrOutOfScope) {
+				t.Fatalf("Should be refused as out of scope: %v", err)
+			}
+		})
+	}
+}
@@ -1,25 +1,120 @@
 package authcore
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/v1/topdown"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
 )
 
 // Authorize attempts to authorize the user with the provided input roles, if
 // none of the input roles are within the user's claims, we return an error
 // otherwise the user is authorized.
 func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, rule string) error {
-	input := map[string]any{
-		"Roles":   claims.Roles,
-		"Subject": claims.Subject,
-		"UserID":  userID,
+	return a.AuthorizeResource(ctx, claims, userID, Resource{}, rule)
+}
+
+// AuthorizeResource authorizes the user like Authorize does, with the
+// attributes of the resource the request acts on added to the input of the
+// rule, for the rules deciding on the department of the owner. Claims limited
+// to scopes are refused unless the permission of the action, set with
+// WithPermission, is in scope.
+func (a *Auth) AuthorizeResource(ctx context.Context, claims Claims, userID uuid.UUID, res Resource, rule string) error {
+	if err := inScope(ctx, claims); err != nil {
+		return err
+	}
+
+	perms, err := a.permissions(ctx, claims)
+	if err != nil {
+		return err
+	}
+
+	if err := a.opaPolicyEvaluation(ctx, rule, authorizeInput(claims, userID, res, perms)); err != nil {
+		return fmt.Errorf("rego evaluation failed : %w", err)
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, regoAuthorization, rule, input); err != nil {
+	return nil
+}
+
+// AuthorizePermission attempts to authorize the user against the specified
+// permission, the user is authorized when any of the roles in the claims
+// grants it.
+func (a *Auth) AuthorizePermission(ctx context.Context, claims Claims, perm string) error {
+	perms, err := a.permissions(ctx, claims)
+	if err != nil {
+		return err
+	}
+
+	input := authorizeInput(claims, uuid.Nil, Resource{}, perms)
+	input["Permission"] = perm
+
+	if err := a.opaPolicyEvaluation(ctx, RulePermission, input); err != nil {
 		return fmt.Errorf("rego evaluation failed : %w", err)
 	}
 
 	return nil
 }
+
+// Explain evaluates the rule for the claims, subject and resource like
+// AuthorizeResource does and returns the trace of the evaluation. It is meant for debugging policy
+// changes, the decision is not recorded.
+func (a *Auth) Explain(ctx context.Context, claims Claims, userID uuid.UUID, res Resource, rule string) (Explanation, error) {
+	perms, err := a.permissions(ctx, claims)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	ps := a.policies.Load()
+	input := authorizeInput(claims, userID, res, perms)
+
+	tracer := topdown.NewBufferTracer()
+	err = evaluate(ctx, ps, rule, input, rego.EvalQueryTracer(tracer))
+
+	var buf bytes.Buffer
+	topdown.PrettyTraceWithLocation(&buf, *tracer)
+
+	exp := Explanation{
+		Rule:     rule,
+		Allowed:  err == nil,
+		Revision: ps.revision,
+		Input:    input,
+		Trace:    strings.Split(strings.TrimSpace(buf.String()), "\n"),
+	}
+
+	if err != nil {
+		exp.Reason = err.Error()
+	}
+
+	return exp, nil
+}
+
+// Department returns the department carried in the claims of the user, the
+// empty string when the user belongs to none.
+func Department(dept name.Null) string {
+	if !dept.Valid() {
+		return ""
+	}
+
+	return dept.String()
+}
+
+// authorizeInput constructs the input of the authorization rules.
+func authorizeInput(claims Claims, userID uuid.UUID, res Resource, perms []string) map[string]any {
+	return map[string]any{
+		"Roles":              claims.Roles,
+		"RegisteredRoles":    role.ParseToString(registeredRoles(claims.Roles)),
+		"Subject":            claims.Subject,
+		"UserID":             userID,
+		"Permissions":        perms,
+		"Department":         claims.Dept,
+		"ResourceType":       res.Type,
+		"ResourceDepartment": res.Department,
+	}
+}
@@ -0,0 +1,133 @@
+package authcore
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
+	"github.com/Housiadas/backend-system/pkg/logger"
+)
+
+// redacted replaces the values of the input that must not be recorded.
+const redacted = "[REDACTED]"
+
+// Decision represents the outcome of a single policy evaluation.
+type Decision struct {
+	Rule      string         `json:"rule"`
+	Input     map[string]any `json:"input"`
+	Allowed   bool           `json:"allowed"`
+	Reason    string         `json:"reason,omitempty"`
+	Latency   time.Duration  `json:"latency"`
+	Revision  string         `json:"revision"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// DecisionSink declares the behavior for recording policy decisions. A sink
+// must not fail the request, errors are handled by the sink itself.
+type DecisionSink interface {
+	Record(ctx context.Context, d Decision)
+}
+
+// newDecision constructs a decision with the token removed from the input.
+func newDecision(rule string, input map[string]any, revision string, started time.Time, err error) Decision {
+	in := maps.Clone(input)
+	if _, exists := in["Token"]; exists {
+		in["Token"] = redacted
+	}
+
+	d := Decision{
+		Rule:      rule,
+		Input:     in,
+		Allowed:   err == nil,
+		Latency:   time.Since(started),
+		Revision:  revision,
+		Timestamp: started.UTC(),
+	}
+
+	if err != nil {
+		d.Reason = err.Error()
+	}
+
+	return d
+}
+
+// =============================================================================
+
+// LogSink records decisions with the logger, this is the default sink.
+type LogSink struct {
+	log *logger.Logger
+}
+
+// NewLogSink constructs a sink writing decisions to the logger.
+func NewLogSink(log *logger.Logger) *LogSink {
+	return &LogSink{
+		log: log,
+	}
+}
+
+// Record implements the DecisionSink interface.
+func (s *LogSink) Record(ctx context.Context, d Decision) {
+	s.log.Info(ctx, "authcore: decision",
+		"rule", d.Rule,
+		"allowed", d.Allowed,
+		"reason", d.Reason,
+		"latency", d.Latency,
+		"revision", d.Revision,
+		"input", d.Input,
+	)
+}
+
+// =============================================================================
+
+// AuditSink records decisions in the audit table.
+type AuditSink struct {
+	log       *logger.Logger
+	auditCore *auditcore.Core
+}
+
+// NewAuditSink constructs a sink writing decisions to the audit table.
+func NewAuditSink(log *logger.Logger, auditCore *auditcore.Core) *AuditSink {
+	return &AuditSink{
+		log:       log,
+		auditCore: auditCore,
+	}
+}
+
+// Record implements the DecisionSink interface.
+func (s *AuditSink) Record(ctx context.Context, d Decision) {
+	var actorID uuid.UUID
+	if subject, ok := d.Input["Subject"].(string); ok {
+		actorID, _ = uuid.Parse(subject)
+	}
+
+	var objID uuid.UUID
+	if userID, ok := d.Input["UserID"].(uuid.UUID); ok {
+		objID = userID
+	}
+
+	action := "denied"
+	if d.Allowed {
+		action = "allowed"
+	}
+
+	na := audit.NewAudit{
+		ObjID:     objID,
+		ObjEntity: entity.Auth,
+		ObjName:   name.MustParse("policy decision"),
+		ActorID:   actorID,
+		Action:    action,
+		Data:      d,
+		Message:   d.Rule,
+	}
+
+	// The decision is recorded even when the request is canceled.
+	if _, err := s.auditCore.Create(context.WithoutCancel(ctx), na); err != nil {
+		s.log.Error(ctx, "authcore: decision", "status", "recording audit", "msg", err)
+	}
+}
@@ -0,0 +1,82 @@
+package authcore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type recordSink struct {
+	decisions []Decision
+}
+
+func (s *recordSink) Record(_ context.Context, d Decision) {
+	s.decisions = append(s.decisions, d)
+}
+
+func Test_Decisions(t *testing.T) {
+	sink := recordSink{}
+
+	a, err := New(Config{DecisionSink: &sink})
+	if err != nil {
+		t.Fatalf("Should be able to create auth: %s", err)
+	}
+
+	input := map[string]any{"Token": "secret", "Roles": []string{"USER"}}
+	if err := a.opaPolicyEvaluation(context.Background(), RuleAdminOnly, input); err == nil {
+		t.Fatal("Should not authorize a user as admin")
+	}
+
+	if len(sink.decisions) != 1 {
+		t.Fatalf("Should record one decision: got %d", len(sink.decisions))
+	}
+
+	d := sink.decisions[0]
+	if d.Allowed || d.Reason == "" {
+		t.Fatalf("Should record the denial: %+v", d)
+	}
+	if d.Rule != RuleAdminOnly || d.Revision != a.Policies().Revision {
+		t.Fatalf("Should record the rule and revision: %+v", d)
+	}
+	if d.Input["Token"] != redacted {
+		t.Fatalf("Should redact the token: got %v", d.Input["Token"])
+	}
+	if input["Token"] != "secret" {
+		t.Fatal("Should not modify the input")
+	}
+}
+
+func Test_Explain(t *testing.T) {
+	sink := recordSink{}
+
+	a, err := New(Config{DecisionSink: &sink})
+	if err != nil {
+		t.Fatalf("Should be able to create auth: %s", err)
+	}
+
+	claims := Claims{Roles: []string{"ADMIN"}}
+
+	exp, err := a.Explain(context.Background(), claims, uuid.New(), Resource{}, RuleAdminOnly)
+	if err != nil {
+		t.Fatalf("Should be able to explain: %s", err)
+	}
+	if !exp.Allowed {
+		t.Fatalf("Should allow an admin: %+v", exp)
+	}
+	if len(exp.Trace) < 2 {
+		t.Fatalf("Should return the trace: %v", exp.Trace)
+	}
+
+	exp, err = a.Explain(context.Background(), claims, uuid.New(), Resource{}, RuleUserOnly)
+	if err != nil {
+		t.Fatalf("Should be able to explain: %s", err)
+	}
+	if exp.Allowed {
+		t.Fatalf("Should not allow an admin as user: %+v", exp)
+	}
+
+	if len(sink.decisions) != 0 {
+		t.Fatal("Should not record explanations as decisions")
+	}
+}
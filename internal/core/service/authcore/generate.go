@@ -7,15 +7,32 @@ import (
 )
 
 // GenerateToken generates a signed JWT token string representing the user Claims.
+// The token is signed with the active key using the algorithm matching its type.
 func (a *Auth) GenerateToken(claims Claims) (string, error) {
-	token := jwt.NewWithClaims(a.method, claims)
+	kid, err := a.keyLookup.ActiveKID()
+	if err != nil {
+		return "", fmt.Errorf("active kid: %w", err)
+	}
+
+	alg, err := a.keyLookup.Algorithm(kid)
+	if err != nil {
+		return "", fmt.Errorf("algorithm: %w", err)
+	}
+
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return "", fmt.Errorf("unsupported signing method: %s", alg)
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
 
-	privateKeyPEM, err := a.keyLookup.PrivateKey()
+	privateKeyPEM, err := a.keyLookup.PrivateKey(kid)
 	if err != nil {
 		return "", fmt.Errorf("private key: %w", err)
 	}
 
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+	privateKey, err := parsePrivateKey(alg, privateKeyPEM)
 	if err != nil {
 		return "", fmt.Errorf("parsing private pem: %w", err)
 	}
@@ -27,3 +44,17 @@ func (a *Auth) GenerateToken(claims Claims) (string, error) {
 
 	return str, nil
 }
+
+// parsePrivateKey parses the private key PEM for the signing algorithm.
+func parsePrivateKey(alg string, privateKeyPEM string) (any, error) {
+	switch alg {
+	case jwt.SigningMethodRS256.Alg():
+		return jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+	case jwt.SigningMethodES256.Alg():
+		return jwt.ParseECPrivateKeyFromPEM([]byte(privateKeyPEM))
+	case jwt.SigningMethodEdDSA.Alg():
+		return jwt.ParseEdPrivateKeyFromPEM([]byte(privateKeyPEM))
+	}
+
+	return nil, fmt.Errorf("unsupported signing method: %s", alg)
+}
@@ -0,0 +1,99 @@
+package authcore
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/Housiadas/backend-system/pkg/keystore"
+)
+
+func Test_GenerateToken_Algorithms(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		alg string
+		key any
+	}{
+		{alg: keystore.AlgRS256, key: rsaKey},
+		{alg: keystore.AlgES256, key: ecKey},
+		{alg: keystore.AlgEdDSA, key: edKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			der, err := x509.MarshalPKCS8PrivateKey(tt.key)
+			if err != nil {
+				t.Fatalf("marshaling key: %s", err)
+			}
+
+			ks := keystore.New()
+			fsys := fstest.MapFS{
+				"key.pem": {Data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})},
+			}
+			if err := ks.LoadKeys(fsys); err != nil {
+				t.Fatalf("Should be able to load keys: %s", err)
+			}
+
+			a, err := New(Config{KeyLookup: ks, Issuer: "test"})
+			if err != nil {
+				t.Fatalf("Should be able to create auth: %s", err)
+			}
+
+			claims := Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Subject:   "subject",
+					Issuer:    "test",
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+					IssuedAt:  jwt.NewNumericDate(time.Now()),
+				},
+			}
+
+			token, err := a.GenerateToken(claims)
+			if err != nil {
+				t.Fatalf("Should be able to generate a token: %s", err)
+			}
+
+			parsed, _, err := a.parser.ParseUnverified(token, &Claims{})
+			if err != nil {
+				t.Fatalf("Should be able to parse the token: %s", err)
+			}
+			if parsed.Method.Alg() != tt.alg {
+				t.Fatalf("Should sign with %s: got %s", tt.alg, parsed.Method.Alg())
+			}
+
+			publicPEM, _ := ks.PublicKey("key")
+			input := map[string]any{
+				"Key":   publicPEM,
+				"Alg":   tt.alg,
+				"Token": token,
+				"ISS":   "test",
+			}
+			if err := a.opaPolicyEvaluation(context.Background(), RuleAuthenticate, input); err != nil {
+				t.Fatalf("Should verify the token: %s", err)
+			}
+
+			// A token can't be verified with a different algorithm than the
+			// one of the key.
+			input["Alg"] = keystore.AlgRS256
+			if tt.alg == keystore.AlgRS256 {
+				input["Alg"] = keystore.AlgES256
+			}
+			if err := a.opaPolicyEvaluation(context.Background(), RuleAuthenticate, input); err == nil {
+				t.Fatal("Should not verify the token with another algorithm")
+			}
+		})
+	}
+}
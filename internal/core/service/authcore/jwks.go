@@ -0,0 +1,58 @@
+package authcore
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Housiadas/backend-system/pkg/keystore"
+)
+
+// JWKS returns the set of public keys that can be used to verify the tokens
+// issued by this service, including the keys retired from signing.
+func (a *Auth) JWKS() (JWKS, error) {
+	kids := a.keyLookup.KIDs()
+
+	keys := make([]keystore.JWK, 0, len(kids))
+	for _, kid := range kids {
+		pem, err := a.keyLookup.PublicKey(kid)
+		if err != nil {
+			return JWKS{}, fmt.Errorf("public key: %w", err)
+		}
+
+		alg, err := a.keyLookup.Algorithm(kid)
+		if err != nil {
+			return JWKS{}, fmt.Errorf("algorithm: %w", err)
+		}
+
+		jwk, err := keystore.PublicPEMToJWK(kid, alg, pem)
+		if err != nil {
+			return JWKS{}, fmt.Errorf("converting kid[%s]: %w", kid, err)
+		}
+
+		keys = append(keys, jwk)
+	}
+
+	return JWKS{Keys: keys}, nil
+}
+
+// OpenIDConfiguration returns a minimal discovery document describing the
+// issuer and where its signing keys are published.
+func (a *Auth) OpenIDConfiguration() OpenIDConfiguration {
+	algs := make([]string, 0, len(validMethods))
+	for _, kid := range a.keyLookup.KIDs() {
+		alg, err := a.keyLookup.Algorithm(kid)
+		if err != nil || slices.Contains(algs, alg) {
+			continue
+		}
+		algs = append(algs, alg)
+	}
+
+	return OpenIDConfiguration{
+		Issuer:                           a.issuer,
+		JWKSURI:                          strings.TrimSuffix(a.issuer, "/") + "/.well-known/jwks.json",
+		IDTokenSigningAlgValuesSupported: algs,
+		SubjectTypesSupported:            []string{"public"},
+		ClaimsSupported:                  []string{"iss", "sub", "exp", "iat", "roles"},
+	}
+}
@@ -0,0 +1,89 @@
+package authcore
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/pkg/keystore"
+)
+
+func Test_Authenticate_LegacyKID(t *testing.T) {
+	legacy, _ := rsa.GenerateKey(rand.Reader, 2048)
+	active, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	fsys := fstest.MapFS{
+		"legacy.pem": {Data: pemKey(t, legacy)},
+		"active.pem": {Data: pemKey(t, active)},
+	}
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   uuid.NewString(),
+			Issuer:    "local",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		Roles: []string{"USER"},
+		Org:   uuid.NewString(),
+	}
+
+	// Tokens issued before the keys were rotated carry no kid.
+	sign := func(key *rsa.PrivateKey) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("Should be able to sign a token: %s", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name  string
+		cfg   keystore.Config
+		token string
+		ok    bool
+	}{
+		{name: "legacy", cfg: keystore.Config{ActiveKID: "active", LegacyKID: "legacy"}, token: sign(legacy), ok: true},
+		{name: "active", cfg: keystore.Config{ActiveKID: "active", LegacyKID: "legacy"}, token: sign(active)},
+		{name: "unconfigured", cfg: keystore.Config{ActiveKID: "legacy"}, token: sign(legacy)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks := keystore.NewWithConfig(tt.cfg)
+			if err := ks.LoadKeys(fsys); err != nil {
+				t.Fatalf("Should be able to load keys: %s", err)
+			}
+
+			a, err := New(Config{KeyLookup: ks, Issuer: "local"})
+			if err != nil {
+				t.Fatalf("Should be able to create auth: %s", err)
+			}
+
+			// The signature alone is checked, no user nor session core is
+			// configured.
+			if _, err := a.verify(context.Background(), tt.token); tt.ok != (err == nil) {
+				t.Fatalf("Should verify the tokens without a kid only against the legacy key: got %v", err)
+			}
+		})
+	}
+}
+
+func pemKey(t *testing.T, key *rsa.PrivateKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshaling key: %s", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
@@ -2,8 +2,11 @@ package authcore
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/pkg/keystore"
 )
 
 type AuthenticateResp struct {
@@ -16,11 +19,76 @@ func (a AuthenticateResp) Encode() ([]byte, string, error) {
 	return data, "application/json", err
 }
 
+// JWKS represents the set of public keys used to verify tokens.
+type JWKS struct {
+	Keys []keystore.JWK `json:"keys"`
+}
+
+// Encode implements the encoder interface.
+func (j JWKS) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(j)
+	return data, "application/json", err
+}
+
+// OpenIDConfiguration represents a minimal OpenID provider discovery document.
+type OpenIDConfiguration struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	ClaimsSupported                  []string `json:"claims_supported"`
+}
+
+// Encode implements the encoder interface.
+func (o OpenIDConfiguration) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(o)
+	return data, "application/json", err
+}
+
+// PolicyInfo describes the revision of the policies being evaluated.
+type PolicyInfo struct {
+	Revision string    `json:"revision"`
+	Source   string    `json:"source"`
+	LoadedAt time.Time `json:"loaded_at"`
+	Rules    []string  `json:"rules"`
+}
+
+// Encode implements the encoder interface.
+func (p PolicyInfo) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(p)
+	return data, "application/json", err
+}
+
+// Explanation describes how a rule was evaluated for an input.
+type Explanation struct {
+	Rule     string         `json:"rule"`
+	Allowed  bool           `json:"allowed"`
+	Reason   string         `json:"reason,omitempty"`
+	Revision string         `json:"revision"`
+	Input    map[string]any `json:"input"`
+	Trace    []string       `json:"trace"`
+}
+
+// Encode implements the encoder interface.
+func (e Explanation) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(e)
+	return data, "application/json", err
+}
+
 // Authorize defines the information required to perform an authorization.
 type Authorize struct {
-	Claims Claims
-	UserID uuid.UUID
-	Rule   string
+	Claims   Claims
+	UserID   uuid.UUID
+	Resource Resource
+	Rule     string
+}
+
+// Resource holds the attributes of the resource a request acts on, for the
+// rules deciding on more than the roles. The owner of the resource is the
+// UserID of the authorization.
+type Resource struct {
+	Type       string
+	Department string
 }
 
 // Error represents an error in the systemapi.
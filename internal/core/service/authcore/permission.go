@@ -0,0 +1,109 @@
+package authcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+)
+
+// ErrOutOfScope is returned when claims limited to scopes, as the ones of an
+// api key, are used for an action none of the scopes allows.
+var ErrOutOfScope = errors.New("action out of scope")
+
+type permissionKey struct{}
+
+// WithPermission returns the context carrying the permission the action
+// requires, as checked by AuthorizePermission. The rules only authorize claims
+// limited to scopes when that permission is in scope.
+func WithPermission(ctx context.Context, perm string) context.Context {
+	return context.WithValue(ctx, permissionKey{}, perm)
+}
+
+// inScope checks the permission of the action is in the scopes of the claims,
+// claims without scopes are not limited.
+func inScope(ctx context.Context, claims Claims) error {
+	if claims.Scopes == nil {
+		return nil
+	}
+
+	perm, _ := ctx.Value(permissionKey{}).(string)
+	if perm == "" || !slices.Contains(claims.Scopes, perm) {
+		return ErrOutOfScope
+	}
+
+	return nil
+}
+
+// ErrRoleNotCovered is returned when a role grants a permission the claims
+// are not granted.
+var ErrRoleNotCovered = errors.New("role grants more than the caller holds")
+
+// AuthorizeRoles checks the claims are granted every permission the roles
+// grant, so acting with the roles gives the caller nothing more.
+func (a *Auth) AuthorizeRoles(ctx context.Context, claims Claims, roles []role.Role) error {
+	if a.rbacBus == nil {
+		return nil
+	}
+
+	held, err := a.permissions(ctx, claims)
+	if err != nil {
+		return err
+	}
+
+	perms, err := a.rbacBus.Permissions(ctx, roles)
+	if err != nil {
+		return fmt.Errorf("query permissions: %w", err)
+	}
+
+	for _, p := range perms {
+		if !slices.Contains(held, p.String()) {
+			return fmt.Errorf("%w: permission[%s]", ErrRoleNotCovered, p)
+		}
+	}
+
+	return nil
+}
+
+// permissions resolves the effective permissions of the roles in the claims.
+// Roles that no longer exist grant no permission. Without the rbac core no
+// permission is granted at all. Claims carrying scopes, as the ones of an api
+// key, are only granted the permissions that are also in scope.
+func (a *Auth) permissions(ctx context.Context, claims Claims) ([]string, error) {
+	if a.rbacBus == nil {
+		return []string{}, nil
+	}
+
+	perms, err := a.rbacBus.Permissions(ctx, registeredRoles(claims.Roles))
+	if err != nil {
+		return nil, fmt.Errorf("query permissions: %w", err)
+	}
+
+	granted := permission.ParseToString(perms)
+
+	if claims.Scopes != nil {
+		granted = slices.DeleteFunc(granted, func(p string) bool {
+			return !slices.Contains(claims.Scopes, p)
+		})
+	}
+
+	return granted, nil
+}
+
+// registeredRoles returns the roles of the claims known to the service, the
+// built-in ones and the ones created at runtime. The others are ignored.
+func registeredRoles(values []string) []role.Role {
+	roles := make([]role.Role, 0, len(values))
+	for _, value := range values {
+		r, err := role.Parse(value)
+		if err != nil {
+			continue
+		}
+		roles = append(roles, r)
+	}
+
+	return roles
+}
@@ -0,0 +1,233 @@
+package authcore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/open-policy-agent/opa/bundle"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// rules lists the rules prepared for evaluation when the policies load.
+var rules = []string{
+	RuleAuthenticate,
+	RuleAny,
+	RuleAdminOnly,
+	RuleSuperAdmin,
+	RuleUserOnly,
+	RuleAdminOrSubject,
+	RuleAdminSubjectOrManager,
+	RulePermission,
+	RuleMFARequired,
+	RuleEmailVerified,
+}
+
+// policySet is an immutable set of prepared queries, one per rule, compiled
+// from a single revision of the policies.
+type policySet struct {
+	queries  map[string]rego.PreparedEvalQuery
+	revision string
+	source   string
+	loadedAt time.Time
+}
+
+// Policies reports the revision of the policies currently being evaluated.
+func (a *Auth) Policies() PolicyInfo {
+	ps := a.policies.Load()
+
+	names := make([]string, 0, len(ps.queries))
+	for rule := range ps.queries {
+		names = append(names, rule)
+	}
+	slices.Sort(names)
+
+	return PolicyInfo{
+		Revision: ps.revision,
+		Source:   ps.source,
+		LoadedAt: ps.loadedAt,
+		Rules:    names,
+	}
+}
+
+// ReloadPolicies compiles the embedded policies along with the ones found in
+// the policy path and swaps them in. The policies in use are kept when the
+// new ones fail to load or compile.
+func (a *Auth) ReloadPolicies(ctx context.Context) error {
+	ps, err := loadPolicies(ctx, a.policyPath)
+	if err != nil {
+		return err
+	}
+
+	a.policies.Store(ps)
+
+	return nil
+}
+
+// WatchPolicies reloads the policies every time the policy path changes until
+// the context is canceled. It returns immediately if no policy path is set.
+func (a *Auth) WatchPolicies(ctx context.Context) error {
+	if a.policyPath == "" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// A bundle is usually replaced by a rename, which drops a watch on the
+	// file itself, so the directory holding it is watched instead.
+	dir := a.policyPath
+	if isBundle(a.policyPath) {
+		dir = filepath.Dir(a.policyPath)
+	}
+
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("watching %s: %w", dir, err)
+	}
+
+	// Writes come in bursts, wait for them to settle before reloading.
+	const settle = 250 * time.Millisecond
+	timer := time.NewTimer(settle)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if isBundle(a.policyPath) && filepath.Clean(ev.Name) != filepath.Clean(a.policyPath) {
+				continue
+			}
+			timer.Reset(settle)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			a.log.Error(ctx, "authcore", "status", "watching policies", "msg", err)
+
+		case <-timer.C:
+			if err := a.ReloadPolicies(ctx); err != nil {
+				a.log.Error(ctx, "authcore", "status", "reloading policies", "msg", err)
+				continue
+			}
+			a.log.Info(ctx, "authcore", "status", "policies reloaded", "revision", a.Policies().Revision)
+		}
+	}
+}
+
+// loadPolicies prepares a query per rule from the embedded policies. Modules
+// read from the policy path replace the embedded modules of the same name
+// and add to the rest.
+func loadPolicies(ctx context.Context, path string) (*policySet, error) {
+	modules := map[string]string{
+		"authentication.rego": regoAuthentication,
+		"authorization.rego":  regoAuthorization,
+	}
+	source := "embedded"
+	var revision string
+
+	if path != "" {
+		b, err := readBundle(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading policies: %w", err)
+		}
+
+		for _, mf := range b.Modules {
+			modules[strings.TrimPrefix(filepath.ToSlash(mf.Path), "/")] = string(mf.Raw)
+		}
+		source = path
+		revision = b.Manifest.Revision
+	}
+
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// Without a revision in a bundle manifest, the revision is derived from
+	// the content of the modules.
+	if revision == "" {
+		h := sha256.New()
+		for _, name := range names {
+			h.Write([]byte(name))
+			h.Write([]byte(modules[name]))
+		}
+		revision = hex.EncodeToString(h.Sum(nil))[:12]
+	}
+
+	opts := make([]func(*rego.Rego), 0, len(names)+1)
+	for _, name := range names {
+		opts = append(opts, rego.Module(name, modules[name]))
+	}
+
+	queries := make(map[string]rego.PreparedEvalQuery, len(rules))
+	for _, rule := range rules {
+		q, err := rego.New(
+			append(opts, rego.Query(fmt.Sprintf("x = data.%s.%s", opaPackage, rule)))...,
+		).PrepareForEval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("preparing rule[%s]: %w", rule, err)
+		}
+		queries[rule] = q
+	}
+
+	ps := policySet{
+		queries:  queries,
+		revision: revision,
+		source:   source,
+		loadedAt: time.Now().UTC(),
+	}
+
+	return &ps, nil
+}
+
+// readBundle reads a directory of policies or an OPA bundle tarball.
+func readBundle(path string) (bundle.Bundle, error) {
+	var loader bundle.DirectoryLoader
+
+	switch {
+	case isBundle(path):
+		f, err := os.Open(path)
+		if err != nil {
+			return bundle.Bundle{}, err
+		}
+		defer f.Close()
+
+		loader = bundle.NewTarballLoader(f)
+
+	default:
+		info, err := os.Stat(path)
+		if err != nil {
+			return bundle.Bundle{}, err
+		}
+		if !info.IsDir() {
+			return bundle.Bundle{}, errors.New("policy path must be a directory or a .tar.gz bundle")
+		}
+
+		loader = bundle.NewDirectoryLoader(path)
+	}
+
+	return bundle.NewCustomReader(loader).Read()
+}
+
+// isBundle reports whether the policy path points to a bundle tarball.
+func isBundle(path string) bool {
+	return strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
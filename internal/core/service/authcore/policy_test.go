@@ -0,0 +1,159 @@
+package authcore
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+)
+
+const denyAdmin = `package housi.rego
+
+import rego.v1
+
+default rule_admin_only := false
+`
+
+func Test_Policies_Embedded(t *testing.T) {
+	a, err := New(Config{})
+	if err != nil {
+		t.Fatalf("Should be able to create auth: %s", err)
+	}
+
+	info := a.Policies()
+	if info.Source != "embedded" {
+		t.Fatalf("Should load the embedded policies: got %s", info.Source)
+	}
+	if len(info.Rules) != len(rules) {
+		t.Fatalf("Should prepare every rule: got %v", info.Rules)
+	}
+
+	input := map[string]any{"Roles": []string{"ADMIN"}}
+	if err := a.opaPolicyEvaluation(context.Background(), RuleAdminOnly, input); err != nil {
+		t.Fatalf("Should authorize an admin: %s", err)
+	}
+
+	perm := map[string]any{"Permission": "audit:read", "Permissions": []string{"product:read", "audit:read"}}
+	if err := a.opaPolicyEvaluation(context.Background(), RulePermission, perm); err != nil {
+		t.Fatalf("Should authorize a granted permission: %s", err)
+	}
+
+	perm["Permission"] = "role:write"
+	if err := a.opaPolicyEvaluation(context.Background(), RulePermission, perm); err == nil {
+		t.Fatal("Should not authorize a permission not granted")
+	}
+
+	if err := a.opaPolicyEvaluation(context.Background(), "rule_unknown", input); err == nil {
+		t.Fatal("Should not evaluate an unknown rule")
+	}
+
+	if !a.MFARequired(context.Background(), []string{"USER", "ADMIN"}) {
+		t.Fatal("Should require mfa for an admin")
+	}
+	if a.MFARequired(context.Background(), []string{"USER"}) {
+		t.Fatal("Should not require mfa for a user")
+	}
+	usr := user.User{Roles: []role.Role{role.User}}
+	if err := a.EmailVerified(context.Background(), usr); err != nil {
+		t.Fatalf("Should not check the email unless required: %s", err)
+	}
+
+	a.requireVerifiedEmail = true
+	if err := a.EmailVerified(context.Background(), usr); !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("Should refuse a user not verified: got %v", err)
+	}
+
+	usr.EmailVerified = true
+	if err := a.EmailVerified(context.Background(), usr); err != nil {
+		t.Fatalf("Should allow a verified user: %s", err)
+	}
+}
+
+func Test_Policies_Directory(t *testing.T) {
+	dir := t.TempDir()
+
+	a, err := New(Config{PolicyPath: dir})
+	if err != nil {
+		t.Fatalf("Should be able to create auth: %s", err)
+	}
+
+	input := map[string]any{"Roles": []string{"ADMIN"}}
+	if err := a.opaPolicyEvaluation(context.Background(), RuleAdminOnly, input); err != nil {
+		t.Fatalf("Should authorize an admin with the embedded policies: %s", err)
+	}
+	revision := a.Policies().Revision
+
+	// Replace the embedded authorization module.
+	if err := os.WriteFile(filepath.Join(dir, "authorization.rego"), []byte(denyAdmin), 0o600); err != nil {
+		t.Fatalf("writing policy: %s", err)
+	}
+	if err := a.ReloadPolicies(context.Background()); err != nil {
+		t.Fatalf("Should be able to reload the policies: %s", err)
+	}
+
+	if a.Policies().Revision == revision {
+		t.Fatal("Should change the revision on reload")
+	}
+	if err := a.opaPolicyEvaluation(context.Background(), RuleAdminOnly, input); err == nil {
+		t.Fatal("Should evaluate the overriding policy")
+	}
+
+	// A policy that fails to compile keeps the previous revision in use.
+	revision = a.Policies().Revision
+	if err := os.WriteFile(filepath.Join(dir, "authorization.rego"), []byte("package housi.rego\n\nrule_admin_only if {"), 0o600); err != nil {
+		t.Fatalf("writing policy: %s", err)
+	}
+	if err := a.ReloadPolicies(context.Background()); err == nil {
+		t.Fatal("Should not reload a broken policy")
+	}
+	if a.Policies().Revision != revision {
+		t.Fatal("Should keep the previous revision")
+	}
+}
+
+func Test_Policies_Bundle(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	files := map[string]string{
+		"/.manifest":          `{"revision": "v42", "roots": [""]}`,
+		"/authorization.rego": denyAdmin,
+	}
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content))}); err != nil {
+			t.Fatalf("writing header: %s", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("writing file: %s", err)
+		}
+	}
+	tw.Close()
+	gw.Close()
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("writing bundle: %s", err)
+	}
+
+	a, err := New(Config{PolicyPath: path})
+	if err != nil {
+		t.Fatalf("Should be able to create auth: %s", err)
+	}
+
+	if got := a.Policies().Revision; got != "v42" {
+		t.Fatalf("Should report the bundle revision: got %s", got)
+	}
+
+	input := map[string]any{"Roles": []string{"ADMIN"}}
+	if err := a.opaPolicyEvaluation(context.Background(), RuleAdminOnly, input); err == nil {
+		t.Fatal("Should evaluate the bundle policy")
+	}
+}
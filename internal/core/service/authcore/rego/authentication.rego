@@ -5,9 +5,17 @@ import rego.v1
 default auth := false
 
 auth if {
-	[valid, _, _] := io.jwt.decode_verify(input.Token, {
-		"cert": input.Key,
-		"iss": input.ISS,
-	})
+	[valid, _, _] := io.jwt.decode_verify(input.Token, object.union(constraints, audience))
 	valid == true
 }
+
+constraints := {
+	"cert": input.Key,
+	"alg": input.Alg,
+	"iss": input.ISS,
+}
+
+# A token issued for an audience is only verified against it.
+audience := {"aud": input.AUD} if {
+	input.AUD != ""
+} else := {}
@@ -6,24 +6,40 @@ role_user := "USER"
 
 role_admin := "ADMIN"
 
-role_all := {role_admin, role_user}
+# The super admin administers every organization.
+role_super_admin := "SUPER_ADMIN"
+
+role_admins := {role_admin, role_super_admin}
+
+# The manager reads the resources of the users in their department.
+role_manager := "MANAGER"
+
+role_all := {role_admin, role_super_admin, role_manager, role_user}
+
+# The roles of the claims the service knows, the built-in ones and the ones
+# created at runtime, listed in RegisteredRoles.
+known_roles := ({role | some role in input.Roles} & role_all) | {role | some role in object.get(input, "RegisteredRoles", [])}
 
 default rule_any := false
 
 rule_any if {
-	claim_roles := {role | some role in input.Roles}
-	input_roles := role_all & claim_roles
-	count(input_roles) > 0
+	count(known_roles) > 0
 }
 
 default rule_admin_only := false
 
 rule_admin_only if {
 	claim_roles := {role | some role in input.Roles}
-	input_admin := {role_admin} & claim_roles
+	input_admin := role_admins & claim_roles
 	count(input_admin) > 0
 }
 
+default rule_super_admin := false
+
+rule_super_admin if {
+	role_super_admin in input.Roles
+}
+
 default rule_user_only := false
 
 rule_user_only if {
@@ -32,15 +48,57 @@ rule_user_only if {
 	count(input_user) > 0
 }
 
+default rule_permission := false
+
+rule_permission if {
+	input.Permission in input.Permissions
+}
+
+# Roles that must authenticate with a second factor.
+mfa_roles := role_admins
+
+default rule_mfa_required := false
+
+rule_mfa_required if {
+	some role in input.Roles
+	role in mfa_roles
+}
+
+# Users allowed in once their email is verified, when the verification is
+# required.
+default rule_email_verified := false
+
+rule_email_verified if {
+	input.EmailVerified == true
+}
+
 default rule_admin_or_subject := false
 
 rule_admin_or_subject if {
 	claim_roles := {role | some role in input.Roles}
-	input_admin := {role_admin} & claim_roles
+	input_admin := role_admins & claim_roles
 	count(input_admin) > 0
 } else if {
-	claim_roles := {role | some role in input.Roles}
-	input_user := {role_user} & claim_roles
-	count(input_user) > 0
+	count(known_roles) > 0
 	input.UserID == input.Subject
 }
+
+# Resource types a manager reads for the users in their department.
+manager_resources := {"PRODUCT"}
+
+default rule_department_manager := false
+
+rule_department_manager if {
+	role_manager in input.Roles
+	input.ResourceType in manager_resources
+	input.Department != ""
+	input.ResourceDepartment == input.Department
+}
+
+default rule_admin_subject_or_manager := false
+
+rule_admin_subject_or_manager if {
+	rule_admin_or_subject
+} else if {
+	rule_department_manager
+}
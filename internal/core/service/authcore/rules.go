@@ -6,11 +6,16 @@ import (
 
 // These the current set of rules we have for authcore.
 const (
-	RuleAuthenticate   = "auth"
-	RuleAny            = "rule_any"
-	RuleAdminOnly      = "rule_admin_only"
-	RuleUserOnly       = "rule_user_only"
-	RuleAdminOrSubject = "rule_admin_or_subject"
+	RuleAuthenticate          = "auth"
+	RuleAny                   = "rule_any"
+	RuleAdminOnly             = "rule_admin_only"
+	RuleSuperAdmin            = "rule_super_admin"
+	RuleUserOnly              = "rule_user_only"
+	RuleAdminOrSubject        = "rule_admin_or_subject"
+	RuleAdminSubjectOrManager = "rule_admin_subject_or_manager"
+	RulePermission            = "rule_permission"
+	RuleMFARequired           = "rule_mfa_required"
+	RuleEmailVerified         = "rule_email_verified"
 )
 
 // Package name of our rego code.
@@ -0,0 +1,50 @@
+package authcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// isSessionActive checks neither the token nor the session it was issued for
+// have been revoked. The revocation status is cached by the session core, so
+// this does not hit the database on every call. Tokens not bound to a session,
+// like the ones generated from the cli, are only checked by their ID.
+func (a *Auth) isSessionActive(ctx context.Context, claims Claims) error {
+	if a.sessionBus == nil {
+		return nil
+	}
+
+	if claims.ID != "" {
+		revoked, err := a.sessionBus.IsTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return fmt.Errorf("query token: %w", err)
+		}
+
+		if revoked {
+			return errors.New("token revoked")
+		}
+	}
+
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return fmt.Errorf("parse session: %w", err)
+	}
+
+	revoked, err := a.sessionBus.IsRevoked(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("query session: %w", err)
+	}
+
+	if revoked {
+		return errors.New("session revoked")
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package authcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrCrossTenant is returned for a caller asking for an organization other
+// than their own.
+var ErrCrossTenant = errors.New("organization not allowed")
+
+// Tenant returns the organization the requests of the claims are scoped to.
+// The super admin is not scoped, unless they ask for an organization to work
+// in. Any other caller is scoped to the organization of the token.
+func (a *Auth) Tenant(ctx context.Context, claims Claims, requested string) (uuid.UUID, bool, error) {
+	input := map[string]any{
+		"Roles": claims.Roles,
+	}
+
+	if err := a.opaPolicyEvaluation(ctx, RuleSuperAdmin, input); err == nil {
+		if requested == "" {
+			return uuid.Nil, false, nil
+		}
+
+		orgID, err := uuid.Parse(requested)
+		if err != nil {
+			return uuid.Nil, false, fmt.Errorf("parse organization: %w", err)
+		}
+
+		return orgID, true, nil
+	}
+
+	orgID, err := uuid.Parse(claims.Org)
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("token carries no organization: %w", err)
+	}
+
+	if requested != "" {
+		reqID, err := uuid.Parse(requested)
+		if err != nil || reqID != orgID {
+			return uuid.Nil, false, ErrCrossTenant
+		}
+	}
+
+	return orgID, true, nil
+}
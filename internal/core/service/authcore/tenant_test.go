@@ -0,0 +1,49 @@
+package authcore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_Tenant(t *testing.T) {
+	a, err := New(Config{})
+	if err != nil {
+		t.Fatalf("Should be able to create auth: %s", err)
+	}
+
+	orgA := uuid.New()
+	orgB := uuid.New()
+
+	admin := Claims{Roles: []string{"ADMIN"}, Org: orgA.String()}
+
+	orgID, scoped, err := a.Tenant(context.Background(), admin, "")
+	if err != nil || !scoped || orgID != orgA {
+		t.Fatalf("Should scope an admin to their organization: got %s %t %v", orgID, scoped, err)
+	}
+
+	if _, _, err := a.Tenant(context.Background(), admin, orgA.String()); err != nil {
+		t.Fatalf("Should let an admin ask for their organization: %s", err)
+	}
+
+	if _, _, err := a.Tenant(context.Background(), admin, orgB.String()); !errors.Is(err, ErrCrossTenant) {
+		t.Fatalf("Should refuse another organization: got %v", err)
+	}
+
+	if _, _, err := a.Tenant(context.Background(), Claims{Roles: []string{"USER"}}, ""); err == nil {
+		t.Fatal("Should refuse a token without organization")
+	}
+
+	super := Claims{Roles: []string{"SUPER_ADMIN"}, Org: orgA.String()}
+
+	if _, scoped, err := a.Tenant(context.Background(), super, ""); err != nil || scoped {
+		t.Fatalf("Should not scope a super admin: got %t %v", scoped, err)
+	}
+
+	orgID, scoped, err = a.Tenant(context.Background(), super, orgB.String())
+	if err != nil || !scoped || orgID != orgB {
+		t.Fatalf("Should scope a super admin to the organization asked: got %s %t %v", orgID, scoped, err)
+	}
+}
@@ -0,0 +1,137 @@
+package authcore
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/pkg/keystore"
+)
+
+func Test_Authenticate_TrustedIssuer(t *testing.T) {
+	remote := newTestAuth(t, "remote", nil)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var set keystore.JWKSet
+		for _, kid := range remote.keyLookup.KIDs() {
+			pem, _ := remote.keyLookup.PublicKey(kid)
+			alg, _ := remote.keyLookup.Algorithm(kid)
+			jwk, err := keystore.PublicPEMToJWK(kid, alg, pem)
+			if err != nil {
+				t.Errorf("Should be able to convert to jwk: %s", err)
+			}
+			set.Keys = append(set.Keys, jwk)
+		}
+
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	orgID := uuid.New()
+	ti := TrustedIssuer{
+		KeyLookup:   keystore.NewRemote(keystore.RemoteConfig{URL: srv.URL}),
+		Audience:    "local",
+		RoleMapping: map[string]string{"USER": "USER"},
+		OrgID:       orgID,
+	}
+
+	// No user nor session core is configured, the tokens of the trusted
+	// issuer are not checked against them.
+	local := newTestAuth(t, "local", map[string]TrustedIssuer{"remote": ti})
+
+	other := newTestAuth(t, "other", nil)
+
+	subject := uuid.NewString()
+
+	tests := []struct {
+		name     string
+		ath      *Auth
+		subject  string
+		audience string
+		roles    []string
+		purpose  string
+		ok       bool
+	}{
+		{name: "trusted", ath: remote, subject: subject, audience: "local", roles: []string{"USER", "ADMIN"}, ok: true},
+		{name: "purpose", ath: remote, subject: subject, audience: "local", roles: []string{"USER"}, purpose: PurposeInvite},
+		{name: "audience", ath: remote, subject: subject, audience: "other", roles: []string{"USER"}},
+		{name: "subject", ath: remote, subject: "subject", audience: "local", roles: []string{"USER"}},
+		{name: "unmapped", ath: remote, subject: subject, audience: "local", roles: []string{"SUPER_ADMIN"}},
+		{name: "untrusted", ath: other, subject: subject, audience: "local", roles: []string{"USER"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.ath.GenerateToken(Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Subject:   tt.subject,
+					Issuer:    tt.ath.Issuer(),
+					Audience:  jwt.ClaimStrings{tt.audience},
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+					IssuedAt:  jwt.NewNumericDate(time.Now()),
+				},
+				Roles:   tt.roles,
+				Org:     uuid.NewString(),
+				Purpose: tt.purpose,
+			})
+			if err != nil {
+				t.Fatalf("Should be able to generate a token: %s", err)
+			}
+
+			claims, err := local.Authenticate(context.Background(), "Bearer "+token)
+			if tt.ok != (err == nil) {
+				t.Fatalf("Should authenticate only the access tokens of a trusted issuer: got %v", err)
+			}
+
+			if !tt.ok {
+				return
+			}
+
+			if claims.Subject != subject || !slices.Equal(claims.Roles, []string{"USER"}) || claims.Org != orgID.String() {
+				t.Fatalf("Should keep the mapped roles in the organization of the issuer: got %+v", claims)
+			}
+		})
+	}
+
+	ti.RoleMapping = map[string]string{"ADMIN": "SUPER_ADMIN"}
+	if _, err := New(Config{KeyLookup: local.keyLookup, Issuer: "local", TrustedIssuers: map[string]TrustedIssuer{"remote": ti}}); err == nil {
+		t.Fatal("Should not grant a role working across the organizations")
+	}
+}
+
+func newTestAuth(t *testing.T, issuer string, trusted map[string]TrustedIssuer) *Auth {
+	t.Helper()
+
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshaling key: %s", err)
+	}
+
+	ks := keystore.New()
+	fsys := fstest.MapFS{
+		issuer + ".pem": {Data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})},
+	}
+	if err := ks.LoadKeys(fsys); err != nil {
+		t.Fatalf("Should be able to load keys: %s", err)
+	}
+
+	a, err := New(Config{KeyLookup: ks, Issuer: issuer, TrustedIssuers: trusted})
+	if err != nil {
+		t.Fatalf("Should be able to create auth: %s", err)
+	}
+
+	return a
+}
@@ -2,12 +2,25 @@ package authcore
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
 )
 
-// isUserEnabled hits the database and checks the user is not disabled
+// ErrEmailNotVerified is returned for a user the email verification rule
+// refuses.
+var ErrEmailNotVerified = errors.New("email not verified")
+
+// isUserEnabled checks the user is not disabled, still belongs to the
+// organization of the token, is allowed in by the email verification rule and
+// the token was not issued before the password was reset. The actor of an
+// impersonation must not be disabled either. The users are read through the
+// user store, which caches them when configured to.
 func (a *Auth) isUserEnabled(ctx context.Context, claims Claims) error {
 	userID, err := uuid.Parse(claims.Subject)
 	if err != nil {
@@ -23,5 +36,58 @@ func (a *Auth) isUserEnabled(ctx context.Context, claims Claims) error {
 		return fmt.Errorf("user disabled")
 	}
 
+	// A user moved to another organization needs a new token.
+	if claims.Org != "" && claims.Org != usr.OrgID.String() {
+		return fmt.Errorf("organization changed")
+	}
+
+	if err := a.EmailVerified(ctx, usr); err != nil {
+		return err
+	}
+
+	// The impersonation ends as soon as the admin acting is disabled.
+	if claims.Impersonated() {
+		actorID, err := uuid.Parse(claims.Actor.Subject)
+		if err != nil {
+			return fmt.Errorf("parse actor: %w", err)
+		}
+
+		actor, err := a.userBus.QueryByID(ctx, actorID)
+		if err != nil {
+			return fmt.Errorf("query actor: %w", err)
+		}
+
+		if !actor.Enabled {
+			return fmt.Errorf("actor disabled")
+		}
+	}
+
+	// The issued at claim has a precision of a second, a token issued within
+	// the second of the reset is accepted.
+	if !usr.TokensValidAfter.IsZero() {
+		if claims.IssuedAt == nil || claims.IssuedAt.Before(usr.TokensValidAfter.Truncate(time.Second)) {
+			return fmt.Errorf("token issued before the password was reset")
+		}
+	}
+
+	return nil
+}
+
+// EmailVerified checks the user against the email verification rule, when
+// the verification is required.
+func (a *Auth) EmailVerified(ctx context.Context, usr user.User) error {
+	if !a.requireVerifiedEmail {
+		return nil
+	}
+
+	input := map[string]any{
+		"EmailVerified": usr.EmailVerified,
+		"Roles":         role.ParseToString(usr.Roles),
+	}
+
+	if err := a.opaPolicyEvaluation(ctx, RuleEmailVerified, input); err != nil {
+		return ErrEmailNotVerified
+	}
+
 	return nil
 }
@@ -0,0 +1,128 @@
+// Package invitecore provides internal access to the invitations of new
+// users.
+package invitecore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/invite"
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/otel"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// defaultTTL is how long an invite is valid when none is configured.
+const defaultTTL = 72 * time.Hour
+
+// Core manages the set of APIs for invite access.
+type Core struct {
+	log    *logger.Logger
+	storer invite.Storer
+	ttl    time.Duration
+}
+
+// NewCore constructs an invite internal API for use. The invites expire after
+// the ttl.
+func NewCore(log *logger.Logger, storer invite.Storer, ttl time.Duration) *Core {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	return &Core{
+		log:    log,
+		storer: storer,
+		ttl:    ttl,
+	}
+}
+
+// NewWithTx constructs a new internal value that will use the
+// specified transaction in any store-related calls.
+func (c *Core) NewWithTx(tx pgsql.CommitRollbacker) (*Core, error) {
+	storer, err := c.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	core := Core{
+		log:    c.log,
+		storer: storer,
+		ttl:    c.ttl,
+	}
+
+	return &core, nil
+}
+
+// TTL returns how long an invite is valid.
+func (c *Core) TTL() time.Duration {
+	return c.ttl
+}
+
+// Create adds a new invite to the system.
+func (c *Core) Create(ctx context.Context, ni invite.NewInvite) (invite.Invite, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitecore.create")
+	defer span.End()
+
+	if err := organization.CheckRoles(ctx, ni.Roles); err != nil {
+		return invite.Invite{}, err
+	}
+
+	now := time.Now()
+
+	inv := invite.Invite{
+		ID:          uuid.New(),
+		OrgID:       organization.Resolve(ctx, ni.OrgID),
+		Email:       ni.Email,
+		Roles:       ni.Roles,
+		InvitedBy:   ni.InvitedBy,
+		ExpiresAt:   now.Add(c.ttl),
+		DateCreated: now,
+	}
+
+	if err := c.storer.Create(ctx, inv); err != nil {
+		return invite.Invite{}, fmt.Errorf("create: %w", err)
+	}
+
+	return inv, nil
+}
+
+// QueryPending finds the invite by the specified ID, it must not be accepted
+// nor expired.
+func (c *Core) QueryPending(ctx context.Context, inviteID uuid.UUID) (invite.Invite, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitecore.querypending")
+	defer span.End()
+
+	inv, err := c.storer.QueryByID(ctx, inviteID)
+	if err != nil {
+		return invite.Invite{}, fmt.Errorf("query: inviteID[%s]: %w", inviteID, err)
+	}
+
+	if inv.AcceptedAt != nil {
+		return invite.Invite{}, invite.ErrAccepted
+	}
+
+	if time.Now().After(inv.ExpiresAt) {
+		return invite.Invite{}, invite.ErrExpired
+	}
+
+	return inv, nil
+}
+
+// Accept marks the invite as accepted, it fails when it was accepted already.
+func (c *Core) Accept(ctx context.Context, inv invite.Invite) (invite.Invite, error) {
+	ctx, span := otel.AddSpan(ctx, "business.invitecore.accept")
+	defer span.End()
+
+	now := time.Now()
+
+	if err := c.storer.Accept(ctx, inv.ID, now); err != nil {
+		return invite.Invite{}, fmt.Errorf("accept: inviteID[%s]: %w", inv.ID, err)
+	}
+	inv.AcceptedAt = &now
+
+	return inv, nil
+}
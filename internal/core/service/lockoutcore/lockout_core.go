@@ -0,0 +1,227 @@
+// Package lockoutcore protects the login against brute force by counting the
+// failed attempts for an email and an ip address.
+package lockoutcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/lockout"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/otel"
+)
+
+// Default values used for the policy settings left unset.
+const (
+	defaultMaxFailures   = 5
+	defaultMaxIPFailures = 20
+	defaultBackoff       = time.Second
+	defaultDuration      = 15 * time.Minute
+)
+
+// Policy configures when logins are slowed down and locked. After every
+// failure for an email the next attempt has to wait for Backoff, doubling
+// with each failure, and once MaxFailures is reached the email is locked for
+// Duration. An ip address is shared by many users, so it is not slowed down
+// and gets its own limit. Failures older than Duration are forgotten.
+type Policy struct {
+	MaxFailures   int
+	MaxIPFailures int
+	Backoff       time.Duration
+	Duration      time.Duration
+}
+
+// Core manages the set of APIs for login attempts access.
+type Core struct {
+	log       *logger.Logger
+	auditCore *auditcore.Core
+	storer    lockout.Storer
+	policy    Policy
+}
+
+// NewCore constructs a lockout internal API for use. The lockout and unlock
+// events are recorded in the audit log.
+func NewCore(log *logger.Logger, auditCore *auditcore.Core, storer lockout.Storer, policy Policy) *Core {
+	if policy.MaxFailures == 0 {
+		policy.MaxFailures = defaultMaxFailures
+	}
+	if policy.MaxIPFailures == 0 {
+		policy.MaxIPFailures = defaultMaxIPFailures
+	}
+	if policy.Backoff == 0 {
+		policy.Backoff = defaultBackoff
+	}
+	if policy.Duration == 0 {
+		policy.Duration = defaultDuration
+	}
+
+	return &Core{
+		log:       log,
+		auditCore: auditCore,
+		storer:    storer,
+		policy:    policy,
+	}
+}
+
+// Check reports an error wrapping lockout.ErrLocked when a login for any of
+// the keys has to wait, either because the key is locked or because it is
+// still backing off from the last failure.
+func (c *Core) Check(ctx context.Context, keys ...string) error {
+	ctx, span := otel.AddSpan(ctx, "business.lockoutcore.check")
+	defer span.End()
+
+	now := time.Now()
+
+	for _, key := range keys {
+		att, err := c.storer.QueryByKey(ctx, key)
+		if err != nil {
+			if errors.Is(err, lockout.ErrNotFound) {
+				continue
+			}
+			return fmt.Errorf("query: key[%s]: %w", key, err)
+		}
+
+		if retry := c.retryAt(att); now.Before(retry) {
+			return fmt.Errorf("key[%s] retry at %s: %w", key, retry.Format(time.RFC3339), lockout.ErrLocked)
+		}
+	}
+
+	return nil
+}
+
+// Fail counts a failed login against every key and locks the keys reaching
+// their limit.
+func (c *Core) Fail(ctx context.Context, keys ...string) error {
+	ctx, span := otel.AddSpan(ctx, "business.lockoutcore.fail")
+	defer span.End()
+
+	now := time.Now()
+
+	for _, key := range keys {
+		att, err := c.storer.Fail(ctx, key, now, now.Add(-c.policy.Duration))
+		if err != nil {
+			return fmt.Errorf("fail: key[%s]: %w", key, err)
+		}
+
+		if att.LockedUntil != nil || att.Failures < c.maxFailures(key) {
+			continue
+		}
+
+		until := now.Add(c.policy.Duration)
+		if err := c.storer.Lock(ctx, key, until); err != nil {
+			return fmt.Errorf("lock: key[%s]: %w", key, err)
+		}
+		att.LockedUntil = &until
+
+		c.log.Info(ctx, "lockoutcore", "status", "login locked", "key", lockout.HashKey(key), "until", until)
+		c.audit(ctx, "locked", key, uuid.UUID{}, uuid.UUID{}, att)
+	}
+
+	return nil
+}
+
+// Succeed forgets the failed logins for a key after a successful login.
+func (c *Core) Succeed(ctx context.Context, key string) error {
+	ctx, span := otel.AddSpan(ctx, "business.lockoutcore.succeed")
+	defer span.End()
+
+	if err := c.storer.Delete(ctx, key); err != nil {
+		return fmt.Errorf("delete: key[%s]: %w", key, err)
+	}
+
+	return nil
+}
+
+// Unlock lets an administrator lift the lockout of a key. The object is the
+// user owning the key and the actor the administrator.
+func (c *Core) Unlock(ctx context.Context, key string, objID uuid.UUID, actorID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.lockoutcore.unlock")
+	defer span.End()
+
+	att, err := c.storer.QueryByKey(ctx, key)
+	if err != nil {
+		return fmt.Errorf("query: key[%s]: %w", key, err)
+	}
+
+	if err := c.storer.Delete(ctx, key); err != nil {
+		return fmt.Errorf("delete: key[%s]: %w", key, err)
+	}
+
+	c.audit(ctx, "unlocked", key, objID, actorID, att)
+
+	return nil
+}
+
+// QueryByKey finds the failed login attempts for the specified key.
+func (c *Core) QueryByKey(ctx context.Context, key string) (lockout.Attempts, error) {
+	ctx, span := otel.AddSpan(ctx, "business.lockoutcore.querybykey")
+	defer span.End()
+
+	att, err := c.storer.QueryByKey(ctx, key)
+	if err != nil {
+		return lockout.Attempts{}, fmt.Errorf("query: key[%s]: %w", key, err)
+	}
+
+	return att, nil
+}
+
+// =============================================================================
+
+// retryAt returns the time the next login for the key is allowed.
+func (c *Core) retryAt(att lockout.Attempts) time.Time {
+	if att.LockedUntil != nil {
+		return *att.LockedUntil
+	}
+
+	if att.Failures == 0 || lockout.IsIPKey(att.Key) {
+		return time.Time{}
+	}
+
+	// The wait doubles with every failure, it never exceeds a lockout.
+	backoff := c.policy.Backoff
+	for i := 1; i < att.Failures && backoff < c.policy.Duration; i++ {
+		backoff *= 2
+	}
+
+	return att.LastFailure.Add(min(backoff, c.policy.Duration))
+}
+
+func (c *Core) maxFailures(key string) int {
+	if lockout.IsIPKey(key) {
+		return c.policy.MaxIPFailures
+	}
+
+	return c.policy.MaxFailures
+}
+
+func (c *Core) audit(ctx context.Context, action string, key string, objID uuid.UUID, actorID uuid.UUID, att lockout.Attempts) {
+	if c.auditCore == nil {
+		return
+	}
+
+	// The key holds the email or the ip address, only its hash is recorded.
+	att.Key = lockout.HashKey(key)
+
+	na := audit.NewAudit{
+		ObjID:     objID,
+		ObjEntity: entity.Auth,
+		ObjName:   name.MustParse("login lockout"),
+		ActorID:   actorID,
+		Action:    action,
+		Data:      att,
+		Message:   att.Key,
+	}
+
+	// The event is recorded even when the request is canceled.
+	if _, err := c.auditCore.Create(context.WithoutCancel(ctx), na); err != nil {
+		c.log.Error(ctx, "lockoutcore", "status", "recording audit", "msg", err)
+	}
+}
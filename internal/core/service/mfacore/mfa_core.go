@@ -0,0 +1,246 @@
+// Package mfacore provides internal access to the totp based multi-factor
+// authentication of the users.
+package mfacore
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/mfa"
+	"github.com/Housiadas/backend-system/internal/core/domain/user"
+	"github.com/Housiadas/backend-system/pkg/encrypt"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/otel"
+	"github.com/Housiadas/backend-system/pkg/totp"
+)
+
+// recoveryCodes is the number of recovery codes issued on confirmation.
+const recoveryCodes = 10
+
+// Core manages the set of APIs for mfa access.
+type Core struct {
+	log     *logger.Logger
+	storer  mfa.Storer
+	keyring *encrypt.Keyring
+	issuer  string
+}
+
+// NewCore constructs an mfa internal API for use. The totp secrets are
+// encrypted with the keyring, the issuer is the name shown by authenticator
+// apps.
+func NewCore(log *logger.Logger, storer mfa.Storer, keyring *encrypt.Keyring, issuer string) *Core {
+	return &Core{
+		log:     log,
+		storer:  storer,
+		keyring: keyring,
+		issuer:  issuer,
+	}
+}
+
+// Enroll starts the enrolment of a user with a new totp secret. The
+// enrolment is not active until it is confirmed with a code. Starting over
+// replaces an enrolment not confirmed yet.
+func (c *Core) Enroll(ctx context.Context, usr user.User) (mfa.Enrolment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.mfacore.enroll")
+	defer span.End()
+
+	existing, err := c.storer.QueryByUserID(ctx, usr.ID)
+	switch {
+	case err == nil && existing.Confirmed:
+		return mfa.Enrolment{}, mfa.ErrAlreadyEnrolled
+	case err != nil && !errors.Is(err, mfa.ErrNotFound):
+		return mfa.Enrolment{}, fmt.Errorf("query: userID[%s]: %w", usr.ID, err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return mfa.Enrolment{}, err
+	}
+
+	encrypted, err := c.keyring.Encrypt([]byte(secret), usr.ID[:])
+	if err != nil {
+		return mfa.Enrolment{}, fmt.Errorf("encrypt: %w", err)
+	}
+
+	now := time.Now()
+
+	m := mfa.MFA{
+		UserID:      usr.ID,
+		Secret:      encrypted,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Upsert(ctx, m); err != nil {
+		return mfa.Enrolment{}, fmt.Errorf("upsert: %w", err)
+	}
+
+	enr := mfa.Enrolment{
+		Secret: secret,
+		URI:    totp.URI(c.issuer, usr.Email.Address, secret),
+	}
+
+	return enr, nil
+}
+
+// Confirm activates the enrolment of a user with a code from the
+// authenticator app and returns the recovery codes. The recovery codes are
+// only returned here, just their hashes are stored.
+func (c *Core) Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	ctx, span := otel.AddSpan(ctx, "business.mfacore.confirm")
+	defer span.End()
+
+	m, err := c.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	if m.Confirmed {
+		return nil, mfa.ErrAlreadyEnrolled
+	}
+
+	step, err := c.validate(m, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	m.Confirmed = true
+	m.LastUsedStep = step
+	m.RecoveryCodes = hashes
+	m.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, m); err != nil {
+		return nil, fmt.Errorf("update: %w", err)
+	}
+
+	return codes, nil
+}
+
+// Verify checks the second factor of a user, either a code from the
+// authenticator app or one of the recovery codes. Every code is accepted
+// only once.
+func (c *Core) Verify(ctx context.Context, userID uuid.UUID, code string) error {
+	ctx, span := otel.AddSpan(ctx, "business.mfacore.verify")
+	defer span.End()
+
+	m, err := c.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	if !m.Confirmed {
+		return mfa.ErrNotConfirmed
+	}
+
+	if step, err := c.validate(m, code); err == nil {
+		m.LastUsedStep = step
+	} else {
+		i := matchRecoveryCode(m.RecoveryCodes, code)
+		if i < 0 {
+			return mfa.ErrInvalidCode
+		}
+
+		m.RecoveryCodes = append(m.RecoveryCodes[:i:i], m.RecoveryCodes[i+1:]...)
+		c.log.Info(ctx, "mfacore", "status", "recovery code used", "userID", userID, "left", len(m.RecoveryCodes))
+	}
+
+	m.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, m); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	return nil
+}
+
+// IsEnrolled reports whether the user has a confirmed enrolment.
+func (c *Core) IsEnrolled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	ctx, span := otel.AddSpan(ctx, "business.mfacore.isenrolled")
+	defer span.End()
+
+	m, err := c.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mfa.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return m.Confirmed, nil
+}
+
+// =============================================================================
+
+// validate checks a totp code against the secret of the enrolment and
+// returns its time step. A code of a step already used is refused.
+func (c *Core) validate(m mfa.MFA, code string) (int64, error) {
+	secret, err := c.keyring.Decrypt(m.Secret, m.UserID[:])
+	if err != nil {
+		return 0, fmt.Errorf("decrypt: %w", err)
+	}
+
+	step, ok := totp.Validate(string(secret), code, time.Now())
+	if !ok || step <= m.LastUsedStep {
+		return 0, mfa.ErrInvalidCode
+	}
+
+	return step, nil
+}
+
+// newRecoveryCodes generates the recovery codes and their hashes. A code is
+// ten base32 characters, split in two for readability.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodes)
+	hashes := make([]string, recoveryCodes)
+
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("generating recovery code: %w", err)
+		}
+
+		code := strings.ToLower(enc.EncodeToString(b)[:10])
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// matchRecoveryCode returns the index of the hash matching the code or -1.
+func matchRecoveryCode(hashes []string, code string) int {
+	hash := []byte(hashRecoveryCode(code))
+
+	match := -1
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), hash) == 1 {
+			match = i
+		}
+	}
+
+	return match
+}
@@ -0,0 +1,334 @@
+// Package ordercore provides internal access to the orders, placing them
+// takes the stock of their products and every change of their status is
+// audited.
+package ordercore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/audit"
+	"github.com/Housiadas/backend-system/internal/core/domain/entity"
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/name"
+	"github.com/Housiadas/backend-system/internal/core/domain/order"
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/internal/core/service/auditcore"
+	"github.com/Housiadas/backend-system/internal/core/service/pricecore"
+	"github.com/Housiadas/backend-system/internal/core/service/productcore"
+	"github.com/Housiadas/backend-system/internal/core/service/stockcore"
+	"github.com/Housiadas/backend-system/internal/core/service/usercore"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	orderPck "github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/otel"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// auditName is the name the audit records of the orders are kept under.
+var auditName = name.MustParse("order")
+
+// Core manages the set of APIs for order access.
+type Core struct {
+	log        *logger.Logger
+	userBus    *usercore.Core
+	productBus *productcore.Core
+	stockBus   *stockcore.Core
+	priceBus   *pricecore.Core
+	auditCore  *auditcore.Core
+	storer     order.Storer
+}
+
+// NewCore constructs an order internal API for use.
+func NewCore(
+	log *logger.Logger,
+	userBus *usercore.Core,
+	productBus *productcore.Core,
+	stockBus *stockcore.Core,
+	priceBus *pricecore.Core,
+	auditCore *auditcore.Core,
+	storer order.Storer,
+) *Core {
+	return &Core{
+		log:        log,
+		userBus:    userBus,
+		productBus: productBus,
+		stockBus:   stockBus,
+		priceBus:   priceBus,
+		auditCore:  auditCore,
+		storer:     storer,
+	}
+}
+
+// NewWithTx constructs a new internal value that will use the
+// specified transaction in any store-related calls.
+func (c *Core) NewWithTx(tx pgsql.CommitRollbacker) (*Core, error) {
+	storer, err := c.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	userBus, err := c.userBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	productBus, err := c.productBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	stockBus, err := c.stockBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	priceBus, err := c.priceBus.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	auditCore, err := c.auditCore.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	core := Core{
+		log:        c.log,
+		userBus:    userBus,
+		productBus: productBus,
+		stockBus:   stockBus,
+		priceBus:   priceBus,
+		auditCore:  auditCore,
+		storer:     storer,
+	}
+
+	return &core, nil
+}
+
+// Create places a new order and takes the stock of its products. It must be
+// run in a transaction, so the stock already taken is given back when an
+// item can't be ordered. The items are priced at the prices in effect when
+// the order is placed, a scheduled price counts once due even before it is
+// applied to the product.
+func (c *Core) Create(ctx context.Context, no order.NewOrder) (order.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.ordercore.create")
+	defer span.End()
+
+	if len(no.Items) == 0 {
+		return order.Order{}, order.ErrNoItems
+	}
+
+	usr, err := c.userBus.QueryByID(ctx, no.UserID)
+	if err != nil {
+		return order.Order{}, fmt.Errorf("user.querybyid: %s: %w", no.UserID, err)
+	}
+
+	if !usr.Enabled {
+		return order.Order{}, order.ErrUserDisabled
+	}
+
+	// The stock is taken in the order of the products, so concurrent orders
+	// of the same products wait for each other instead of deadlocking.
+	nis := slices.Clone(no.Items)
+	slices.SortFunc(nis, func(a, b order.NewItem) int {
+		return bytes.Compare(a.ProductID[:], b.ProductID[:])
+	})
+
+	now := time.Now()
+
+	// The products may belong to other users the caller can't reach, their
+	// price is read and their stock taken for the service.
+	catalogCtx := pgsql.WithoutCaller(ctx)
+
+	items := make([]order.Item, len(nis))
+	var total money.Money
+
+	for i, ni := range nis {
+		if i > 0 && nis[i-1].ProductID == ni.ProductID {
+			return order.Order{}, fmt.Errorf("productID[%s]: %w", ni.ProductID, order.ErrDuplicateItem)
+		}
+
+		prd, err := c.productBus.QueryByID(catalogCtx, ni.ProductID)
+		if err != nil {
+			return order.Order{}, fmt.Errorf("product.querybyid: %s: %w", ni.ProductID, err)
+		}
+
+		pr, err := c.priceBus.PriceAt(catalogCtx, prd.ID, now)
+		if err != nil {
+			return order.Order{}, fmt.Errorf("price: %w", err)
+		}
+
+		if i == 0 {
+			total = money.Zero(pr.Cost.Currency())
+		}
+
+		lineTotal, err := pr.Cost.Mul(int64(ni.Quantity.Value()))
+		if err != nil {
+			return order.Order{}, fmt.Errorf("total: productID[%s]: %w", ni.ProductID, err)
+		}
+
+		total, err = total.Add(lineTotal)
+		if err != nil {
+			return order.Order{}, fmt.Errorf("total: productID[%s]: %w: %w", ni.ProductID, order.ErrCurrencyMismatch, err)
+		}
+
+		_, err = c.stockBus.Move(catalogCtx, stock.NewMovement{
+			ProductID: ni.ProductID,
+			Kind:      stock.Sale,
+			Quantity:  ni.Quantity.Value(),
+		})
+		if err != nil {
+			return order.Order{}, fmt.Errorf("stock: %w", err)
+		}
+
+		items[i] = order.Item{
+			ID:        uuid.New(),
+			ProductID: prd.ID,
+			Name:      prd.Name,
+			Price:     pr.Cost,
+			Quantity:  ni.Quantity,
+			Total:     lineTotal,
+		}
+	}
+
+	ord := order.Order{
+		ID:          uuid.New(),
+		OrgID:       usr.OrgID,
+		UserID:      no.UserID,
+		Status:      order.Pending,
+		Total:       total,
+		Items:       items,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, ord); err != nil {
+		return order.Order{}, fmt.Errorf("create: %w", err)
+	}
+
+	if err := c.audit(ctx, ord, no.UserID, "placed", struct {
+		Status string
+		Total  money.Money
+	}{
+		Status: ord.Status.String(),
+		Total:  ord.Total,
+	}); err != nil {
+		return order.Order{}, err
+	}
+
+	return ord, nil
+}
+
+// UpdateStatus moves the order to another status, a cancelled order gives
+// the stock of its products back. It must be run in a transaction, like
+// Create.
+func (c *Core) UpdateStatus(ctx context.Context, ord order.Order, us order.UpdateStatus) (order.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.ordercore.updatestatus")
+	defer span.End()
+
+	if !ord.Status.CanMoveTo(us.Status) {
+		return order.Order{}, fmt.Errorf("%s to %s: %w", ord.Status, us.Status, order.ErrInvalidTransition)
+	}
+
+	now := time.Now()
+
+	if err := c.storer.UpdateStatus(ctx, ord, us.Status, now); err != nil {
+		return order.Order{}, fmt.Errorf("updatestatus: orderID[%s]: %w", ord.ID, err)
+	}
+
+	if us.Status.Equal(order.Cancelled) {
+		for _, itm := range ord.Items {
+			// The product was removed along with its stock.
+			if itm.ProductID == uuid.Nil {
+				continue
+			}
+
+			// The stock is given back for the service, like it was taken.
+			_, err := c.stockBus.Move(pgsql.WithoutCaller(ctx), stock.NewMovement{
+				ProductID: itm.ProductID,
+				Kind:      stock.Receipt,
+				Quantity:  itm.Quantity.Value(),
+			})
+			if err != nil {
+				return order.Order{}, fmt.Errorf("restock: productID[%s]: %w", itm.ProductID, err)
+			}
+		}
+	}
+
+	from := ord.Status
+	ord.Status = us.Status
+	ord.DateUpdated = now
+
+	if err := c.audit(ctx, ord, us.ActorID, strings.ToLower(us.Status.String()), struct {
+		From string
+		To   string
+	}{
+		From: from.String(),
+		To:   us.Status.String(),
+	}); err != nil {
+		return order.Order{}, err
+	}
+
+	return ord, nil
+}
+
+// QueryByID finds the order by the specified ID.
+func (c *Core) QueryByID(ctx context.Context, orderID uuid.UUID) (order.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.ordercore.querybyid")
+	defer span.End()
+
+	ord, err := c.storer.QueryByID(ctx, orderID)
+	if err != nil {
+		return order.Order{}, fmt.Errorf("query: orderID[%s]: %w", orderID, err)
+	}
+
+	return ord, nil
+}
+
+// Query retrieves a list of existing orders.
+func (c *Core) Query(ctx context.Context, filter order.QueryFilter, orderBy orderPck.By, page page.Page) ([]order.Order, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.ordercore.query")
+	defer span.End()
+
+	ords, err := c.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return ords, nil
+}
+
+// Count returns the total number of orders.
+func (c *Core) Count(ctx context.Context, filter order.QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.ordercore.count")
+	defer span.End()
+
+	return c.storer.Count(ctx, filter)
+}
+
+// audit records the change of the order along with the actor who made it.
+func (c *Core) audit(ctx context.Context, ord order.Order, actorID uuid.UUID, action string, data any) error {
+	na := audit.NewAudit{
+		ObjID:     ord.ID,
+		ObjEntity: entity.Order,
+		ObjName:   auditName,
+		ActorID:   actorID,
+		Action:    action,
+		Data:      data,
+		Message:   "order " + action,
+		OrgID:     ord.OrgID,
+	}
+
+	if _, err := c.auditCore.Create(ctx, na); err != nil {
+		return fmt.Errorf("audit: orderID[%s]: %w", ord.ID, err)
+	}
+
+	return nil
+}
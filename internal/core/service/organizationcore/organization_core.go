@@ -0,0 +1,85 @@
+// Package organizationcore provides internal access to the organizations,
+// the tenants of the system.
+package organizationcore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/otel"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+// Core manages the set of APIs for organization access.
+type Core struct {
+	log    *logger.Logger
+	storer organization.Storer
+}
+
+// NewCore constructs an organization internal API for use.
+func NewCore(log *logger.Logger, storer organization.Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Create adds a new organization to the system.
+func (c *Core) Create(ctx context.Context, no organization.NewOrganization) (organization.Organization, error) {
+	ctx, span := otel.AddSpan(ctx, "business.organizationcore.create")
+	defer span.End()
+
+	now := time.Now()
+
+	org := organization.Organization{
+		ID:          uuid.New(),
+		Name:        no.Name,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, org); err != nil {
+		return organization.Organization{}, fmt.Errorf("create: %w", err)
+	}
+
+	return org, nil
+}
+
+// Query retrieves a list of existing organizations.
+func (c *Core) Query(ctx context.Context, page page.Page) ([]organization.Organization, error) {
+	ctx, span := otel.AddSpan(ctx, "business.organizationcore.query")
+	defer span.End()
+
+	orgs, err := c.storer.Query(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// Count returns the total number of organizations.
+func (c *Core) Count(ctx context.Context) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.organizationcore.count")
+	defer span.End()
+
+	return c.storer.Count(ctx)
+}
+
+// QueryByID finds the organization by the specified ID.
+func (c *Core) QueryByID(ctx context.Context, orgID uuid.UUID) (organization.Organization, error) {
+	ctx, span := otel.AddSpan(ctx, "business.organizationcore.querybyid")
+	defer span.End()
+
+	org, err := c.storer.QueryByID(ctx, orgID)
+	if err != nil {
+		return organization.Organization{}, fmt.Errorf("query: orgID[%s]: %w", orgID, err)
+	}
+
+	return org, nil
+}
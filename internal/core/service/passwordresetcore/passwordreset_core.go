@@ -0,0 +1,162 @@
+// Package passwordresetcore provides internal access to the single use tokens
+// resetting the password of a user.
+package passwordresetcore
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/passwordreset"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/otel"
+)
+
+// defaultTTL is how long a reset token is valid when none is configured.
+const defaultTTL = 30 * time.Minute
+
+// Core manages the set of APIs for password reset access.
+type Core struct {
+	log    *logger.Logger
+	storer passwordreset.Storer
+	ttl    time.Duration
+}
+
+// NewCore constructs a password reset internal API for use. The tokens
+// expire after the ttl.
+func NewCore(log *logger.Logger, storer passwordreset.Storer, ttl time.Duration) *Core {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	return &Core{
+		log:    log,
+		storer: storer,
+		ttl:    ttl,
+	}
+}
+
+// TTL returns how long a reset token is valid.
+func (c *Core) TTL() time.Duration {
+	return c.ttl
+}
+
+// Create starts a password reset for a user and returns the token for it.
+// The token is only returned here, just its hash is stored.
+func (c *Core) Create(ctx context.Context, userID uuid.UUID) (passwordreset.Reset, string, error) {
+	ctx, span := otel.AddSpan(ctx, "business.passwordresetcore.create")
+	defer span.End()
+
+	secret, hash, err := newSecret()
+	if err != nil {
+		return passwordreset.Reset{}, "", err
+	}
+
+	now := time.Now()
+
+	r := passwordreset.Reset{
+		ID:          uuid.New(),
+		UserID:      userID,
+		TokenHash:   hash,
+		ExpiresAt:   now.Add(c.ttl),
+		DateCreated: now,
+	}
+
+	if err := c.storer.Create(ctx, r); err != nil {
+		return passwordreset.Reset{}, "", fmt.Errorf("create: %w", err)
+	}
+
+	return r, toToken(r.ID, secret), nil
+}
+
+// Redeem checks the token and marks it as used, along with every other token
+// of the user not used yet. The reset is returned to know the user.
+func (c *Core) Redeem(ctx context.Context, token string) (passwordreset.Reset, error) {
+	ctx, span := otel.AddSpan(ctx, "business.passwordresetcore.redeem")
+	defer span.End()
+
+	resetID, secret, err := parseToken(token)
+	if err != nil {
+		return passwordreset.Reset{}, err
+	}
+
+	r, err := c.storer.QueryByID(ctx, resetID)
+	if err != nil {
+		if errors.Is(err, passwordreset.ErrNotFound) {
+			return passwordreset.Reset{}, passwordreset.ErrInvalidToken
+		}
+		return passwordreset.Reset{}, fmt.Errorf("query: resetID[%s]: %w", resetID, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(r.TokenHash)) != 1 {
+		return passwordreset.Reset{}, passwordreset.ErrInvalidToken
+	}
+
+	if r.UsedAt != nil {
+		return passwordreset.Reset{}, passwordreset.ErrUsed
+	}
+
+	now := time.Now()
+
+	if now.After(r.ExpiresAt) {
+		return passwordreset.Reset{}, passwordreset.ErrExpired
+	}
+
+	if err := c.storer.Use(ctx, r.ID, now); err != nil {
+		return passwordreset.Reset{}, fmt.Errorf("use: resetID[%s]: %w", r.ID, err)
+	}
+	r.UsedAt = &now
+
+	if err := c.storer.UseByUserID(ctx, r.UserID, now); err != nil {
+		return passwordreset.Reset{}, fmt.Errorf("use: userID[%s]: %w", r.UserID, err)
+	}
+
+	return r, nil
+}
+
+// =============================================================================
+
+// newSecret generates a random reset token secret and its hash.
+func newSecret() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generating secret: %w", err)
+	}
+
+	secret := base64.RawURLEncoding.EncodeToString(b)
+
+	return secret, hashSecret(secret), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// toToken builds a reset token in the form <reset id>.<secret>.
+func toToken(resetID uuid.UUID, secret string) string {
+	return resetID.String() + "." + secret
+}
+
+func parseToken(token string) (uuid.UUID, string, error) {
+	id, secret, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return uuid.UUID{}, "", passwordreset.ErrInvalidToken
+	}
+
+	resetID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.UUID{}, "", passwordreset.ErrInvalidToken
+	}
+
+	return resetID, secret, nil
+}
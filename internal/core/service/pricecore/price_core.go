@@ -0,0 +1,179 @@
+// Package pricecore provides internal access to the history of the prices of
+// the products and the prices scheduled to take effect later on.
+package pricecore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/otel"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// applyBatch is the number of due prices applied at a time.
+const applyBatch = 100
+
+// Core manages the set of APIs for price access.
+type Core struct {
+	log    *logger.Logger
+	storer price.Storer
+}
+
+// NewCore constructs a price internal API for use.
+func NewCore(log *logger.Logger, storer price.Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// NewWithTx constructs a new internal value that will use the
+// specified transaction in any store-related calls.
+func (c *Core) NewWithTx(tx pgsql.CommitRollbacker) (*Core, error) {
+	storer, err := c.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	core := Core{
+		log:    c.log,
+		storer: storer,
+	}
+
+	return &core, nil
+}
+
+// Schedule sets a price of the product to take effect later on, the cost of
+// the product is left as it is until then.
+func (c *Core) Schedule(ctx context.Context, prd product.Product, np price.NewPrice) (price.Price, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.pricecore.schedule")
+	defer span.End()
+
+	if err := product.CheckCost(np.Cost); err != nil {
+		return price.Price{}, price.ErrInvalidCost
+	}
+
+	now := time.Now()
+
+	if !np.EffectiveFrom.After(now) {
+		return price.Price{}, price.ErrNotInFuture
+	}
+
+	pr := price.Price{
+		ID:            uuid.New(),
+		OrgID:         prd.OrgID,
+		ProductID:     prd.ID,
+		Cost:          np.Cost,
+		EffectiveFrom: np.EffectiveFrom,
+		DateCreated:   now,
+	}
+
+	if err := c.storer.Create(ctx, pr); err != nil {
+		return price.Price{}, fmt.Errorf("create: productID[%s]: %w", prd.ID, err)
+	}
+
+	return pr, nil
+}
+
+// ApplyDue sets the cost of the products to their prices that took effect
+// and returns how many were applied.
+func (c *Core) ApplyDue(ctx context.Context) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.pricecore.applydue")
+	defer span.End()
+
+	var applied int
+	for {
+		now := time.Now()
+
+		prs, err := c.storer.QueryDue(ctx, now, applyBatch)
+		if err != nil {
+			return applied, fmt.Errorf("query due: %w", err)
+		}
+
+		if len(prs) == 0 {
+			return applied, nil
+		}
+
+		for _, pr := range prs {
+			err := c.storer.Apply(ctx, pr, now)
+			switch {
+			case err == nil:
+				applied++
+
+			// The price was applied by another instance in the meantime.
+			case errors.Is(err, price.ErrAlreadyApplied):
+
+			default:
+				return applied, fmt.Errorf("apply: priceID[%s]: %w", pr.ID, err)
+			}
+		}
+	}
+}
+
+// WatchDue applies the due prices every interval until the context is
+// canceled.
+func (c *Core) WatchDue(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			n, err := c.ApplyDue(ctx)
+			if err != nil {
+				c.log.Error(ctx, "pricecore: apply due", "msg", err)
+			}
+
+			if n > 0 {
+				c.log.Info(ctx, "pricecore: apply due", "applied", n)
+			}
+		}
+	}
+}
+
+// PriceAt returns the price of the product in effect at the time. A price
+// that took effect is returned even before it is applied to the product.
+func (c *Core) PriceAt(ctx context.Context, productID uuid.UUID, at time.Time) (price.Price, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.pricecore.priceat")
+	defer span.End()
+
+	pr, err := c.storer.QueryAt(ctx, productID, at)
+	if err != nil {
+		return price.Price{}, fmt.Errorf("query at: productID[%s]: %w", productID, err)
+	}
+
+	return pr, nil
+}
+
+// Query retrieves a list of existing prices.
+func (c *Core) Query(ctx context.Context, filter price.QueryFilter, orderBy order.By, page page.Page) ([]price.Price, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.pricecore.query")
+	defer span.End()
+
+	prs, err := c.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return prs, nil
+}
+
+// Count returns the total number of prices.
+func (c *Core) Count(ctx context.Context, filter price.QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.pricecore.count")
+	defer span.End()
+
+	return c.storer.Count(ctx, filter)
+}
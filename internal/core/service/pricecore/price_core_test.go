@@ -0,0 +1,195 @@
+package pricecore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/money"
+	"github.com/Housiadas/backend-system/internal/core/domain/price"
+	"github.com/Housiadas/backend-system/internal/core/domain/product"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+type memStore struct {
+	prices []price.Price
+	cost   map[uuid.UUID]money.Money
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		cost: make(map[uuid.UUID]money.Money),
+	}
+}
+
+func (s *memStore) NewWithTx(_ pgsql.CommitRollbacker) (price.Storer, error) {
+	return s, nil
+}
+
+func (s *memStore) Create(_ context.Context, pr price.Price) error {
+	s.prices = append(s.prices, pr)
+	return nil
+}
+
+func (s *memStore) Apply(_ context.Context, pr price.Price, now time.Time) error {
+	for i := range s.prices {
+		if s.prices[i].ID != pr.ID {
+			continue
+		}
+
+		if s.prices[i].IsApplied() {
+			return price.ErrAlreadyApplied
+		}
+
+		s.prices[i].DateApplied = now
+		s.cost[pr.ProductID] = pr.Cost
+
+		return nil
+	}
+
+	return price.ErrAlreadyApplied
+}
+
+func (s *memStore) QueryAt(_ context.Context, productID uuid.UUID, at time.Time) (price.Price, error) {
+	var found *price.Price
+	for i, pr := range s.prices {
+		if pr.ProductID != productID || pr.EffectiveFrom.After(at) {
+			continue
+		}
+
+		if found == nil || pr.EffectiveFrom.After(found.EffectiveFrom) {
+			found = &s.prices[i]
+		}
+	}
+
+	if found == nil {
+		return price.Price{}, price.ErrNotFound
+	}
+
+	return *found, nil
+}
+
+func (s *memStore) QueryDue(_ context.Context, now time.Time, limit int) ([]price.Price, error) {
+	var prs []price.Price
+	for _, pr := range s.prices {
+		if !pr.IsApplied() && !pr.EffectiveFrom.After(now) && len(prs) < limit {
+			prs = append(prs, pr)
+		}
+	}
+
+	return prs, nil
+}
+
+func (s *memStore) Query(_ context.Context, _ price.QueryFilter, _ order.By, _ page.Page) ([]price.Price, error) {
+	return s.prices, nil
+}
+
+func (s *memStore) Count(_ context.Context, _ price.QueryFilter) (int, error) {
+	return len(s.prices), nil
+}
+
+func newTestCore(store price.Storer) *Core {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", func(context.Context) string { return "" }, func(context.Context) string { return "" })
+
+	return NewCore(log, store)
+}
+
+func Test_Schedule(t *testing.T) {
+	ctx := context.Background()
+	prd := product.Product{ID: uuid.New(), OrgID: uuid.New()}
+
+	tests := []struct {
+		name          string
+		cost          money.Money
+		effectiveFrom time.Time
+		err           error
+	}{
+		{name: "future", cost: money.MustParse("12.50", money.USD), effectiveFrom: time.Now().Add(time.Hour)},
+		{name: "free", cost: money.Zero(money.USD), effectiveFrom: time.Now().Add(time.Hour)},
+		{name: "negative", cost: money.MustParse("-1", money.USD), effectiveFrom: time.Now().Add(time.Hour), err: price.ErrInvalidCost},
+		{name: "range", cost: money.MustParse("1000000000000000", money.USD), effectiveFrom: time.Now().Add(time.Hour), err: price.ErrInvalidCost},
+		{name: "past", cost: money.MustParse("1", money.USD), effectiveFrom: time.Now().Add(-time.Hour), err: price.ErrNotInFuture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemStore()
+			core := newTestCore(store)
+
+			pr, err := core.Schedule(ctx, prd, price.NewPrice{ProductID: prd.ID, Cost: tt.cost, EffectiveFrom: tt.effectiveFrom})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Should get the expected error: got %v, exp %v", err, tt.err)
+			}
+
+			if tt.err != nil {
+				if len(store.prices) != 0 {
+					t.Fatalf("Should not keep a refused price: got %d", len(store.prices))
+				}
+				return
+			}
+
+			if pr.IsApplied() || pr.OrgID != prd.OrgID {
+				t.Fatalf("Should keep the price pending in the organization of the product: %+v", pr)
+			}
+		})
+	}
+}
+
+func Test_ApplyDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	productID := uuid.New()
+
+	store := newMemStore()
+	for i := range applyBatch + 5 {
+		store.prices = append(store.prices, price.Price{
+			ID:            uuid.New(),
+			ProductID:     productID,
+			Cost:          money.New(int64(i), money.USD),
+			EffectiveFrom: now.Add(-time.Duration(applyBatch+5-i) * time.Minute),
+		})
+	}
+
+	pending := price.Price{
+		ID:            uuid.New(),
+		ProductID:     productID,
+		Cost:          money.MustParse("99", money.USD),
+		EffectiveFrom: now.Add(time.Hour),
+	}
+	store.prices = append(store.prices, pending)
+
+	core := newTestCore(store)
+
+	n, err := core.ApplyDue(ctx)
+	if err != nil {
+		t.Fatalf("Should apply the due prices: %s", err)
+	}
+
+	if n != applyBatch+5 {
+		t.Fatalf("Should apply every due price over the batches: got %d, exp %d", n, applyBatch+5)
+	}
+
+	if got := store.cost[productID]; !got.Equal(money.New(applyBatch+4, money.USD)) {
+		t.Fatalf("Should leave the product at the latest due price: got %s", got)
+	}
+
+	pr, err := core.PriceAt(ctx, productID, now)
+	if err != nil {
+		t.Fatalf("Should get the price in effect: %s", err)
+	}
+
+	if pr.ID == pending.ID {
+		t.Fatalf("Should not be in effect before its time: %s", pr.ID)
+	}
+
+	if n, _ := core.ApplyDue(ctx); n != 0 {
+		t.Fatalf("Should apply a price once: got %d", n)
+	}
+}
@@ -75,10 +75,15 @@ func (c *Core) Create(ctx context.Context, np product.NewProduct) (product.Produ
 		return product.Product{}, product.ErrUserDisabled
 	}
 
+	if err := product.CheckCost(np.Cost); err != nil {
+		return product.Product{}, err
+	}
+
 	now := time.Now()
 
 	prd := product.Product{
 		ID:          uuid.New(),
+		OrgID:       usr.OrgID,
 		Name:        np.Name,
 		Cost:        np.Cost,
 		Quantity:    np.Quantity,
@@ -94,23 +99,26 @@ func (c *Core) Create(ctx context.Context, np product.NewProduct) (product.Produ
 	return prd, nil
 }
 
-// Update modifies information about a product.
+// Update modifies information about a product. A new quantity replaces the
+// stock and is recorded as an adjustment by its difference with the quantity
+// the product has when updated, and a new cost as a price in effect from now.
+// Without a new quantity the stock moved since the product was read is kept.
 func (c *Core) Update(ctx context.Context, prd product.Product, up product.UpdateProduct) (product.Product, error) {
 	if up.Name != nil {
 		prd.Name = *up.Name
 	}
 
 	if up.Cost != nil {
+		if err := product.CheckCost(*up.Cost); err != nil {
+			return product.Product{}, err
+		}
 		prd.Cost = *up.Cost
 	}
 
-	if up.Quantity != nil {
-		prd.Quantity = *up.Quantity
-	}
-
 	prd.DateUpdated = time.Now()
 
-	if err := c.storer.Update(ctx, prd); err != nil {
+	prd, err := c.storer.Update(ctx, prd, up.Quantity)
+	if err != nil {
 		return product.Product{}, fmt.Errorf("update: %w", err)
 	}
 
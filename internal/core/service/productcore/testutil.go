@@ -23,7 +23,7 @@ func TestGenerateNewProducts(n int, userID uuid.UUID) []product.NewProduct {
 
 		np := product.NewProduct{
 			Name:     name.MustParse(fmt.Sprintf("Name%d", idx)),
-			Cost:     money.MustParse(float64(rand.Intn(500))),
+			Cost:     money.New(int64(rand.Intn(50000)), money.DefaultCurrency),
 			Quantity: quantity.MustParse(rand.Intn(50)),
 			UserID:   userID,
 		}
@@ -0,0 +1,210 @@
+// Package rbaccore provides internal access to the roles and the permissions
+// granted to them.
+package rbaccore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/viccon/sturdyc"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/permission"
+	"github.com/Housiadas/backend-system/internal/core/domain/rbac"
+	"github.com/Housiadas/backend-system/internal/core/domain/role"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/otel"
+)
+
+// permissionsTTL is how long the permissions of a role are cached. A change
+// made by another instance of the service applies at the latest after this
+// period.
+const permissionsTTL = time.Minute
+
+// lookupTimeout bounds the query of a role unknown to this instance.
+const lookupTimeout = 5 * time.Second
+
+// Core manages the set of APIs for role access.
+type Core struct {
+	log         *logger.Logger
+	storer      rbac.Storer
+	permissions *sturdyc.Client[[]permission.Permission]
+}
+
+// NewCore constructs a role internal API for use.
+func NewCore(log *logger.Logger, storer rbac.Storer) *Core {
+	const capacity = 1000
+	const numShards = 10
+	const evictionPercentage = 10
+
+	return &Core{
+		log:         log,
+		storer:      storer,
+		permissions: sturdyc.New[[]permission.Permission](capacity, numShards, permissionsTTL, evictionPercentage),
+	}
+}
+
+// Load registers the roles stored in the database so they can be parsed. The
+// roles created later by another instance of the service are looked up in the
+// database the first time they are parsed.
+func (c *Core) Load(ctx context.Context) error {
+	ctx, span := otel.AddSpan(ctx, "business.rbaccore.load")
+	defer span.End()
+
+	roles, err := c.storer.Query(ctx)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	for _, r := range roles {
+		role.Register(r.Name)
+	}
+
+	role.SetLookup(c.exists)
+
+	return nil
+}
+
+// exists reports whether the role is stored in the database.
+func (c *Core) exists(value string) bool {
+	r, err := role.New(value)
+	if err != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	if _, err := c.storer.QueryByName(ctx, r); err != nil {
+		if !errors.Is(err, rbac.ErrNotFound) {
+			c.log.Error(ctx, "rbaccore: lookup role", "role", value, "msg", err)
+		}
+		return false
+	}
+
+	return true
+}
+
+// Create adds a new role to the system.
+func (c *Core) Create(ctx context.Context, nr rbac.NewRole) (rbac.Role, error) {
+	ctx, span := otel.AddSpan(ctx, "business.rbaccore.create")
+	defer span.End()
+
+	now := time.Now()
+
+	r := rbac.Role{
+		Name:        nr.Name,
+		Description: nr.Description,
+		Permissions: nr.Permissions,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, r); err != nil {
+		return rbac.Role{}, fmt.Errorf("create: %w", err)
+	}
+
+	role.Register(r.Name)
+
+	return r, nil
+}
+
+// Update modifies information about a role.
+func (c *Core) Update(ctx context.Context, r rbac.Role, ur rbac.UpdateRole) (rbac.Role, error) {
+	ctx, span := otel.AddSpan(ctx, "business.rbaccore.update")
+	defer span.End()
+
+	if ur.Description != nil {
+		r.Description = *ur.Description
+	}
+
+	if ur.Permissions != nil {
+		r.Permissions = ur.Permissions
+	}
+
+	r.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, r); err != nil {
+		return rbac.Role{}, fmt.Errorf("update: %w", err)
+	}
+
+	c.permissions.Delete(r.Name.String())
+
+	return r, nil
+}
+
+// Delete removes the specified role. The built-in roles can't be removed.
+// Users keep the role in their claims until it is removed from them, but it
+// no longer grants any permission.
+func (c *Core) Delete(ctx context.Context, r rbac.Role) error {
+	ctx, span := otel.AddSpan(ctx, "business.rbaccore.delete")
+	defer span.End()
+
+	if r.Name.IsBuiltIn() {
+		return rbac.ErrBuiltIn
+	}
+
+	if err := c.storer.Delete(ctx, r); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	role.Unregister(r.Name)
+	c.permissions.Delete(r.Name.String())
+
+	return nil
+}
+
+// Query retrieves all the roles.
+func (c *Core) Query(ctx context.Context) ([]rbac.Role, error) {
+	ctx, span := otel.AddSpan(ctx, "business.rbaccore.query")
+	defer span.End()
+
+	roles, err := c.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return roles, nil
+}
+
+// QueryByName finds the role by the specified name.
+func (c *Core) QueryByName(ctx context.Context, name role.Role) (rbac.Role, error) {
+	ctx, span := otel.AddSpan(ctx, "business.rbaccore.querybyname")
+	defer span.End()
+
+	r, err := c.storer.QueryByName(ctx, name)
+	if err != nil {
+		return rbac.Role{}, fmt.Errorf("query: name[%s]: %w", name, err)
+	}
+
+	return r, nil
+}
+
+// Permissions returns the effective permissions of the specified roles, the
+// union of the permissions granted to each of them. The permissions of each
+// role are cached so the database is not hit on every authorized call.
+func (c *Core) Permissions(ctx context.Context, roles []role.Role) ([]permission.Permission, error) {
+	var perms []permission.Permission
+
+	for _, r := range roles {
+		rolePerms, ok := c.permissions.Get(r.String())
+		if !ok {
+			var err error
+			rolePerms, err = c.storer.QueryPermissions(ctx, []role.Role{r})
+			if err != nil {
+				return nil, fmt.Errorf("query: role[%s]: %w", r, err)
+			}
+			c.permissions.Set(r.String(), rolePerms)
+		}
+
+		for _, p := range rolePerms {
+			if !slices.ContainsFunc(perms, p.Equal) {
+				perms = append(perms, p)
+			}
+		}
+	}
+
+	return perms, nil
+}
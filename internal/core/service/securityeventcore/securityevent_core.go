@@ -0,0 +1,103 @@
+// Package securityeventcore records the security events, like the logins and
+// the permission denials, and forwards them to a SIEM when configured.
+package securityeventcore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/otel"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+// Core manages the set of APIs for security event access.
+type Core struct {
+	log       *logger.Logger
+	storer    securityevent.Storer
+	publisher securityevent.Publisher
+}
+
+// NewCore constructs a security event business API for use. The publisher is
+// optional, without it the events are only stored.
+func NewCore(log *logger.Logger, storer securityevent.Storer, publisher securityevent.Publisher) *Core {
+	return &Core{
+		log:       log,
+		storer:    storer,
+		publisher: publisher,
+	}
+}
+
+// Create stores a new event, the client of the context provides the ip
+// address and the user agent. The event is published in the background so a
+// slow broker doesn't hold the request.
+func (b *Core) Create(ctx context.Context, ne securityevent.NewEvent) (securityevent.Event, error) {
+	ctx, span := otel.AddSpan(ctx, "business.securityeventcore.create")
+	defer span.End()
+
+	client := securityevent.GetClient(ctx)
+
+	evt := securityevent.Event{
+		ID:        uuid.New(),
+		OrgID:     organization.Resolve(ctx, ne.OrgID),
+		Kind:      ne.Kind,
+		ActorID:   ne.ActorID,
+		Subject:   ne.Subject,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Outcome:   ne.Outcome,
+		Reason:    ne.Reason,
+		Timestamp: time.Now(),
+	}
+
+	if err := b.storer.Create(ctx, evt); err != nil {
+		return securityevent.Event{}, fmt.Errorf("create event: %w", err)
+	}
+
+	if b.publisher != nil {
+		go b.publish(context.WithoutCancel(ctx), evt)
+	}
+
+	return evt, nil
+}
+
+// Record stores the event like Create does. It must not fail the request the
+// event is about, the errors are logged.
+func (b *Core) Record(ctx context.Context, ne securityevent.NewEvent) {
+	if _, err := b.Create(ctx, ne); err != nil {
+		b.log.Error(ctx, "securityeventcore: record", "kind", ne.Kind, "msg", err)
+	}
+}
+
+// Query retrieves a list of existing events.
+func (b *Core) Query(ctx context.Context, filter securityevent.QueryFilter, orderBy order.By, page page.Page) ([]securityevent.Event, error) {
+	ctx, span := otel.AddSpan(ctx, "business.securityeventcore.query")
+	defer span.End()
+
+	events, err := b.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Count returns the total number of events.
+func (b *Core) Count(ctx context.Context, filter securityevent.QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.securityeventcore.count")
+	defer span.End()
+
+	return b.storer.Count(ctx, filter)
+}
+
+func (b *Core) publish(ctx context.Context, evt securityevent.Event) {
+	if err := b.publisher.Publish(ctx, evt); err != nil {
+		b.log.Error(ctx, "securityeventcore: publish", "eventID", evt.ID, "msg", err)
+	}
+}
@@ -0,0 +1,100 @@
+package securityeventcore
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
+	"github.com/Housiadas/backend-system/internal/core/domain/securityevent"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+)
+
+type memStore struct {
+	events []securityevent.Event
+}
+
+func (s *memStore) Create(_ context.Context, evt securityevent.Event) error {
+	s.events = append(s.events, evt)
+	return nil
+}
+
+func (s *memStore) Query(_ context.Context, _ securityevent.QueryFilter, _ order.By, _ page.Page) ([]securityevent.Event, error) {
+	return s.events, nil
+}
+
+func (s *memStore) Count(_ context.Context, _ securityevent.QueryFilter) (int, error) {
+	return len(s.events), nil
+}
+
+type chanPublisher chan securityevent.Event
+
+func (p chanPublisher) Publish(_ context.Context, evt securityevent.Event) error {
+	p <- evt
+	return nil
+}
+
+func Test_Create(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", func(context.Context) string { return "" }, func(context.Context) string { return "" })
+
+	store := memStore{}
+	published := make(chanPublisher, 1)
+	core := NewCore(log, &store, published)
+
+	ctx := securityevent.WithClient(context.Background(), securityevent.Client{IP: "10.0.0.1", UserAgent: "curl/8.0"})
+
+	actorID := uuid.New()
+	evt, err := core.Create(ctx, securityevent.NewEvent{
+		Kind:    securityevent.LoginFailed,
+		ActorID: actorID,
+		Subject: "user@example.com",
+		Outcome: securityevent.OutcomeFailure,
+		Reason:  "invalid credentials",
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create an event: %s", err)
+	}
+
+	if evt.IP != "10.0.0.1" || evt.UserAgent != "curl/8.0" {
+		t.Fatalf("Should read the client from the context: got %q %q", evt.IP, evt.UserAgent)
+	}
+	if evt.OrgID != organization.DefaultID {
+		t.Fatalf("Should default the organization: got %s", evt.OrgID)
+	}
+	if len(store.events) != 1 || store.events[0].ID != evt.ID {
+		t.Fatalf("Should store the event: got %+v", store.events)
+	}
+
+	select {
+	case got := <-published:
+		if got.ID != evt.ID || got.ActorID != actorID {
+			t.Fatalf("Should publish the event: got %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Should publish the event")
+	}
+}
+
+func Test_Create_Unpublished(t *testing.T) {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", func(context.Context) string { return "" }, func(context.Context) string { return "" })
+
+	store := memStore{}
+	core := NewCore(log, &store, nil)
+
+	core.Record(context.Background(), securityevent.NewEvent{
+		Kind:    securityevent.Login,
+		Outcome: securityevent.OutcomeSuccess,
+	})
+
+	if len(store.events) != 1 {
+		t.Fatalf("Should store the event without a publisher: got %d", len(store.events))
+	}
+	if store.events[0].IP != "" {
+		t.Fatalf("Should leave the ip empty without a client: got %q", store.events[0].IP)
+	}
+}
@@ -0,0 +1,282 @@
+// Package sessioncore provides internal access to the login sessions and the
+// refresh tokens backing them.
+package sessioncore
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viccon/sturdyc"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/session"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/otel"
+)
+
+// revocationTTL is how long the revocation status of a session is cached.
+// A session revoked by another instance of the service is rejected at the
+// latest after this period.
+const revocationTTL = 30 * time.Second
+
+// Core manages the set of APIs for session access.
+type Core struct {
+	log     *logger.Logger
+	storer  session.Storer
+	revoked *sturdyc.Client[bool]
+}
+
+// NewCore constructs a session internal API for use.
+func NewCore(log *logger.Logger, storer session.Storer) *Core {
+	const capacity = 10000
+	const numShards = 10
+	const evictionPercentage = 10
+
+	return &Core{
+		log:     log,
+		storer:  storer,
+		revoked: sturdyc.New[bool](capacity, numShards, revocationTTL, evictionPercentage),
+	}
+}
+
+// Create starts a new session for a user and returns the refresh token for it.
+// The refresh token is only returned here, just its hash is stored.
+func (c *Core) Create(ctx context.Context, ns session.NewSession) (session.Session, string, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessioncore.create")
+	defer span.End()
+
+	secret, hash, err := newSecret()
+	if err != nil {
+		return session.Session{}, "", err
+	}
+
+	now := time.Now()
+
+	sess := session.Session{
+		ID:          uuid.New(),
+		UserID:      ns.UserID,
+		TokenHash:   hash,
+		ExpiresAt:   ns.ExpiresAt,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, sess); err != nil {
+		return session.Session{}, "", fmt.Errorf("create: %w", err)
+	}
+
+	return sess, toRefreshToken(sess.ID, secret), nil
+}
+
+// Rotate exchanges a refresh token for a new one. A refresh token can only be
+// used once, presenting an already rotated token is treated as a token theft
+// and revokes the whole session.
+func (c *Core) Rotate(ctx context.Context, refreshToken string) (session.Session, string, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessioncore.rotate")
+	defer span.End()
+
+	sessionID, secret, err := parseRefreshToken(refreshToken)
+	if err != nil {
+		return session.Session{}, "", err
+	}
+
+	sess, err := c.storer.QueryByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return session.Session{}, "", session.ErrInvalidToken
+		}
+		return session.Session{}, "", fmt.Errorf("query: sessionID[%s]: %w", sessionID, err)
+	}
+
+	if sess.Revoked {
+		return session.Session{}, "", session.ErrRevoked
+	}
+
+	if time.Now().After(sess.ExpiresAt) {
+		return session.Session{}, "", session.ErrExpired
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(sess.TokenHash)) != 1 {
+		c.log.Info(ctx, "sessioncore", "status", "refresh token reused, revoking session", "sessionID", sess.ID)
+		if err := c.Revoke(ctx, sess); err != nil {
+			return session.Session{}, "", err
+		}
+		return session.Session{}, "", session.ErrInvalidToken
+	}
+
+	newSecret, hash, err := newSecret()
+	if err != nil {
+		return session.Session{}, "", err
+	}
+
+	oldHash := sess.TokenHash
+	sess.TokenHash = hash
+	sess.DateUpdated = time.Now()
+
+	// The token is only replaced when it was not rotated or revoked since it
+	// was read, a concurrent use of the same token loses the race.
+	if err := c.storer.Update(ctx, sess, oldHash); err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			return session.Session{}, "", session.ErrInvalidToken
+		}
+		return session.Session{}, "", fmt.Errorf("update: %w", err)
+	}
+
+	return sess, toRefreshToken(sess.ID, newSecret), nil
+}
+
+// QueryByID finds the session by the specified ID.
+func (c *Core) QueryByID(ctx context.Context, sessionID uuid.UUID) (session.Session, error) {
+	ctx, span := otel.AddSpan(ctx, "business.sessioncore.querybyid")
+	defer span.End()
+
+	sess, err := c.storer.QueryByID(ctx, sessionID)
+	if err != nil {
+		return session.Session{}, fmt.Errorf("query: sessionID[%s]: %w", sessionID, err)
+	}
+
+	return sess, nil
+}
+
+// Revoke ends the specified session.
+func (c *Core) Revoke(ctx context.Context, sess session.Session) error {
+	ctx, span := otel.AddSpan(ctx, "business.sessioncore.revoke")
+	defer span.End()
+
+	sess.Revoked = true
+	sess.DateUpdated = time.Now()
+
+	if err := c.storer.Revoke(ctx, sess); err != nil {
+		return fmt.Errorf("revoke: %w", err)
+	}
+
+	c.revoked.Set(sess.ID.String(), true)
+
+	return nil
+}
+
+// RevokeAll ends every session of the specified user.
+func (c *Core) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.sessioncore.revokeall")
+	defer span.End()
+
+	if err := c.storer.RevokeByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("revoke: userID[%s]: %w", userID, err)
+	}
+
+	sessions, err := c.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	for _, sess := range sessions {
+		c.revoked.Set(sess.ID.String(), true)
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the specified session has been revoked. The
+// result is cached so the database is not hit on every authenticated call.
+func (c *Core) IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	if revoked, ok := c.revoked.Get(sessionID.String()); ok {
+		return revoked, nil
+	}
+
+	sess, err := c.storer.QueryByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			c.revoked.Set(sessionID.String(), true)
+			return true, nil
+		}
+		return false, fmt.Errorf("query: sessionID[%s]: %w", sessionID, err)
+	}
+
+	revoked := sess.Revoked || time.Now().After(sess.ExpiresAt)
+	c.revoked.Set(sessionID.String(), revoked)
+
+	return revoked, nil
+}
+
+// RevokeToken revokes a single access token by its ID, the token is kept in
+// the revocation list until it expires.
+func (c *Core) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ctx, span := otel.AddSpan(ctx, "business.sessioncore.revoketoken")
+	defer span.End()
+
+	if err := c.storer.RevokeToken(ctx, tokenID, expiresAt); err != nil {
+		return fmt.Errorf("revoke: tokenID[%s]: %w", tokenID, err)
+	}
+
+	c.revoked.Set(tokenKey(tokenID), true)
+
+	return nil
+}
+
+// IsTokenRevoked reports whether the access token with the specified ID has
+// been revoked. The result is cached along with the revoked sessions.
+func (c *Core) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	if revoked, ok := c.revoked.Get(tokenKey(tokenID)); ok {
+		return revoked, nil
+	}
+
+	revoked, err := c.storer.IsTokenRevoked(ctx, tokenID)
+	if err != nil {
+		return false, fmt.Errorf("query: tokenID[%s]: %w", tokenID, err)
+	}
+
+	c.revoked.Set(tokenKey(tokenID), revoked)
+
+	return revoked, nil
+}
+
+// =============================================================================
+
+// tokenKey keeps the token IDs apart from the session IDs in the cache.
+func tokenKey(tokenID string) string {
+	return "token:" + tokenID
+}
+
+// newSecret generates a random refresh token secret and its hash.
+func newSecret() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generating secret: %w", err)
+	}
+
+	secret := base64.RawURLEncoding.EncodeToString(b)
+
+	return secret, hashSecret(secret), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// toRefreshToken builds a refresh token in the form <session id>.<secret>.
+func toRefreshToken(sessionID uuid.UUID, secret string) string {
+	return sessionID.String() + "." + secret
+}
+
+func parseRefreshToken(refreshToken string) (uuid.UUID, string, error) {
+	id, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || secret == "" {
+		return uuid.UUID{}, "", session.ErrInvalidToken
+	}
+
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.UUID{}, "", session.ErrInvalidToken
+	}
+
+	return sessionID, secret, nil
+}
@@ -0,0 +1,285 @@
+// Package stockcore provides internal access to the stock of the products,
+// the movements changing it and the reservations setting it aside.
+package stockcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/otel"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+// defaultTTL is how long a reservation holds the stock when none is
+// configured.
+const defaultTTL = 15 * time.Minute
+
+// releaseBatch is the number of expired reservations released at a time.
+const releaseBatch = 100
+
+// Core manages the set of APIs for stock access.
+type Core struct {
+	log    *logger.Logger
+	storer stock.Storer
+	ttl    time.Duration
+}
+
+// NewCore constructs a stock internal API for use. The reservations expire
+// after the ttl unless they ask for another one.
+func NewCore(log *logger.Logger, storer stock.Storer, ttl time.Duration) *Core {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	return &Core{
+		log:    log,
+		storer: storer,
+		ttl:    ttl,
+	}
+}
+
+// NewWithTx constructs a new internal value that will use the
+// specified transaction in any store-related calls.
+func (c *Core) NewWithTx(tx pgsql.CommitRollbacker) (*Core, error) {
+	storer, err := c.storer.NewWithTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	core := Core{
+		log:    c.log,
+		storer: storer,
+		ttl:    c.ttl,
+	}
+
+	return &core, nil
+}
+
+// Move receives, adjusts or sells stock of a product. The reservations are
+// made with Reserve.
+func (c *Core) Move(ctx context.Context, nm stock.NewMovement) (stock.Movement, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.stockcore.move")
+	defer span.End()
+
+	var delta int
+	switch {
+	case nm.Kind.Equal(stock.Receipt) && nm.Quantity > 0:
+		delta = nm.Quantity
+	case nm.Kind.Equal(stock.Sale) && nm.Quantity > 0:
+		delta = -nm.Quantity
+	case nm.Kind.Equal(stock.Adjustment) && nm.Quantity != 0:
+		delta = nm.Quantity
+	default:
+		return stock.Movement{}, fmt.Errorf("%s of %d: %w", nm.Kind, nm.Quantity, stock.ErrInvalidMovement)
+	}
+
+	mov := stock.Movement{
+		ID:          uuid.New(),
+		ProductID:   nm.ProductID,
+		Kind:        nm.Kind,
+		Delta:       delta,
+		DateCreated: time.Now(),
+	}
+
+	mov, err := c.storer.Move(ctx, mov)
+	if err != nil {
+		return stock.Movement{}, fmt.Errorf("move: productID[%s]: %w", nm.ProductID, err)
+	}
+
+	return mov, nil
+}
+
+// Reserve takes stock of a product until the reservation is committed,
+// released or expires.
+func (c *Core) Reserve(ctx context.Context, nr stock.NewReservation) (stock.Reservation, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.stockcore.reserve")
+	defer span.End()
+
+	if nr.Quantity <= 0 || nr.TTL < 0 {
+		return stock.Reservation{}, stock.ErrInvalidMovement
+	}
+
+	ttl := nr.TTL
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+
+	now := time.Now()
+
+	res := stock.Reservation{
+		ID:          uuid.New(),
+		ProductID:   nr.ProductID,
+		Quantity:    nr.Quantity,
+		Status:      stock.Pending,
+		ExpiresAt:   now.Add(ttl),
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	mov := stock.Movement{
+		ID:            uuid.New(),
+		ProductID:     nr.ProductID,
+		ReservationID: res.ID,
+		Kind:          stock.Reserve,
+		Delta:         -nr.Quantity,
+		DateCreated:   now,
+	}
+
+	mov, err := c.storer.Reserve(ctx, res, mov)
+	if err != nil {
+		return stock.Reservation{}, fmt.Errorf("reserve: productID[%s]: %w", nr.ProductID, err)
+	}
+	res.OrgID = mov.OrgID
+
+	return res, nil
+}
+
+// QueryReservationByID finds the reservation by the specified ID.
+func (c *Core) QueryReservationByID(ctx context.Context, reservationID uuid.UUID) (stock.Reservation, error) {
+	res, err := c.storer.QueryReservationByID(ctx, reservationID)
+	if err != nil {
+		return stock.Reservation{}, fmt.Errorf("query: reservationID[%s]: %w", reservationID, err)
+	}
+
+	return res, nil
+}
+
+// Commit keeps the stock of a pending reservation as sold, it fails once the
+// reservation expired.
+func (c *Core) Commit(ctx context.Context, res stock.Reservation) (stock.Reservation, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.stockcore.commit")
+	defer span.End()
+
+	if !res.Status.Equal(stock.Pending) {
+		return stock.Reservation{}, stock.ErrReservationClosed
+	}
+
+	now := time.Now()
+
+	if !now.Before(res.ExpiresAt) {
+		return stock.Reservation{}, stock.ErrReservationExpired
+	}
+
+	if err := c.storer.Commit(ctx, res, now); err != nil {
+		return stock.Reservation{}, fmt.Errorf("commit: reservationID[%s]: %w", res.ID, err)
+	}
+
+	res.Status = stock.Committed
+	res.DateUpdated = now
+
+	return res, nil
+}
+
+// Release gives the stock of a pending reservation back to the product, an
+// expired reservation can still be released.
+func (c *Core) Release(ctx context.Context, res stock.Reservation) (stock.Reservation, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.stockcore.release")
+	defer span.End()
+
+	if !res.Status.Equal(stock.Pending) {
+		return stock.Reservation{}, stock.ErrReservationClosed
+	}
+
+	now := time.Now()
+
+	mov := stock.Movement{
+		ID:            uuid.New(),
+		ProductID:     res.ProductID,
+		ReservationID: res.ID,
+		Kind:          stock.Release,
+		Delta:         res.Quantity,
+		DateCreated:   now,
+	}
+
+	if _, err := c.storer.Release(ctx, res, mov); err != nil {
+		return stock.Reservation{}, fmt.Errorf("release: reservationID[%s]: %w", res.ID, err)
+	}
+
+	res.Status = stock.Released
+	res.DateUpdated = now
+
+	return res, nil
+}
+
+// ReleaseExpired releases the pending reservations that expired and returns
+// how many were released.
+func (c *Core) ReleaseExpired(ctx context.Context) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.stockcore.releaseexpired")
+	defer span.End()
+
+	var released int
+	for {
+		ress, err := c.storer.QueryExpired(ctx, time.Now(), releaseBatch)
+		if err != nil {
+			return released, fmt.Errorf("query expired: %w", err)
+		}
+
+		if len(ress) == 0 {
+			return released, nil
+		}
+
+		for _, res := range ress {
+			_, err := c.Release(ctx, res)
+			switch {
+			case err == nil:
+				released++
+
+			// The reservation was committed or released in the meantime.
+			case errors.Is(err, stock.ErrReservationClosed):
+
+			default:
+				return released, err
+			}
+		}
+	}
+}
+
+// WatchExpired releases the expired reservations every interval until the
+// context is canceled.
+func (c *Core) WatchExpired(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			n, err := c.ReleaseExpired(ctx)
+			if err != nil {
+				c.log.Error(ctx, "stockcore: release expired", "msg", err)
+			}
+
+			if n > 0 {
+				c.log.Info(ctx, "stockcore: release expired", "released", n)
+			}
+		}
+	}
+}
+
+// Query retrieves a list of existing movements.
+func (c *Core) Query(ctx context.Context, filter stock.QueryFilter, orderBy order.By, page page.Page) ([]stock.Movement, error) {
+	ctx, span := otel.AddSpan(ctx, "internal.stockcore.query")
+	defer span.End()
+
+	movs, err := c.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return movs, nil
+}
+
+// Count returns the total number of movements.
+func (c *Core) Count(ctx context.Context, filter stock.QueryFilter) (int, error) {
+	return c.storer.Count(ctx, filter)
+}
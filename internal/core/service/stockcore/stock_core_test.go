@@ -0,0 +1,213 @@
+package stockcore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Housiadas/backend-system/internal/core/domain/stock"
+	"github.com/Housiadas/backend-system/pkg/logger"
+	"github.com/Housiadas/backend-system/pkg/order"
+	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/pgsql"
+)
+
+type memStore struct {
+	quantity     int
+	movements    []stock.Movement
+	reservations map[uuid.UUID]stock.Reservation
+}
+
+func newMemStore(quantity int) *memStore {
+	return &memStore{
+		quantity:     quantity,
+		reservations: make(map[uuid.UUID]stock.Reservation),
+	}
+}
+
+func (s *memStore) NewWithTx(_ pgsql.CommitRollbacker) (stock.Storer, error) {
+	return s, nil
+}
+
+func (s *memStore) Move(_ context.Context, mov stock.Movement) (stock.Movement, error) {
+	if s.quantity+mov.Delta < 0 {
+		return stock.Movement{}, stock.ErrInsufficientStock
+	}
+
+	s.quantity += mov.Delta
+	mov.Quantity = s.quantity
+	s.movements = append(s.movements, mov)
+
+	return mov, nil
+}
+
+func (s *memStore) Reserve(ctx context.Context, res stock.Reservation, mov stock.Movement) (stock.Movement, error) {
+	mov, err := s.Move(ctx, mov)
+	if err != nil {
+		return stock.Movement{}, err
+	}
+	s.reservations[res.ID] = res
+
+	return mov, nil
+}
+
+func (s *memStore) Commit(_ context.Context, res stock.Reservation, now time.Time) error {
+	res.Status = stock.Committed
+	res.DateUpdated = now
+	s.reservations[res.ID] = res
+
+	return nil
+}
+
+func (s *memStore) Release(ctx context.Context, res stock.Reservation, mov stock.Movement) (stock.Movement, error) {
+	if !s.reservations[res.ID].Status.Equal(stock.Pending) {
+		return stock.Movement{}, stock.ErrReservationClosed
+	}
+
+	res.Status = stock.Released
+	s.reservations[res.ID] = res
+
+	return s.Move(ctx, mov)
+}
+
+func (s *memStore) QueryReservationByID(_ context.Context, reservationID uuid.UUID) (stock.Reservation, error) {
+	res, ok := s.reservations[reservationID]
+	if !ok {
+		return stock.Reservation{}, stock.ErrReservationNotFound
+	}
+
+	return res, nil
+}
+
+func (s *memStore) QueryExpired(_ context.Context, now time.Time, limit int) ([]stock.Reservation, error) {
+	var ress []stock.Reservation
+	for _, res := range s.reservations {
+		if res.Status.Equal(stock.Pending) && res.ExpiresAt.Before(now) && len(ress) < limit {
+			ress = append(ress, res)
+		}
+	}
+
+	return ress, nil
+}
+
+func (s *memStore) Query(_ context.Context, _ stock.QueryFilter, _ order.By, _ page.Page) ([]stock.Movement, error) {
+	return s.movements, nil
+}
+
+func (s *memStore) Count(_ context.Context, _ stock.QueryFilter) (int, error) {
+	return len(s.movements), nil
+}
+
+func newTestCore(store stock.Storer) *Core {
+	log := logger.New(io.Discard, logger.LevelInfo, "TEST", func(context.Context) string { return "" }, func(context.Context) string { return "" })
+
+	return NewCore(log, store, 0)
+}
+
+func Test_Move(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		kind     stock.Kind
+		quantity int
+		delta    int
+		err      error
+	}{
+		{name: "receipt", kind: stock.Receipt, quantity: 5, delta: 5},
+		{name: "sale", kind: stock.Sale, quantity: 3, delta: -3},
+		{name: "adjustment-down", kind: stock.Adjustment, quantity: -2, delta: -2},
+		{name: "adjustment-up", kind: stock.Adjustment, quantity: 4, delta: 4},
+		{name: "negative-receipt", kind: stock.Receipt, quantity: -1, err: stock.ErrInvalidMovement},
+		{name: "zero-sale", kind: stock.Sale, err: stock.ErrInvalidMovement},
+		{name: "zero-adjustment", kind: stock.Adjustment, err: stock.ErrInvalidMovement},
+		{name: "reservation", kind: stock.Reserve, quantity: 1, err: stock.ErrInvalidMovement},
+		{name: "release", kind: stock.Release, quantity: 1, err: stock.ErrInvalidMovement},
+		{name: "oversell", kind: stock.Sale, quantity: 11, err: stock.ErrInsufficientStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core := newTestCore(newMemStore(10))
+
+			mov, err := core.Move(ctx, stock.NewMovement{ProductID: uuid.New(), Kind: tt.kind, Quantity: tt.quantity})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Should get the expected error: got %v, exp %v", err, tt.err)
+			}
+
+			if tt.err == nil && (mov.Delta != tt.delta || mov.Quantity != 10+tt.delta) {
+				t.Fatalf("Should move the stock: got %d -> %d, exp %d", mov.Delta, mov.Quantity, tt.delta)
+			}
+		})
+	}
+}
+
+func Test_Reservation(t *testing.T) {
+	ctx := context.Background()
+
+	store := newMemStore(10)
+	core := newTestCore(store)
+
+	if _, err := core.Reserve(ctx, stock.NewReservation{ProductID: uuid.New()}); !errors.Is(err, stock.ErrInvalidMovement) {
+		t.Fatalf("Should refuse an empty reservation: %v", err)
+	}
+
+	before := time.Now()
+
+	res, err := core.Reserve(ctx, stock.NewReservation{ProductID: uuid.New(), Quantity: 4})
+	if err != nil {
+		t.Fatalf("Should reserve: %s", err)
+	}
+
+	if res.ExpiresAt.Before(before.Add(defaultTTL)) {
+		t.Fatalf("Should default the ttl: got %s", res.ExpiresAt)
+	}
+	if store.quantity != 6 {
+		t.Fatalf("Should take the stock: got %d", store.quantity)
+	}
+
+	res, err = core.Commit(ctx, res)
+	if err != nil {
+		t.Fatalf("Should commit: %s", err)
+	}
+
+	if _, err := core.Commit(ctx, res); !errors.Is(err, stock.ErrReservationClosed) {
+		t.Fatalf("Should not commit twice: %v", err)
+	}
+	if _, err := core.Release(ctx, res); !errors.Is(err, stock.ErrReservationClosed) {
+		t.Fatalf("Should not release a committed reservation: %v", err)
+	}
+
+	expired, err := core.Reserve(ctx, stock.NewReservation{ProductID: uuid.New(), Quantity: 2, TTL: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("Should reserve: %s", err)
+	}
+
+	time.Sleep(time.Millisecond)
+
+	if _, err := core.Commit(ctx, expired); !errors.Is(err, stock.ErrReservationExpired) {
+		t.Fatalf("Should not commit an expired reservation: %v", err)
+	}
+
+	n, err := core.ReleaseExpired(ctx)
+	if err != nil {
+		t.Fatalf("Should release the expired reservations: %s", err)
+	}
+
+	if n != 1 || store.quantity != 6 {
+		t.Fatalf("Should give the stock back: released %d, quantity %d", n, store.quantity)
+	}
+
+	var sum int
+	for _, mov := range store.movements {
+		sum += mov.Delta
+	}
+
+	if 10+sum != store.quantity {
+		t.Fatalf("Should derive the quantity from the movements: got %d, exp %d", 10+sum, store.quantity)
+	}
+}
@@ -3,32 +3,46 @@ package usercore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"net/mail"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 
+	"github.com/Housiadas/backend-system/internal/core/domain/organization"
 	"github.com/Housiadas/backend-system/internal/core/domain/user"
 	"github.com/Housiadas/backend-system/pkg/logger"
 	"github.com/Housiadas/backend-system/pkg/order"
 	"github.com/Housiadas/backend-system/pkg/page"
+	"github.com/Housiadas/backend-system/pkg/passhash"
 	"github.com/Housiadas/backend-system/pkg/pgsql"
 )
 
 // Core manages the set of APIs for user access.
 type Core struct {
-	log    *logger.Logger
-	storer user.Storer
+	log       *logger.Logger
+	storer    user.Storer
+	hasher    passhash.Hasher
+	dummyHash func() string
 }
 
-// NewCore constructs a user.User internal API for use.
+// NewCore constructs a user.User internal API for use. The passwords are
+// hashed with argon2id and the default parameters.
 func NewCore(log *logger.Logger, storer user.Storer) *Core {
+	return NewCoreWithHasher(log, storer, passhash.NewArgon2id(passhash.Argon2Params{}))
+}
+
+// NewCoreWithHasher constructs a user.User internal API for use with the
+// hasher for the passwords.
+func NewCoreWithHasher(log *logger.Logger, storer user.Storer, hasher passhash.Hasher) *Core {
 	return &Core{
-		log:    log,
-		storer: storer,
+		log:       log,
+		storer:    storer,
+		hasher:    hasher,
+		dummyHash: newDummyHash(hasher),
 	}
 }
 
@@ -41,8 +55,10 @@ func (c *Core) NewWithTx(tx pgsql.CommitRollbacker) (*Core, error) {
 	}
 
 	bus := Core{
-		log:    c.log,
-		storer: storer,
+		log:       c.log,
+		storer:    storer,
+		hasher:    c.hasher,
+		dummyHash: c.dummyHash,
 	}
 
 	return &bus, nil
@@ -50,23 +66,29 @@ func (c *Core) NewWithTx(tx pgsql.CommitRollbacker) (*Core, error) {
 
 // Create adds a new User to the system.
 func (c *Core) Create(ctx context.Context, nu user.NewUser) (user.User, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(nu.Password), bcrypt.DefaultCost)
+	if err := organization.CheckRoles(ctx, nu.Roles); err != nil {
+		return user.User{}, err
+	}
+
+	hash, err := c.hasher.Hash(nu.Password)
 	if err != nil {
-		return user.User{}, fmt.Errorf("generatefrompassword: %w", err)
+		return user.User{}, fmt.Errorf("hash: %w", err)
 	}
 
 	now := time.Now()
 
 	usr := user.User{
-		ID:           uuid.New(),
-		Name:         nu.Name,
-		Email:        nu.Email,
-		PasswordHash: hash,
-		Roles:        nu.Roles,
-		Department:   nu.Department,
-		Enabled:      true,
-		DateCreated:  now,
-		DateUpdated:  now,
+		ID:            uuid.New(),
+		OrgID:         organization.Resolve(ctx, nu.OrgID),
+		Name:          nu.Name,
+		Email:         nu.Email,
+		PasswordHash:  []byte(hash),
+		Roles:         nu.Roles,
+		Department:    nu.Department,
+		Enabled:       true,
+		EmailVerified: nu.EmailVerified,
+		DateCreated:   now,
+		DateUpdated:   now,
 	}
 
 	if err := c.storer.Create(ctx, usr); err != nil {
@@ -83,19 +105,25 @@ func (c *Core) Update(ctx context.Context, usr user.User, uu user.UpdateUser) (u
 	}
 
 	if uu.Email != nil {
+		if uu.Email.Address != usr.Email.Address {
+			usr.EmailVerified = false
+		}
 		usr.Email = *uu.Email
 	}
 
 	if uu.Roles != nil {
+		if err := organization.CheckRoles(ctx, uu.Roles); err != nil {
+			return user.User{}, err
+		}
 		usr.Roles = uu.Roles
 	}
 
 	if uu.Password != nil {
-		pw, err := bcrypt.GenerateFromPassword([]byte(*uu.Password), bcrypt.DefaultCost)
+		hash, err := c.hasher.Hash(*uu.Password)
 		if err != nil {
-			return user.User{}, fmt.Errorf("generatefrompassword: %w", err)
+			return user.User{}, fmt.Errorf("hash: %w", err)
 		}
-		usr.PasswordHash = pw
+		usr.PasswordHash = []byte(hash)
 	}
 
 	if uu.Department != nil {
@@ -114,6 +142,39 @@ func (c *Core) Update(ctx context.Context, usr user.User, uu user.UpdateUser) (u
 	return usr, nil
 }
 
+// ResetPassword replaces the password of the user and rejects every token
+// issued to the user before now.
+func (c *Core) ResetPassword(ctx context.Context, usr user.User, password string) (user.User, error) {
+	hash, err := c.hasher.Hash(password)
+	if err != nil {
+		return user.User{}, fmt.Errorf("hash: %w", err)
+	}
+
+	now := time.Now()
+
+	usr.PasswordHash = []byte(hash)
+	usr.TokensValidAfter = now
+	usr.DateUpdated = now
+
+	if err := c.storer.Update(ctx, usr); err != nil {
+		return user.User{}, fmt.Errorf("update: %w", err)
+	}
+
+	return usr, nil
+}
+
+// VerifyEmail marks the email of the user as verified.
+func (c *Core) VerifyEmail(ctx context.Context, usr user.User) (user.User, error) {
+	usr.EmailVerified = true
+	usr.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, usr); err != nil {
+		return user.User{}, fmt.Errorf("update: %w", err)
+	}
+
+	return usr, nil
+}
+
 // Delete removes the specified user.
 func (c *Core) Delete(ctx context.Context, usr user.User) error {
 	if err := c.storer.Delete(ctx, usr); err != nil {
@@ -158,18 +219,62 @@ func (c *Core) QueryByEmail(ctx context.Context, email mail.Address) (user.User,
 	return usr, nil
 }
 
+// newDummyHash returns the hash compared against when the email is unknown,
+// so the response takes as long as for a wrong password and does not reveal
+// which emails exist.
+func newDummyHash(hasher passhash.Hasher) func() string {
+	return sync.OnceValue(func() string {
+		hash, _ := hasher.Hash("dummy password")
+		return hash
+	})
+}
+
 // Authenticate finds a user by their email and verifies their password. On
 // success, it returns a Claims User representing this user. The claims can be
-// used to generate a token for future authentication.
+// used to generate a token for future authentication. A password hashed with
+// another algorithm, or weaker parameters, than the current hasher is hashed
+// again and saved.
 func (c *Core) Authenticate(ctx context.Context, email mail.Address, password string) (user.User, error) {
 	usr, err := c.QueryByEmail(ctx, email)
 	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			_, _ = c.hasher.Verify(c.dummyHash(), password)
+			return user.User{}, fmt.Errorf("query: email[%s]: %w", email, user.ErrAuthenticationFailure)
+		}
 		return user.User{}, fmt.Errorf("query: email[%s]: %w", email, err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword(usr.PasswordHash, []byte(password)); err != nil {
-		return user.User{}, fmt.Errorf("comparehashandpassword: %w", user.ErrAuthenticationFailure)
+	ok, err := c.hasher.Verify(string(usr.PasswordHash), password)
+	if err != nil {
+		return user.User{}, fmt.Errorf("verify: userID[%s]: %w", usr.ID, err)
+	}
+	if !ok {
+		return user.User{}, fmt.Errorf("verify: %w", user.ErrAuthenticationFailure)
+	}
+
+	if c.hasher.NeedsRehash(string(usr.PasswordHash)) {
+		usr = c.rehash(ctx, usr, password)
 	}
 
 	return usr, nil
 }
+
+// rehash replaces the password hash of the user with one of the current
+// hasher. A failure is only logged, the login goes on with the old hash.
+func (c *Core) rehash(ctx context.Context, usr user.User, password string) user.User {
+	hash, err := c.hasher.Hash(password)
+	if err != nil {
+		c.log.Error(ctx, "usercore", "status", "rehashing password", "userID", usr.ID, "msg", err)
+		return usr
+	}
+
+	updated := usr
+	updated.PasswordHash = []byte(hash)
+
+	if err := c.storer.Update(ctx, updated); err != nil {
+		c.log.Error(ctx, "usercore", "status", "saving rehashed password", "userID", usr.ID, "msg", err)
+		return usr
+	}
+
+	return updated
+}
@@ -0,0 +1,33 @@
+package encrypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// BlindIndex computes a keyed hash of a value, so an encrypted value can be
+// looked up, or kept unique, by the hash of its plaintext without revealing
+// it. The key must never change, the hashes computed before would no longer
+// match.
+type BlindIndex struct {
+	key []byte
+}
+
+// NewBlindIndex constructs a blind index from a key of at least 32 bytes.
+func NewBlindIndex(key []byte) (*BlindIndex, error) {
+	if len(key) < 32 {
+		return nil, fmt.Errorf("index key must be at least 32 bytes, got %d", len(key))
+	}
+
+	return &BlindIndex{key: key}, nil
+}
+
+// Sum returns the hex encoded HMAC-SHA256 of the value.
+func (b *BlindIndex) Sum(value string) string {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
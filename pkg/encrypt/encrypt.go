@@ -0,0 +1,201 @@
+// Package encrypt provides authenticated encryption of small values, like
+// secrets stored in the database, with support for rotating the keys.
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ErrUnknownKey is returned when a value was encrypted with a key the
+// keyring does not hold.
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Keyring encrypts values with AES-256-GCM under the active key and
+// decrypts them with whichever key they were encrypted with. A value is
+// encoded as <kid>:<base64 nonce and ciphertext>, so old keys can be kept
+// for decryption while new values use the active one.
+type Keyring struct {
+	active string
+	aeads  map[string]cipher.AEAD
+}
+
+// New constructs a keyring from keys of 32 bytes identified by a key id.
+func New(activeKID string, keys map[string][]byte) (*Keyring, error) {
+	if _, exists := keys[activeKID]; !exists {
+		return nil, fmt.Errorf("active key[%s] not found", activeKID)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for kid, key := range keys {
+		if strings.Contains(kid, ":") {
+			return nil, fmt.Errorf("key id[%s] must not contain a colon", kid)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key[%s] must be 32 bytes, got %d", kid, len(key))
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("key[%s]: %w", kid, err)
+		}
+
+		aeads[kid] = aead
+	}
+
+	k := Keyring{
+		active: activeKID,
+		aeads:  aeads,
+	}
+
+	return &k, nil
+}
+
+// NewFromBase64 constructs a keyring from base64 encoded keys, the way they
+// are found in the configuration.
+func NewFromBase64(activeKID string, keys map[string]string) (*Keyring, error) {
+	raw := make(map[string][]byte, len(keys))
+	for kid, value := range keys {
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("decoding key[%s]: %w", kid, err)
+		}
+		raw[kid] = key
+	}
+
+	return New(activeKID, raw)
+}
+
+// ReadKeys reads the keys of the <kid>.key files found in the file system,
+// each holding a base64 encoded key. The other files are ignored, so the
+// keys can live next to the signing keys.
+func ReadKeys(fsys fs.FS) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+
+	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("walkdir failure: %w", err)
+		}
+
+		if dirEntry.IsDir() || path.Ext(fileName) != ".key" {
+			return nil
+		}
+
+		file, err := fsys.Open(fileName)
+		if err != nil {
+			return fmt.Errorf("opening key file: %w", err)
+		}
+		defer file.Close()
+
+		encoded, err := io.ReadAll(io.LimitReader(file, 1024))
+		if err != nil {
+			return fmt.Errorf("reading key file: %w", err)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if err != nil {
+			return fmt.Errorf("decoding key file %s: %w", fileName, err)
+		}
+
+		keys[strings.TrimSuffix(dirEntry.Name(), ".key")] = key
+
+		return nil
+	}
+
+	if err := fs.WalkDir(fsys, ".", fn); err != nil {
+		return nil, fmt.Errorf("walking directory: %w", err)
+	}
+
+	return keys, nil
+}
+
+// ActiveKID returns the id of the key new values are encrypted with.
+func (k *Keyring) ActiveKID() string {
+	return k.active
+}
+
+// Encrypt encrypts the plaintext under the active key. The associated data
+// is authenticated but not encrypted, binding the value to its context, like
+// the id of the row it is stored in, so it can't be moved to another one.
+func (k *Keyring) Encrypt(plaintext []byte, associatedData []byte) (string, error) {
+	sealed, err := seal(k.aeads[k.active], plaintext, associatedData)
+	if err != nil {
+		return "", err
+	}
+
+	return k.active + ":" + sealed, nil
+}
+
+// Decrypt decrypts a value produced by Encrypt with the same associated data.
+func (k *Keyring) Decrypt(ciphertext string, associatedData []byte) ([]byte, error) {
+	kid, encoded, ok := strings.Cut(ciphertext, ":")
+	if !ok {
+		return nil, errors.New("malformed ciphertext")
+	}
+
+	aead, exists := k.aeads[kid]
+	if !exists {
+		return nil, fmt.Errorf("key[%s]: %w", kid, ErrUnknownKey)
+	}
+
+	return open(aead, encoded, associatedData)
+}
+
+// KID returns the id of the key the value was encrypted with.
+func (k *Keyring) KID(ciphertext string) string {
+	kid, _, _ := strings.Cut(ciphertext, ":")
+	return kid
+}
+
+// =============================================================================
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the plaintext under a random nonce and encodes the nonce
+// along with the ciphertext.
+func seal(aead cipher.AEAD, plaintext []byte, associatedData []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, associatedData)
+
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts a value encoded by seal.
+func open(aead cipher.AEAD, encoded string, associatedData []byte) ([]byte, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding ciphertext: %w", err)
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("malformed ciphertext")
+	}
+
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting: %w", err)
+	}
+
+	return plaintext, nil
+}
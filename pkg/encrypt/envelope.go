@@ -0,0 +1,97 @@
+package encrypt
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// Envelope encrypts records under data keys of their own, each wrapped by
+// the master keyring and stored next to the record. Rotating the master key
+// only requires to wrap the data keys again, not to encrypt every value.
+type Envelope struct {
+	master *Keyring
+}
+
+// NewEnvelope constructs an envelope wrapping the data keys with the master
+// keyring.
+func NewEnvelope(master *Keyring) *Envelope {
+	return &Envelope{
+		master: master,
+	}
+}
+
+// GenerateDataKey generates a new data key and returns it along with its
+// wrapped form to store. The associated data binds the wrapped key to the
+// record it encrypts.
+func (e *Envelope) GenerateDataKey(associatedData []byte) (DataKey, string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return DataKey{}, "", fmt.Errorf("generating data key: %w", err)
+	}
+
+	dk, err := newDataKey(key)
+	if err != nil {
+		return DataKey{}, "", err
+	}
+
+	wrapped, err := e.master.Encrypt(key, associatedData)
+	if err != nil {
+		return DataKey{}, "", fmt.Errorf("wrapping data key: %w", err)
+	}
+
+	return dk, wrapped, nil
+}
+
+// DataKey unwraps a data key produced by GenerateDataKey with the same
+// associated data.
+func (e *Envelope) DataKey(wrapped string, associatedData []byte) (DataKey, error) {
+	key, err := e.master.Decrypt(wrapped, associatedData)
+	if err != nil {
+		return DataKey{}, fmt.Errorf("unwrapping data key: %w", err)
+	}
+
+	return newDataKey(key)
+}
+
+// MasterKID returns the id of the master key new data keys are wrapped with.
+func (e *Envelope) MasterKID() string {
+	return e.master.ActiveKID()
+}
+
+// Current reports whether the data key is wrapped with the active master key.
+func (e *Envelope) Current(wrapped string) bool {
+	return e.master.KID(wrapped) == e.master.ActiveKID()
+}
+
+// =============================================================================
+
+// DataKey encrypts the values of a single record with AES-256-GCM.
+type DataKey struct {
+	aead cipher.AEAD
+}
+
+func newDataKey(key []byte) (DataKey, error) {
+	if len(key) != 32 {
+		return DataKey{}, errors.New("data key must be 32 bytes")
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return DataKey{}, fmt.Errorf("data key: %w", err)
+	}
+
+	return DataKey{aead: aead}, nil
+}
+
+// Encrypt encrypts the plaintext, the associated data is authenticated but
+// not encrypted, like the column the value is stored in.
+func (dk DataKey) Encrypt(plaintext []byte, associatedData []byte) (string, error) {
+	return seal(dk.aead, plaintext, associatedData)
+}
+
+// Decrypt decrypts a value produced by Encrypt with the same associated data.
+func (dk DataKey) Decrypt(ciphertext string, associatedData []byte) ([]byte, error) {
+	return open(dk.aead, ciphertext, associatedData)
+}
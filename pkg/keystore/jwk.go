@@ -0,0 +1,197 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JWK represents a public JSON Web Key as defined by RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet represents a JSON Web Key Set.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicPEMToJWK converts a PKIX public key in PEM form to a JWK used for
+// signature verification.
+func PublicPEMToJWK(kid string, alg string, publicPEM string) (JWK, error) {
+	block, _ := pem.Decode([]byte(publicPEM))
+	if block == nil {
+		return JWK{}, errors.New("invalid key: Key must be a PEM encoded PKIX public key")
+	}
+
+	parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return JWK{}, fmt.Errorf("parsing public key: %w", err)
+	}
+
+	switch pk := parsedKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: alg,
+			N:   base64.RawURLEncoding.EncodeToString(pk.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pk.E)).Bytes()),
+		}, nil
+
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pk.ECDH()
+		if err != nil {
+			return JWK{}, fmt.Errorf("converting ecdsa key: %w", err)
+		}
+
+		// The uncompressed point is 0x04 || X || Y with fixed size coordinates.
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+
+		return JWK{
+			Kty: "EC",
+			Kid: kid,
+			Use: "sig",
+			Alg: alg,
+			Crv: pk.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+			Y:   base64.RawURLEncoding.EncodeToString(point[1+size:]),
+		}, nil
+
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: alg,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pk),
+		}, nil
+	}
+
+	return JWK{}, fmt.Errorf("unsupported key type: %T", parsedKey)
+}
+
+// JWKToPublicPEM converts a JWK to a PKIX public key in PEM form.
+func JWKToPublicPEM(jwk JWK) (string, error) {
+	var publicKey any
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return "", fmt.Errorf("decoding modulus: %w", err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return "", fmt.Errorf("decoding exponent: %w", err)
+		}
+
+		publicKey = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+
+	case "EC":
+		if jwk.Crv != elliptic.P256().Params().Name {
+			return "", fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return "", fmt.Errorf("decoding x: %w", err)
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return "", fmt.Errorf("decoding y: %w", err)
+		}
+
+		// Validate the point is on the curve before using it.
+		point := append([]byte{0x04}, append(x, y...)...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return "", fmt.Errorf("invalid point: %w", err)
+		}
+
+		publicKey = &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return "", fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return "", fmt.Errorf("decoding x: %w", err)
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return "", errors.New("invalid ed25519 key size")
+		}
+
+		publicKey = ed25519.PublicKey(x)
+
+	default:
+		return "", fmt.Errorf("unsupported key type: %s", jwk.Kty)
+	}
+
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("marshaling public key: %w", err)
+	}
+
+	publicBlock := pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: asn1Bytes,
+	}
+
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &publicBlock); err != nil {
+		return "", fmt.Errorf("encoding to public PEM: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// JWKAlgorithm returns the signing algorithm of a JWK, derived from the key
+// type when the alg parameter is not present.
+func JWKAlgorithm(jwk JWK) (string, error) {
+	if jwk.Alg != "" {
+		return jwk.Alg, nil
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		return AlgRS256, nil
+	case "EC":
+		return AlgES256, nil
+	case "OKP":
+		return AlgEdDSA, nil
+	}
+
+	return "", fmt.Errorf("unsupported key type: %s", jwk.Kty)
+}
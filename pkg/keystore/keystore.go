@@ -4,6 +4,9 @@ package keystore
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -12,30 +15,81 @@ import (
 	"io"
 	"io/fs"
 	"path"
+	"sort"
 	"strings"
+	"sync"
+)
+
+// Set of signing algorithms supported, chosen from the type of the key.
+const (
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
 )
 
 // key represents key information.
 type key struct {
 	privatePEM string
 	publicPEM  string
+	alg        string
 }
 
 // KeyStore represents an in memory store implementation of the
 // KeyLookup interface for use with the authapi package.
+//
+// Every key loaded from the keys folder can be used to verify a token, the
+// file name (without the extension) being the key id (kid). Only one of them,
+// the active key, is used for signing new tokens. The active key is chosen
+// by its kid in the Config, a single key loaded is active by default. The
+// other keys are kept as retired but still valid for verification.
 type KeyStore struct {
-	store map[string]key
+	mu        sync.RWMutex
+	fsys      fs.FS
+	store     map[string]key
+	cfg       Config
+	activeKID string
+}
+
+// Config chooses the keys of a KeyStore by their kid. ActiveKID is the key
+// signing new tokens, it may be left empty when a single key is loaded.
+// LegacyKID is the key the tokens issued before the keys were rotated, which
+// carry no kid, were signed with. Without it these tokens are refused.
+type Config struct {
+	ActiveKID string
+	LegacyKID string
 }
 
 // New constructs an empty KeyStore ready for use.
 func New() *KeyStore {
+	return NewWithConfig(Config{})
+}
+
+// NewWithConfig constructs an empty KeyStore using the keys chosen by the
+// config.
+func NewWithConfig(cfg Config) *KeyStore {
 	return &KeyStore{
 		store: make(map[string]key),
+		cfg:   cfg,
 	}
 }
 
-// LoadRSAKeys loads a set of RSA PEM files rooted inside a directory.
-func (ks *KeyStore) LoadRSAKeys(fsys fs.FS) error {
+// LoadKeys loads a set of PEM files rooted inside a directory. RSA, ECDSA
+// P-256 and Ed25519 private keys are supported. The currently loaded keys are
+// only replaced if all the files are loaded successfully and the keys chosen
+// by the config are found.
+func (ks *KeyStore) LoadKeys(fsys fs.FS) error {
+	ks.mu.RLock()
+	cfg := ks.cfg
+	ks.mu.RUnlock()
+
+	return ks.load(fsys, cfg)
+}
+
+// load reads the keys of the directory and chooses the active one by the
+// config, the keys and the config only replace the current ones on success.
+func (ks *KeyStore) load(fsys fs.FS, cfg Config) error {
+	store := make(map[string]key)
+
 	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("walkdir failure: %w", err)
@@ -64,17 +118,18 @@ func (ks *KeyStore) LoadRSAKeys(fsys fs.FS) error {
 		}
 
 		privatePEM := string(pem)
-		publicPEM, err := toPublicPEM(privatePEM)
+		publicPEM, alg, err := toPublicPEM(privatePEM)
 		if err != nil {
-			return fmt.Errorf("converting private PEM to public: %w", err)
+			return fmt.Errorf("converting private PEM to public: %s: %w", fileName, err)
 		}
 
 		key := key{
 			privatePEM: privatePEM,
 			publicPEM:  publicPEM,
+			alg:        alg,
 		}
 
-		ks.store[strings.TrimSuffix(dirEntry.Name(), ".pem")] = key
+		store[strings.TrimSuffix(dirEntry.Name(), ".pem")] = key
 
 		return nil
 	}
@@ -83,52 +138,191 @@ func (ks *KeyStore) LoadRSAKeys(fsys fs.FS) error {
 		return fmt.Errorf("walking directory: %w", err)
 	}
 
+	if len(store) == 0 {
+		return errors.New("no keys found")
+	}
+
+	activeKID := cfg.ActiveKID
+	if activeKID == "" {
+		if len(store) > 1 {
+			return errors.New("active kid not configured, many keys found")
+		}
+		for kid := range store {
+			activeKID = kid
+		}
+	}
+
+	if _, exists := store[activeKID]; !exists {
+		return fmt.Errorf("active key not found: kid[%s]", activeKID)
+	}
+
+	if legacyKID := cfg.LegacyKID; legacyKID != "" {
+		if _, exists := store[legacyKID]; !exists {
+			return fmt.Errorf("legacy key not found: kid[%s]", legacyKID)
+		}
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.fsys = fsys
+	ks.store = store
+	ks.cfg = cfg
+	ks.activeKID = activeKID
+
 	return nil
 }
 
+// Reload loads again the keys from the directory used by the last successful
+// call to LoadKeys. New keys are picked up and removed keys are no longer
+// valid. On failure the previously loaded keys are kept.
+func (ks *KeyStore) Reload() error {
+	ks.mu.RLock()
+	cfg := ks.cfg
+	ks.mu.RUnlock()
+
+	return ks.ReloadWithConfig(cfg)
+}
+
+// ReloadWithConfig loads again the keys like Reload, choosing the active and
+// legacy keys by the given config. It rotates the active key once the new key
+// file is in place.
+func (ks *KeyStore) ReloadWithConfig(cfg Config) error {
+	ks.mu.RLock()
+	fsys := ks.fsys
+	ks.mu.RUnlock()
+
+	if fsys == nil {
+		return errors.New("keys were never loaded")
+	}
+
+	return ks.load(fsys, cfg)
+}
+
+// ActiveKID returns the key id of the key used for signing new tokens.
+func (ks *KeyStore) ActiveKID() (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if ks.activeKID == "" {
+		return "", errors.New("no active key")
+	}
+
+	return ks.activeKID, nil
+}
+
+// LegacyKID returns the key id of the key the tokens carrying no kid are
+// verified with.
+func (ks *KeyStore) LegacyKID() (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if ks.cfg.LegacyKID == "" {
+		return "", errors.New("no legacy key")
+	}
+
+	return ks.cfg.LegacyKID, nil
+}
+
+// KIDs returns the ids of all the keys that are valid for verification.
+func (ks *KeyStore) KIDs() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	kids := make([]string, 0, len(ks.store))
+	for kid := range ks.store {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	return kids
+}
+
 // PrivateKey searches the key store for a given kid and returns the private key.
-func (ks *KeyStore) PrivateKey() (string, error) {
-	key, found := ks.store["key"]
+func (ks *KeyStore) PrivateKey(kid string) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, found := ks.store[kid]
 	if !found {
-		return "", errors.New("key lookup failed")
+		return "", fmt.Errorf("key lookup failed: kid[%s]", kid)
 	}
 
 	return key.privatePEM, nil
 }
 
 // PublicKey searches the key store for a given kid and returns the public key.
-func (ks *KeyStore) PublicKey() (string, error) {
-	key, found := ks.store["key"]
+func (ks *KeyStore) PublicKey(kid string) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, found := ks.store[kid]
 	if !found {
-		return "", errors.New("key lookup failed")
+		return "", fmt.Errorf("key lookup failed: kid[%s]", kid)
 	}
 
 	return key.publicPEM, nil
 }
 
-func toPublicPEM(privatePEM string) (string, error) {
+// Algorithm returns the signing algorithm of the key for the given kid.
+func (ks *KeyStore) Algorithm(kid string) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, found := ks.store[kid]
+	if !found {
+		return "", fmt.Errorf("key lookup failed: kid[%s]", kid)
+	}
+
+	return key.alg, nil
+}
+
+// toPublicPEM derives the public key in PEM form from the private key and
+// returns the signing algorithm matching the key type.
+func toPublicPEM(privatePEM string) (string, string, error) {
 	block, _ := pem.Decode([]byte(privatePEM))
 	if block == nil {
-		return "", errors.New("invalid key: Key must be a PEM encoded PKCS1 or PKCS8 key")
+		return "", "", errors.New("invalid key: Key must be a PEM encoded PKCS1, SEC1 or PKCS8 key")
 	}
 
 	var parsedKey any
 	parsedKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
 	if err != nil {
-		parsedKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		parsedKey, err = x509.ParseECPrivateKey(block.Bytes)
 		if err != nil {
-			return "", err
+			parsedKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return "", "", err
+			}
 		}
 	}
 
-	pk, ok := parsedKey.(*rsa.PrivateKey)
-	if !ok {
-		return "", errors.New("key is not a valid RSA private key")
+	var publicKey any
+	var alg string
+
+	switch pk := parsedKey.(type) {
+	case *rsa.PrivateKey:
+		publicKey = &pk.PublicKey
+		alg = AlgRS256
+
+	case *ecdsa.PrivateKey:
+		if pk.Curve != elliptic.P256() {
+			return "", "", fmt.Errorf("unsupported curve: %s", pk.Curve.Params().Name)
+		}
+		publicKey = &pk.PublicKey
+		alg = AlgES256
+
+	case ed25519.PrivateKey:
+		publicKey = pk.Public()
+		alg = AlgEdDSA
+
+	default:
+		return "", "", fmt.Errorf("unsupported key type: %T", parsedKey)
 	}
 
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(&pk.PublicKey)
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(publicKey)
 	if err != nil {
-		return "", fmt.Errorf("marshaling public key: %w", err)
+		return "", "", fmt.Errorf("marshaling public key: %w", err)
 	}
 
 	publicBlock := pem.Block{
@@ -138,8 +332,8 @@ func toPublicPEM(privatePEM string) (string, error) {
 
 	var buf bytes.Buffer
 	if err := pem.Encode(&buf, &publicBlock); err != nil {
-		return "", fmt.Errorf("encoding to public PEM: %w", err)
+		return "", "", fmt.Errorf("encoding to public PEM: %w", err)
 	}
 
-	return buf.String(), nil
+	return buf.String(), alg, nil
 }
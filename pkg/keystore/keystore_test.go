@@ -0,0 +1,290 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_KeyStore_LoadKeys(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240101000000.pem": {Data: genPEM(t)},
+		"20250101000000.pem": {Data: genPEM(t)},
+		"20250101000000.pub": {Data: []byte("not a private key")},
+	}
+
+	if err := New().LoadKeys(fsys); err == nil {
+		t.Fatal("Should not choose an active key among many")
+	}
+
+	if err := NewWithConfig(Config{ActiveKID: "20260101000000"}).LoadKeys(fsys); err == nil {
+		t.Fatal("Should fail to load without the configured active key")
+	}
+
+	if err := NewWithConfig(Config{ActiveKID: "20240101000000", LegacyKID: "legacy"}).LoadKeys(fsys); err == nil {
+		t.Fatal("Should fail to load without the configured legacy key")
+	}
+
+	ks := NewWithConfig(Config{ActiveKID: "20240101000000", LegacyKID: "20240101000000"})
+	if err := ks.LoadKeys(fsys); err != nil {
+		t.Fatalf("Should be able to load keys: %s", err)
+	}
+
+	kid, err := ks.ActiveKID()
+	if err != nil {
+		t.Fatalf("Should be able to get the active kid: %s", err)
+	}
+	if kid != "20240101000000" {
+		t.Fatalf("Should get the configured key as active, not the newest: got %q", kid)
+	}
+
+	kid, err = ks.LegacyKID()
+	if err != nil || kid != "20240101000000" {
+		t.Fatalf("Should get the configured legacy kid: got %q, %v", kid, err)
+	}
+
+	for _, kid := range []string{"20240101000000", "20250101000000"} {
+		if _, err := ks.PrivateKey(kid); err != nil {
+			t.Errorf("Should be able to get private key %q: %s", kid, err)
+		}
+		if _, err := ks.PublicKey(kid); err != nil {
+			t.Errorf("Should be able to get public key %q: %s", kid, err)
+		}
+	}
+
+	if _, err := ks.PublicKey("unknown"); err == nil {
+		t.Error("Should fail to get an unknown key")
+	}
+}
+
+func Test_KeyStore_Reload(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240101000000.pem": {Data: genPEM(t)},
+	}
+
+	ks := New()
+	if err := ks.LoadKeys(fsys); err != nil {
+		t.Fatalf("Should be able to load keys: %s", err)
+	}
+
+	if _, err := ks.LegacyKID(); err == nil {
+		t.Fatal("Should not have a legacy kid unless configured")
+	}
+
+	fsys["20250101000000.pem"] = &fstest.MapFile{Data: genPEM(t)}
+	if err := ks.Reload(); err == nil {
+		t.Fatal("Should not choose an active key among many on reload")
+	}
+
+	kid, _ := ks.ActiveKID()
+	if kid != "20240101000000" {
+		t.Fatalf("Should keep the previous active key: got %q", kid)
+	}
+
+	if err := ks.ReloadWithConfig(Config{ActiveKID: "20250101000000"}); err != nil {
+		t.Fatalf("Should be able to reload keys: %s", err)
+	}
+
+	kid, _ = ks.ActiveKID()
+	if kid != "20250101000000" {
+		t.Fatalf("Should rotate to the new key: got %q", kid)
+	}
+	if _, err := ks.PublicKey("20240101000000"); err != nil {
+		t.Fatalf("Should keep the retired key valid: %s", err)
+	}
+
+	// A broken file must not replace the keys already loaded.
+	fsys["20260101000000.pem"] = &fstest.MapFile{Data: []byte("broken")}
+	if err := ks.ReloadWithConfig(Config{ActiveKID: "20260101000000"}); err == nil {
+		t.Fatal("Should fail to reload a broken key")
+	}
+
+	kid, _ = ks.ActiveKID()
+	if kid != "20250101000000" {
+		t.Fatalf("Should keep the previous active key: got %q", kid)
+	}
+}
+
+func Test_KeyStore_NoKeys(t *testing.T) {
+	ks := New()
+	if err := ks.LoadKeys(fstest.MapFS{}); err == nil {
+		t.Fatal("Should fail to load an empty folder")
+	}
+	if _, err := ks.ActiveKID(); err == nil {
+		t.Fatal("Should not have an active kid")
+	}
+	if err := ks.Reload(); err == nil {
+		t.Fatal("Should fail to reload keys never loaded")
+	}
+}
+
+func Test_KeyStore_Algorithms(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %s", err)
+	}
+	ecSEC1, _ := x509.MarshalECPrivateKey(ecKey)
+	ecPKCS8, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %s", err)
+	}
+	edPKCS8, _ := x509.MarshalPKCS8PrivateKey(edKey)
+
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p384, _ := x509.MarshalPKCS8PrivateKey(p384Key)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		alg     string
+		wantErr bool
+	}{
+		{name: "rsa", data: genPEM(t), alg: AlgRS256},
+		{name: "ecdsa-sec1", data: toPEM("EC PRIVATE KEY", ecSEC1), alg: AlgES256},
+		{name: "ecdsa-pkcs8", data: toPEM("PRIVATE KEY", ecPKCS8), alg: AlgES256},
+		{name: "ed25519", data: toPEM("PRIVATE KEY", edPKCS8), alg: AlgEdDSA},
+		{name: "ecdsa-p384", data: toPEM("PRIVATE KEY", p384), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks := New()
+			err := ks.LoadKeys(fstest.MapFS{"key.pem": {Data: tt.data}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			alg, err := ks.Algorithm("key")
+			if err != nil || alg != tt.alg {
+				t.Fatalf("Should get algorithm %s: got %s: %v", tt.alg, alg, err)
+			}
+
+			publicPEM, _ := ks.PublicKey("key")
+			jwk, err := PublicPEMToJWK("key", alg, publicPEM)
+			if err != nil {
+				t.Fatalf("Should be able to convert to jwk: %s", err)
+			}
+
+			got, err := JWKToPublicPEM(jwk)
+			if err != nil {
+				t.Fatalf("Should be able to convert from jwk: %s", err)
+			}
+			if got != publicPEM {
+				t.Fatalf("Should round trip the public key:\ngot: %s\nexp: %s", got, publicPEM)
+			}
+
+			jwkAlg, err := JWKAlgorithm(JWK{Kty: jwk.Kty})
+			if err != nil || jwkAlg != tt.alg {
+				t.Fatalf("Should derive algorithm %s from the key type: got %s: %v", tt.alg, jwkAlg, err)
+			}
+		})
+	}
+}
+
+func Test_RemoteKeyStore(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240101000000.pem": {Data: genPEM(t)},
+	}
+
+	ks := New()
+	if err := ks.LoadKeys(fsys); err != nil {
+		t.Fatalf("Should be able to load keys: %s", err)
+	}
+
+	var fetches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+
+		var set JWKSet
+		for _, kid := range ks.KIDs() {
+			pem, _ := ks.PublicKey(kid)
+			alg, _ := ks.Algorithm(kid)
+			jwk, err := PublicPEMToJWK(kid, alg, pem)
+			if err != nil {
+				t.Errorf("Should be able to convert to jwk: %s", err)
+			}
+			set.Keys = append(set.Keys, jwk)
+		}
+
+		// A key that can't be used doesn't prevent using the others.
+		set.Keys = append(set.Keys, JWK{Kty: "oct", Kid: "symmetric", Use: "sig"})
+
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	rks := NewRemote(RemoteConfig{
+		URL:        srv.URL,
+		MinRefresh: -1,
+	})
+
+	got, err := rks.PublicKey("20240101000000")
+	if err != nil {
+		t.Fatalf("Should be able to fetch the remote key: %s", err)
+	}
+
+	exp, _ := ks.PublicKey("20240101000000")
+	if got != exp {
+		t.Fatalf("Should get the same public key:\ngot: %s\nexp: %s", got, exp)
+	}
+
+	if _, err := rks.PublicKey("20240101000000"); err != nil {
+		t.Fatalf("Should get the cached key: %s", err)
+	}
+	if fetches != 1 {
+		t.Fatalf("Should fetch the keys once: got %d", fetches)
+	}
+
+	// A rotated key is picked up by fetching again on an unknown kid.
+	fsys["20250101000000.pem"] = &fstest.MapFile{Data: genPEM(t)}
+	if err := ks.ReloadWithConfig(Config{ActiveKID: "20250101000000"}); err != nil {
+		t.Fatalf("Should be able to reload keys: %s", err)
+	}
+
+	if _, err := rks.PublicKey("20250101000000"); err != nil {
+		t.Fatalf("Should be able to fetch the rotated key: %s", err)
+	}
+	if fetches != 2 {
+		t.Fatalf("Should fetch the keys again: got %d", fetches)
+	}
+
+	if _, err := rks.PublicKey("symmetric"); err == nil {
+		t.Fatal("Should skip a key that can't be used")
+	}
+
+	if _, err := rks.PrivateKey("20250101000000"); err == nil {
+		t.Fatal("Should not be able to get a private key")
+	}
+}
+
+func toPEM(typ string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func genPEM(t *testing.T) []byte {
+	t.Helper()
+
+	pk, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %s", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(pk),
+	})
+}
@@ -0,0 +1,213 @@
+package keystore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RemoteKeyStore implements the KeyLookup interface by fetching the public keys
+// from a remote JWKS endpoint. It allows a service to verify tokens issued by
+// another deployment. Since only public keys are known, it can't be used to
+// sign tokens.
+type RemoteKeyStore struct {
+	url        string
+	client     *http.Client
+	ttl        time.Duration
+	minRefresh time.Duration
+
+	mu          sync.RWMutex
+	store       map[string]remoteKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+// remoteKey represents a public key fetched from the remote endpoint.
+type remoteKey struct {
+	publicPEM string
+	alg       string
+}
+
+// RemoteConfig represents the information required to construct a RemoteKeyStore.
+type RemoteConfig struct {
+	URL    string
+	Client *http.Client
+
+	// TTL is how long the fetched keys are cached before fetching them again.
+	TTL time.Duration
+
+	// MinRefresh is the minimum time between two fetches. It protects the
+	// remote endpoint when tokens with unknown kids are received.
+	MinRefresh time.Duration
+}
+
+// NewRemote constructs a RemoteKeyStore for the specified JWKS url.
+func NewRemote(cfg RemoteConfig) *RemoteKeyStore {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	if cfg.TTL == 0 {
+		cfg.TTL = 15 * time.Minute
+	}
+
+	if cfg.MinRefresh == 0 {
+		cfg.MinRefresh = 30 * time.Second
+	}
+
+	return &RemoteKeyStore{
+		url:        cfg.URL,
+		client:     cfg.Client,
+		ttl:        cfg.TTL,
+		minRefresh: cfg.MinRefresh,
+		store:      make(map[string]remoteKey),
+	}
+}
+
+// ActiveKID is not supported, a remote keystore can't sign tokens.
+func (rks *RemoteKeyStore) ActiveKID() (string, error) {
+	return "", errors.New("remote keystore can't sign tokens")
+}
+
+// LegacyKID is not supported, the tokens of a remote issuer must carry a kid.
+func (rks *RemoteKeyStore) LegacyKID() (string, error) {
+	return "", errors.New("remote keystore has no legacy key")
+}
+
+// PrivateKey is not supported, a remote keystore can't sign tokens.
+func (rks *RemoteKeyStore) PrivateKey(kid string) (string, error) {
+	return "", errors.New("remote keystore can't sign tokens")
+}
+
+// KIDs returns the ids of the keys fetched from the remote endpoint.
+func (rks *RemoteKeyStore) KIDs() []string {
+	rks.mu.RLock()
+	defer rks.mu.RUnlock()
+
+	kids := make([]string, 0, len(rks.store))
+	for kid := range rks.store {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	return kids
+}
+
+// PublicKey returns the public key for the given kid.
+func (rks *RemoteKeyStore) PublicKey(kid string) (string, error) {
+	key, err := rks.lookup(kid)
+	if err != nil {
+		return "", err
+	}
+
+	return key.publicPEM, nil
+}
+
+// Algorithm returns the signing algorithm of the key for the given kid.
+func (rks *RemoteKeyStore) Algorithm(kid string) (string, error) {
+	key, err := rks.lookup(kid)
+	if err != nil {
+		return "", err
+	}
+
+	return key.alg, nil
+}
+
+// lookup returns the key for the given kid. The keys are fetched again when
+// the cache has expired or when the kid is unknown, so keys rotated in by the
+// remote service are picked up.
+func (rks *RemoteKeyStore) lookup(kid string) (remoteKey, error) {
+	rks.mu.RLock()
+	key, found := rks.store[kid]
+	age := time.Since(rks.fetchedAt)
+	sinceAttempt := time.Since(rks.attemptedAt)
+	rks.mu.RUnlock()
+
+	if found && age < rks.ttl {
+		return key, nil
+	}
+
+	if sinceAttempt >= rks.minRefresh {
+		if err := rks.refresh(context.Background()); err != nil {
+			// Serve the stale key if the remote endpoint is unavailable.
+			if found {
+				return key, nil
+			}
+			return remoteKey{}, fmt.Errorf("fetching keys: %w", err)
+		}
+	}
+
+	rks.mu.RLock()
+	defer rks.mu.RUnlock()
+
+	key, found = rks.store[kid]
+	if !found {
+		return remoteKey{}, fmt.Errorf("key lookup failed: kid[%s]", kid)
+	}
+
+	return key, nil
+}
+
+func (rks *RemoteKeyStore) refresh(ctx context.Context) error {
+	rks.mu.Lock()
+	rks.attemptedAt = time.Now()
+	rks.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rks.url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := rks.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&set); err != nil {
+		return fmt.Errorf("decoding jwks: %w", err)
+	}
+
+	// A key this service can't use, like one of an algorithm it doesn't
+	// support, is skipped so the other keys of the set are still used.
+	store := make(map[string]remoteKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		pem, err := JWKToPublicPEM(jwk)
+		if err != nil {
+			continue
+		}
+
+		alg, err := JWKAlgorithm(jwk)
+		if err != nil {
+			continue
+		}
+
+		store[jwk.Kid] = remoteKey{
+			publicPEM: pem,
+			alg:       alg,
+		}
+	}
+
+	rks.mu.Lock()
+	defer rks.mu.Unlock()
+
+	rks.store = store
+	rks.fetchedAt = time.Now()
+
+	return nil
+}
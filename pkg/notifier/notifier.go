@@ -0,0 +1,90 @@
+// Package notifier delivers messages, like password reset links, to users.
+// The log and file notifiers stand in for a real delivery service during
+// local development.
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Housiadas/backend-system/pkg/logger"
+)
+
+// Message represents a message sent to a single recipient.
+type Message struct {
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier declares the behavior for delivering messages.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// =============================================================================
+
+// Log writes the messages to the logger.
+type Log struct {
+	log *logger.Logger
+}
+
+// NewLog constructs a notifier writing the messages to the logger.
+func NewLog(log *logger.Logger) *Log {
+	return &Log{
+		log: log,
+	}
+}
+
+// Notify implements the Notifier interface.
+func (n *Log) Notify(ctx context.Context, msg Message) error {
+	n.log.Info(ctx, "notifier", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// =============================================================================
+
+// File appends the messages to a file, one json document per line.
+type File struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFile constructs a notifier appending the messages to the file.
+func NewFile(path string) *File {
+	return &File{
+		path: path,
+	}
+}
+
+// Notify implements the Notifier interface.
+func (n *File) Notify(_ context.Context, msg Message) error {
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
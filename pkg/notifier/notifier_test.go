@@ -0,0 +1,44 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.jsonl")
+	n := NewFile(path)
+
+	for _, to := range []string{"a@example.com", "b@example.com"} {
+		if err := n.Notify(context.Background(), Message{To: to, Subject: "Hello"}); err != nil {
+			t.Fatalf("Should be able to notify: %s", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Should be able to open the file: %s", err)
+	}
+	defer f.Close()
+
+	var got []Message
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var msg Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			t.Fatalf("Should write a json document per line: %s", err)
+		}
+		got = append(got, msg)
+	}
+
+	if len(got) != 2 || got[1].To != "b@example.com" {
+		t.Fatalf("Should append every message: got %+v", got)
+	}
+	if got[0].Timestamp.IsZero() {
+		t.Fatal("Should set the timestamp")
+	}
+}
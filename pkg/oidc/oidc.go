@@ -0,0 +1,364 @@
+// Package oidc implements the relying party side of the OpenID Connect
+// authorization code flow with PKCE (RFC 7636). The provider is discovered
+// from its issuer and the ID tokens are verified against the keys it
+// publishes.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/Housiadas/backend-system/pkg/keystore"
+)
+
+// Set of error variables for the verification of an ID token.
+var (
+	ErrInvalidToken = errors.New("invalid id token")
+	ErrNonce        = errors.New("id token nonce mismatch")
+)
+
+// Config represents the registration of the client with the provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Client       *http.Client
+}
+
+// Discovery is the part of the provider metadata used by the flow.
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is a discovered OpenID provider the users log in with.
+type Provider struct {
+	cfg       Config
+	discovery Discovery
+	keys      *keystore.RemoteKeyStore
+	parser    *jwt.Parser
+}
+
+// NewProvider discovers the provider of the issuer. The openid scope is
+// always requested, along with email and profile when no scopes are set.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"email", "profile"}
+	}
+
+	if !slices.Contains(cfg.Scopes, "openid") {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+
+	wellKnown := strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery: unexpected status: %d", resp.StatusCode)
+	}
+
+	var d Discovery
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&d); err != nil {
+		return nil, fmt.Errorf("decoding discovery: %w", err)
+	}
+
+	// The issuer of the metadata must be the one configured, it is the
+	// issuer the ID tokens are checked against.
+	if strings.TrimSuffix(d.Issuer, "/") != strings.TrimSuffix(cfg.Issuer, "/") {
+		return nil, fmt.Errorf("discovery: issuer mismatch: got %s", d.Issuer)
+	}
+
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, errors.New("discovery: missing endpoints")
+	}
+
+	p := Provider{
+		cfg:       cfg,
+		discovery: d,
+		keys: keystore.NewRemote(keystore.RemoteConfig{
+			URL:    d.JWKSURI,
+			Client: cfg.Client,
+		}),
+		parser: jwt.NewParser(jwt.WithValidMethods([]string{
+			jwt.SigningMethodRS256.Alg(),
+			jwt.SigningMethodES256.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+		})),
+	}
+
+	return &p, nil
+}
+
+// Discovery returns the metadata of the provider.
+func (p *Provider) Discovery() Discovery {
+	return p.discovery
+}
+
+// =============================================================================
+
+// AuthRequest holds the secrets of a login in progress. They are kept by the
+// client, only the state, the nonce and the challenge of the verifier are
+// sent to the provider.
+type AuthRequest struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// NewAuthRequest generates the secrets of a new login.
+func NewAuthRequest() (AuthRequest, error) {
+	var ar AuthRequest
+
+	for _, v := range []*string{&ar.State, &ar.Nonce, &ar.Verifier} {
+		s, err := random()
+		if err != nil {
+			return AuthRequest{}, err
+		}
+		*v = s
+	}
+
+	return ar, nil
+}
+
+// S256Challenge returns the code challenge of the verifier.
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the url of the provider the user is sent to for the
+// login.
+func (p *Provider) AuthCodeURL(ar AuthRequest) string {
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {ar.State},
+		"nonce":                 {ar.Nonce},
+		"code_challenge":        {S256Challenge(ar.Verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(p.discovery.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return p.discovery.AuthorizationEndpoint + sep + q.Encode()
+}
+
+// CheckState compares the state returned by the provider to the one of the
+// request in constant time.
+func (ar AuthRequest) CheckState(state string) bool {
+	return subtle.ConstantTimeCompare([]byte(ar.State), []byte(state)) == 1
+}
+
+// =============================================================================
+
+// tokenResponse is the response of the token endpoint.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// Exchange trades the authorization code for the tokens of the user and
+// returns the raw ID token, it still has to be verified.
+func (p *Provider) Exchange(ctx context.Context, code string, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.cfg.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("exchange: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tr tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&tr); err != nil {
+		return "", fmt.Errorf("decoding token response: status[%d]: %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("exchange: status[%d]: %s: %s", resp.StatusCode, tr.Error, tr.Description)
+	}
+
+	if tr.IDToken == "" {
+		return "", errors.New("exchange: no id token")
+	}
+
+	return tr.IDToken, nil
+}
+
+// =============================================================================
+
+// IDToken represents the verified claims of an ID token.
+type IDToken struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Claims        map[string]any
+}
+
+// Strings returns the values of a claim holding a list of strings, like the
+// groups of the user. A claim holding a single string is a list of one.
+func (t IDToken) Strings(claim string) []string {
+	switch v := t.Claims[claim].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
+
+// idClaims are the claims of an ID token checked by the verification.
+type idClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// Verify checks the signature, issuer, audience, expiry and nonce of an ID
+// token and returns its claims.
+func (p *Provider) Verify(ctx context.Context, rawIDToken string, nonce string) (IDToken, error) {
+	var claims idClaims
+	if _, err := p.parser.ParseWithClaims(rawIDToken, &claims, p.keyFunc); err != nil {
+		return IDToken{}, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	if strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(p.discovery.Issuer, "/") {
+		return IDToken{}, fmt.Errorf("%w: issuer mismatch", ErrInvalidToken)
+	}
+
+	if !claims.VerifyAudience(p.cfg.ClientID, true) {
+		return IDToken{}, fmt.Errorf("%w: audience mismatch", ErrInvalidToken)
+	}
+
+	if claims.ExpiresAt == nil {
+		return IDToken{}, fmt.Errorf("%w: no expiry", ErrInvalidToken)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return IDToken{}, ErrNonce
+	}
+
+	// The claims are decoded again to keep the ones specific to the
+	// provider, like the groups.
+	all := make(map[string]any)
+	if _, _, err := p.parser.ParseUnverified(rawIDToken, jwt.MapClaims(all)); err != nil {
+		return IDToken{}, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	t := IDToken{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		Name:          claims.Name,
+		Claims:        all,
+	}
+
+	return t, nil
+}
+
+// keyFunc returns the public key of the provider the token was signed with.
+// The algorithm must be the one of the key.
+func (p *Provider) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	alg, err := p.keys.Algorithm(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Method.Alg() != alg {
+		return nil, fmt.Errorf("unexpected algorithm: %s", token.Method.Alg())
+	}
+
+	pem, err := p.keys.PublicKey(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	switch alg {
+	case jwt.SigningMethodRS256.Alg():
+		return jwt.ParseRSAPublicKeyFromPEM([]byte(pem))
+	case jwt.SigningMethodES256.Alg():
+		return jwt.ParseECPublicKeyFromPEM([]byte(pem))
+	case jwt.SigningMethodEdDSA.Alg():
+		return jwt.ParseEdPublicKeyFromPEM([]byte(pem))
+	}
+
+	return nil, fmt.Errorf("unsupported algorithm: %s", alg)
+}
+
+// random returns 32 random bytes encoded for use in a url.
+func random() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating random: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,159 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/Housiadas/backend-system/pkg/oidc"
+	"github.com/Housiadas/backend-system/pkg/oidc/oidctest"
+)
+
+func newProvider(t *testing.T) (*oidctest.Provider, *oidc.Provider) {
+	t.Helper()
+
+	fake, err := oidctest.New("client", "secret")
+	if err != nil {
+		t.Fatalf("Should be able to start the provider: %s", err)
+	}
+	t.Cleanup(fake.Close)
+
+	p, err := oidc.NewProvider(context.Background(), oidc.Config{
+		Issuer:       fake.Issuer(),
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+	})
+	if err != nil {
+		t.Fatalf("Should be able to discover the provider: %s", err)
+	}
+
+	return fake, p
+}
+
+func Test_Flow(t *testing.T) {
+	fake, p := newProvider(t)
+
+	fake.SetUser(map[string]any{
+		"sub":            "user-1",
+		"email":          "chris@housi.com",
+		"email_verified": true,
+		"name":           "Chris Housi",
+		"groups":         []string{"engineering", "admins"},
+	})
+
+	ar, err := oidc.NewAuthRequest()
+	if err != nil {
+		t.Fatalf("Should be able to create a request: %s", err)
+	}
+
+	authURL, err := url.Parse(p.AuthCodeURL(ar))
+	if err != nil {
+		t.Fatalf("Should be able to parse the url: %s", err)
+	}
+	if got := authURL.Query().Get("code_challenge"); got != oidc.S256Challenge(ar.Verifier) {
+		t.Fatalf("Should send the challenge of the verifier: got %s", got)
+	}
+	if got := authURL.Query().Get("scope"); got != "openid email profile" {
+		t.Fatalf("Should request the openid scope: got %s", got)
+	}
+
+	code, state, err := fake.Login(authURL.String())
+	if err != nil {
+		t.Fatalf("Should be able to log in: %s", err)
+	}
+	if !ar.CheckState(state) {
+		t.Fatal("Should get the state back")
+	}
+
+	// The code is bound to the verifier, another one is refused.
+	if _, err := p.Exchange(context.Background(), code, "wrong"); err == nil {
+		t.Fatal("Should refuse a wrong verifier")
+	}
+
+	code, _, err = fake.Login(authURL.String())
+	if err != nil {
+		t.Fatalf("Should be able to log in: %s", err)
+	}
+
+	raw, err := p.Exchange(context.Background(), code, ar.Verifier)
+	if err != nil {
+		t.Fatalf("Should be able to exchange the code: %s", err)
+	}
+
+	if _, err := p.Exchange(context.Background(), code, ar.Verifier); err == nil {
+		t.Fatal("Should not exchange a code twice")
+	}
+
+	if _, err := p.Verify(context.Background(), raw, "other"); !errors.Is(err, oidc.ErrNonce) {
+		t.Fatalf("Should refuse another nonce: got %v", err)
+	}
+
+	idt, err := p.Verify(context.Background(), raw, ar.Nonce)
+	if err != nil {
+		t.Fatalf("Should be able to verify the id token: %s", err)
+	}
+
+	if idt.Subject != "user-1" || idt.Email != "chris@housi.com" || !idt.EmailVerified || idt.Name != "Chris Housi" {
+		t.Fatalf("Should get the claims of the user: got %+v", idt)
+	}
+
+	if groups := idt.Strings("groups"); len(groups) != 2 || groups[1] != "admins" {
+		t.Fatalf("Should get the groups: got %v", groups)
+	}
+}
+
+func Test_Verify(t *testing.T) {
+	fake, p := newProvider(t)
+
+	now := time.Now()
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   fake.Issuer(),
+			"aud":   "client",
+			"sub":   "user-1",
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Minute).Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	tests := map[string]func(c jwt.MapClaims){
+		"issuer":   func(c jwt.MapClaims) { c["iss"] = "http://other" },
+		"audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":  func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() },
+		"no-exp":   func(c jwt.MapClaims) { delete(c, "exp") },
+	}
+
+	for name, change := range tests {
+		claims := valid()
+		change(claims)
+
+		raw, err := fake.Sign(claims)
+		if err != nil {
+			t.Fatalf("%s: Should be able to sign: %s", name, err)
+		}
+
+		if _, err := p.Verify(context.Background(), raw, "nonce"); !errors.Is(err, oidc.ErrInvalidToken) {
+			t.Fatalf("%s: Should refuse the token: got %v", name, err)
+		}
+	}
+
+	raw, err := fake.Sign(valid())
+	if err != nil {
+		t.Fatalf("Should be able to sign: %s", err)
+	}
+
+	if _, err := p.Verify(context.Background(), raw+"A", "nonce"); !errors.Is(err, oidc.ErrInvalidToken) {
+		t.Fatalf("Should refuse a bad signature: got %v", err)
+	}
+
+	if _, err := p.Verify(context.Background(), raw, "nonce"); err != nil {
+		t.Fatalf("Should accept a valid token: %s", err)
+	}
+}
@@ -0,0 +1,265 @@
+// Package oidctest provides an in-process OpenID provider to test the login
+// flow against. It serves the discovery document, the keys, the authorization
+// and the token endpoints, checking the PKCE verifier.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/Housiadas/backend-system/pkg/keystore"
+	"github.com/Housiadas/backend-system/pkg/oidc"
+)
+
+const kid = "oidctest"
+
+// Provider is a fake OpenID provider. Every authorization request is
+// approved for the user set with SetUser.
+type Provider struct {
+	ClientID     string
+	ClientSecret string
+
+	srv *httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	user  map[string]any
+	codes map[string]grant
+}
+
+// grant is an authorization code waiting to be exchanged.
+type grant struct {
+	clientID    string
+	redirectURI string
+	challenge   string
+	nonce       string
+	user        map[string]any
+}
+
+// New starts a fake provider for the client, it is stopped with Close.
+func New(clientID string, clientSecret string) (*Provider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("generating key: %w", err)
+	}
+
+	p := Provider{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		codes:        make(map[string]grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+
+	p.srv = httptest.NewServer(mux)
+
+	return &p, nil
+}
+
+// Issuer returns the issuer of the provider.
+func (p *Provider) Issuer() string {
+	return p.srv.URL
+}
+
+// Close stops the provider.
+func (p *Provider) Close() {
+	p.srv.Close()
+}
+
+// SetUser sets the claims of the user logging in next, like sub, email,
+// email_verified, name or groups.
+func (p *Provider) SetUser(claims map[string]any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.user = claims
+}
+
+// Login follows the authorization url as a browser would, the user being
+// already signed in with the provider, and returns the code and state sent
+// back to the redirect url.
+func (p *Provider) Login(authURL string) (code string, state string, err error) {
+	client := http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", fmt.Errorf("authorize: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("authorize: unexpected status: %d", resp.StatusCode)
+	}
+
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", fmt.Errorf("authorize: parsing redirect: %w", err)
+	}
+
+	return loc.Query().Get("code"), loc.Query().Get("state"), nil
+}
+
+// Sign signs the claims with the key of the provider, to craft ID tokens.
+func (p *Provider) Sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	return token.SignedString(p.key)
+}
+
+// =============================================================================
+
+func (p *Provider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, oidc.Discovery{
+		Issuer:                p.srv.URL,
+		AuthorizationEndpoint: p.srv.URL + "/authorize",
+		TokenEndpoint:         p.srv.URL + "/token",
+		JWKSURI:               p.srv.URL + "/jwks",
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, _ *http.Request) {
+	der, err := x509.MarshalPKIXPublicKey(&p.key.PublicKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	jwk, err := keystore.PublicPEMToJWK(kid, keystore.AlgRS256, string(publicPEM))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, keystore.JWKSet{Keys: []keystore.JWK{jwk}})
+}
+
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	if q.Get("response_type") != "code" || q.Get("client_id") != p.ClientID {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "pkce required", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	code := rand.Text()
+	p.codes[code] = grant{
+		clientID:    q.Get("client_id"),
+		redirectURI: q.Get("redirect_uri"),
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		user:        p.user,
+	}
+	p.mu.Unlock()
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	if r.PostForm.Get("client_id") != p.ClientID || r.PostForm.Get("client_secret") != p.ClientSecret {
+		tokenError(w, "invalid_client")
+		return
+	}
+
+	// A code is exchanged once.
+	p.mu.Lock()
+	code := r.PostForm.Get("code")
+	g, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	if !ok || g.clientID != p.ClientID || g.redirectURI != r.PostForm.Get("redirect_uri") {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	if oidc.S256Challenge(r.PostForm.Get("code_verifier")) != g.challenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss":   p.srv.URL,
+		"aud":   p.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": g.nonce,
+	}
+	for k, v := range g.user {
+		claims[k] = v
+	}
+
+	idToken, err := p.Sign(claims)
+	if err != nil {
+		tokenError(w, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
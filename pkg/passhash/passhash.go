@@ -0,0 +1,215 @@
+// Package passhash hashes and verifies passwords. Hashes are encoded in the
+// PHC string format, $<id>$<params>$<salt>$<hash>, so a hash tells which
+// algorithm and parameters produced it and old hashes keep verifying after
+// the algorithm changes.
+package passhash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrUnknownAlgorithm is returned for a hash of an algorithm not supported.
+var ErrUnknownAlgorithm = errors.New("unknown password hash algorithm")
+
+// Hasher declares the behavior for hashing passwords. NeedsRehash reports
+// whether a hash was produced by another algorithm or by weaker parameters
+// than the ones of the hasher, so it should be replaced on the next login.
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(encoded string, password string) (bool, error)
+	NeedsRehash(encoded string) bool
+}
+
+// Verify checks the password against a hash of any supported algorithm.
+func Verify(encoded string, password string) (bool, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		p, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, err
+		}
+
+		other := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1, nil
+
+	case isBcrypt(encoded):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		switch {
+		case err == nil:
+			return true, nil
+		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+			return false, nil
+		}
+		return false, err
+	}
+
+	return false, ErrUnknownAlgorithm
+}
+
+// =============================================================================
+
+// Default parameters of argon2id, following the OWASP recommendations.
+const (
+	defaultMemory  = 64 * 1024
+	defaultTime    = 3
+	defaultThreads = 2
+	saltLength     = 16
+	keyLength      = 32
+)
+
+// Argon2Params holds the cost parameters of argon2id, Memory is in KiB. The
+// zero values fall back to the defaults.
+type Argon2Params struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+// Argon2id hashes passwords with argon2id.
+type Argon2id struct {
+	params Argon2Params
+}
+
+// NewArgon2id constructs an argon2id hasher with the parameters.
+func NewArgon2id(params Argon2Params) *Argon2id {
+	if params.Memory == 0 {
+		params.Memory = defaultMemory
+	}
+	if params.Time == 0 {
+		params.Time = defaultTime
+	}
+	if params.Threads == 0 {
+		params.Threads = defaultThreads
+	}
+
+	return &Argon2id{
+		params: params,
+	}
+}
+
+// Hash implements the Hasher interface.
+func (h *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generating salt: %w", err)
+	}
+
+	p := h.params
+	key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, keyLength)
+
+	encoded := fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+
+	return encoded, nil
+}
+
+// Verify implements the Hasher interface.
+func (h *Argon2id) Verify(encoded string, password string) (bool, error) {
+	return Verify(encoded, password)
+}
+
+// NeedsRehash implements the Hasher interface.
+func (h *Argon2id) NeedsRehash(encoded string) bool {
+	p, _, _, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return p.Memory < h.params.Memory || p.Time < h.params.Time || p.Threads < h.params.Threads
+}
+
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2Params{}, nil, nil, errors.New("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return Argon2Params{}, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	var p Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	return p, salt, key, nil
+}
+
+// =============================================================================
+
+// Bcrypt hashes passwords with bcrypt. It is kept to verify the hashes
+// created before argon2id became the default.
+type Bcrypt struct {
+	cost int
+}
+
+// NewBcrypt constructs a bcrypt hasher with the cost, zero is the default
+// cost of the bcrypt package.
+func NewBcrypt(cost int) *Bcrypt {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+
+	return &Bcrypt{
+		cost: cost,
+	}
+}
+
+// Hash implements the Hasher interface.
+func (h *Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("generatefrompassword: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+// Verify implements the Hasher interface.
+func (h *Bcrypt) Verify(encoded string, password string) (bool, error) {
+	return Verify(encoded, password)
+}
+
+// NeedsRehash implements the Hasher interface.
+func (h *Bcrypt) NeedsRehash(encoded string) bool {
+	if !isBcrypt(encoded) {
+		return true
+	}
+
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+
+	return cost < h.cost
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
@@ -0,0 +1,63 @@
+package passhash
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func Test_Argon2id(t *testing.T) {
+	h := NewArgon2id(Argon2Params{Memory: 1024, Time: 1, Threads: 1})
+
+	encoded, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Should be able to hash: %s", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("Should encode the hash in the PHC format: got %s", encoded)
+	}
+
+	if ok, err := h.Verify(encoded, "correct horse"); err != nil || !ok {
+		t.Fatalf("Should verify the password: %v %s", ok, err)
+	}
+	if ok, _ := h.Verify(encoded, "wrong horse"); ok {
+		t.Fatal("Should not verify a wrong password")
+	}
+
+	if h.NeedsRehash(encoded) {
+		t.Fatal("Should not rehash a hash of the same parameters")
+	}
+
+	stronger := NewArgon2id(Argon2Params{Memory: 2048, Time: 1, Threads: 1})
+	if !stronger.NeedsRehash(encoded) {
+		t.Fatal("Should rehash a hash of weaker parameters")
+	}
+}
+
+func Test_Bcrypt(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Should be able to hash: %s", err)
+	}
+
+	if ok, err := Verify(string(hash), "correct horse"); err != nil || !ok {
+		t.Fatalf("Should verify a bcrypt hash: %v %s", ok, err)
+	}
+	if ok, _ := Verify(string(hash), "wrong horse"); ok {
+		t.Fatal("Should not verify a wrong password")
+	}
+
+	if !NewArgon2id(Argon2Params{}).NeedsRehash(string(hash)) {
+		t.Fatal("Should rehash a bcrypt hash with argon2id")
+	}
+	if !NewBcrypt(bcrypt.DefaultCost).NeedsRehash(string(hash)) {
+		t.Fatal("Should rehash a bcrypt hash of a lower cost")
+	}
+}
+
+func Test_Unknown(t *testing.T) {
+	if _, err := Verify("$md5$abc", "password"); err != ErrUnknownAlgorithm {
+		t.Fatalf("Should not verify an unknown algorithm: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const sessionKey ctxKey = "sessionKey"
+
+// Session is the identity of the caller the statements run for. When one is
+// set on the context, the statements are run with the app.user_id, app.roles,
+// app.org_id and app.department settings so the row level security policies
+// of the database can check it. An empty OrgID is not scoped to an
+// organization.
+type Session struct {
+	UserID     string
+	Roles      []string
+	OrgID      string
+	Department string
+}
+
+// WithSession sets the session the statements run with.
+func WithSession(ctx context.Context, s Session) context.Context {
+	return context.WithValue(ctx, sessionKey, s)
+}
+
+// GetSession returns the session the statements run with.
+func GetSession(ctx context.Context) (Session, bool) {
+	s, ok := ctx.Value(sessionKey).(Session)
+	return s, ok
+}
+
+// WithoutCaller runs the statements of the context for the service rather
+// than for its caller, the row level security policies don't restrict them.
+// It is meant for the statements a caller runs on rows it can't reach, like
+// the ones taking the stock of the products it orders. The settings are
+// still set, so the ones of the caller don't carry over in a transaction,
+// and the organization of the session is kept.
+func WithoutCaller(ctx context.Context) context.Context {
+	s, ok := GetSession(ctx)
+	if !ok {
+		return ctx
+	}
+
+	return WithSession(ctx, Session{OrgID: s.OrgID})
+}
+
+// txBeginner is implemented by a connection pool, as opposed to a
+// transaction already begun.
+type txBeginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// withSession runs the statement with the session of the context. The
+// settings are local to a transaction so they never leak to the next user
+// of the connection: inside a transaction they are set on it, otherwise the
+// statement is run in a transaction of its own.
+func withSession(ctx context.Context, db sqlx.ExtContext, fn func(db sqlx.ExtContext) error) error {
+	s, ok := GetSession(ctx)
+	if !ok {
+		return fn(db)
+	}
+
+	beginner, ok := db.(txBeginner)
+	if !ok {
+		if err := setSession(ctx, db, s); err != nil {
+			return err
+		}
+		return fn(db)
+	}
+
+	tx, err := beginner.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin session: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := setSession(ctx, tx, s); err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit session: %w", err)
+	}
+
+	return nil
+}
+
+func setSession(ctx context.Context, db sqlx.ExtContext, s Session) error {
+	const q = `SELECT set_config('app.user_id', $1, true),
+       set_config('app.roles', $2, true),
+       set_config('app.org_id', $3, true),
+       set_config('app.department', $4, true)`
+
+	if _, err := db.ExecContext(ctx, q, s.UserID, strings.Join(s.Roles, ","), s.OrgID, s.Department); err != nil {
+		return fmt.Errorf("set session: %w", err)
+	}
+
+	return nil
+}
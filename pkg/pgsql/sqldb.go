@@ -22,8 +22,9 @@ import (
 // lib/pq errorCodeNames
 // https://github.com/lib/pq/blob/master/error.go#L178
 const (
-	uniqueViolation = "23505"
-	undefinedTable  = "42P01"
+	uniqueViolation      = "23505"
+	undefinedTable       = "42P01"
+	serializationFailure = "40001"
 )
 
 // Set of error variables for CRUD operations.
@@ -136,7 +137,11 @@ func NamedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	ctx, span := otel.AddSpan(ctx, "internal.api.pgsql.exec", attribute.String("query", q))
 	defer span.End()
 
-	if _, err := sqlx.NamedExecContext(ctx, db, query, data); err != nil {
+	err = withSession(ctx, db, func(db sqlx.ExtContext) error {
+		_, err := sqlx.NamedExecContext(ctx, db, query, data)
+		return err
+	})
+	if err != nil {
 		var pqerr *pgconn.PgError
 		if errors.As(err, &pqerr) {
 			switch pqerr.Code {
@@ -184,6 +189,12 @@ func namedQuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.Ext
 	ctx, span := otel.AddSpan(ctx, "internal.api.pgsql.queryslice", attribute.String("query", q))
 	defer span.End()
 
+	return withSession(ctx, db, func(db sqlx.ExtContext) error {
+		return querySlice(ctx, db, query, data, dest, withIn)
+	})
+}
+
+func querySlice[T any](ctx context.Context, db sqlx.ExtContext, query string, data any, dest *[]T, withIn bool) (err error) {
 	var rows *sqlx.Rows
 
 	switch withIn {
@@ -224,6 +235,9 @@ func namedQuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.Ext
 		}
 		slice = append(slice, *v)
 	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
 	*dest = slice
 
 	return nil
@@ -260,6 +274,12 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	ctx, span := otel.AddSpan(ctx, "internal.api.pgsql.query", attribute.String("query", q))
 	defer span.End()
 
+	return withSession(ctx, db, func(db sqlx.ExtContext) error {
+		return queryStruct(ctx, db, query, data, dest, withIn)
+	})
+}
+
+func queryStruct(ctx context.Context, db sqlx.ExtContext, query string, data any, dest any, withIn bool) (err error) {
 	var rows *sqlx.Rows
 
 	switch withIn {
@@ -293,6 +313,10 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	defer rows.Close()
 
 	if !rows.Next() {
+		// An error of the statement may only surface on reading its rows.
+		if err := rows.Err(); err != nil {
+			return err
+		}
 		return ErrDBNotFound
 	}
 
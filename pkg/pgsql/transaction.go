@@ -2,9 +2,13 @@ package pgsql
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -56,6 +60,59 @@ func GetExtContext(tx CommitRollbacker) (sqlx.ExtContext, error) {
 	return ec, nil
 }
 
+// WithSerializable runs fn in a serializable transaction of its own, so it
+// behaves as if no other transaction ran at the same time. The transaction is
+// run again, up to attempts times, when it conflicts with a concurrent one;
+// the attempts are spread apart by a random wait so the conflicting
+// transactions don't meet again. Inside a transaction already begun, fn is
+// run in it as is.
+func WithSerializable(ctx context.Context, db sqlx.ExtContext, attempts int, fn func(tx sqlx.ExtContext) error) error {
+	beginner, ok := db.(txBeginner)
+	if !ok {
+		return fn(db)
+	}
+
+	var err error
+	for attempt := range attempts {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(rand.N(time.Duration(attempt) * 5 * time.Millisecond)):
+			}
+		}
+
+		err = serializable(ctx, beginner, fn)
+
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != serializationFailure {
+			return err
+		}
+	}
+
+	return err
+}
+
+func serializable(ctx context.Context, beginner txBeginner, fn func(tx sqlx.ExtContext) error) error {
+	tx, err := beginner.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return fmt.Errorf("begin serializable: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit serializable: %w", err)
+	}
+
+	return nil
+}
+
 func SetTran(ctx context.Context, tx CommitRollbacker) context.Context {
 	return context.WithValue(ctx, tranKey, tx)
 }
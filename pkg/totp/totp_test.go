@@ -0,0 +1,67 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// secret is the RFC 6238 SHA1 test key "12345678901234567890" in base32.
+const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func Test_Code(t *testing.T) {
+	// The RFC lists eight digit codes, these are their last six digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Should be able to compute the code: %s", err)
+		}
+		if got != tt.want {
+			t.Errorf("time %d: got %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	code, err := Code(secret, now)
+	if err != nil {
+		t.Fatalf("Should be able to compute the code: %s", err)
+	}
+
+	s, ok := Validate(secret, code, now.Add(period*time.Second))
+	if !ok || s != step(now) {
+		t.Fatalf("Should accept the code of the previous period: step[%d] ok[%v]", s, ok)
+	}
+
+	if _, ok := Validate(secret, code, now.Add(2*period*time.Second)); ok {
+		t.Fatal("Should not accept a code two periods old")
+	}
+
+	if _, ok := Validate(secret, "12345", now); ok {
+		t.Fatal("Should not accept a code of the wrong length")
+	}
+}
+
+func Test_URI(t *testing.T) {
+	uri := URI("Housi", "admin@example.com", secret)
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Housi:admin@example.com?") {
+		t.Fatalf("Should build a totp uri: %s", uri)
+	}
+	if !strings.Contains(uri, "secret="+secret) || !strings.Contains(uri, "issuer=Housi") {
+		t.Fatalf("Should carry the secret and the issuer: %s", uri)
+	}
+}